@@ -6,12 +6,15 @@ package admin
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/snow/engine/snowman"
 	"github.com/ava-labs/gecko/utils/logging"
 
 	cjson "github.com/ava-labs/gecko/utils/json"
@@ -22,25 +25,29 @@ const (
 )
 
 var (
-	errAliasTooLong = errors.New("alias length is too long")
+	errAliasTooLong    = errors.New("alias length is too long")
+	errUnknownChain    = errors.New("unknown chain")
+	errNoPreferenceLog = errors.New("chain's consensus engine doesn't record preference changes")
 )
 
 // Admin is the API service for node admin management
 type Admin struct {
 	log          logging.Logger
+	logFactory   logging.Factory
 	performance  Performance
 	chainManager chains.Manager
 	httpServer   *api.Server
 }
 
 // NewService returns a new admin API service
-func NewService(log logging.Logger, chainManager chains.Manager, httpServer *api.Server) (*common.HTTPHandler, error) {
+func NewService(log logging.Logger, logFactory logging.Factory, chainManager chains.Manager, httpServer *api.Server) (*common.HTTPHandler, error) {
 	newServer := rpc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
 	if err := newServer.RegisterService(&Admin{
 		log:          log,
+		logFactory:   logFactory,
 		chainManager: chainManager,
 		httpServer:   httpServer,
 	}, "admin"); err != nil {
@@ -136,3 +143,147 @@ func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *Stacktrace
 	reply.Stacktrace = logging.Stacktrace{Global: true}.String()
 	return nil
 }
+
+// SetLoggerLevelArgs are the arguments for calling SetLoggerLevel
+type SetLoggerLevelArgs struct {
+	// LoggerName is the logger to modify. If empty, the default levels are set.
+	LoggerName   string `json:"loggerName"`
+	LogLevel     string `json:"logLevel"`
+	DisplayLevel string `json:"displayLevel"`
+}
+
+// SetLoggerLevel sets the log and display levels of a logger while the node is
+// running
+func (service *Admin) SetLoggerLevel(_ *http.Request, args *SetLoggerLevelArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: SetLoggerLevel called with LoggerName: %q, LogLevel: %s, DisplayLevel: %s", args.LoggerName, args.LogLevel, args.DisplayLevel)
+
+	logLevel, err := logging.ToLevel(args.LogLevel)
+	if err != nil {
+		return err
+	}
+	displayLevel, err := logging.ToLevel(args.DisplayLevel)
+	if err != nil {
+		return err
+	}
+
+	if args.LoggerName == "" {
+		service.logFactory.SetDefaultLogLevels(logLevel, displayLevel)
+	} else if err := service.logFactory.SetLogLevels(args.LoggerName, logLevel, displayLevel); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ResetLoggerLevelArgs are the arguments for calling ResetLoggerLevel
+type ResetLoggerLevelArgs struct {
+	LoggerName string `json:"loggerName"`
+}
+
+// ResetLoggerLevel reverts a logger to the default levels
+func (service *Admin) ResetLoggerLevel(_ *http.Request, args *ResetLoggerLevelArgs, reply *api.SuccessResponse) error {
+	service.log.Info("Admin: ResetLoggerLevel called with LoggerName: %q", args.LoggerName)
+
+	if err := service.logFactory.ResetLogLevels(args.LoggerName); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// GetLoggerNamesReply are the results from calling GetLoggerNames
+type GetLoggerNamesReply struct {
+	LoggerNames []string `json:"loggerNames"`
+}
+
+// GetLoggerNames returns the names of the loggers whose levels can be set
+func (service *Admin) GetLoggerNames(_ *http.Request, _ *struct{}, reply *GetLoggerNamesReply) error {
+	service.log.Info("Admin: GetLoggerNames called")
+
+	reply.LoggerNames = service.logFactory.GetLoggerNames()
+	return nil
+}
+
+// GetRecentLogsArgs are the arguments for calling GetRecentLogs
+type GetRecentLogsArgs struct {
+	// LoggerName is the logger to read from. If empty, the main logger is read.
+	LoggerName string `json:"loggerName"`
+}
+
+// GetRecentLogsReply are the results from calling GetRecentLogs
+type GetRecentLogsReply struct {
+	Lines []string `json:"lines"`
+}
+
+// GetRecentLogs returns the lines most recently written by a logger, oldest
+// first
+func (service *Admin) GetRecentLogs(_ *http.Request, args *GetRecentLogsArgs, reply *GetRecentLogsReply) error {
+	service.log.Info("Admin: GetRecentLogs called with LoggerName: %q", args.LoggerName)
+
+	name := args.LoggerName
+	if name == "" {
+		name = logging.MainLoggerName
+	}
+	lines, err := service.logFactory.GetRecentLines(name)
+	reply.Lines = lines
+	return err
+}
+
+// GetPreferenceChangesArgs are the arguments for calling GetPreferenceChanges
+type GetPreferenceChangesArgs struct {
+	Chain string `json:"chain"`
+}
+
+// PreferenceChange is a single change of a chain's preferred block
+type PreferenceChange struct {
+	Time      time.Time               `json:"time"`
+	OldHead   ids.ID                  `json:"oldHead"`
+	NewHead   ids.ID                  `json:"newHead"`
+	Height    cjson.Uint64            `json:"height"`
+	Depth     cjson.Uint32            `json:"depth"`
+	RequestID cjson.Uint32            `json:"requestID"`
+	Votes     map[string]cjson.Uint32 `json:"votes"`
+}
+
+// GetPreferenceChangesReply are the results from calling GetPreferenceChanges
+type GetPreferenceChangesReply struct {
+	Changes []PreferenceChange `json:"changes"`
+}
+
+// GetPreferenceChanges returns the most recent changes to a linear chain's
+// preferred block, oldest first, along with the polls that caused them
+func (service *Admin) GetPreferenceChanges(_ *http.Request, args *GetPreferenceChangesArgs, reply *GetPreferenceChangesReply) error {
+	service.log.Info("Admin: GetPreferenceChanges called with Chain: %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return err
+	}
+	engine, exists := service.chainManager.Engine(chainID)
+	if !exists {
+		return errUnknownChain
+	}
+	transitive, ok := engine.(*snowman.Transitive)
+	if !ok {
+		return errNoPreferenceLog
+	}
+
+	changes := transitive.PreferenceChanges()
+	reply.Changes = make([]PreferenceChange, len(changes))
+	for i, change := range changes {
+		votes := make(map[string]cjson.Uint32, change.Votes.Len())
+		for _, blkID := range change.Votes.List() {
+			votes[blkID.String()] = cjson.Uint32(change.Votes.Count(blkID))
+		}
+		reply.Changes[i] = PreferenceChange{
+			Time:      change.Time,
+			OldHead:   change.OldHead,
+			NewHead:   change.NewHead,
+			Height:    cjson.Uint64(change.Height),
+			Depth:     cjson.Uint32(change.Depth),
+			RequestID: cjson.Uint32(change.RequestID),
+			Votes:     votes,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+// authMiddleware wraps a handler so that only requests with the header
+// "Authorization: Bearer <token>", for one of [tokens], are served. All other
+// requests are rejected with 401 Unauthorized.
+func authMiddleware(handler http.Handler, tokens []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, tokens) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("API call rejected because of a missing or invalid bearer token"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// authorized returns true if [r] carries one of [tokens] as a bearer token
+func authorized(r *http.Request, tokens []string) bool {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return false
+	}
+	token := []byte(strings.TrimPrefix(header, bearerPrefix))
+
+	// Check every token, in constant time, so that the response time doesn't
+	// reveal which, if any, of the tokens were close to matching
+	matched := 0
+	for _, allowed := range tokens {
+		matched |= subtle.ConstantTimeCompare(token, []byte(allowed))
+	}
+	return matched == 1
+}
@@ -0,0 +1,89 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// authTestServer returns a server with an "admin" route that requires one of
+// [tokens] and an "info" route that doesn't require authentication
+func authTestServer(t *testing.T, tokens ...string) *Server {
+	s := &Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, "localhost", 8080)
+	s.RequireAuth("admin", tokens...)
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	for _, base := range []string{"admin", "info"} {
+		if err := s.AddRoute(&common.HTTPHandler{Handler: ok}, new(sync.RWMutex), base, "", logging.NoLog{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func authTestRequest(s *Server, path, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	writer := httptest.NewRecorder()
+	s.handler().ServeHTTP(writer, req)
+	return writer
+}
+
+func TestAuthAuthorized(t *testing.T) {
+	s := authTestServer(t, "token1", "token2")
+
+	for _, token := range []string{"token1", "token2"} {
+		writer := authTestRequest(s, "/ext/admin", "Bearer "+token)
+		if writer.Code != http.StatusOK {
+			t.Fatalf("Wrong status code for %s. Expected %d ; Returned %d", token, http.StatusOK, writer.Code)
+		}
+	}
+}
+
+func TestAuthUnauthorized(t *testing.T) {
+	s := authTestServer(t, "token1")
+
+	for _, authorization := range []string{"Bearer token2", "Bearer token1x", "Bearer ", "token1", "Basic token1"} {
+		writer := authTestRequest(s, "/ext/admin", authorization)
+		if writer.Code != http.StatusUnauthorized {
+			t.Fatalf("Wrong status code for %q. Expected %d ; Returned %d", authorization, http.StatusUnauthorized, writer.Code)
+		}
+		if challenge := writer.Header().Get("WWW-Authenticate"); challenge != "Bearer" {
+			t.Fatalf("Wrong challenge. Expected %q ; Returned %q", "Bearer", challenge)
+		}
+	}
+}
+
+func TestAuthMissingToken(t *testing.T) {
+	s := authTestServer(t, "token1")
+
+	writer := authTestRequest(s, "/ext/admin", "")
+	if writer.Code != http.StatusUnauthorized {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusUnauthorized, writer.Code)
+	}
+
+	// Routes that don't require authentication stay open
+	writer = authTestRequest(s, "/ext/info", "")
+	if writer.Code != http.StatusOK {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusOK, writer.Code)
+	}
+}
+
+func TestAuthDisabled(t *testing.T) {
+	s := authTestServer(t)
+
+	writer := authTestRequest(s, "/ext/admin", "")
+	if writer.Code != http.StatusOK {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusOK, writer.Code)
+	}
+}
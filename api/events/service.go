@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// Accepted is the notification pushed to subscribers when a chain accepts a
+// container
+type Accepted struct {
+	ChainID     ids.ID          `json:"chainID"`
+	ContainerID ids.ID          `json:"containerID"`
+	Container   formatting.CB58 `json:"container"`
+}
+
+// Service pushes the decisions accepted by chains to websocket subscribers.
+// Each chain has a channel named by its ID. The decisions of linear chains are
+// blocks and the decisions of DAG based chains are transactions.
+//
+// It should be registered with the decision dispatcher, to be notified of
+// accepted decisions, and with the chain manager, to be notified of new
+// chains.
+type Service struct {
+	log    logging.Logger
+	pubsub *json.PubSubServer
+}
+
+// NewService returns a new events service
+func NewService(log logging.Logger) *Service {
+	return &Service{
+		log:    log,
+		pubsub: json.NewPubSubServer(log),
+	}
+}
+
+// Handler returns the websocket handler that clients subscribe through
+func (s *Service) Handler() *common.HTTPHandler {
+	return &common.HTTPHandler{LockOptions: common.NoLock, Handler: s.pubsub}
+}
+
+// RegisterChain implements the chains.Registrant interface
+func (s *Service) RegisterChain(ctx *snow.Context, _ interface{}) {
+	if err := s.pubsub.Register(ctx.ChainID.String()); err != nil {
+		s.log.Error("couldn't register events channel for chain %s: %s", ctx.ChainID, err)
+	}
+}
+
+// Accept implements the triggers.Acceptor interface
+func (s *Service) Accept(chainID, containerID ids.ID, container []byte) error {
+	s.pubsub.Publish(chainID.String(), &Accepted{
+		ChainID:     chainID,
+		ContainerID: containerID,
+		Container:   formatting.CB58{Bytes: container},
+	})
+	return nil
+}
@@ -0,0 +1,83 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package events
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestServiceAcceptPushesNotification(t *testing.T) {
+	s := NewService(logging.NoLog{})
+
+	ctx := snow.DefaultContextTest()
+	ctx.ChainID = ids.GenerateTestID()
+	s.RegisterChain(ctx, nil)
+
+	server := httptest.NewServer(s.Handler().Handler)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteJSON(map[string]interface{}{"channel": ctx.ChainID.String()}); err != nil {
+		t.Fatal(err)
+	}
+
+	blkID := ids.GenerateTestID()
+	blkBytes := []byte{1, 2, 3}
+
+	// The subscription is handled asynchronously, so keep accepting the block
+	// until the notification is received
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = s.Accept(ctx.ChainID, blkID, blkBytes)
+			}
+		}
+	}()
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	msg := struct {
+		Channel string   `json:"channel"`
+		Value   Accepted `json:"value"`
+	}{}
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Channel != ctx.ChainID.String() {
+		t.Fatalf("Wrong channel. Expected %s ; Returned %s", ctx.ChainID, msg.Channel)
+	}
+	if !msg.Value.ChainID.Equals(ctx.ChainID) {
+		t.Fatalf("Wrong chain ID. Expected %s ; Returned %s", ctx.ChainID, msg.Value.ChainID)
+	}
+	if !msg.Value.ContainerID.Equals(blkID) {
+		t.Fatalf("Wrong container ID. Expected %s ; Returned %s", blkID, msg.Value.ContainerID)
+	}
+	if !bytes.Equal(msg.Value.Container.Bytes, blkBytes) {
+		t.Fatalf("Wrong container")
+	}
+}
@@ -0,0 +1,152 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// DefaultGzipThreshold is the minimum response size, in bytes, that will be
+// compressed when gzip is enabled
+const DefaultGzipThreshold = 1024
+
+var errHijackNotSupported = errors.New("response writer doesn't support hijacking")
+
+// gzipMiddleware wraps a handler so that responses of at least [threshold]
+// bytes are gzip compressed when the client sends "Accept-Encoding: gzip".
+// Smaller responses are written uncompressed. Requests to upgrade the
+// connection, such as websocket handshakes, are never compressed.
+func gzipMiddleware(handler http.Handler, threshold int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) || r.Header.Get("Upgrade") != "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{
+			ResponseWriter: w,
+			threshold:      threshold,
+			statusCode:     http.StatusOK,
+		}
+		defer gw.close()
+		handler.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip returns true if the request advertises gzip support
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		if strings.TrimSpace(strings.Split(encoding, ";")[0]) == "gzip" {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the response until either [threshold] bytes have
+// been written, in which case the response is compressed, or the handler
+// returns, in which case the response is written as is.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+
+	threshold  int
+	statusCode int
+	buf        []byte
+	gz         *gzip.Writer
+	done       bool
+}
+
+// WriteHeader delays writing the status code until it is known whether the
+// response will be compressed
+func (w *gzipResponseWriter) WriteHeader(statusCode int) { w.statusCode = statusCode }
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(b)
+	case w.done:
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.threshold {
+		return len(b), nil
+	}
+
+	// If the handler already encoded the response, don't encode it again
+	if w.Header().Get("Content-Encoding") != "" {
+		return len(b), w.flushRaw()
+	}
+
+	// The content type must be sniffed from the uncompressed response, as it
+	// would otherwise be sniffed from the compressed bytes
+	header := w.Header()
+	if _, ok := header["Content-Type"]; !ok {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.statusCode)
+
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf)
+	w.buf = nil
+	return len(b), err
+}
+
+// flushRaw writes out the buffered response without compression
+func (w *gzipResponseWriter) flushRaw() error {
+	w.done = true
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	_, err := w.ResponseWriter.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+// Flush writes out the response written so far. If it isn't yet known whether
+// the response will be compressed, it's written uncompressed.
+func (w *gzipResponseWriter) Flush() {
+	switch {
+	case w.gz != nil:
+		w.gz.Flush()
+	case !w.done:
+		w.flushRaw()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hands the connection over to the handler. Nothing buffered is
+// written to the connection.
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackNotSupported
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		w.done = true
+		w.buf = nil
+	}
+	return conn, rw, err
+}
+
+// close must be called once the wrapped handler has returned
+func (w *gzipResponseWriter) close() error {
+	switch {
+	case w.gz != nil:
+		return w.gz.Close()
+	case w.done:
+		return nil
+	default:
+		return w.flushRaw()
+	}
+}
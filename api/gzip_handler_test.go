@@ -0,0 +1,177 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func gzipTestHandler(body []byte) http.Handler {
+	return gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		// Write in two pieces to exercise buffering across writes
+		w.Write(body[:len(body)/2])
+		w.Write(body[len(body)/2:])
+	}), 64)
+}
+
+func TestGzipLargeResponse(t *testing.T) {
+	body := bytes.Repeat([]byte("avalanche"), 100)
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Accept-Encoding", "deflate, gzip;q=1.0")
+	writer := httptest.NewRecorder()
+	gzipTestHandler(body).ServeHTTP(writer, req)
+
+	if writer.Code != http.StatusAccepted {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusAccepted, writer.Code)
+	}
+	if encoding := writer.Header().Get("Content-Encoding"); encoding != "gzip" {
+		t.Fatalf("Wrong content encoding. Expected %q ; Returned %q", "gzip", encoding)
+	}
+	// The content type is sniffed from the uncompressed body
+	if contentType := writer.Header().Get("Content-Type"); contentType != "text/plain; charset=utf-8" {
+		t.Fatalf("Wrong content type. Expected %q ; Returned %q", "text/plain; charset=utf-8", contentType)
+	}
+
+	reader, err := gzip.NewReader(writer.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(body, decompressed) {
+		t.Fatalf("Decompressed body doesn't match the original body")
+	}
+}
+
+func TestGzipKeepsContentType(t *testing.T) {
+	handler := gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(bytes.Repeat([]byte("avalanche"), 100))
+	}), 64)
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+
+	if contentType := writer.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Fatalf("Wrong content type. Expected %q ; Returned %q", "application/json", contentType)
+	}
+}
+
+func TestGzipSmallResponse(t *testing.T) {
+	body := []byte("avalanche")
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	writer := httptest.NewRecorder()
+	gzipTestHandler(body).ServeHTTP(writer, req)
+
+	if writer.Code != http.StatusAccepted {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusAccepted, writer.Code)
+	}
+	if encoding := writer.Header().Get("Content-Encoding"); encoding != "" {
+		t.Fatalf("Small responses shouldn't be compressed but had encoding %q", encoding)
+	}
+	if !bytes.Equal(body, writer.Body.Bytes()) {
+		t.Fatalf("Body was modified")
+	}
+}
+
+func TestGzipNotAccepted(t *testing.T) {
+	body := bytes.Repeat([]byte("avalanche"), 100)
+
+	req := httptest.NewRequest("POST", "/", nil)
+	writer := httptest.NewRecorder()
+	gzipTestHandler(body).ServeHTTP(writer, req)
+
+	if encoding := writer.Header().Get("Content-Encoding"); encoding != "" {
+		t.Fatalf("Response shouldn't be compressed but had encoding %q", encoding)
+	}
+	if !bytes.Equal(body, writer.Body.Bytes()) {
+		t.Fatalf("Body was modified")
+	}
+}
+
+func TestGzipFlush(t *testing.T) {
+	body := []byte("avalanche")
+	handler := gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+		w.(http.Flusher).Flush()
+	}), 64)
+
+	req := httptest.NewRequest("POST", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	writer := httptest.NewRecorder()
+	handler.ServeHTTP(writer, req)
+
+	if !writer.Flushed {
+		t.Fatalf("Flush wasn't forwarded to the underlying writer")
+	}
+	if !bytes.Equal(body, writer.Body.Bytes()) {
+		t.Fatalf("Flushed body was modified")
+	}
+}
+
+func TestGzipUpgradeNotCompressed(t *testing.T) {
+	body := bytes.Repeat([]byte("avalanche"), 100)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	writer := httptest.NewRecorder()
+	gzipTestHandler(body).ServeHTTP(writer, req)
+
+	if encoding := writer.Header().Get("Content-Encoding"); encoding != "" {
+		t.Fatalf("Upgrade responses shouldn't be compressed but had encoding %q", encoding)
+	}
+}
+
+func TestGzipHijack(t *testing.T) {
+	response := "HTTP/1.1 200 OK\r\nContent-Length: 9\r\nConnection: close\r\n\r\navalanche"
+	server := httptest.NewServer(gzipMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Errorf("Writer doesn't support hijacking")
+			return
+		}
+		conn, _, err := hijacker.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte(response))
+	}), 1))
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != "avalanche" {
+		t.Fatalf("Wrong body. Expected %q ; Returned %q", "avalanche", body)
+	}
+}
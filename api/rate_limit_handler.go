@@ -0,0 +1,108 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// maxRateLimitedClients is the number of client IPs whose buckets are
+// remembered per route group. Once exceeded, the least recently seen client's
+// bucket is dropped, which refills it.
+const maxRateLimitedClients = 4096
+
+// RateLimit describes how quickly calls may be made to a group of routes
+type RateLimit struct {
+	// Rate is the number of calls per second allowed in the long term
+	Rate float64
+	// Burst is the number of calls that may be made at once
+	Burst int
+	// PerClient limits each client IP separately, rather than all clients
+	// sharing the limit
+	PerClient bool
+}
+
+// rateLimiter hands out the token bucket that a request must take a token from
+type rateLimiter struct {
+	limit RateLimit
+	clock *timer.Clock
+
+	lock sync.Mutex
+	// shared is the bucket used when clients aren't limited separately
+	shared *timer.TokenBucket
+	// clients maps the hash of a client's IP to its bucket
+	clients cache.LRU
+}
+
+func newRateLimiter(limit RateLimit, clock *timer.Clock) *rateLimiter {
+	l := &rateLimiter{
+		limit:   limit,
+		clock:   clock,
+		clients: cache.LRU{Size: maxRateLimitedClients},
+	}
+	if !limit.PerClient {
+		l.shared = l.newBucket()
+	}
+	return l
+}
+
+func (l *rateLimiter) newBucket() *timer.TokenBucket {
+	return timer.NewTokenBucket(l.clock, l.limit.Rate, l.limit.Burst)
+}
+
+// bucket returns the bucket that limits [r]
+func (l *rateLimiter) bucket(r *http.Request) *timer.TokenBucket {
+	if l.shared != nil {
+		return l.shared
+	}
+
+	// RemoteAddr doesn't always include a port
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	key := ids.NewID(hashing.ComputeHash256Array([]byte(host)))
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if bucket, ok := l.clients.Get(key); ok {
+		return bucket.(*timer.TokenBucket)
+	}
+	bucket := l.newBucket()
+	l.clients.Put(key, bucket)
+	return bucket
+}
+
+// retryAfter returns the number of whole seconds until a token will be
+// available in an empty bucket
+func (l *rateLimiter) retryAfter() int {
+	if l.limit.Rate <= 0 {
+		return 1
+	}
+	return int(math.Ceil(1 / l.limit.Rate))
+}
+
+// rateLimitMiddleware wraps a handler so that requests over [limiter]'s limit
+// are rejected with 429 Too Many Requests and a Retry-After hint
+func rateLimitMiddleware(handler http.Handler, limiter *rateLimiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.bucket(r).Take() {
+			w.Header().Set("Retry-After", strconv.Itoa(limiter.retryAfter()))
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("API call rejected because the rate limit was exceeded"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,146 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// rateLimitTestServer returns a server with "admin" and "info" routes, where
+// "admin" is limited to [limit] and "info" falls back to [defaultLimit], if
+// it's set
+func rateLimitTestServer(t *testing.T, limit RateLimit, defaultLimit *RateLimit) *Server {
+	s := &Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, "localhost", 8080)
+	s.clock.Set(time.Unix(0, 0))
+	s.SetRateLimit("admin", limit)
+	if defaultLimit != nil {
+		s.SetRateLimit("", *defaultLimit)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	for _, base := range []string{"admin", "info"} {
+		if err := s.AddRoute(&common.HTTPHandler{Handler: ok}, new(sync.RWMutex), base, "", logging.NoLog{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func rateLimitTestRequest(s *Server, path, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", path, nil)
+	req.RemoteAddr = remoteAddr
+	writer := httptest.NewRecorder()
+	s.handler().ServeHTTP(writer, req)
+	return writer
+}
+
+func TestRateLimitExceeded(t *testing.T) {
+	s := rateLimitTestServer(t, RateLimit{Rate: 0.5, Burst: 2}, nil)
+
+	for i := 0; i < 2; i++ {
+		writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.1:1000")
+		if writer.Code != http.StatusOK {
+			t.Fatalf("Wrong status code for call %d. Expected %d ; Returned %d", i, http.StatusOK, writer.Code)
+		}
+	}
+
+	writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.1:1000")
+	if writer.Code != http.StatusTooManyRequests {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusTooManyRequests, writer.Code)
+	}
+	if retryAfter := writer.Header().Get("Retry-After"); retryAfter != "2" {
+		t.Fatalf("Wrong retry hint. Expected %q ; Returned %q", "2", retryAfter)
+	}
+
+	// Routes without a limit stay open
+	for i := 0; i < 3; i++ {
+		writer := rateLimitTestRequest(s, "/ext/info", "127.0.0.1:1000")
+		if writer.Code != http.StatusOK {
+			t.Fatalf("Wrong status code for call %d. Expected %d ; Returned %d", i, http.StatusOK, writer.Code)
+		}
+	}
+}
+
+func TestRateLimitRefill(t *testing.T) {
+	s := rateLimitTestServer(t, RateLimit{Rate: 1, Burst: 1}, nil)
+
+	if writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.1:1000"); writer.Code != http.StatusOK {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusOK, writer.Code)
+	}
+	if writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.1:1000"); writer.Code != http.StatusTooManyRequests {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusTooManyRequests, writer.Code)
+	}
+
+	s.clock.Set(time.Unix(1, 0))
+	if writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.1:1000"); writer.Code != http.StatusOK {
+		t.Fatalf("Bucket should have refilled. Expected %d ; Returned %d", http.StatusOK, writer.Code)
+	}
+}
+
+func TestRateLimitPerClient(t *testing.T) {
+	s := rateLimitTestServer(t, RateLimit{Rate: 1, Burst: 1, PerClient: true}, nil)
+
+	if writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.1:1000"); writer.Code != http.StatusOK {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusOK, writer.Code)
+	}
+	// A different port is the same client
+	if writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.1:1001"); writer.Code != http.StatusTooManyRequests {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusTooManyRequests, writer.Code)
+	}
+	if writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.2:1000"); writer.Code != http.StatusOK {
+		t.Fatalf("Clients should be limited separately. Expected %d ; Returned %d", http.StatusOK, writer.Code)
+	}
+}
+
+func TestRateLimitDefault(t *testing.T) {
+	s := rateLimitTestServer(t, RateLimit{Rate: 1, Burst: 2}, &RateLimit{Rate: 1, Burst: 1})
+
+	// The route group's own limit overrides the default
+	for i := 0; i < 2; i++ {
+		writer := rateLimitTestRequest(s, "/ext/admin", "127.0.0.1:1000")
+		if writer.Code != http.StatusOK {
+			t.Fatalf("Wrong status code for call %d. Expected %d ; Returned %d", i, http.StatusOK, writer.Code)
+		}
+	}
+
+	if writer := rateLimitTestRequest(s, "/ext/info", "127.0.0.1:1000"); writer.Code != http.StatusOK {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusOK, writer.Code)
+	}
+	if writer := rateLimitTestRequest(s, "/ext/info", "127.0.0.1:1000"); writer.Code != http.StatusTooManyRequests {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusTooManyRequests, writer.Code)
+	}
+}
+
+func TestRateLimitAlias(t *testing.T) {
+	s := &Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, "localhost", 8080)
+	s.clock.Set(time.Unix(0, 0))
+	s.SetRateLimit("bc/X", RateLimit{Rate: 1, Burst: 1})
+
+	chainBase := "bc/" + ids.GenerateTestID().String()
+	if err := s.AddAliases(chainBase, "X", "bc/X"); err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	if err := s.AddRoute(&common.HTTPHandler{Handler: ok}, new(sync.RWMutex), chainBase, "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if writer := rateLimitTestRequest(s, "/ext/"+chainBase, "127.0.0.1:1000"); writer.Code != http.StatusOK {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusOK, writer.Code)
+	}
+	// The limit is shared by every URL of the route
+	if writer := rateLimitTestRequest(s, "/ext/X", "127.0.0.1:1000"); writer.Code != http.StatusTooManyRequests {
+		t.Fatalf("Wrong status code. Expected %d ; Returned %d", http.StatusTooManyRequests, writer.Code)
+	}
+}
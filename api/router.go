@@ -58,6 +58,14 @@ func (r *router) GetHandler(base, endpoint string) (http.Handler, error) {
 	return handler, nil
 }
 
+// Aliases returns the routes that [base] is aliased to
+func (r *router) Aliases(base string) []string {
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	return append([]string(nil), r.aliases[base]...)
+}
+
 func (r *router) AddRouter(base, endpoint string, handler http.Handler) error {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 
 	"github.com/gorilla/handlers"
@@ -19,6 +20,7 @@ import (
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
 )
 
 const baseURL = "/ext"
@@ -33,6 +35,20 @@ type Server struct {
 	factory       logging.Factory
 	router        *router
 	listenAddress string
+
+	// If positive, responses of at least this many bytes are gzip compressed
+	// for clients that accept it
+	gzipThreshold int
+
+	// Maps a route's base to the bearer tokens that may call it. Routes that
+	// aren't in the map don't require authentication.
+	authTokens map[string][]string
+
+	// Maps a route's base to the rate its routes may be called at. Routes that
+	// aren't in the map are limited by [defaultRateLimit], if it's set.
+	rateLimits       map[string]RateLimit
+	defaultRateLimit *RateLimit
+	clock            timer.Clock
 }
 
 // Initialize creates the API server at the provided host and port
@@ -43,9 +59,85 @@ func (s *Server) Initialize(log logging.Logger, factory logging.Factory, host st
 	s.router = newRouter()
 }
 
+// EnableGzip compresses responses of at least [threshold] bytes for clients
+// that send "Accept-Encoding: gzip". Must be called before Dispatch.
+func (s *Server) EnableGzip(threshold int) { s.gzipThreshold = threshold }
+
+// RequireAuth restricts the routes under [base], such as "admin" or
+// "keystore", to requests that carry one of [tokens] as a bearer token. Must be
+// called before the routes are added.
+func (s *Server) RequireAuth(base string, tokens ...string) {
+	if len(tokens) == 0 {
+		return
+	}
+	if s.authTokens == nil {
+		s.authTokens = make(map[string][]string)
+	}
+	s.authTokens[base] = append(s.authTokens[base], tokens...)
+}
+
+// SetRateLimit limits how often the routes under [base], such as "admin" or
+// "bc/X", may be called. [base] may be an alias of the route group, if the
+// alias is added before the routes are. If [base] is empty, [limit] applies to
+// every route that doesn't have its own limit. Each route has its own limit,
+// rather than sharing one with the rest of its group. Must be called before the
+// routes are added.
+func (s *Server) SetRateLimit(base string, limit RateLimit) {
+	if base == "" {
+		s.defaultRateLimit = &limit
+		return
+	}
+	if s.rateLimits == nil {
+		s.rateLimits = make(map[string]RateLimit)
+	}
+	s.rateLimits[base] = limit
+}
+
+// rateLimit wraps [handler] with rate limiting if the routes under [base], or
+// under one of its aliases, are limited
+func (s *Server) rateLimit(handler http.Handler, base string) http.Handler {
+	limit, ok := s.rateLimits[base]
+	if !ok {
+		for _, alias := range s.router.Aliases(fmt.Sprintf("%s/%s", baseURL, base)) {
+			if limit, ok = s.rateLimits[strings.TrimPrefix(alias, baseURL+"/")]; ok {
+				break
+			}
+		}
+	}
+	switch {
+	case ok:
+	case s.defaultRateLimit != nil:
+		limit = *s.defaultRateLimit
+	default:
+		return handler
+	}
+	s.log.Info("limiting calls to %s/%s to %.2f per second, with bursts of %d", baseURL, base, limit.Rate, limit.Burst)
+	return rateLimitMiddleware(handler, newRateLimiter(limit, &s.clock))
+}
+
+// authenticate wraps [handler] with authentication if the routes under [base]
+// require it
+func (s *Server) authenticate(handler http.Handler, base string) http.Handler {
+	tokens, ok := s.authTokens[base]
+	if !ok {
+		return handler
+	}
+	s.log.Info("requiring authentication for %s/%s", baseURL, base)
+	return authMiddleware(handler, tokens)
+}
+
+// handler returns the root handler of the API server
+func (s *Server) handler() http.Handler {
+	handler := cors.Default().Handler(s.router)
+	if s.gzipThreshold > 0 {
+		handler = gzipMiddleware(handler, s.gzipThreshold)
+	}
+	return handler
+}
+
 // Dispatch starts the API server
 func (s *Server) Dispatch() error {
-	handler := cors.Default().Handler(s.router)
+	handler := s.handler()
 	listener, err := net.Listen("tcp", s.listenAddress)
 	if err != nil {
 		return err
@@ -56,7 +148,7 @@ func (s *Server) Dispatch() error {
 
 // DispatchTLS starts the API server with the provided TLS certificate
 func (s *Server) DispatchTLS(certFile, keyFile string) error {
-	handler := cors.Default().Handler(s.router)
+	handler := s.handler()
 	listener, err := net.Listen("tcp", s.listenAddress)
 	if err != nil {
 		return err
@@ -111,6 +203,10 @@ func (s *Server) AddChainRoute(handler *common.HTTPHandler, ctx *snow.Context, b
 	}
 	// Apply middleware to reject calls to the handler before the chain finishes bootstrapping
 	h = rejectMiddleware(h, ctx)
+	// Apply middleware to reject unauthenticated calls
+	h = s.authenticate(h, base)
+	// Apply middleware to reject calls over the rate limit
+	h = s.rateLimit(h, base)
 	return s.router.AddRouter(url, endpoint, h)
 }
 
@@ -125,6 +221,10 @@ func (s *Server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base,
 	if err != nil {
 		return err
 	}
+	// Apply middleware to reject unauthenticated calls
+	h = s.authenticate(h, base)
+	// Apply middleware to reject calls over the rate limit
+	h = s.rateLimit(h, base)
 	return s.router.AddRouter(url, endpoint, h)
 }
 
@@ -29,6 +29,7 @@ import (
 	"github.com/ava-labs/gecko/snow/triggers"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils/constants"
+	"github.com/ava-labs/gecko/utils/forks"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/vms"
 
@@ -86,6 +87,9 @@ type Manager interface {
 	// Returns true iff the chain with the given ID exists and is finished bootstrapping
 	IsBootstrapped(ids.ID) bool
 
+	// Returns the consensus engine running the chain, if the chain exists
+	Engine(ids.ID) (common.Engine, bool)
+
 	Shutdown()
 }
 
@@ -134,7 +138,19 @@ type manager struct {
 	atomicMemory                       *atomic.Memory
 	avaxAssetID                        ids.ID
 	xChainID                           ids.ID
-	criticalChains                     ids.Set // Chains that can't exit gracefully
+	criticalChains                     ids.Set         // Chains that can't exit gracefully
+	forkSchedule                       *forks.Schedule // Times that network upgrades activate
+
+	// Options for the snowman engines of new chains. See smeng.Config.
+	maxAcceptedPerInterval int
+	acceptInterval         time.Duration
+	maxIdleInterval        time.Duration
+	unsafeAllowForceAccept bool
+
+	// Options for the avalanche engines of new chains. See aveng.Config.
+	maxVerticesPerInterval int
+	vertexInterval         time.Duration
+	maxProcessingVertices  int
 
 	unblocked     bool
 	blockedChains []ChainParameters
@@ -172,6 +188,14 @@ func New(
 	avaxAssetID ids.ID,
 	xChainID ids.ID,
 	criticalChains ids.Set,
+	forkSchedule *forks.Schedule,
+	maxAcceptedPerInterval int,
+	acceptInterval time.Duration,
+	maxIdleInterval time.Duration,
+	unsafeAllowForceAccept bool,
+	maxVerticesPerInterval int,
+	vertexInterval time.Duration,
+	maxProcessingVertices int,
 ) (Manager, error) {
 	timeoutManager := timeout.Manager{}
 	err := timeoutManager.Initialize(
@@ -208,7 +232,16 @@ func New(
 		avaxAssetID:      avaxAssetID,
 		xChainID:         xChainID,
 		criticalChains:   criticalChains,
+		forkSchedule:     forkSchedule,
 		chains:           make(map[[32]byte]*router.Handler),
+
+		maxAcceptedPerInterval: maxAcceptedPerInterval,
+		acceptInterval:         acceptInterval,
+		maxIdleInterval:        maxIdleInterval,
+		unsafeAllowForceAccept: unsafeAllowForceAccept,
+		maxVerticesPerInterval: maxVerticesPerInterval,
+		vertexInterval:         vertexInterval,
+		maxProcessingVertices:  maxProcessingVertices,
 	}
 	m.Initialize()
 	return m, nil
@@ -285,6 +318,7 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 		NodeID:      m.nodeID,
 		XChainID:    m.xChainID,
 		AVAXAssetID: m.avaxAssetID,
+		Forks:       m.forkSchedule,
 
 		Log:                 chainLog,
 		DecisionDispatcher:  m.decisionEvents,
@@ -409,7 +443,7 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 			return nil, fmt.Errorf("failed to start consensus engine: %w", err)
 		}
 	} else {
-		awaiter := NewAwaiter(beacons, reqWeight, func() {
+		go m.net.AwaitBeacons(beacons, reqWeight, func() {
 			ctx.Lock.Lock()
 			defer ctx.Lock.Unlock()
 			if err := chain.Engine.Startup(); err != nil {
@@ -417,7 +451,6 @@ func (m *manager) buildChain(chainParams ChainParameters) (*chain, error) {
 				chain.Handler.Shutdown()
 			}
 		})
-		go m.net.RegisterHandler(awaiter)
 	}
 
 	return chain, nil
@@ -497,8 +530,11 @@ func (m *manager) createAvalancheChain(
 			Manager:    vtxManager,
 			VM:         vm,
 		},
-		Params:    consensusParams,
-		Consensus: &avcon.Topological{},
+		Params:                 consensusParams,
+		Consensus:              &avcon.Topological{},
+		MaxVerticesPerInterval: m.maxVerticesPerInterval,
+		VertexInterval:         m.vertexInterval,
+		MaxProcessingVertices:  m.maxProcessingVertices,
 	}); err != nil {
 		return nil, fmt.Errorf("error initializing avalanche engine: %w", err)
 	}
@@ -575,8 +611,13 @@ func (m *manager) createSnowmanChain(
 			VM:           vm,
 			Bootstrapped: m.unblockChains,
 		},
-		Params:    consensusParams,
-		Consensus: &smcon.Topological{},
+		Params:                 consensusParams,
+		Consensus:              &smcon.Topological{},
+		MaxAcceptedPerInterval: m.maxAcceptedPerInterval,
+		AcceptInterval:         m.acceptInterval,
+		Reputation:             m.net,
+		MaxIdleInterval:        m.maxIdleInterval,
+		UnsafeAllowForceAccept: m.unsafeAllowForceAccept,
 	}); err != nil {
 		return nil, fmt.Errorf("error initializing snowman engine: %w", err)
 	}
@@ -624,6 +665,17 @@ func (m *manager) IsBootstrapped(id ids.ID) bool {
 	return chain.Engine().IsBootstrapped()
 }
 
+func (m *manager) Engine(id ids.ID) (common.Engine, bool) {
+	m.chainsLock.Lock()
+	chain, exists := m.chains[id.Key()]
+	m.chainsLock.Unlock()
+	if !exists {
+		return nil, false
+	}
+
+	return chain.Engine(), true
+}
+
 // Shutdown stops all the chains
 func (m *manager) Shutdown() {
 	m.chainRouter.Shutdown()
@@ -2,6 +2,7 @@ package chains
 
 import (
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/networking/router"
 )
 
@@ -53,3 +54,6 @@ func (mm MockManager) SubnetID(ids.ID) (ids.ID, error) { return ids.ID{}, nil }
 
 // IsBootstrapped ...
 func (mm MockManager) IsBootstrapped(ids.ID) bool { return false }
+
+// Engine ...
+func (mm MockManager) Engine(ids.ID) (common.Engine, bool) { return nil, false }
@@ -0,0 +1,213 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crossdb
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	pendingKey = []byte("pending")
+
+	errWrongNumDatabases = errors.New("pending commit was written for a different number of databases")
+)
+
+// Coordinator atomically commits writes that span multiple databases.
+//
+// Before any of the databases are modified, every write is recorded under a
+// single key in the log database. Only once every database has been written
+// to is that record removed. If the node crashes part way through a commit,
+// calling Recover on restart re-applies the recorded writes so that either
+// all of the databases reflect the commit, or none of them do.
+type Coordinator struct {
+	log database.Database
+	dbs []database.Database
+}
+
+// New returns a coordinator for writing to [dbs]. [log] must not be one of
+// [dbs], and the same databases must be provided, in the same order, every
+// time a coordinator is created over [log].
+func New(log database.Database, dbs ...database.Database) *Coordinator {
+	return &Coordinator{
+		log: log,
+		dbs: dbs,
+	}
+}
+
+// NewBatch returns a batch that will atomically write to all of this
+// coordinator's databases
+func (c *Coordinator) NewBatch() *Batch {
+	return &Batch{
+		c:   c,
+		ops: make([]ops, len(c.dbs)),
+	}
+}
+
+// Recover finishes any commit that was interrupted part way through. It should
+// be called before the databases are read from after a restart.
+func (c *Coordinator) Recover() error {
+	record, err := c.log.Get(pendingKey)
+	if err == database.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	dbOps, err := unmarshalOps(record)
+	if err != nil {
+		return fmt.Errorf("couldn't parse pending commit: %w", err)
+	}
+	if len(dbOps) != len(c.dbs) {
+		return errWrongNumDatabases
+	}
+	return c.apply(dbOps)
+}
+
+// apply writes [dbOps] to the databases and then clears the pending record.
+// Every write is idempotent, so this is safe to retry.
+func (c *Coordinator) apply(dbOps []ops) error {
+	for i, db := range c.dbs {
+		batch := db.NewBatch()
+		if err := dbOps[i].Replay(batch); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return c.log.Delete(pendingKey)
+}
+
+// Batch is a set of writes to several databases that will be committed
+// atomically. A batch cannot be used concurrently.
+type Batch struct {
+	c   *Coordinator
+	ops []ops
+}
+
+// Database returns a writer for the [i]th database of the coordinator. Writes
+// are buffered until Write is called.
+func (b *Batch) Database(i int) database.KeyValueWriter { return &b.ops[i] }
+
+// Write commits the batch to all of the databases. If an error is returned,
+// the databases may be partially written to until Recover succeeds.
+func (b *Batch) Write() error {
+	has, err := b.c.log.Has(pendingKey)
+	if err != nil {
+		return err
+	}
+	if has {
+		// Writing over an interrupted commit would lose it
+		if err := b.c.Recover(); err != nil {
+			return err
+		}
+	}
+
+	record, err := marshalOps(b.ops)
+	if err != nil {
+		return err
+	}
+	// Once this put succeeds, the commit is guaranteed to eventually be fully
+	// applied
+	if err := b.c.log.Put(pendingKey, record); err != nil {
+		return err
+	}
+	return b.c.apply(b.ops)
+}
+
+// Reset clears the batch for reuse
+func (b *Batch) Reset() {
+	for i := range b.ops {
+		b.ops[i] = nil
+	}
+}
+
+type op struct {
+	delete     bool
+	key, value []byte
+}
+
+// ops records writes so that they can be replayed later
+type ops []op
+
+func (o *ops) Put(key, value []byte) error {
+	*o = append(*o, op{
+		key:   copyBytes(key),
+		value: copyBytes(value),
+	})
+	return nil
+}
+
+func (o *ops) Delete(key []byte) error {
+	*o = append(*o, op{
+		delete: true,
+		key:    copyBytes(key),
+	})
+	return nil
+}
+
+func (o ops) Replay(w database.KeyValueWriter) error {
+	for _, op := range o {
+		if op.delete {
+			if err := w.Delete(op.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(op.key, op.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func marshalOps(dbOps []ops) ([]byte, error) {
+	p := wrappers.Packer{MaxSize: math.MaxInt32}
+	p.PackInt(uint32(len(dbOps)))
+	for _, o := range dbOps {
+		p.PackInt(uint32(len(o)))
+		for _, op := range o {
+			p.PackBool(op.delete)
+			p.PackBytes(op.key)
+			if !op.delete {
+				p.PackBytes(op.value)
+			}
+		}
+	}
+	return p.Bytes, p.Err
+}
+
+func unmarshalOps(b []byte) ([]ops, error) {
+	p := wrappers.Packer{Bytes: b}
+	numDBs := p.UnpackInt()
+	// Every database takes at least [wrappers.IntLen] bytes
+	if int(numDBs) > len(b)/wrappers.IntLen {
+		return nil, errWrongNumDatabases
+	}
+	dbOps := make([]ops, numDBs)
+	for i := range dbOps {
+		numOps := p.UnpackInt()
+		for j := uint32(0); j < numOps && !p.Errored(); j++ {
+			op := op{
+				delete: p.UnpackBool(),
+				key:    p.UnpackBytes(),
+			}
+			if !op.delete {
+				op.value = p.UnpackBytes()
+			}
+			dbOps[i] = append(dbOps[i], op)
+		}
+	}
+	return dbOps, p.Err
+}
+
+func copyBytes(b []byte) []byte {
+	cb := make([]byte, len(b))
+	copy(cb, b)
+	return cb
+}
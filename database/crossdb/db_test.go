@@ -0,0 +1,131 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crossdb
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+var errCrashed = errors.New("crashed")
+
+// crashingDB fails every batch write, simulating the node crashing before the
+// batch reached disk
+type crashingDB struct{ *memdb.Database }
+
+func (db crashingDB) NewBatch() database.Batch { return crashingBatch{db.Database.NewBatch()} }
+
+type crashingBatch struct{ database.Batch }
+
+func (crashingBatch) Write() error { return errCrashed }
+
+func assertValue(t *testing.T, db database.KeyValueReader, key, expected []byte) {
+	value, err := db.Get(key)
+	switch {
+	case expected == nil && err != database.ErrNotFound:
+		t.Fatalf("Key %s should have been missing but got %v, %v", key, value, err)
+	case expected == nil:
+	case err != nil:
+		t.Fatal(err)
+	case !bytes.Equal(value, expected):
+		t.Fatalf("Key %s should have value %s but has %s", key, expected, value)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	log, db0, db1 := memdb.New(), memdb.New(), memdb.New()
+	if err := db1.Put([]byte("removed"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(log, db0, db1)
+	batch := c.NewBatch()
+	if err := batch.Database(0).Put([]byte("key0"), []byte("value0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Database(1).Put([]byte("key1"), []byte("value1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Database(1).Delete([]byte("removed")); err != nil {
+		t.Fatal(err)
+	}
+
+	assertValue(t, db0, []byte("key0"), nil)
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertValue(t, db0, []byte("key0"), []byte("value0"))
+	assertValue(t, db1, []byte("key1"), []byte("value1"))
+	assertValue(t, db1, []byte("removed"), nil)
+	assertValue(t, log, pendingKey, nil)
+}
+
+func TestRecoverAfterCrash(t *testing.T) {
+	log, db0, db1 := memdb.New(), memdb.New(), memdb.New()
+
+	// The node crashes after writing to the first database but before writing
+	// to the second
+	c := New(log, db0, crashingDB{db1})
+	batch := c.NewBatch()
+	if err := batch.Database(0).Put([]byte("key0"), []byte("value0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Database(1).Put([]byte("key1"), []byte("value1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != errCrashed {
+		t.Fatalf("Expected the write to crash but got %v", err)
+	}
+
+	assertValue(t, db0, []byte("key0"), []byte("value0"))
+	assertValue(t, db1, []byte("key1"), nil)
+
+	// After restarting, recovery should roll the commit forward
+	c = New(log, db0, db1)
+	if err := c.Recover(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertValue(t, db0, []byte("key0"), []byte("value0"))
+	assertValue(t, db1, []byte("key1"), []byte("value1"))
+	assertValue(t, log, pendingKey, nil)
+
+	// Recovering again should be a no-op
+	if err := c.Recover(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecoverBeforeMarker(t *testing.T) {
+	db0, db1 := memdb.New(), memdb.New()
+
+	// The node crashes before the commit was recorded, so none of the
+	// databases should be modified
+	log := crashingLog{memdb.New()}
+	c := New(log, db0, db1)
+	batch := c.NewBatch()
+	if err := batch.Database(0).Put([]byte("key0"), []byte("value0")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != errCrashed {
+		t.Fatalf("Expected the write to crash but got %v", err)
+	}
+
+	c = New(log.Database, db0, db1)
+	if err := c.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	assertValue(t, db0, []byte("key0"), nil)
+}
+
+// crashingLog fails every put, simulating the node crashing before the commit
+// was recorded
+type crashingLog struct{ *memdb.Database }
+
+func (crashingLog) Put([]byte, []byte) error { return errCrashed }
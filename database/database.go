@@ -49,6 +49,21 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// Sizer wraps the Count and SizeEstimate methods of a backing data store.
+type Sizer interface {
+	// Count returns the number of keys in the DB that start with [prefix].
+	// A nil prefix counts every key in the DB.
+	Count(prefix []byte) (int, error)
+
+	// SizeEstimate returns the approximate number of bytes used to store the
+	// keys in the range [start, limit). The estimate may not include recent
+	// writes that haven't been persisted yet.
+	//
+	// A nil start is treated as a key before all keys in the DB.
+	// And a nil limit is treated as a key after all keys in the DB.
+	SizeEstimate(start []byte, limit []byte) (uint64, error)
+}
+
 // Database contains all the methods required to allow handling different
 // key-value data stores backing the database.
 type Database interface {
@@ -58,5 +73,7 @@ type Database interface {
 	Iteratee
 	Stater
 	Compacter
+	Sizer
+	Snapshotter
 	io.Closer
 }
@@ -4,20 +4,44 @@
 package encdb
 
 import (
+	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"errors"
 	"sync"
 
 	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/nodb"
+	"github.com/ava-labs/gecko/database/prefixdb"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/codec"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/logging"
 )
 
-// Database encrypts all values that are provided
+const (
+	saltLen = 32
+
+	// scrypt parameters used to derive the AES-GCM key from a password
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+var (
+	saltPrefix   = []byte("salt")
+	valuesPrefix = []byte("values")
+	saltKey      = []byte("salt")
+
+	errInvalidSalt  = errors.New("stored salt has the wrong length")
+	errInvalidNonce = errors.New("encrypted value has the wrong nonce length")
+)
+
+// Database encrypts all values that are provided. Keys are stored in
+// plaintext, so that iteration stays ordered.
 type Database struct {
 	lock   sync.RWMutex
 	codec  codec.Codec
@@ -25,18 +49,69 @@ type Database struct {
 	db     database.Database
 }
 
-// New returns a new encrypted database
+// New returns a new encrypted database. Values are encrypted with
+// XChaCha20-Poly1305, using the hash of [password] as the key.
 func New(password []byte, db database.Database) (*Database, error) {
 	h := hashing.ComputeHash256(password)
 	aead, err := chacha20poly1305.NewX(h)
 	if err != nil {
 		return nil, err
 	}
+	return newDatabase(aead, db), nil
+}
+
+// NewAESGCM returns a new encrypted database. Values are encrypted with
+// AES-256-GCM, using a key derived from [password] with scrypt. The salt is
+// generated when [db] is first used and is stored in [db], separately from the
+// values. As nonces are random, no more than 2^32 values should be written with
+// the same password.
+func NewAESGCM(password []byte, db database.Database) (*Database, error) {
+	salt, err := getSalt(prefixdb.New(saltPrefix, db))
+	if err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(password, salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return newDatabase(aead, prefixdb.New(valuesPrefix, db)), nil
+}
+
+// getSalt returns the salt stored in [db], generating and storing one if there
+// isn't one yet
+func getSalt(db database.Database) ([]byte, error) {
+	salt, err := db.Get(saltKey)
+	switch err {
+	case nil:
+		if len(salt) != saltLen {
+			return nil, errInvalidSalt
+		}
+		return salt, nil
+	case database.ErrNotFound:
+		salt = make([]byte, saltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		return salt, db.Put(saltKey, salt)
+	default:
+		return nil, err
+	}
+}
+
+func newDatabase(aead cipher.AEAD, db database.Database) *Database {
 	return &Database{
 		codec:  codec.NewDefault(),
 		cipher: aead,
 		db:     db,
-	}, nil
+	}
 }
 
 // Has implements the Database interface
@@ -127,6 +202,38 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	}
 }
 
+// NewTolerantIterator returns an iterator over the keys with [prefix], starting
+// at [start]. Unlike the other iterators, a value that can't be decrypted, for
+// example because it was corrupted on disk, doesn't stop the iteration. The
+// key is logged to [log] and skipped instead, so that the rest of the database
+// can still be salvaged. If the underlying database supports tolerant
+// iteration as well, corruption it detects is skipped too.
+func (db *Database) NewTolerantIterator(start, prefix []byte, log logging.Logger) database.Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	var it database.Iterator
+	if tolerantDB, ok := db.db.(tolerantIteratee); ok {
+		it = tolerantDB.NewTolerantIterator(start, prefix)
+	} else {
+		it = db.db.NewIteratorWithStartAndPrefix(start, prefix)
+	}
+	return &iterator{
+		Iterator: it,
+		db:       db,
+		log:      log,
+	}
+}
+
+// tolerantIteratee is implemented by databases that can iterate past corrupted
+// entries, such as leveldb.
+type tolerantIteratee interface {
+	NewTolerantIterator(start, prefix []byte) database.Iterator
+}
+
 // Stat implements the Database interface
 func (db *Database) Stat(stat string) (string, error) {
 	db.lock.RLock()
@@ -149,6 +256,46 @@ func (db *Database) Compact(start, limit []byte) error {
 	return db.db.Compact(start, limit)
 }
 
+// Count implements the Database interface
+func (db *Database) Count(prefix []byte) (int, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+	return db.db.Count(prefix)
+}
+
+// SizeEstimate implements the Database interface
+func (db *Database) SizeEstimate(start, limit []byte) (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+	return db.db.SizeEstimate(start, limit)
+}
+
+// NewSnapshot implements the Database interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	snap, err := db.db.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		Snapshot: snap,
+		db:       db,
+	}, nil
+}
+
 // Close implements the Database interface
 func (db *Database) Close() error {
 	db.lock.Lock()
@@ -223,28 +370,37 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 	return nil
 }
 
+// iterator decrypts each value as it advances, so that a value that can't be
+// decrypted is reported by Error before it can be read. If [log] is non-nil,
+// the iterator is tolerant: keys whose values can't be decrypted are logged and
+// skipped instead.
 type iterator struct {
 	database.Iterator
-	db *Database
+	db  *Database
+	log logging.Logger
 
 	val []byte
 	err error
 }
 
 func (it *iterator) Next() bool {
-	next := it.Iterator.Next()
-	if next {
-		encVal := it.Iterator.Value()
-		val, err := it.db.decrypt(encVal)
-		if err != nil {
+	if it.err != nil {
+		return false
+	}
+	it.val = nil
+	for it.Iterator.Next() {
+		val, err := it.db.decrypt(it.Iterator.Value())
+		if err == nil {
+			it.val = val
+			return true
+		}
+		if it.log == nil {
 			it.err = err
 			return false
 		}
-		it.val = val
-	} else {
-		it.val = nil
+		it.log.Warn("skipping key 0x%x as its value couldn't be decrypted: %s", it.Iterator.Key(), err)
 	}
-	return next
+	return false
 }
 
 func (it *iterator) Error() error {
@@ -256,13 +412,49 @@ func (it *iterator) Error() error {
 
 func (it *iterator) Value() []byte { return it.val }
 
+// snapshot decrypts the values read from a snapshot of the underlying database
+type snapshot struct {
+	database.Snapshot
+	db *Database
+}
+
+// Get implements the Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	encVal, err := s.Snapshot.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.db.decrypt(encVal)
+}
+
+// NewIterator implements the Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator { return s.NewIteratorWithStartAndPrefix(nil, nil) }
+
+// NewIteratorWithStart implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &iterator{
+		Iterator: s.Snapshot.NewIteratorWithStartAndPrefix(start, prefix),
+		db:       s.db,
+	}
+}
+
 type encryptedValue struct {
 	Ciphertext []byte `serialize:"true"`
 	Nonce      []byte `serialize:"true"`
 }
 
 func (db *Database) encrypt(plaintext []byte) ([]byte, error) {
-	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	nonce := make([]byte, db.cipher.NonceSize())
 	if _, err := rand.Read(nonce); err != nil {
 		return nil, err
 	}
@@ -278,5 +470,9 @@ func (db *Database) decrypt(ciphertext []byte) ([]byte, error) {
 	if err := db.codec.Unmarshal(ciphertext, &val); err != nil {
 		return nil, err
 	}
+	// Open panics if the nonce has the wrong length
+	if len(val.Nonce) != db.cipher.NonceSize() {
+		return nil, errInvalidNonce
+	}
 	return db.cipher.Open(nil, val.Nonce, val.Ciphertext, nil)
 }
@@ -4,21 +4,214 @@
 package encdb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/utils/logging"
 )
 
+var constructors = map[string]func(password []byte, db database.Database) (*Database, error){
+	"xchacha20poly1305": New,
+	"aes-gcm":           NewAESGCM,
+}
+
 func TestInterface(t *testing.T) {
 	pw := "lol totally a secure password"
-	for _, test := range database.Tests {
+	for name, newDB := range constructors {
+		for _, test := range database.Tests {
+			unencryptedDB := memdb.New()
+			db, err := newDB([]byte(pw), unencryptedDB)
+			if err != nil {
+				t.Fatalf("%s: %s", name, err)
+			}
+
+			test(t, db)
+		}
+	}
+}
+
+func TestEncryptedAtRest(t *testing.T) {
+	key := []byte("key")
+	value := []byte("a value that shouldn't be readable at rest")
+	for name, newDB := range constructors {
+		unencryptedDB := memdb.New()
+		db, err := newDB([]byte("password"), unencryptedDB)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err := db.Put(key, value); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		stored, err := db.db.Get(key)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if bytes.Contains(stored, value) {
+			t.Fatalf("%s: value was stored in plaintext", name)
+		}
+
+		if v, err := db.Get(key); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		} else if !bytes.Equal(v, value) {
+			t.Fatalf("%s: Get returned %q but should have returned %q", name, v, value)
+		}
+
+		// Opening the database with the wrong password shouldn't reveal the
+		// value
+		wrongDB, err := newDB([]byte("wrong password"), unencryptedDB)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if _, err := wrongDB.Get(key); err == nil {
+			t.Fatalf("%s: Get with the wrong password should have failed", name)
+		}
+		it := wrongDB.NewIterator()
+		if it.Next() {
+			t.Fatalf("%s: iterator with the wrong password returned %q", name, it.Value())
+		}
+		if it.Error() == nil {
+			t.Fatalf("%s: iterator with the wrong password should have failed", name)
+		}
+		it.Release()
+	}
+}
+
+func TestInvalidNonce(t *testing.T) {
+	for name, newDB := range constructors {
+		db, err := newDB([]byte("password"), memdb.New())
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		encVal, err := db.codec.Marshal(&encryptedValue{
+			Ciphertext: []byte("ciphertext"),
+			Nonce:      []byte{1, 2, 3},
+		})
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err := db.db.Put([]byte("key"), encVal); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		if _, err := db.Get([]byte("key")); err != errInvalidNonce {
+			t.Fatalf("%s: expected %s but got %v", name, errInvalidNonce, err)
+		}
+		it := db.NewIterator()
+		if it.Next() {
+			t.Fatalf("%s: iterator shouldn't have returned the invalid value", name)
+		}
+		if err := it.Error(); err != errInvalidNonce {
+			t.Fatalf("%s: expected %s but got %v", name, errInvalidNonce, err)
+		}
+		it.Release()
+	}
+}
+
+func TestAESGCMStoresSalt(t *testing.T) {
+	unencryptedDB := memdb.New()
+	db, err := NewAESGCM([]byte("password"), unencryptedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reopening the database should derive the same key from the stored salt
+	db, err = NewAESGCM([]byte("password"), unencryptedDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := db.Get([]byte("key")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("value")) {
+		t.Fatalf("Get returned %q but should have returned %q", value, "value")
+	}
+
+	// The same password with a different salt should derive a different key
+	otherDB, err := NewAESGCM([]byte("password"), memdb.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(db.cipher.Seal(nil, make([]byte, 12), nil, nil), otherDB.cipher.Seal(nil, make([]byte, 12), nil, nil)) {
+		t.Fatalf("Databases with different salts shouldn't share a key")
+	}
+
+	if err := prefixdb.New(saltPrefix, unencryptedDB).Put(saltKey, []byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewAESGCM([]byte("password"), unencryptedDB); err != errInvalidSalt {
+		t.Fatalf("Expected %s but got %v", errInvalidSalt, err)
+	}
+}
+
+func TestTolerantIteratorSkipsCorruptValues(t *testing.T) {
+	for name, newDB := range constructors {
 		unencryptedDB := memdb.New()
-		db, err := New([]byte(pw), unencryptedDB)
+		db, err := newDB([]byte("password"), unencryptedDB)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err := db.Put([]byte("key0"), []byte("value0")); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		if err := db.Put([]byte("key2"), []byte("value2")); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		// Corrupt an entry between the two valid ones
+		if err := db.db.Put([]byte("key1"), []byte("garbage")); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		// Store an entry after them whose nonce is too short to be passed to
+		// the cipher
+		shortNonce, err := db.codec.Marshal(&encryptedValue{
+			Ciphertext: []byte("ciphertext"),
+			Nonce:      []byte{1, 2, 3},
+		})
 		if err != nil {
-			t.Fatal(err)
+			t.Fatalf("%s: %s", name, err)
 		}
+		if err := db.db.Put([]byte("key3"), shortNonce); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		// The default iterator stops at the corrupt entry
+		it := db.NewIterator()
+		numValues := 0
+		for it.Next() {
+			numValues++
+		}
+		if it.Error() == nil {
+			t.Fatalf("%s: iterator should have failed on the corrupt value", name)
+		}
+		if numValues != 1 {
+			t.Fatalf("%s: iterator should have read 1 value before failing but read %d", name, numValues)
+		}
+		it.Release()
 
-		test(t, db)
+		it = db.NewTolerantIterator(nil, nil, logging.NoLog{})
+		expected := [][2]string{{"key0", "value0"}, {"key2", "value2"}}
+		for _, kv := range expected {
+			if !it.Next() {
+				t.Fatalf("%s: iterator stopped before %s", name, kv[0])
+			}
+			if key := it.Key(); !bytes.Equal(key, []byte(kv[0])) {
+				t.Fatalf("%s: iterator returned key %q but should have returned %q", name, key, kv[0])
+			}
+			if value := it.Value(); !bytes.Equal(value, []byte(kv[1])) {
+				t.Fatalf("%s: iterator returned value %q but should have returned %q", name, value, kv[1])
+			}
+		}
+		if it.Next() {
+			t.Fatalf("%s: iterator should have been exhausted", name)
+		}
+		if err := it.Error(); err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		it.Release()
 	}
 }
@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+// FlushingBatch wraps a batch so that its contents are written, and the batch
+// reset, whenever the keys and values queued since the last write total at
+// least [limit] bytes. This allows an arbitrary number of writes to be streamed
+// into a database with bounded memory.
+//
+// Only each flush is atomic. If a write fails, or the node crashes, part way
+// through, the writes that were flushed before the failure remain in the
+// database. Callers that need all of their writes to be applied atomically
+// shouldn't use a FlushingBatch.
+type FlushingBatch struct {
+	Batch
+
+	limit int
+	// number of key and value bytes queued in [Batch] since it was last written
+	size int
+}
+
+// NewFlushingBatch returns a batch that flushes [batch] once at least [limit]
+// bytes have been queued in it. If [limit] isn't positive, every write is
+// flushed immediately.
+func NewFlushingBatch(batch Batch, limit int) *FlushingBatch {
+	return &FlushingBatch{
+		Batch: batch,
+		limit: limit,
+	}
+}
+
+// Put implements the Batch interface
+func (b *FlushingBatch) Put(key, value []byte) error {
+	if err := b.Batch.Put(key, value); err != nil {
+		return err
+	}
+	b.size += len(key) + len(value)
+	return b.flushIfFull()
+}
+
+// Delete implements the Batch interface
+func (b *FlushingBatch) Delete(key []byte) error {
+	if err := b.Batch.Delete(key); err != nil {
+		return err
+	}
+	b.size += len(key)
+	return b.flushIfFull()
+}
+
+// Write implements the Batch interface. It flushes any writes that haven't
+// been flushed yet.
+func (b *FlushingBatch) Write() error {
+	if err := b.Batch.Write(); err != nil {
+		return err
+	}
+	b.Reset()
+	return nil
+}
+
+// Reset implements the Batch interface. Writes that have already been flushed
+// aren't undone.
+func (b *FlushingBatch) Reset() {
+	b.Batch.Reset()
+	b.size = 0
+}
+
+// Replay implements the Batch interface. Only the writes that haven't been
+// flushed yet are replayed.
+func (b *FlushingBatch) Replay(w KeyValueWriter) error { return b.Batch.Replay(w) }
+
+func (b *FlushingBatch) flushIfFull() error {
+	if b.size < b.limit {
+		return nil
+	}
+	return b.Write()
+}
@@ -0,0 +1,161 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keys
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// Every segment of a key starts with a tag recording its type, so that
+// segments of different types can never be confused for one another
+const (
+	byteSegment byte = iota + 1
+	uint64Segment
+	idSegment
+	bytesSegment
+)
+
+func segmentName(tag byte) string {
+	switch tag {
+	case byteSegment:
+		return "byte"
+	case uint64Segment:
+		return "uint64"
+	case idSegment:
+		return "ID"
+	case bytesSegment:
+		return "bytes"
+	default:
+		return fmt.Sprintf("unknown (%d)", tag)
+	}
+}
+
+// Builder builds a database key out of typed segments. Two keys are only
+// equal if they were built from the same segments, in the same order, so
+// concatenating values can't cause keys to collide. Keys that start with the
+// same segments share a prefix, so they can be iterated over together. Within
+// segments of the same type, integers are ordered numerically and byte slices
+// by their length first.
+type Builder struct {
+	p wrappers.Packer
+}
+
+// NewBuilder returns a builder of an empty key
+func NewBuilder() *Builder {
+	return &Builder{p: wrappers.Packer{MaxSize: math.MaxInt32}}
+}
+
+// Byte appends [val] to the key
+func (b *Builder) Byte(val byte) *Builder {
+	b.p.PackByte(byteSegment)
+	b.p.PackByte(val)
+	return b
+}
+
+// Uint64 appends [val] to the key
+func (b *Builder) Uint64(val uint64) *Builder {
+	b.p.PackByte(uint64Segment)
+	b.p.PackLong(val)
+	return b
+}
+
+// ID appends [id] to the key
+func (b *Builder) ID(id ids.ID) *Builder {
+	b.p.PackByte(idSegment)
+	b.p.PackFixedBytes(id.Bytes())
+	return b
+}
+
+// Bytes appends [val], prefixed with its length, to the key
+func (b *Builder) Bytes(val []byte) *Builder {
+	b.p.PackByte(bytesSegment)
+	b.p.PackBytes(val)
+	return b
+}
+
+// Key returns the key built so far
+func (b *Builder) Key() ([]byte, error) { return b.p.Bytes, b.p.Err }
+
+// Parser splits a key built by a Builder back into its segments. The segments
+// must be read in the order they were appended. Reading a segment of the wrong
+// type, or past the end of the key, fails, after which every read returns the
+// zero value and Err reports why.
+type Parser struct {
+	p wrappers.Packer
+}
+
+// NewParser returns a parser of [key]
+func NewParser(key []byte) *Parser {
+	return &Parser{p: wrappers.Packer{Bytes: key}}
+}
+
+// Byte reads a byte segment
+func (p *Parser) Byte() byte {
+	if !p.segment(byteSegment) {
+		return 0
+	}
+	return p.p.UnpackByte()
+}
+
+// Uint64 reads a uint64 segment
+func (p *Parser) Uint64() uint64 {
+	if !p.segment(uint64Segment) {
+		return 0
+	}
+	return p.p.UnpackLong()
+}
+
+// ID reads an ID segment
+func (p *Parser) ID() ids.ID {
+	if !p.segment(idSegment) {
+		return ids.ID{}
+	}
+	idBytes := p.p.UnpackFixedBytes(hashing.HashLen)
+	if p.p.Errored() {
+		return ids.ID{}
+	}
+	id, err := ids.ToID(idBytes)
+	if err != nil {
+		p.p.Add(err)
+		return ids.ID{}
+	}
+	return id
+}
+
+// Bytes reads a byte slice segment
+func (p *Parser) Bytes() []byte {
+	if !p.segment(bytesSegment) {
+		return nil
+	}
+	return p.p.UnpackBytes()
+}
+
+// Done returns true if every segment of the key has been read
+func (p *Parser) Done() bool { return p.p.Offset == len(p.p.Bytes) }
+
+// Err returns the error that caused a read to fail, if any
+func (p *Parser) Err() error { return p.p.Err }
+
+// segment reads the tag of the next segment and returns true if it is [tag]
+func (p *Parser) segment(tag byte) bool {
+	if p.p.Errored() {
+		return false
+	}
+	found := p.p.UnpackByte()
+	if p.p.Errored() {
+		return false
+	}
+	if found != tag {
+		p.p.Add(fmt.Errorf("expected a %s segment but found a %s segment",
+			segmentName(tag),
+			segmentName(found)))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package keys
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestRoundTrip(t *testing.T) {
+	id := ids.NewID([32]byte{1, 2, 3})
+	key, err := NewBuilder().
+		Byte(7).
+		Uint64(1 << 40).
+		ID(id).
+		Bytes([]byte("value")).
+		Bytes(nil).
+		Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser(key)
+	if b := p.Byte(); b != 7 {
+		t.Fatalf("Byte returned %d but should have returned 7", b)
+	}
+	if v := p.Uint64(); v != 1<<40 {
+		t.Fatalf("Uint64 returned %d but should have returned %d", v, uint64(1<<40))
+	}
+	if parsedID := p.ID(); !parsedID.Equals(id) {
+		t.Fatalf("ID returned %s but should have returned %s", parsedID, id)
+	}
+	if b := p.Bytes(); !bytes.Equal(b, []byte("value")) {
+		t.Fatalf("Bytes returned %q but should have returned %q", b, "value")
+	}
+	if b := p.Bytes(); len(b) != 0 {
+		t.Fatalf("Bytes returned %q but should have returned an empty slice", b)
+	}
+	if err := p.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Done() {
+		t.Fatalf("Parser should have read the whole key")
+	}
+}
+
+func TestNoCollisions(t *testing.T) {
+	id := ids.NewID([32]byte{1})
+	builders := map[string]*Builder{
+		"byte":              NewBuilder().Byte(1),
+		"bytes of byte":     NewBuilder().Bytes([]byte{1}),
+		"uint64":            NewBuilder().Uint64(1),
+		"bytes of uint64":   NewBuilder().Bytes([]byte{0, 0, 0, 0, 0, 0, 0, 1}),
+		"8 bytes":           NewBuilder().Byte(0).Byte(0).Byte(0).Byte(0).Byte(0).Byte(0).Byte(0).Byte(1),
+		"id":                NewBuilder().ID(id),
+		"bytes of id":       NewBuilder().Bytes(id.Bytes()),
+		"split bytes left":  NewBuilder().Bytes([]byte{1, 2}).Bytes([]byte{3}),
+		"split bytes right": NewBuilder().Bytes([]byte{1}).Bytes([]byte{2, 3}),
+		"joined bytes":      NewBuilder().Bytes([]byte{1, 2, 3}),
+		"byte then uint64":  NewBuilder().Byte(1).Uint64(2),
+		"uint64 then byte":  NewBuilder().Uint64(1).Byte(2),
+		"empty bytes":       NewBuilder().Bytes(nil),
+		"empty":             NewBuilder(),
+	}
+
+	seen := map[string]string{}
+	for name, builder := range builders {
+		key, err := builder.Key()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if other, exists := seen[string(key)]; exists {
+			t.Fatalf("%s and %s produced the same key %x", name, other, key)
+		}
+		seen[string(key)] = name
+	}
+}
+
+func TestSharedPrefix(t *testing.T) {
+	id := ids.NewID([32]byte{1})
+	prefix, err := NewBuilder().Byte(1).ID(id).Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := NewBuilder().Byte(1).ID(id).Uint64(5).Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(key, prefix) {
+		t.Fatalf("Key %x should start with %x", key, prefix)
+	}
+}
+
+func TestParseWrongType(t *testing.T) {
+	key, err := NewBuilder().Uint64(1).Byte(2).Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser(key)
+	if b := p.Byte(); b != 0 {
+		t.Fatalf("Reading the wrong type should have returned 0 but returned %d", b)
+	}
+	if p.Err() == nil {
+		t.Fatalf("Reading a uint64 segment as a byte should have failed")
+	}
+	// Later reads keep failing
+	if v := p.Uint64(); v != 0 {
+		t.Fatalf("Reads after a failure should return 0 but returned %d", v)
+	}
+}
+
+func TestParseTruncated(t *testing.T) {
+	key, err := NewBuilder().Bytes([]byte("value")).Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser(key[:len(key)-1])
+	if b := p.Bytes(); b != nil {
+		t.Fatalf("Reading a truncated segment should have returned nil but returned %q", b)
+	}
+	if p.Err() == nil {
+		t.Fatalf("Reading a truncated segment should have failed")
+	}
+
+	p = NewParser(key)
+	p.Bytes()
+	if p.ID(); p.Err() == nil {
+		t.Fatalf("Reading past the end of the key should have failed")
+	}
+}
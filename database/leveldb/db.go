@@ -5,6 +5,7 @@ package leveldb
 
 import (
 	"bytes"
+	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/errors"
@@ -14,6 +15,7 @@ import (
 	"github.com/syndtr/goleveldb/leveldb/util"
 
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/nodb"
 	"github.com/ava-labs/gecko/utils"
 )
 
@@ -118,6 +120,23 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	return &iter{db.DB.NewIterator(iterRange, nil)}
 }
 
+// NewTolerantIterator creates a lexicographically ordered iterator over the
+// database starting at start and ignoring keys that do not start with the
+// provided prefix. Unlike the other iterators, a corrupted table block, for
+// example one that fails its checksum, doesn't stop the iteration. The keys
+// stored in that block are skipped instead, so that the rest of the database
+// can still be salvaged. levelDB doesn't report which keys were skipped.
+func (db *Database) NewTolerantIterator(start, prefix []byte) database.Iterator {
+	iterRange := util.BytesPrefix(prefix)
+	if bytes.Compare(start, prefix) == 1 {
+		iterRange.Start = start
+	}
+	// Overriding the strict flags drops StrictReader, which is what makes the
+	// iterator fail on corrupted blocks. Checksums are still verified, as that
+	// is controlled by the options the database was opened with.
+	return &iter{db.DB.NewIterator(iterRange, &opt.ReadOptions{Strict: opt.StrictOverride})}
+}
+
 // Stat returns a particular internal stat of the database.
 func (db *Database) Stat(property string) (string, error) {
 	stat, err := db.DB.GetProperty(property)
@@ -139,9 +158,124 @@ func (db *Database) Compact(start []byte, limit []byte) error {
 	return updateError(db.DB.CompactRange(util.Range{Start: start, Limit: limit}))
 }
 
+// Count implements the Database interface
+func (db *Database) Count(prefix []byte) (int, error) {
+	return database.CountWithIterator(db, prefix)
+}
+
+// SizeEstimate returns the approximate file system space used by the keys in
+// the given range. Keys that are still in the memtable aren't included.
+func (db *Database) SizeEstimate(start []byte, limit []byte) (uint64, error) {
+	if limit == nil {
+		// levelDB treats a nil limit as a key before all keys, so use the key
+		// immediately after the last key instead
+		it := db.DB.NewIterator(nil, nil)
+		hasLast := it.Last()
+		if hasLast {
+			limit = append(utils.CopyBytes(it.Key()), 0)
+		}
+		it.Release()
+		if err := it.Error(); err != nil || !hasLast {
+			return 0, updateError(err)
+		}
+	}
+
+	sizes, err := db.DB.SizeOf([]util.Range{{Start: start, Limit: limit}})
+	if err != nil {
+		return 0, updateError(err)
+	}
+	return uint64(sizes.Sum()), nil
+}
+
+// NewSnapshot returns a snapshot of the current state of the database. Taking
+// a snapshot is cheap, but it prevents leveldb from discarding the versions
+// of the keys it refers to until it's released.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	snap, err := db.DB.GetSnapshot()
+	if err != nil {
+		return nil, updateError(err)
+	}
+	return &snapshot{snap: snap}, nil
+}
+
 // Close implements the Database interface
 func (db *Database) Close() error { return updateError(db.DB.Close()) }
 
+// snapshot is a wrapper around a levelDB snapshot to convert its errors. The
+// levelDB snapshot can't be used at all once it's released, so releasing it is
+// tracked here.
+type snapshot struct {
+	lock sync.RWMutex
+	snap *leveldb.Snapshot
+}
+
+// Has implements the Snapshot interface
+func (s *snapshot) Has(key []byte) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.snap == nil {
+		return false, database.ErrClosed
+	}
+	has, err := s.snap.Has(key, nil)
+	return has, updateError(err)
+}
+
+// Get implements the Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.snap == nil {
+		return nil, database.ErrClosed
+	}
+	value, err := s.snap.Get(key, nil)
+	return value, updateError(err)
+}
+
+// NewIterator implements the Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator { return s.newIterator(new(util.Range)) }
+
+// NewIteratorWithStart implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.newIterator(&util.Range{Start: start})
+}
+
+// NewIteratorWithPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.newIterator(util.BytesPrefix(prefix))
+}
+
+// NewIteratorWithStartAndPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	iterRange := util.BytesPrefix(prefix)
+	if bytes.Compare(start, prefix) == 1 {
+		iterRange.Start = start
+	}
+	return s.newIterator(iterRange)
+}
+
+// Release implements the Snapshot interface
+func (s *snapshot) Release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.snap != nil {
+		s.snap.Release()
+		s.snap = nil
+	}
+}
+
+func (s *snapshot) newIterator(iterRange *util.Range) database.Iterator {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.snap == nil {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return &iter{s.snap.NewIterator(iterRange, nil)}
+}
+
 // batch is a wrapper around a levelDB batch to contain sizes.
 type batch struct {
 	leveldb.Batch
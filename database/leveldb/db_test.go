@@ -5,7 +5,9 @@ package leveldb
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ava-labs/gecko/database"
@@ -25,3 +27,84 @@ func TestInterface(t *testing.T) {
 		test(t, db)
 	}
 }
+
+func TestTolerantIteratorSkipsCorruptBlocks(t *testing.T) {
+	folder, err := ioutil.TempDir("", "leveldb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(folder)
+
+	db, err := New(folder, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Write enough keys to span many table blocks
+	numKeys := 256
+	value := make([]byte, 1024)
+	for i := 0; i < numKeys; i++ {
+		if err := db.Put([]byte(fmt.Sprintf("key%04d", i)), value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Flush everything to a table file
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt a block in the middle of the table
+	tables, err := filepath.Glob(filepath.Join(folder, "*.ldb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table but found %d", len(tables))
+	}
+	table, err := ioutil.ReadFile(tables[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := len(table) / 2; i < len(table)/2+16; i++ {
+		table[i] ^= 0xff
+	}
+	if err := ioutil.WriteFile(tables[0], table, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = New(folder, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The default iterator stops at the corrupt block
+	it := db.NewIterator()
+	for it.Next() {
+	}
+	if it.Error() == nil {
+		t.Fatalf("iterator should have failed on the corrupt block")
+	}
+	it.Release()
+
+	it = db.NewTolerantIterator(nil, nil)
+	numRead := 0
+	var lastKey []byte
+	for it.Next() {
+		numRead++
+		lastKey = it.Key()
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	it.Release()
+
+	if numRead == 0 || numRead >= numKeys {
+		t.Fatalf("tolerant iterator should have skipped only the corrupt block but read %d of %d keys", numRead, numKeys)
+	}
+	if expected := fmt.Sprintf("key%04d", numKeys-1); string(lastKey) != expected {
+		t.Fatalf("tolerant iterator should have continued to %s but stopped at %s", expected, lastKey)
+	}
+}
@@ -152,6 +152,61 @@ func (db *Database) Compact(start []byte, limit []byte) error {
 	return nil
 }
 
+// Count implements the Database interface
+func (db *Database) Count(prefix []byte) (int, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+
+	count := 0
+	for key := range db.db {
+		if strings.HasPrefix(key, string(prefix)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SizeEstimate implements the Database interface. The estimate is the total
+// length of the keys and values in the range.
+func (db *Database) SizeEstimate(start []byte, limit []byte) (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+
+	size := uint64(0)
+	for key, value := range db.db {
+		if key >= string(start) && (limit == nil || key < string(limit)) {
+			size += uint64(len(key) + len(value))
+		}
+	}
+	return size, nil
+}
+
+// NewSnapshot implements the Database interface. The snapshot is a copy of the
+// database, so taking it takes time linear in the size of the database.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+
+	// Values are never modified in place, so they don't need to be copied
+	copied := make(map[string][]byte, len(db.db))
+	for key, value := range db.db {
+		copied[key] = value
+	}
+	return &snapshot{db: &Database{db: copied}}, nil
+}
+
 type keyValue struct {
 	key    []byte
 	value  []byte
@@ -268,3 +323,33 @@ func (it *iterator) Value() []byte {
 
 // Release implements the Iterator interface
 func (it *iterator) Release() { it.keys = nil; it.values = nil }
+
+// snapshot is a read-only copy of a database
+type snapshot struct{ db *Database }
+
+// Has implements the Snapshot interface
+func (s *snapshot) Has(key []byte) (bool, error) { return s.db.Has(key) }
+
+// Get implements the Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) { return s.db.Get(key) }
+
+// NewIterator implements the Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator { return s.db.NewIterator() }
+
+// NewIteratorWithStart implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.db.NewIteratorWithStart(start)
+}
+
+// NewIteratorWithPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.db.NewIteratorWithPrefix(prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return s.db.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Release implements the Snapshot interface
+func (s *snapshot) Release() { _ = s.db.Close() }
@@ -33,6 +33,7 @@ func (db *Database) Has(key []byte) (bool, error) {
 	has, err := db.db.Has(key)
 	end := db.clock.Time()
 	db.has.Observe(float64(end.Sub(start)))
+	db.observeErr(err)
 	return has, err
 }
 
@@ -42,6 +43,7 @@ func (db *Database) Get(key []byte) ([]byte, error) {
 	value, err := db.db.Get(key)
 	end := db.clock.Time()
 	db.get.Observe(float64(end.Sub(start)))
+	db.observeErr(err)
 	return value, err
 }
 
@@ -51,6 +53,7 @@ func (db *Database) Put(key, value []byte) error {
 	err := db.db.Put(key, value)
 	end := db.clock.Time()
 	db.put.Observe(float64(end.Sub(start)))
+	db.observeErr(err)
 	return err
 }
 
@@ -60,6 +63,7 @@ func (db *Database) Delete(key []byte) error {
 	err := db.db.Delete(key)
 	end := db.clock.Time()
 	db.delete.Observe(float64(end.Sub(start)))
+	db.observeErr(err)
 	return err
 }
 
@@ -111,6 +115,7 @@ func (db *Database) Stat(stat string) (string, error) {
 	result, err := db.db.Stat(stat)
 	end := db.clock.Time()
 	db.stat.Observe(float64(end.Sub(start)))
+	db.observeErr(err)
 	return result, err
 }
 
@@ -120,15 +125,47 @@ func (db *Database) Compact(start, limit []byte) error {
 	err := db.db.Compact(start, limit)
 	end := db.clock.Time()
 	db.compact.Observe(float64(end.Sub(startTime)))
+	db.observeErr(err)
 	return err
 }
 
+// Count implements the Database interface
+func (db *Database) Count(prefix []byte) (int, error) {
+	start := db.clock.Time()
+	count, err := db.db.Count(prefix)
+	end := db.clock.Time()
+	db.count.Observe(float64(end.Sub(start)))
+	db.observeErr(err)
+	return count, err
+}
+
+// SizeEstimate implements the Database interface
+func (db *Database) SizeEstimate(start, limit []byte) (uint64, error) {
+	startTime := db.clock.Time()
+	size, err := db.db.SizeEstimate(start, limit)
+	end := db.clock.Time()
+	db.sizeEstimate.Observe(float64(end.Sub(startTime)))
+	db.observeErr(err)
+	return size, err
+}
+
+// NewSnapshot implements the Database interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	start := db.clock.Time()
+	snap, err := db.db.NewSnapshot()
+	end := db.clock.Time()
+	db.newSnapshot.Observe(float64(end.Sub(start)))
+	db.observeErr(err)
+	return snap, err
+}
+
 // Close implements the Database interface
 func (db *Database) Close() error {
 	start := db.clock.Time()
 	err := db.db.Close()
 	end := db.clock.Time()
 	db.close.Observe(float64(end.Sub(start)))
+	db.observeErr(err)
 	return err
 }
 
@@ -142,6 +179,7 @@ func (b *batch) Put(key, value []byte) error {
 	err := b.batch.Put(key, value)
 	end := b.db.clock.Time()
 	b.db.bPut.Observe(float64(end.Sub(start)))
+	b.db.observeErr(err)
 	return err
 }
 
@@ -150,6 +188,7 @@ func (b *batch) Delete(key []byte) error {
 	err := b.batch.Delete(key)
 	end := b.db.clock.Time()
 	b.db.bDelete.Observe(float64(end.Sub(start)))
+	b.db.observeErr(err)
 	return err
 }
 
@@ -166,6 +205,7 @@ func (b *batch) Write() error {
 	err := b.batch.Write()
 	end := b.db.clock.Time()
 	b.db.bWrite.Observe(float64(end.Sub(start)))
+	b.db.observeErr(err)
 	return err
 }
 
@@ -181,6 +221,7 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 	err := b.batch.Replay(w)
 	end := b.db.clock.Time()
 	b.db.bReplay.Observe(float64(end.Sub(start)))
+	b.db.observeErr(err)
 	return err
 }
 
@@ -210,6 +251,7 @@ func (it *iterator) Error() error {
 	err := it.iterator.Error()
 	end := it.db.clock.Time()
 	it.db.iError.Observe(float64(end.Sub(start)))
+	it.db.observeErr(err)
 	return err
 }
 
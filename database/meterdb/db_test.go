@@ -7,9 +7,13 @@ import (
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
 )
 
 func TestInterface(t *testing.T) {
@@ -23,3 +27,74 @@ func TestInterface(t *testing.T) {
 		test(t, db)
 	}
 }
+
+// sampleCount returns the number of observations made by [h]
+func sampleCount(t *testing.T, h prometheus.Histogram) uint64 {
+	metric := dto.Metric{}
+	if err := h.Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestMetrics(t *testing.T) {
+	baseDB := memdb.New()
+	db, err := New("", prometheus.NewRegistry(), baseDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The meterdb should be composable with a prefixdb
+	prefixDB := prefixdb.New([]byte("prefix"), db)
+
+	key := []byte("key")
+	value := []byte("value")
+	if err := prefixDB.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prefixDB.Get(key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prefixDB.Has(key); err != nil {
+		t.Fatal(err)
+	}
+	if err := prefixDB.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prefixDB.Get(key); err != database.ErrNotFound {
+		t.Fatalf("Expected %s but got %v", database.ErrNotFound, err)
+	}
+	it := prefixDB.NewIterator()
+	it.Release()
+
+	if count := sampleCount(t, db.put); count != 1 {
+		t.Fatalf("Expected 1 put observation but got %d", count)
+	}
+	if count := sampleCount(t, db.get); count != 2 {
+		t.Fatalf("Expected 2 get observations but got %d", count)
+	}
+	if count := sampleCount(t, db.has); count != 1 {
+		t.Fatalf("Expected 1 has observation but got %d", count)
+	}
+	if count := sampleCount(t, db.delete); count != 1 {
+		t.Fatalf("Expected 1 delete observation but got %d", count)
+	}
+	if count := sampleCount(t, db.newIterator); count != 1 {
+		t.Fatalf("Expected 1 new iterator observation but got %d", count)
+	}
+
+	// Not finding a key isn't counted as an error
+	if numErrors := testutil.ToFloat64(db.numErrors); numErrors != 0 {
+		t.Fatalf("Expected 0 errors but got %f", numErrors)
+	}
+
+	if err := baseDB.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(key, value); err == nil {
+		t.Fatalf("Put on a closed database should have errored")
+	}
+	if numErrors := testutil.ToFloat64(db.numErrors); numErrors != 1 {
+		t.Fatalf("Expected 1 error but got %f", numErrors)
+	}
+}
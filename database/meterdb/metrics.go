@@ -8,6 +8,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
@@ -30,6 +31,9 @@ type metrics struct {
 	newIterator,
 	stat,
 	compact,
+	count,
+	sizeEstimate,
+	newSnapshot,
 	close,
 	bPut,
 	bDelete,
@@ -43,6 +47,9 @@ type metrics struct {
 	iKey,
 	iValue,
 	iRelease prometheus.Histogram
+
+	// number of calls that returned an error, other than database.ErrNotFound
+	numErrors prometheus.Counter
 }
 
 func (m *metrics) Initialize(
@@ -57,6 +64,9 @@ func (m *metrics) Initialize(
 	m.newIterator = newMetric(namespace, "new_iterator")
 	m.stat = newMetric(namespace, "stat")
 	m.compact = newMetric(namespace, "compact")
+	m.count = newMetric(namespace, "count")
+	m.sizeEstimate = newMetric(namespace, "size_estimate")
+	m.newSnapshot = newMetric(namespace, "new_snapshot")
 	m.close = newMetric(namespace, "close")
 	m.bPut = newMetric(namespace, "batch_put")
 	m.bDelete = newMetric(namespace, "batch_delete")
@@ -70,6 +80,11 @@ func (m *metrics) Initialize(
 	m.iKey = newMetric(namespace, "iterator_key")
 	m.iValue = newMetric(namespace, "iterator_value")
 	m.iRelease = newMetric(namespace, "iterator_release")
+	m.numErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "errors",
+		Help:      "Number of calls that returned an error",
+	})
 
 	errs := wrappers.Errs{}
 	errs.Add(
@@ -81,6 +96,9 @@ func (m *metrics) Initialize(
 		registerer.Register(m.newIterator),
 		registerer.Register(m.stat),
 		registerer.Register(m.compact),
+		registerer.Register(m.count),
+		registerer.Register(m.sizeEstimate),
+		registerer.Register(m.newSnapshot),
 		registerer.Register(m.close),
 		registerer.Register(m.bPut),
 		registerer.Register(m.bDelete),
@@ -94,6 +112,14 @@ func (m *metrics) Initialize(
 		registerer.Register(m.iKey),
 		registerer.Register(m.iValue),
 		registerer.Register(m.iRelease),
+		registerer.Register(m.numErrors),
 	)
 	return errs.Err
 }
+
+// observeErr counts [err] if it is an unexpected error
+func (m *metrics) observeErr(err error) {
+	if err != nil && err != database.ErrNotFound {
+		m.numErrors.Inc()
+	}
+}
@@ -0,0 +1,94 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/versiondb"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	versionKey = []byte("schema_version")
+
+	errUnknownVersion = errors.New("database was written with a newer schema than is known")
+)
+
+// Migration upgrades the data in a database from one schema version to the
+// next. Writes made by a migration are only committed if it succeeds.
+type Migration func(db database.Database) error
+
+// Migrator upgrades a database to the latest schema version by applying, in
+// order, the migrations it hasn't yet had applied. Migration i upgrades the
+// database from version i to version i+1, so a database that has never been
+// migrated is at version 0.
+type Migrator struct {
+	db         database.Database
+	migrations []Migration
+}
+
+// New returns a migrator for [db]. The schema version is stored in [db]
+// under its own key, which must not be used by the migrations.
+func New(db database.Database, migrations ...Migration) *Migrator {
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}
+}
+
+// Version returns the schema version of the database
+func (m *Migrator) Version() (uint64, error) {
+	versionBytes, err := m.db.Get(versionKey)
+	if err == database.ErrNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	p := wrappers.Packer{Bytes: versionBytes}
+	version := p.UnpackLong()
+	if p.Offset != len(versionBytes) {
+		p.Add(fmt.Errorf("expected version to be %d bytes but was %d", wrappers.LongLen, len(versionBytes)))
+	}
+	return version, p.Err
+}
+
+// Migrate applies every migration the database hasn't had applied yet. Each
+// migration's writes are committed atomically with the version it upgraded
+// the database to, so if a migration fails, neither its writes nor the new
+// version are recorded and it will be retried the next time Migrate is
+// called. Migrating a database that is already at the latest version is a
+// no-op.
+func (m *Migrator) Migrate() error {
+	version, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("couldn't read schema version: %w", err)
+	}
+	if version > uint64(len(m.migrations)) {
+		return fmt.Errorf("%w: database is at version %d but the latest version is %d",
+			errUnknownVersion, version, len(m.migrations))
+	}
+
+	for ; version < uint64(len(m.migrations)); version++ {
+		vdb := versiondb.New(m.db)
+		if err := m.migrations[version](vdb); err != nil {
+			vdb.Abort()
+			return fmt.Errorf("migration from version %d failed: %w", version, err)
+		}
+
+		p := wrappers.Packer{Bytes: make([]byte, wrappers.LongLen)}
+		p.PackLong(version + 1)
+		if err := vdb.Put(versionKey, p.Bytes); err != nil {
+			vdb.Abort()
+			return err
+		}
+		if err := vdb.Commit(); err != nil {
+			return fmt.Errorf("couldn't commit migration from version %d: %w", version, err)
+		}
+	}
+	return nil
+}
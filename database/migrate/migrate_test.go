@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+)
+
+var errMigrationFailed = errors.New("migration failed")
+
+func assertVersion(t *testing.T, m *Migrator, expected uint64) {
+	version, err := m.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != expected {
+		t.Fatalf("Expected version %d but got %d", expected, version)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	db := memdb.New()
+	if err := db.Put([]byte("old"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	runs := make([]int, 2)
+	migrations := []Migration{
+		// Rename "old" to "new"
+		func(db database.Database) error {
+			runs[0]++
+			value, err := db.Get([]byte("old"))
+			if err != nil {
+				return err
+			}
+			if err := db.Put([]byte("new"), value); err != nil {
+				return err
+			}
+			return db.Delete([]byte("old"))
+		},
+		// Double the value of "new"
+		func(db database.Database) error {
+			runs[1]++
+			value, err := db.Get([]byte("new"))
+			if err != nil {
+				return err
+			}
+			return db.Put([]byte("new"), append(value, value...))
+		},
+	}
+
+	m := New(db, migrations...)
+	assertVersion(t, m, 0)
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	assertVersion(t, m, 2)
+
+	if has, err := db.Has([]byte("old")); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("The first migration should have removed the old key")
+	}
+	if value, err := db.Get([]byte("new")); err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(value, []byte("valuevalue")) {
+		t.Fatalf("Migrations were applied out of order, resulting in %s", value)
+	}
+
+	// Migrating again should be a no-op
+	m = New(db, migrations...)
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	assertVersion(t, m, 2)
+	if runs[0] != 1 || runs[1] != 1 {
+		t.Fatalf("Expected each migration to run once but they ran %v times", runs)
+	}
+}
+
+func TestMigrateFailure(t *testing.T) {
+	db := memdb.New()
+
+	fail := true
+	migrations := []Migration{
+		func(db database.Database) error { return db.Put([]byte("first"), []byte{1}) },
+		func(db database.Database) error {
+			if err := db.Put([]byte("second"), []byte{2}); err != nil {
+				return err
+			}
+			if fail {
+				return errMigrationFailed
+			}
+			return nil
+		},
+	}
+
+	m := New(db, migrations...)
+	if err := m.Migrate(); !errors.Is(err, errMigrationFailed) {
+		t.Fatalf("Expected the migration to fail but got %v", err)
+	}
+	// The first migration succeeded, but the failed one must not have
+	// advanced the version or left its writes behind
+	assertVersion(t, m, 1)
+	if has, err := db.Has([]byte("second")); err != nil {
+		t.Fatal(err)
+	} else if has {
+		t.Fatal("The failed migration's writes shouldn't have been committed")
+	}
+
+	// Retrying picks up from the failed migration
+	fail = false
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	assertVersion(t, m, 2)
+	if has, err := db.Has([]byte("second")); err != nil {
+		t.Fatal(err)
+	} else if !has {
+		t.Fatal("The retried migration's writes should have been committed")
+	}
+}
+
+func TestMigrateUnknownVersion(t *testing.T) {
+	db := memdb.New()
+	m := New(db, func(database.Database) error { return nil }, func(database.Database) error { return nil })
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// An older release only knows about the first migration
+	m = New(db, func(database.Database) error { return nil })
+	if err := m.Migrate(); !errors.Is(err, errUnknownVersion) {
+		t.Fatalf("Expected errUnknownVersion but got %v", err)
+	}
+}
@@ -32,6 +32,9 @@ type Database struct {
 	OnNewIteratorWithStartAndPrefix func([]byte, []byte) database.Iterator
 	OnStat                          func(string) (string, error)
 	OnCompact                       func([]byte, []byte) error
+	OnCount                         func([]byte) (int, error)
+	OnSizeEstimate                  func([]byte, []byte) (uint64, error)
+	OnNewSnapshot                   func() (database.Snapshot, error)
 	OnClose                         func() error
 }
 
@@ -123,6 +126,30 @@ func (db *Database) Compact(start []byte, limit []byte) error {
 	return db.OnCompact(start, limit)
 }
 
+// Count implements the database.Database interface
+func (db *Database) Count(prefix []byte) (int, error) {
+	if db.OnCount == nil {
+		return 0, errNoFunction
+	}
+	return db.OnCount(prefix)
+}
+
+// SizeEstimate implements the database.Database interface
+func (db *Database) SizeEstimate(start []byte, limit []byte) (uint64, error) {
+	if db.OnSizeEstimate == nil {
+		return 0, errNoFunction
+	}
+	return db.OnSizeEstimate(start, limit)
+}
+
+// NewSnapshot implements the database.Database interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	if db.OnNewSnapshot == nil {
+		return nil, errNoFunction
+	}
+	return db.OnNewSnapshot()
+}
+
 // Close implements the database.Database interface
 func (db *Database) Close() error {
 	if db.OnClose == nil {
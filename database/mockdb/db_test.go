@@ -49,6 +49,9 @@ func TestDefaultError(t *testing.T) {
 	if _, err := db.Stat(""); err == nil {
 		t.Fatal("should have errored")
 	}
+	if _, err := db.NewSnapshot(); err == nil {
+		t.Fatal("should have errored")
+	}
 }
 
 // Assert that mocking works for Get
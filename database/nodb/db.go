@@ -45,6 +45,15 @@ func (*Database) Stat(string) (string, error) { return "", database.ErrClosed }
 // Compact returns nil
 func (*Database) Compact(_, _ []byte) error { return database.ErrClosed }
 
+// Count returns an error
+func (*Database) Count([]byte) (int, error) { return 0, database.ErrClosed }
+
+// SizeEstimate returns an error
+func (*Database) SizeEstimate(_, _ []byte) (uint64, error) { return 0, database.ErrClosed }
+
+// NewSnapshot returns an error
+func (*Database) NewSnapshot() (database.Snapshot, error) { return nil, database.ErrClosed }
+
 // Close returns nil
 func (*Database) Close() error { return database.ErrClosed }
 
@@ -142,6 +142,51 @@ func (db *Database) Compact(start, limit []byte) error {
 	return db.db.Compact(db.prefix(start), db.prefix(limit))
 }
 
+// Count implements the Database interface
+func (db *Database) Count(prefix []byte) (int, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+	return db.db.Count(db.prefix(prefix))
+}
+
+// SizeEstimate implements the Database interface
+func (db *Database) SizeEstimate(start, limit []byte) (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+
+	prefixedLimit := nextPrefix(db.dbPrefix)
+	if limit != nil {
+		prefixedLimit = db.prefix(limit)
+	}
+	return db.db.SizeEstimate(db.prefix(start), prefixedLimit)
+}
+
+// NewSnapshot implements the Database interface
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return nil, database.ErrClosed
+	}
+	snap, err := db.db.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot{
+		Snapshot: snap,
+		db:       db,
+	}, nil
+}
+
 // Close implements the Database interface
 func (db *Database) Close() error {
 	db.lock.Lock()
@@ -161,6 +206,19 @@ func (db *Database) prefix(key []byte) []byte {
 	return prefixedKey
 }
 
+// nextPrefix returns the smallest key that is after every key starting with
+// [prefix], or nil if there is no such key
+func nextPrefix(prefix []byte) []byte {
+	next := utils.CopyBytes(prefix)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next[:i+1]
+		}
+	}
+	return nil
+}
+
 type keyValue struct {
 	key    []byte
 	value  []byte
@@ -234,3 +292,36 @@ func (it *iterator) Key() []byte {
 	}
 	return key
 }
+
+// snapshot prefixes the keys read from a snapshot of the underlying database
+type snapshot struct {
+	database.Snapshot
+	db *Database
+}
+
+// Has implements the Snapshot interface
+func (s *snapshot) Has(key []byte) (bool, error) { return s.Snapshot.Has(s.db.prefix(key)) }
+
+// Get implements the Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) { return s.Snapshot.Get(s.db.prefix(key)) }
+
+// NewIterator implements the Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator { return s.NewIteratorWithStartAndPrefix(nil, nil) }
+
+// NewIteratorWithStart implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return &iterator{
+		Iterator: s.Snapshot.NewIteratorWithStartAndPrefix(s.db.prefix(start), s.db.prefix(prefix)),
+		db:       s.db,
+	}
+}
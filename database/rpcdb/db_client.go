@@ -120,6 +120,29 @@ func (db *DatabaseClient) Compact(start, limit []byte) error {
 	return updateError(err)
 }
 
+// Count returns the number of keys with the provided prefix
+func (db *DatabaseClient) Count(prefix []byte) (int, error) {
+	return database.CountWithIterator(db, prefix)
+}
+
+// SizeEstimate returns the length of the keys and values in the provided range
+func (db *DatabaseClient) SizeEstimate(start, limit []byte) (uint64, error) {
+	return database.SizeWithIterator(db, start, limit)
+}
+
+// NewSnapshot takes a snapshot on the served database. The snapshot's contents
+// stay on the server and are read over RPC.
+func (db *DatabaseClient) NewSnapshot() (database.Snapshot, error) {
+	resp, err := db.client.NewSnapshot(context.Background(), &rpcdbproto.NewSnapshotRequest{})
+	if err != nil {
+		return nil, updateError(err)
+	}
+	return &snapshot{
+		db: db,
+		id: resp.Id,
+	}, nil
+}
+
 // Close attempts to close the database
 func (db *DatabaseClient) Close() error {
 	_, err := db.client.Close(context.Background(), &rpcdbproto.CloseRequest{})
@@ -204,6 +227,85 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 
 func (b *batch) Inner() database.Batch { return b }
 
+type snapshot struct {
+	db       *DatabaseClient
+	id       uint64
+	released bool
+}
+
+func (s *snapshot) Has(key []byte) (bool, error) {
+	if s.released {
+		return false, database.ErrClosed
+	}
+	resp, err := s.db.client.SnapshotHas(context.Background(), &rpcdbproto.SnapshotHasRequest{
+		Id:  s.id,
+		Key: key,
+	})
+	if err != nil {
+		return false, updateError(err)
+	}
+	return resp.Has, nil
+}
+
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	if s.released {
+		return nil, database.ErrClosed
+	}
+	resp, err := s.db.client.SnapshotGet(context.Background(), &rpcdbproto.SnapshotGetRequest{
+		Id:  s.id,
+		Key: key,
+	})
+	if err != nil {
+		return nil, updateError(err)
+	}
+	return resp.Value, nil
+}
+
+func (s *snapshot) NewIterator() database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, nil)
+}
+
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	if s.released {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	resp, err := s.db.client.SnapshotNewIteratorWithStartAndPrefix(context.Background(), &rpcdbproto.SnapshotNewIteratorWithStartAndPrefixRequest{
+		Id:     s.id,
+		Start:  start,
+		Prefix: prefix,
+	})
+	if err != nil {
+		return &nodb.Iterator{Err: updateError(err)}
+	}
+	return &iterator{
+		db: s.db,
+		id: resp.Id,
+	}
+}
+
+// Release frees the snapshot held by the server. It's safe to call multiple
+// times.
+func (s *snapshot) Release() {
+	if s.released {
+		return
+	}
+	s.released = true
+
+	// Release can't report an error; if the server is gone the snapshot is
+	// gone with it.
+	_, _ = s.db.client.SnapshotRelease(context.Background(), &rpcdbproto.SnapshotReleaseRequest{
+		Id: s.id,
+	})
+}
+
 type iterator struct {
 	db    *DatabaseClient
 	id    uint64
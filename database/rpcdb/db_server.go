@@ -14,6 +14,7 @@ import (
 
 var (
 	errUnknownIterator = errors.New("unknown iterator")
+	errUnknownSnapshot = errors.New("unknown snapshot")
 )
 
 // DatabaseServer is a database that is managed over RPC.
@@ -23,6 +24,9 @@ type DatabaseServer struct {
 
 	nextIteratorID uint64
 	iterators      map[uint64]database.Iterator
+
+	nextSnapshotID uint64
+	snapshots      map[uint64]database.Snapshot
 }
 
 // NewServer returns a database instance that is managed remotely
@@ -31,6 +35,7 @@ func NewServer(db database.Database) *DatabaseServer {
 		db:        db,
 		batch:     db.NewBatch(),
 		iterators: make(map[uint64]database.Iterator),
+		snapshots: make(map[uint64]database.Snapshot),
 	}
 }
 
@@ -145,3 +150,72 @@ func (db *DatabaseServer) IteratorRelease(_ context.Context, req *rpcdbproto.Ite
 	}
 	return &rpcdbproto.IteratorReleaseResponse{}, nil
 }
+
+// NewSnapshot takes a snapshot of the managed database and returns the snapshot
+// ID
+func (db *DatabaseServer) NewSnapshot(context.Context, *rpcdbproto.NewSnapshotRequest) (*rpcdbproto.NewSnapshotResponse, error) {
+	snapshot, err := db.db.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	id := db.nextSnapshotID
+	db.snapshots[id] = snapshot
+
+	db.nextSnapshotID++
+	return &rpcdbproto.NewSnapshotResponse{Id: id}, nil
+}
+
+// SnapshotHas delegates the Has call to the requested snapshot and returns the
+// result
+func (db *DatabaseServer) SnapshotHas(_ context.Context, req *rpcdbproto.SnapshotHasRequest) (*rpcdbproto.HasResponse, error) {
+	snapshot, exists := db.snapshots[req.Id]
+	if !exists {
+		return nil, errUnknownSnapshot
+	}
+	has, err := snapshot.Has(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcdbproto.HasResponse{Has: has}, nil
+}
+
+// SnapshotGet delegates the Get call to the requested snapshot and returns the
+// result
+func (db *DatabaseServer) SnapshotGet(_ context.Context, req *rpcdbproto.SnapshotGetRequest) (*rpcdbproto.GetResponse, error) {
+	snapshot, exists := db.snapshots[req.Id]
+	if !exists {
+		return nil, errUnknownSnapshot
+	}
+	value, err := snapshot.Get(req.Key)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcdbproto.GetResponse{Value: value}, nil
+}
+
+// SnapshotNewIteratorWithStartAndPrefix allocates an iterator over the
+// requested snapshot and returns the iterator ID
+func (db *DatabaseServer) SnapshotNewIteratorWithStartAndPrefix(_ context.Context, req *rpcdbproto.SnapshotNewIteratorWithStartAndPrefixRequest) (*rpcdbproto.NewIteratorWithStartAndPrefixResponse, error) {
+	snapshot, exists := db.snapshots[req.Id]
+	if !exists {
+		return nil, errUnknownSnapshot
+	}
+
+	id := db.nextIteratorID
+	it := snapshot.NewIteratorWithStartAndPrefix(req.Start, req.Prefix)
+	db.iterators[id] = it
+
+	db.nextIteratorID++
+	return &rpcdbproto.NewIteratorWithStartAndPrefixResponse{Id: id}, nil
+}
+
+// SnapshotRelease attempts to release the resources allocated to a snapshot
+func (db *DatabaseServer) SnapshotRelease(_ context.Context, req *rpcdbproto.SnapshotReleaseRequest) (*rpcdbproto.SnapshotReleaseResponse, error) {
+	snapshot, exists := db.snapshots[req.Id]
+	if exists {
+		delete(db.snapshots, req.Id)
+		snapshot.Release()
+	}
+	return &rpcdbproto.SnapshotReleaseResponse{}, nil
+}
@@ -49,3 +49,55 @@ func TestInterface(t *testing.T) {
 		conn.Close()
 	}
 }
+
+func TestSnapshotHeldByServer(t *testing.T) {
+	listener := bufconn.Listen(bufSize)
+	server := grpc.NewServer()
+	dbServer := NewServer(memdb.New())
+	rpcdbproto.RegisterDatabaseServer(server, dbServer)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Fatalf("Server exited with error: %v", err)
+		}
+	}()
+	defer server.Stop()
+
+	dialer := grpc.WithContextDialer(
+		func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		})
+
+	conn, err := grpc.DialContext(context.Background(), "", dialer, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Failed to dial: %s", err)
+	}
+	defer conn.Close()
+
+	db := NewClient(rpcdbproto.NewDatabaseClient(conn))
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := db.NewSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dbServer.snapshots) != 1 {
+		t.Fatalf("server should be holding 1 snapshot but is holding %d", len(dbServer.snapshots))
+	}
+
+	it := snapshot.NewIterator()
+	if !it.Next() {
+		t.Fatalf("snapshot iterator should have found the key")
+	}
+	it.Release()
+
+	snapshot.Release()
+	snapshot.Release()
+	if len(dbServer.snapshots) != 0 {
+		t.Fatalf("server should have released the snapshot but is holding %d", len(dbServer.snapshots))
+	}
+	if _, err := snapshot.Has([]byte("key")); err != database.ErrClosed {
+		t.Fatalf("Expected error %s on snapshot.Has after release but got %s", database.ErrClosed, err)
+	}
+}
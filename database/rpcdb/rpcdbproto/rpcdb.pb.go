@@ -975,6 +975,299 @@ func (m *IteratorReleaseResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_IteratorReleaseResponse proto.InternalMessageInfo
 
+type NewSnapshotRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NewSnapshotRequest) Reset()         { *m = NewSnapshotRequest{} }
+func (m *NewSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*NewSnapshotRequest) ProtoMessage()    {}
+func (*NewSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af52f4b90339c3f4, []int{25}
+}
+
+func (m *NewSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewSnapshotRequest.Unmarshal(m, b)
+}
+func (m *NewSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *NewSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewSnapshotRequest.Merge(m, src)
+}
+func (m *NewSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_NewSnapshotRequest.Size(m)
+}
+func (m *NewSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewSnapshotRequest proto.InternalMessageInfo
+
+type NewSnapshotResponse struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NewSnapshotResponse) Reset()         { *m = NewSnapshotResponse{} }
+func (m *NewSnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*NewSnapshotResponse) ProtoMessage()    {}
+func (*NewSnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af52f4b90339c3f4, []int{26}
+}
+
+func (m *NewSnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewSnapshotResponse.Unmarshal(m, b)
+}
+func (m *NewSnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewSnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *NewSnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewSnapshotResponse.Merge(m, src)
+}
+func (m *NewSnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_NewSnapshotResponse.Size(m)
+}
+func (m *NewSnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewSnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewSnapshotResponse proto.InternalMessageInfo
+
+func (m *NewSnapshotResponse) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type SnapshotHasRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotHasRequest) Reset()         { *m = SnapshotHasRequest{} }
+func (m *SnapshotHasRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotHasRequest) ProtoMessage()    {}
+func (*SnapshotHasRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af52f4b90339c3f4, []int{27}
+}
+
+func (m *SnapshotHasRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotHasRequest.Unmarshal(m, b)
+}
+func (m *SnapshotHasRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotHasRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotHasRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotHasRequest.Merge(m, src)
+}
+func (m *SnapshotHasRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotHasRequest.Size(m)
+}
+func (m *SnapshotHasRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotHasRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotHasRequest proto.InternalMessageInfo
+
+func (m *SnapshotHasRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *SnapshotHasRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type SnapshotGetRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotGetRequest) Reset()         { *m = SnapshotGetRequest{} }
+func (m *SnapshotGetRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotGetRequest) ProtoMessage()    {}
+func (*SnapshotGetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af52f4b90339c3f4, []int{28}
+}
+
+func (m *SnapshotGetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotGetRequest.Unmarshal(m, b)
+}
+func (m *SnapshotGetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotGetRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotGetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotGetRequest.Merge(m, src)
+}
+func (m *SnapshotGetRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotGetRequest.Size(m)
+}
+func (m *SnapshotGetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotGetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotGetRequest proto.InternalMessageInfo
+
+func (m *SnapshotGetRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *SnapshotGetRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+type SnapshotNewIteratorWithStartAndPrefixRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Start                []byte   `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	Prefix               []byte   `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) Reset() {
+	*m = SnapshotNewIteratorWithStartAndPrefixRequest{}
+}
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) String() string {
+	return proto.CompactTextString(m)
+}
+func (*SnapshotNewIteratorWithStartAndPrefixRequest) ProtoMessage() {}
+func (*SnapshotNewIteratorWithStartAndPrefixRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af52f4b90339c3f4, []int{29}
+}
+
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotNewIteratorWithStartAndPrefixRequest.Unmarshal(m, b)
+}
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotNewIteratorWithStartAndPrefixRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotNewIteratorWithStartAndPrefixRequest.Merge(m, src)
+}
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotNewIteratorWithStartAndPrefixRequest.Size(m)
+}
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotNewIteratorWithStartAndPrefixRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotNewIteratorWithStartAndPrefixRequest proto.InternalMessageInfo
+
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) GetStart() []byte {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *SnapshotNewIteratorWithStartAndPrefixRequest) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+type SnapshotReleaseRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotReleaseRequest) Reset()         { *m = SnapshotReleaseRequest{} }
+func (m *SnapshotReleaseRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotReleaseRequest) ProtoMessage()    {}
+func (*SnapshotReleaseRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af52f4b90339c3f4, []int{30}
+}
+
+func (m *SnapshotReleaseRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotReleaseRequest.Unmarshal(m, b)
+}
+func (m *SnapshotReleaseRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotReleaseRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotReleaseRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotReleaseRequest.Merge(m, src)
+}
+func (m *SnapshotReleaseRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotReleaseRequest.Size(m)
+}
+func (m *SnapshotReleaseRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotReleaseRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotReleaseRequest proto.InternalMessageInfo
+
+func (m *SnapshotReleaseRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type SnapshotReleaseResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotReleaseResponse) Reset()         { *m = SnapshotReleaseResponse{} }
+func (m *SnapshotReleaseResponse) String() string { return proto.CompactTextString(m) }
+func (*SnapshotReleaseResponse) ProtoMessage()    {}
+func (*SnapshotReleaseResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_af52f4b90339c3f4, []int{31}
+}
+
+func (m *SnapshotReleaseResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotReleaseResponse.Unmarshal(m, b)
+}
+func (m *SnapshotReleaseResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotReleaseResponse.Marshal(b, m, deterministic)
+}
+func (m *SnapshotReleaseResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotReleaseResponse.Merge(m, src)
+}
+func (m *SnapshotReleaseResponse) XXX_Size() int {
+	return xxx_messageInfo_SnapshotReleaseResponse.Size(m)
+}
+func (m *SnapshotReleaseResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotReleaseResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotReleaseResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*HasRequest)(nil), "rpcdbproto.HasRequest")
 	proto.RegisterType((*HasResponse)(nil), "rpcdbproto.HasResponse")
@@ -1001,53 +1294,68 @@ func init() {
 	proto.RegisterType((*IteratorErrorResponse)(nil), "rpcdbproto.IteratorErrorResponse")
 	proto.RegisterType((*IteratorReleaseRequest)(nil), "rpcdbproto.IteratorReleaseRequest")
 	proto.RegisterType((*IteratorReleaseResponse)(nil), "rpcdbproto.IteratorReleaseResponse")
+	proto.RegisterType((*NewSnapshotRequest)(nil), "rpcdbproto.NewSnapshotRequest")
+	proto.RegisterType((*NewSnapshotResponse)(nil), "rpcdbproto.NewSnapshotResponse")
+	proto.RegisterType((*SnapshotHasRequest)(nil), "rpcdbproto.SnapshotHasRequest")
+	proto.RegisterType((*SnapshotGetRequest)(nil), "rpcdbproto.SnapshotGetRequest")
+	proto.RegisterType((*SnapshotNewIteratorWithStartAndPrefixRequest)(nil), "rpcdbproto.SnapshotNewIteratorWithStartAndPrefixRequest")
+	proto.RegisterType((*SnapshotReleaseRequest)(nil), "rpcdbproto.SnapshotReleaseRequest")
+	proto.RegisterType((*SnapshotReleaseResponse)(nil), "rpcdbproto.SnapshotReleaseResponse")
 }
 
 func init() { proto.RegisterFile("rpcdb.proto", fileDescriptor_af52f4b90339c3f4) }
 
 var fileDescriptor_af52f4b90339c3f4 = []byte{
-	// 654 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x5d, 0x4f, 0xd4, 0x40,
-	0x14, 0x0d, 0xbb, 0xcb, 0xd7, 0xd9, 0x0f, 0x60, 0x5c, 0xf9, 0xa8, 0xf2, 0x35, 0x88, 0x41, 0x1f,
-	0x88, 0x82, 0xc1, 0x98, 0x90, 0x18, 0x01, 0x03, 0xc6, 0x84, 0x60, 0x21, 0x21, 0x31, 0xbe, 0x0c,
-	0xec, 0x10, 0x1a, 0x97, 0x6d, 0x6d, 0x67, 0x15, 0xdf, 0x7d, 0xf1, 0x5f, 0x9b, 0x4e, 0x6f, 0xdb,
-	0x99, 0xdd, 0x76, 0xd5, 0xb7, 0xb9, 0x73, 0xcf, 0x39, 0x73, 0xe7, 0xce, 0x3d, 0x83, 0x7a, 0x18,
-	0x5c, 0x77, 0xae, 0xb6, 0x83, 0xd0, 0x57, 0x3e, 0x83, 0x0e, 0xf4, 0x9a, 0xaf, 0x00, 0x27, 0x22,
-	0x72, 0xe5, 0xb7, 0xbe, 0x8c, 0x14, 0x9b, 0x45, 0xf5, 0xab, 0xfc, 0xb9, 0x38, 0xb6, 0x36, 0xb6,
-	0xd5, 0x70, 0xe3, 0x25, 0x5f, 0x45, 0x5d, 0xe7, 0xa3, 0xc0, 0xef, 0x45, 0x32, 0x06, 0xdc, 0x8a,
-	0x48, 0x03, 0xa6, 0xdc, 0x78, 0x19, 0x0b, 0x1c, 0x4b, 0x55, 0x2e, 0xb0, 0x81, 0xba, 0xce, 0x93,
-	0x40, 0x1b, 0xe3, 0xdf, 0x45, 0xb7, 0x2f, 0x09, 0x92, 0x04, 0xfc, 0x15, 0x70, 0xd6, 0x2f, 0x17,
-	0xc9, 0x59, 0x15, 0x93, 0xd5, 0x44, 0x5d, 0xb3, 0x12, 0x69, 0xbe, 0x8e, 0xe6, 0x91, 0xec, 0x4a,
-	0x25, 0xcb, 0x8b, 0x99, 0x45, 0x2b, 0x85, 0x10, 0xe9, 0x19, 0xea, 0xe7, 0x4a, 0x64, 0x47, 0x3b,
-	0x98, 0x0a, 0x42, 0x3f, 0x90, 0xa1, 0x4a, 0x78, 0xd3, 0x6e, 0x16, 0x73, 0x8e, 0x46, 0x02, 0xa5,
-	0xab, 0x30, 0xd4, 0x22, 0x25, 0x14, 0xe1, 0xf4, 0x9a, 0xef, 0xa3, 0x75, 0xe8, 0xdf, 0x05, 0xe2,
-	0x3a, 0x53, 0x6c, 0x63, 0x3c, 0x52, 0x22, 0x54, 0xe9, 0x85, 0x75, 0x10, 0xef, 0x76, 0xbd, 0x3b,
-	0x4f, 0xa5, 0x17, 0xd2, 0x01, 0x9f, 0xc3, 0x4c, 0xc6, 0xa6, 0xfa, 0x5a, 0x68, 0x1c, 0x76, 0xfd,
-	0x28, 0xbd, 0x13, 0x9f, 0x41, 0x93, 0x62, 0x02, 0x28, 0xcc, 0x5d, 0x86, 0x9e, 0x92, 0x07, 0x42,
-	0x5d, 0xdf, 0xa6, 0x87, 0x3e, 0x47, 0x2d, 0xe8, 0xab, 0xf8, 0x9d, 0xaa, 0x5b, 0xf5, 0x9d, 0xf9,
-	0xed, 0xfc, 0xc1, 0xb7, 0xf3, 0x3e, 0xbb, 0x1a, 0xc3, 0x76, 0x31, 0xd9, 0xd1, 0x3d, 0x89, 0x16,
-	0x2b, 0x1a, 0xbe, 0x64, 0xc2, 0xad, 0x8e, 0xba, 0x29, 0x92, 0xb7, 0xc1, 0xcc, 0x53, 0xa9, 0x96,
-	0x36, 0xd8, 0xa9, 0xfc, 0xf1, 0x41, 0xc9, 0x50, 0x28, 0x3f, 0x4c, 0x4b, 0xbe, 0xc0, 0x13, 0x63,
-	0xf7, 0xd2, 0x53, 0xb7, 0xe7, 0x71, 0x0f, 0xde, 0xf5, 0x3a, 0x67, 0xa1, 0xbc, 0xf1, 0xee, 0x47,
-	0x77, 0x6a, 0x1e, 0x13, 0x81, 0x86, 0x51, 0xab, 0x28, 0xe2, 0xaf, 0xb1, 0xf9, 0x17, 0x55, 0x7a,
-	0xa6, 0x16, 0x2a, 0x5e, 0x47, 0x6b, 0xd6, 0xdc, 0x8a, 0xd7, 0xe1, 0x9b, 0x78, 0x90, 0xb2, 0x4e,
-	0xe5, 0x7d, 0xf6, 0x4e, 0x83, 0xb0, 0x2f, 0x68, 0xdb, 0x30, 0x92, 0x7b, 0x8c, 0xe9, 0x1b, 0xbf,
-	0xdf, 0xeb, 0xc4, 0x9b, 0xe4, 0x83, 0x7c, 0x23, 0x1d, 0xb9, 0x4a, 0xc1, 0xe8, 0x56, 0xcd, 0xd1,
-	0x7d, 0x9a, 0xab, 0xbf, 0x0f, 0xc3, 0xac, 0x57, 0x43, 0x55, 0x2c, 0xe0, 0xe1, 0x00, 0x8e, 0x5a,
-	0xbd, 0x85, 0xf9, 0xbc, 0xcf, 0x5d, 0x29, 0xb2, 0x09, 0x19, 0x92, 0x58, 0xc2, 0xc2, 0x10, 0x32,
-	0x11, 0xd9, 0xf9, 0x3d, 0x89, 0xa9, 0x23, 0xa1, 0xc4, 0x95, 0x88, 0x24, 0xdb, 0x43, 0xf5, 0x44,
-	0x44, 0xcc, 0x1a, 0x96, 0xfc, 0x6b, 0x70, 0x16, 0x86, 0xf6, 0xa9, 0x21, 0x7b, 0xa8, 0x1e, 0x4b,
-	0x65, 0xf3, 0xf2, 0x1f, 0xc1, 0xe6, 0x99, 0x3f, 0xc1, 0x1e, 0xaa, 0x67, 0xfd, 0x01, 0x5e, 0x3e,
-	0x9c, 0x36, 0xcf, 0xb0, 0x39, 0x7b, 0x8b, 0x89, 0x64, 0x28, 0x59, 0xf9, 0xa0, 0x3a, 0x4e, 0x51,
-	0x8a, 0x04, 0xde, 0xa0, 0x16, 0xfb, 0x98, 0x59, 0x27, 0x18, 0x9f, 0x80, 0xb3, 0x38, 0x9c, 0x20,
-	0xea, 0x01, 0x26, 0xc9, 0xa0, 0xcc, 0x3a, 0xc1, 0xf6, 0xbc, 0xf3, 0xa8, 0x30, 0x47, 0x1a, 0xfb,
-	0x18, 0xd7, 0x0e, 0x66, 0xd6, 0x31, 0xa6, 0xc9, 0x9d, 0xa5, 0x82, 0x0c, 0xb1, 0x3f, 0x02, 0xb9,
-	0xf1, 0xd8, 0xb2, 0x09, 0x1c, 0xfa, 0x06, 0x9c, 0x95, 0xb2, 0x34, 0x89, 0xfd, 0x1a, 0xc3, 0xf2,
-	0x48, 0x13, 0xb1, 0x17, 0xa6, 0xc2, 0xbf, 0xb8, 0xd8, 0x79, 0xf9, 0x1f, 0x0c, 0x2a, 0xe3, 0x13,
-	0x1a, 0xa6, 0xd5, 0xd8, 0xaa, 0x29, 0x51, 0xe0, 0x55, 0x67, 0xad, 0x1c, 0x40, 0x92, 0x17, 0x68,
-	0x5a, 0xbe, 0x61, 0x85, 0x14, 0xd3, 0x7a, 0xce, 0xfa, 0x08, 0x04, 0xa9, 0x7e, 0xc6, 0xcc, 0x80,
-	0x95, 0x18, 0x2f, 0x62, 0xd9, 0x8e, 0x74, 0x36, 0x46, 0x62, 0x12, 0xed, 0xab, 0x09, 0x9d, 0xde,
-	0xfd, 0x13, 0x00, 0x00, 0xff, 0xff, 0x91, 0xec, 0x29, 0xb2, 0xaa, 0x07, 0x00, 0x00,
+	// 781 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0x5b, 0x4f, 0x1b, 0x39,
+	0x18, 0x55, 0x2e, 0xdc, 0xce, 0xe4, 0x02, 0xde, 0x6c, 0x02, 0xde, 0xe5, 0x36, 0x2c, 0xab, 0xec,
+	0xaa, 0x42, 0x2d, 0x54, 0xb4, 0x95, 0x90, 0xaa, 0x02, 0x2d, 0x54, 0x95, 0x50, 0x1a, 0x90, 0x90,
+	0x50, 0x5f, 0x0c, 0x31, 0x4a, 0xd4, 0x90, 0x99, 0xce, 0x38, 0x2d, 0x7d, 0xef, 0x4f, 0xe8, 0xcf,
+	0xeb, 0x8f, 0xa9, 0xc6, 0xf1, 0x64, 0xec, 0xb9, 0x84, 0xf4, 0xa1, 0x6f, 0x63, 0x7f, 0xe7, 0x1c,
+	0x7b, 0x8e, 0xbf, 0xef, 0xc0, 0xf2, 0xdc, 0x9b, 0xce, 0xf5, 0x8e, 0xeb, 0x39, 0xc2, 0x21, 0x90,
+	0x0b, 0xf9, 0x6d, 0xaf, 0x01, 0xa7, 0xcc, 0x6f, 0xf3, 0x4f, 0x43, 0xee, 0x0b, 0xb2, 0x88, 0xc2,
+	0x47, 0xfe, 0x75, 0x39, 0xb7, 0x91, 0x6b, 0x96, 0xda, 0xc1, 0xa7, 0xbd, 0x0e, 0x4b, 0xd6, 0x7d,
+	0xd7, 0x19, 0xf8, 0x3c, 0x00, 0x74, 0x99, 0x2f, 0x01, 0xf3, 0xed, 0xe0, 0x33, 0x10, 0x38, 0xe1,
+	0x22, 0x5b, 0x60, 0x0b, 0x96, 0xac, 0x2b, 0x81, 0x1a, 0x66, 0x3e, 0xb3, 0xfe, 0x90, 0x2b, 0xc8,
+	0x68, 0x61, 0x3f, 0x05, 0x5a, 0xc3, 0x6c, 0x91, 0x88, 0x95, 0xd7, 0x59, 0x65, 0x58, 0xad, 0xe1,
+	0x58, 0xda, 0xde, 0x44, 0xf9, 0x98, 0xf7, 0xb9, 0xe0, 0xd9, 0x97, 0x59, 0x44, 0x25, 0x84, 0x28,
+	0xd2, 0x7f, 0xb0, 0xce, 0x05, 0x1b, 0x1f, 0x4d, 0x31, 0xef, 0x7a, 0x8e, 0xcb, 0x3d, 0x31, 0xe2,
+	0x2d, 0xb4, 0xc7, 0x6b, 0xdb, 0x46, 0x69, 0x04, 0x55, 0xbf, 0x42, 0x50, 0xf4, 0x05, 0x13, 0x0a,
+	0x27, 0xbf, 0xed, 0x03, 0x54, 0x8e, 0x9c, 0x3b, 0x97, 0xdd, 0x8c, 0x15, 0x6b, 0x98, 0xf1, 0x05,
+	0xf3, 0x44, 0xf8, 0xc3, 0x72, 0x11, 0xec, 0xf6, 0x7b, 0x77, 0x3d, 0x11, 0xfe, 0x90, 0x5c, 0xd8,
+	0x4b, 0xa8, 0x8e, 0xd9, 0xea, 0x7e, 0x15, 0x94, 0x8e, 0xfa, 0x8e, 0x1f, 0xfe, 0x93, 0x5d, 0x45,
+	0x59, 0xad, 0x15, 0x40, 0x60, 0xe9, 0xd2, 0xeb, 0x09, 0x7e, 0xc8, 0xc4, 0x4d, 0x37, 0x3c, 0xf4,
+	0x7f, 0x14, 0xdd, 0xa1, 0x08, 0xde, 0xa9, 0xd0, 0xb4, 0x76, 0xeb, 0x3b, 0xd1, 0x83, 0xef, 0x44,
+	0x3e, 0xb7, 0x25, 0x86, 0xec, 0x61, 0xae, 0x23, 0x3d, 0xf1, 0x97, 0xf3, 0x12, 0xbe, 0xa2, 0xc3,
+	0x0d, 0x47, 0xdb, 0x21, 0xd2, 0xae, 0x81, 0xe8, 0xa7, 0xaa, 0xbb, 0xd4, 0x40, 0xce, 0xf8, 0x97,
+	0xb7, 0x82, 0x7b, 0x4c, 0x38, 0x5e, 0x78, 0xe5, 0x0b, 0xfc, 0xa3, 0xed, 0x5e, 0xf6, 0x44, 0xf7,
+	0x3c, 0xf0, 0xe0, 0xd5, 0xa0, 0xd3, 0xf2, 0xf8, 0x6d, 0xef, 0x7e, 0xb2, 0x53, 0x75, 0xcc, 0xba,
+	0x12, 0xa6, 0xac, 0x52, 0x2b, 0xfb, 0x19, 0xb6, 0x1f, 0x50, 0x55, 0xcf, 0x54, 0x41, 0xbe, 0xd7,
+	0x91, 0x9a, 0xc5, 0x76, 0xbe, 0xd7, 0xb1, 0xb7, 0xf1, 0x47, 0xc8, 0x3a, 0xe3, 0xf7, 0xe3, 0x77,
+	0x8a, 0xc3, 0x3e, 0xa0, 0x66, 0xc2, 0x94, 0xdc, 0xdf, 0x58, 0xb8, 0x75, 0x86, 0x83, 0x4e, 0xb0,
+	0xa9, 0xe6, 0x20, 0xda, 0x08, 0x5b, 0x2e, 0x9f, 0xd2, 0xba, 0x05, 0xbd, 0x75, 0xff, 0x8d, 0xd4,
+	0x5f, 0x7b, 0x9e, 0xe3, 0x65, 0xdd, 0xa2, 0x81, 0x3f, 0x63, 0x38, 0x65, 0x75, 0x13, 0xf5, 0xc8,
+	0xe7, 0x3e, 0x67, 0x3e, 0xcf, 0x92, 0x58, 0x41, 0x23, 0x81, 0x34, 0xde, 0xeb, 0x7c, 0xc0, 0x5c,
+	0xbf, 0xeb, 0x84, 0x4e, 0x04, 0x06, 0x19, 0xbb, 0x19, 0x3e, 0xee, 0x83, 0x84, 0x18, 0x2d, 0x41,
+	0x62, 0xa8, 0xa4, 0x21, 0x3a, 0x4f, 0x0b, 0x8e, 0x87, 0x79, 0x7d, 0x3c, 0x0a, 0x79, 0x53, 0xb5,
+	0x53, 0x5c, 0x71, 0xdc, 0x5e, 0xf9, 0xf4, 0xf6, 0x2a, 0x18, 0xed, 0xd5, 0x44, 0x3d, 0x72, 0xe0,
+	0x21, 0x7f, 0x13, 0xc8, 0x91, 0x65, 0xbb, 0x3f, 0x80, 0xf9, 0x63, 0x26, 0xd8, 0x35, 0xf3, 0x39,
+	0xd9, 0x47, 0xe1, 0x94, 0xf9, 0xc4, 0x18, 0xc6, 0xc8, 0x38, 0xda, 0x48, 0xec, 0x2b, 0xdf, 0xf7,
+	0x51, 0x38, 0xe1, 0xc2, 0xe4, 0x45, 0xc6, 0xd1, 0x46, 0x62, 0x3f, 0xe2, 0xb5, 0x86, 0x82, 0x64,
+	0x0c, 0x3f, 0x6d, 0x24, 0xf6, 0x15, 0xef, 0x25, 0x66, 0x47, 0x43, 0x4f, 0xb2, 0x83, 0x80, 0xd2,
+	0xb4, 0x92, 0x12, 0x78, 0x81, 0x62, 0x90, 0x93, 0xc4, 0x38, 0x41, 0x0b, 0x59, 0xba, 0x9c, 0x2c,
+	0x28, 0xea, 0x21, 0xe6, 0x54, 0x00, 0x12, 0xe3, 0x04, 0x33, 0x53, 0xe9, 0x5f, 0xa9, 0x35, 0xa5,
+	0x71, 0x80, 0x19, 0x99, 0x90, 0xc4, 0x38, 0x46, 0x0f, 0x51, 0xba, 0x92, 0x52, 0x51, 0xec, 0x77,
+	0x40, 0x14, 0x6c, 0x64, 0x55, 0x07, 0x26, 0x62, 0x96, 0xae, 0x65, 0x95, 0x95, 0xd8, 0xb7, 0x1c,
+	0x56, 0x27, 0xf6, 0x2a, 0x79, 0xac, 0x2b, 0x4c, 0xd3, 0xd6, 0xf4, 0xc9, 0x2f, 0x30, 0xd4, 0x35,
+	0xde, 0xa3, 0xa4, 0x47, 0x19, 0x59, 0xd7, 0x25, 0x52, 0xb2, 0x90, 0x6e, 0x64, 0x03, 0x94, 0xe4,
+	0x05, 0xca, 0x46, 0x2e, 0x91, 0x54, 0x8a, 0x1e, 0x6d, 0x74, 0x73, 0x02, 0x42, 0xa9, 0x5e, 0xa1,
+	0x1a, 0x8b, 0x2a, 0x62, 0xa7, 0xb1, 0xcc, 0x89, 0xa4, 0x5b, 0x13, 0x31, 0x4a, 0xfb, 0x0c, 0x96,
+	0x96, 0x6a, 0x64, 0x2d, 0x66, 0x63, 0x2c, 0x04, 0xe9, 0x7a, 0x66, 0x5d, 0xe9, 0xbd, 0x81, 0xa5,
+	0xc5, 0x9f, 0xa9, 0x97, 0xcc, 0xc5, 0xec, 0xf1, 0xd6, 0x74, 0x82, 0x31, 0x4f, 0xd5, 0x99, 0x66,
+	0xdc, 0xbf, 0xe7, 0xb0, 0x3d, 0x55, 0x3e, 0x92, 0xe7, 0x69, 0x47, 0xfc, 0xae, 0xde, 0xbb, 0x42,
+	0x35, 0x96, 0x8e, 0xe6, 0x93, 0xa6, 0x87, 0x2c, 0xdd, 0x9a, 0x88, 0x19, 0x69, 0x5f, 0xcf, 0xca,
+	0xf2, 0xde, 0xcf, 0x01, 0x00, 0x19, 0x5a, 0xc0, 0x8d, 0xdd, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1074,6 +1382,11 @@ type DatabaseClient interface {
 	IteratorNext(ctx context.Context, in *IteratorNextRequest, opts ...grpc.CallOption) (*IteratorNextResponse, error)
 	IteratorError(ctx context.Context, in *IteratorErrorRequest, opts ...grpc.CallOption) (*IteratorErrorResponse, error)
 	IteratorRelease(ctx context.Context, in *IteratorReleaseRequest, opts ...grpc.CallOption) (*IteratorReleaseResponse, error)
+	NewSnapshot(ctx context.Context, in *NewSnapshotRequest, opts ...grpc.CallOption) (*NewSnapshotResponse, error)
+	SnapshotHas(ctx context.Context, in *SnapshotHasRequest, opts ...grpc.CallOption) (*HasResponse, error)
+	SnapshotGet(ctx context.Context, in *SnapshotGetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	SnapshotNewIteratorWithStartAndPrefix(ctx context.Context, in *SnapshotNewIteratorWithStartAndPrefixRequest, opts ...grpc.CallOption) (*NewIteratorWithStartAndPrefixResponse, error)
+	SnapshotRelease(ctx context.Context, in *SnapshotReleaseRequest, opts ...grpc.CallOption) (*SnapshotReleaseResponse, error)
 }
 
 type databaseClient struct {
@@ -1192,6 +1505,51 @@ func (c *databaseClient) IteratorRelease(ctx context.Context, in *IteratorReleas
 	return out, nil
 }
 
+func (c *databaseClient) NewSnapshot(ctx context.Context, in *NewSnapshotRequest, opts ...grpc.CallOption) (*NewSnapshotResponse, error) {
+	out := new(NewSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/rpcdbproto.Database/NewSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) SnapshotHas(ctx context.Context, in *SnapshotHasRequest, opts ...grpc.CallOption) (*HasResponse, error) {
+	out := new(HasResponse)
+	err := c.cc.Invoke(ctx, "/rpcdbproto.Database/SnapshotHas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) SnapshotGet(ctx context.Context, in *SnapshotGetRequest, opts ...grpc.CallOption) (*GetResponse, error) {
+	out := new(GetResponse)
+	err := c.cc.Invoke(ctx, "/rpcdbproto.Database/SnapshotGet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) SnapshotNewIteratorWithStartAndPrefix(ctx context.Context, in *SnapshotNewIteratorWithStartAndPrefixRequest, opts ...grpc.CallOption) (*NewIteratorWithStartAndPrefixResponse, error) {
+	out := new(NewIteratorWithStartAndPrefixResponse)
+	err := c.cc.Invoke(ctx, "/rpcdbproto.Database/SnapshotNewIteratorWithStartAndPrefix", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *databaseClient) SnapshotRelease(ctx context.Context, in *SnapshotReleaseRequest, opts ...grpc.CallOption) (*SnapshotReleaseResponse, error) {
+	out := new(SnapshotReleaseResponse)
+	err := c.cc.Invoke(ctx, "/rpcdbproto.Database/SnapshotRelease", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DatabaseServer is the server API for Database service.
 type DatabaseServer interface {
 	Has(context.Context, *HasRequest) (*HasResponse, error)
@@ -1206,6 +1564,11 @@ type DatabaseServer interface {
 	IteratorNext(context.Context, *IteratorNextRequest) (*IteratorNextResponse, error)
 	IteratorError(context.Context, *IteratorErrorRequest) (*IteratorErrorResponse, error)
 	IteratorRelease(context.Context, *IteratorReleaseRequest) (*IteratorReleaseResponse, error)
+	NewSnapshot(context.Context, *NewSnapshotRequest) (*NewSnapshotResponse, error)
+	SnapshotHas(context.Context, *SnapshotHasRequest) (*HasResponse, error)
+	SnapshotGet(context.Context, *SnapshotGetRequest) (*GetResponse, error)
+	SnapshotNewIteratorWithStartAndPrefix(context.Context, *SnapshotNewIteratorWithStartAndPrefixRequest) (*NewIteratorWithStartAndPrefixResponse, error)
+	SnapshotRelease(context.Context, *SnapshotReleaseRequest) (*SnapshotReleaseResponse, error)
 }
 
 // UnimplementedDatabaseServer can be embedded to have forward compatible implementations.
@@ -1248,6 +1611,21 @@ func (*UnimplementedDatabaseServer) IteratorError(ctx context.Context, req *Iter
 func (*UnimplementedDatabaseServer) IteratorRelease(ctx context.Context, req *IteratorReleaseRequest) (*IteratorReleaseResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IteratorRelease not implemented")
 }
+func (*UnimplementedDatabaseServer) NewSnapshot(ctx context.Context, req *NewSnapshotRequest) (*NewSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewSnapshot not implemented")
+}
+func (*UnimplementedDatabaseServer) SnapshotHas(ctx context.Context, req *SnapshotHasRequest) (*HasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotHas not implemented")
+}
+func (*UnimplementedDatabaseServer) SnapshotGet(ctx context.Context, req *SnapshotGetRequest) (*GetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotGet not implemented")
+}
+func (*UnimplementedDatabaseServer) SnapshotNewIteratorWithStartAndPrefix(ctx context.Context, req *SnapshotNewIteratorWithStartAndPrefixRequest) (*NewIteratorWithStartAndPrefixResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotNewIteratorWithStartAndPrefix not implemented")
+}
+func (*UnimplementedDatabaseServer) SnapshotRelease(ctx context.Context, req *SnapshotReleaseRequest) (*SnapshotReleaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SnapshotRelease not implemented")
+}
 
 func RegisterDatabaseServer(s *grpc.Server, srv DatabaseServer) {
 	s.RegisterService(&_Database_serviceDesc, srv)
@@ -1469,6 +1847,96 @@ func _Database_IteratorRelease_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Database_NewSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).NewSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcdbproto.Database/NewSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).NewSnapshot(ctx, req.(*NewSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_SnapshotHas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotHasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).SnapshotHas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcdbproto.Database/SnapshotHas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).SnapshotHas(ctx, req.(*SnapshotHasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_SnapshotGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).SnapshotGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcdbproto.Database/SnapshotGet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).SnapshotGet(ctx, req.(*SnapshotGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_SnapshotNewIteratorWithStartAndPrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotNewIteratorWithStartAndPrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).SnapshotNewIteratorWithStartAndPrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcdbproto.Database/SnapshotNewIteratorWithStartAndPrefix",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).SnapshotNewIteratorWithStartAndPrefix(ctx, req.(*SnapshotNewIteratorWithStartAndPrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Database_SnapshotRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DatabaseServer).SnapshotRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcdbproto.Database/SnapshotRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DatabaseServer).SnapshotRelease(ctx, req.(*SnapshotReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Database_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rpcdbproto.Database",
 	HandlerType: (*DatabaseServer)(nil),
@@ -1521,6 +1989,26 @@ var _Database_serviceDesc = grpc.ServiceDesc{
 			MethodName: "IteratorRelease",
 			Handler:    _Database_IteratorRelease_Handler,
 		},
+		{
+			MethodName: "NewSnapshot",
+			Handler:    _Database_NewSnapshot_Handler,
+		},
+		{
+			MethodName: "SnapshotHas",
+			Handler:    _Database_SnapshotHas_Handler,
+		},
+		{
+			MethodName: "SnapshotGet",
+			Handler:    _Database_SnapshotGet_Handler,
+		},
+		{
+			MethodName: "SnapshotNewIteratorWithStartAndPrefix",
+			Handler:    _Database_SnapshotNewIteratorWithStartAndPrefix_Handler,
+		},
+		{
+			MethodName: "SnapshotRelease",
+			Handler:    _Database_SnapshotRelease_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpcdb.proto",
@@ -0,0 +1,40 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+import (
+	"bytes"
+)
+
+// CountWithIterator returns the number of keys in [db] that start with
+// [prefix], by iterating over them. It can be used to implement Count by
+// databases that don't track the number of keys they hold.
+func CountWithIterator(db Iteratee, prefix []byte) (int, error) {
+	it := db.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+	return count, it.Error()
+}
+
+// SizeWithIterator returns the total length of the keys and values in [db] in
+// the range [start, limit), by iterating over them. It can be used to
+// implement SizeEstimate by databases that can't estimate their size on disk.
+func SizeWithIterator(db Iteratee, start, limit []byte) (uint64, error) {
+	it := db.NewIteratorWithStart(start)
+	defer it.Release()
+
+	size := uint64(0)
+	for it.Next() {
+		key := it.Key()
+		if limit != nil && bytes.Compare(key, limit) >= 0 {
+			break
+		}
+		size += uint64(len(key) + len(it.Value()))
+	}
+	return size, it.Error()
+}
@@ -0,0 +1,25 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package database
+
+// Snapshot is a read-only view of a database at the time the snapshot was
+// taken. Writes made to the database after the snapshot was taken aren't
+// visible through it, which allows multiple reads to see a consistent state.
+//
+// A snapshot must be released after use. Once it's released, reads from it
+// return ErrClosed. A snapshot is safe for concurrent use.
+type Snapshot interface {
+	KeyValueReader
+	Iteratee
+
+	// Release releases associated resources. Release should always succeed
+	// and can be called multiple times without causing error.
+	Release()
+}
+
+// Snapshotter wraps the NewSnapshot method of a backing data store.
+type Snapshotter interface {
+	// NewSnapshot returns a snapshot of the current state of the data store.
+	NewSnapshot() (Snapshot, error)
+}
@@ -0,0 +1,144 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshotdb
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+var errReadOnly = errors.New("database is read only")
+
+// Database implements the Database interface on top of a snapshot, so that
+// code written against a Database can read a consistent view of the database
+// the snapshot was taken from. Writes always fail. Closing the database
+// releases the snapshot.
+type Database struct{ snapshot database.Snapshot }
+
+// New returns a read only database backed by [snapshot]
+func New(snapshot database.Snapshot) *Database { return &Database{snapshot: snapshot} }
+
+// Has implements the Database interface
+func (db *Database) Has(key []byte) (bool, error) { return db.snapshot.Has(key) }
+
+// Get implements the Database interface
+func (db *Database) Get(key []byte) ([]byte, error) { return db.snapshot.Get(key) }
+
+// Put returns an error
+func (*Database) Put(_, _ []byte) error { return errReadOnly }
+
+// Delete returns an error
+func (*Database) Delete([]byte) error { return errReadOnly }
+
+// NewBatch returns a batch that can't be written
+func (*Database) NewBatch() database.Batch { return &batch{} }
+
+// NewIterator implements the Database interface
+func (db *Database) NewIterator() database.Iterator { return db.snapshot.NewIterator() }
+
+// NewIteratorWithStart implements the Database interface
+func (db *Database) NewIteratorWithStart(start []byte) database.Iterator {
+	return db.snapshot.NewIteratorWithStart(start)
+}
+
+// NewIteratorWithPrefix implements the Database interface
+func (db *Database) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return db.snapshot.NewIteratorWithPrefix(prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the Database interface
+func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	return db.snapshot.NewIteratorWithStartAndPrefix(start, prefix)
+}
+
+// Stat implements the Database interface. Snapshots don't have any stats.
+func (*Database) Stat(string) (string, error) { return "", database.ErrNotFound }
+
+// Compact returns an error
+func (*Database) Compact(_, _ []byte) error { return errReadOnly }
+
+// Count implements the Database interface
+func (db *Database) Count(prefix []byte) (int, error) {
+	return database.CountWithIterator(db, prefix)
+}
+
+// SizeEstimate implements the Database interface. The estimate is the total
+// length of the keys and values in the range.
+func (db *Database) SizeEstimate(start, limit []byte) (uint64, error) {
+	return database.SizeWithIterator(db, start, limit)
+}
+
+// NewSnapshot implements the Database interface. The snapshot never changes,
+// so the returned snapshot shares it, and is only usable until this database
+// is closed.
+func (db *Database) NewSnapshot() (database.Snapshot, error) { return &view{db.snapshot}, nil }
+
+// Close releases the snapshot
+func (db *Database) Close() error {
+	db.snapshot.Release()
+	return nil
+}
+
+// batch queues writes, but can't write them
+type batch struct {
+	writes []keyValue
+	size   int
+}
+
+type keyValue struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+// Put implements the Batch interface
+func (b *batch) Put(key, value []byte) error {
+	b.writes = append(b.writes, keyValue{key: key, value: value})
+	b.size += len(value)
+	return nil
+}
+
+// Delete implements the Batch interface
+func (b *batch) Delete(key []byte) error {
+	b.writes = append(b.writes, keyValue{key: key, delete: true})
+	b.size++
+	return nil
+}
+
+// ValueSize implements the Batch interface
+func (b *batch) ValueSize() int { return b.size }
+
+// Write returns an error
+func (*batch) Write() error { return errReadOnly }
+
+// Reset implements the Batch interface
+func (b *batch) Reset() {
+	b.writes = b.writes[:0]
+	b.size = 0
+}
+
+// Replay implements the Batch interface
+func (b *batch) Replay(w database.KeyValueWriter) error {
+	for _, kv := range b.writes {
+		if kv.delete {
+			if err := w.Delete(kv.key); err != nil {
+				return err
+			}
+		} else if err := w.Put(kv.key, kv.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inner returns itself
+func (b *batch) Inner() database.Batch { return b }
+
+// view is a snapshot that is released along with the database it came from
+type view struct{ database.Snapshot }
+
+// Release does nothing, as the snapshot is released when the database is
+// closed
+func (*view) Release() {}
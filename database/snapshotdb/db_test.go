@@ -0,0 +1,96 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snapshotdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+)
+
+func TestReadOnly(t *testing.T) {
+	baseDB := memdb.New()
+	key := []byte("hello")
+	value := []byte("world")
+	if err := baseDB.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := baseDB.NewSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := New(snapshot)
+
+	if err := baseDB.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, err := db.Get(key); err != nil {
+		t.Fatalf("Unexpected error on db.Get: %s", err)
+	} else if !bytes.Equal(v, value) {
+		t.Fatalf("db.Get: Returned: 0x%x ; Expected: 0x%x", v, value)
+	}
+	if count, err := db.Count(nil); err != nil {
+		t.Fatalf("Unexpected error on db.Count: %s", err)
+	} else if count != 1 {
+		t.Fatalf("db.Count returned %d but expected 1", count)
+	}
+
+	if err := db.Put(key, value); err != errReadOnly {
+		t.Fatalf("Expected error %s on db.Put but got %s", errReadOnly, err)
+	}
+	if err := db.Delete(key); err != errReadOnly {
+		t.Fatalf("Expected error %s on db.Delete but got %s", errReadOnly, err)
+	}
+	batch := db.NewBatch()
+	if err := batch.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != errReadOnly {
+		t.Fatalf("Expected error %s on batch.Write but got %s", errReadOnly, err)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Get(key); err != database.ErrClosed {
+		t.Fatalf("Expected error %s on db.Get after close but got %s", database.ErrClosed, err)
+	}
+}
+
+func TestWrapped(t *testing.T) {
+	baseDB := memdb.New()
+	prefixed := prefixdb.New([]byte("prefix"), baseDB)
+	key := []byte("hello")
+	value := []byte("world")
+	if err := prefixed.Put(key, value); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := baseDB.NewSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := New(snapshot)
+	defer db.Close()
+
+	// Databases can be layered on top of the snapshot
+	it := prefixdb.New([]byte("prefix"), db).NewIterator()
+	defer it.Release()
+
+	if !it.Next() {
+		t.Fatalf("Expected the iterator to have a key")
+	} else if k := it.Key(); !bytes.Equal(k, key) {
+		t.Fatalf("it.Key: Returned: 0x%x ; Expected: 0x%x", k, key)
+	} else if v := it.Value(); !bytes.Equal(v, value) {
+		t.Fatalf("it.Value: Returned: 0x%x ; Expected: 0x%x", v, value)
+	}
+	if it.Next() {
+		t.Fatalf("Expected the iterator to be exhausted")
+	}
+}
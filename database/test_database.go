@@ -5,6 +5,8 @@ package database
 
 import (
 	"bytes"
+	"fmt"
+	"math/rand"
 	"testing"
 )
 
@@ -20,6 +22,7 @@ var (
 		TestBatchRewrite,
 		TestBatchReplay,
 		TestBatchInner,
+		TestFlushingBatch,
 		TestIterator,
 		TestIteratorStart,
 		TestIteratorPrefix,
@@ -28,6 +31,10 @@ var (
 		TestIteratorClosed,
 		TestStatNoPanic,
 		TestCompactNoPanic,
+		TestCompactRange,
+		TestCount,
+		TestSizeEstimate,
+		TestSnapshot,
 	}
 )
 
@@ -460,6 +467,56 @@ func TestBatchInner(t *testing.T, db Database) {
 	}
 }
 
+// TestFlushingBatch ...
+func TestFlushingBatch(t *testing.T, db Database) {
+	const numKeys = 100
+	keys := make([][]byte, numKeys)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("key%03d", i))
+	}
+	value := []byte("value")
+	// Flush after every 3 puts
+	limit := 3 * (len(keys[0]) + len(value))
+
+	batch := NewFlushingBatch(db.NewBatch(), limit)
+	for i, key := range keys {
+		if err := batch.Put(key, value); err != nil {
+			t.Fatalf("Unexpected error on batch.Put: %s", err)
+		}
+
+		flushed := (i + 1) / 3 * 3
+		for j, key := range keys[:i+1] {
+			if has, err := db.Has(key); err != nil {
+				t.Fatalf("Unexpected error on db.Has: %s", err)
+			} else if has != (j < flushed) {
+				t.Fatalf("After %d puts, db.Has returned %v on key %s", i+1, has, key)
+			}
+		}
+	}
+
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Unexpected error on batch.Write: %s", err)
+	}
+	for _, key := range keys {
+		if v, err := db.Get(key); err != nil {
+			t.Fatalf("Unexpected error on db.Get: %s", err)
+		} else if !bytes.Equal(value, v) {
+			t.Fatalf("db.Get: Returned: 0x%x ; Expected: 0x%x", v, value)
+		}
+	}
+
+	// Deletes count towards the limit as well
+	batch = NewFlushingBatch(db.NewBatch(), len(keys[0]))
+	if err := batch.Delete(keys[0]); err != nil {
+		t.Fatalf("Unexpected error on batch.Delete: %s", err)
+	}
+	if has, err := db.Has(keys[0]); err != nil {
+		t.Fatalf("Unexpected error on db.Has: %s", err)
+	} else if has {
+		t.Fatalf("db.Has unexpectedly returned true on key %s", keys[0])
+	}
+}
+
 // TestIterator ...
 func TestIterator(t *testing.T, db Database) {
 	key1 := []byte("hello1")
@@ -775,3 +832,227 @@ func TestCompactNoPanic(t *testing.T, db Database) {
 		t.Fatalf("Expected error %s on db.Close but got %s", ErrClosed, err)
 	}
 }
+
+// TestCompactRange ...
+func TestCompactRange(t *testing.T, db Database) {
+	numKeys := 100
+	key := func(i int) []byte { return []byte(fmt.Sprintf("key%03d", i)) }
+	value := func(i int) []byte { return []byte(fmt.Sprintf("value%03d", i)) }
+
+	for i := 0; i < numKeys; i++ {
+		if err := db.Put(key(i), value(i)); err != nil {
+			t.Fatalf("Unexpected error on db.Put: %s", err)
+		}
+	}
+
+	// Prune a range of keys, as is done when old blocks are pruned
+	pruneStart, pruneEnd := 20, 80
+	for i := pruneStart; i < pruneEnd; i++ {
+		if err := db.Delete(key(i)); err != nil {
+			t.Fatalf("Unexpected error on db.Delete: %s", err)
+		}
+	}
+
+	if err := db.Compact(key(pruneStart), key(pruneEnd)); err != nil {
+		t.Fatalf("Unexpected error on db.Compact: %s", err)
+	}
+
+	for i := 0; i < numKeys; i++ {
+		v, err := db.Get(key(i))
+		switch pruned := i >= pruneStart && i < pruneEnd; {
+		case pruned && err != ErrNotFound:
+			t.Fatalf("Expected %s on db.Get for pruned key %s. Returned 0x%x", ErrNotFound, key(i), v)
+		case !pruned && err != nil:
+			t.Fatalf("Unexpected error on db.Get: %s", err)
+		case !pruned && !bytes.Equal(v, value(i)):
+			t.Fatalf("db.Get: Returned: 0x%x ; Expected: 0x%x", v, value(i))
+		}
+	}
+
+	iterator := db.NewIterator()
+	defer iterator.Release()
+
+	numIterated := 0
+	for iterator.Next() {
+		numIterated++
+	}
+	if err := iterator.Error(); err != nil {
+		t.Fatalf("Unexpected error on iterator.Error: %s", err)
+	}
+	if expected := numKeys - (pruneEnd - pruneStart); numIterated != expected {
+		t.Fatalf("Iterated over %d keys after compaction but expected %d", numIterated, expected)
+	}
+}
+
+// TestCount ...
+func TestCount(t *testing.T, db Database) {
+	for i := 0; i < 10; i++ {
+		if err := db.Put([]byte(fmt.Sprintf("a%d", i)), []byte("value")); err != nil {
+			t.Fatalf("Unexpected error on db.Put: %s", err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if err := db.Put([]byte(fmt.Sprintf("b%d", i)), []byte("value")); err != nil {
+			t.Fatalf("Unexpected error on db.Put: %s", err)
+		}
+	}
+	if err := db.Delete([]byte("a0")); err != nil {
+		t.Fatalf("Unexpected error on db.Delete: %s", err)
+	}
+
+	tests := []struct {
+		prefix   []byte
+		expected int
+	}{
+		{prefix: []byte("a"), expected: 9},
+		{prefix: []byte("b"), expected: 5},
+		{prefix: []byte("b1"), expected: 1},
+		{prefix: []byte("c"), expected: 0},
+		{prefix: nil, expected: 14},
+	}
+	for _, test := range tests {
+		if count, err := db.Count(test.prefix); err != nil {
+			t.Fatalf("Unexpected error on db.Count: %s", err)
+		} else if count != test.expected {
+			t.Fatalf("db.Count(%q) returned %d but expected %d", test.prefix, count, test.expected)
+		}
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("Unexpected error on db.Close: %s", err)
+	}
+	if _, err := db.Count(nil); err != ErrClosed {
+		t.Fatalf("Expected error %s on db.Count but got %s", ErrClosed, err)
+	}
+}
+
+// TestSizeEstimate ...
+func TestSizeEstimate(t *testing.T, db Database) {
+	numKeys, valueSize := 100, 1024
+	key := func(i int) []byte { return []byte(fmt.Sprintf("key%03d", i)) }
+
+	// Random values can't be compressed, so the size on disk should be close
+	// to the size of the data
+	r := rand.New(rand.NewSource(0))
+	totalSize := uint64(0)
+	for i := 0; i < numKeys; i++ {
+		value := make([]byte, valueSize)
+		_, _ = r.Read(value)
+		if err := db.Put(key(i), value); err != nil {
+			t.Fatalf("Unexpected error on db.Put: %s", err)
+		}
+		totalSize += uint64(len(key(i)) + len(value))
+	}
+
+	// Make sure the writes have been persisted
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatalf("Unexpected error on db.Compact: %s", err)
+	}
+
+	size, err := db.SizeEstimate(nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error on db.SizeEstimate: %s", err)
+	}
+	if size < totalSize/2 || size > 2*totalSize {
+		t.Fatalf("db.SizeEstimate returned %d but %d bytes were written", size, totalSize)
+	}
+
+	halfSize, err := db.SizeEstimate(nil, key(numKeys/2))
+	if err != nil {
+		t.Fatalf("Unexpected error on db.SizeEstimate: %s", err)
+	}
+	if halfSize > size {
+		t.Fatalf("db.SizeEstimate of half the keys returned %d but all the keys returned %d", halfSize, size)
+	}
+
+	emptySize, err := db.SizeEstimate(key(numKeys/2), key(numKeys/2))
+	if err != nil {
+		t.Fatalf("Unexpected error on db.SizeEstimate: %s", err)
+	}
+	if emptySize != 0 {
+		t.Fatalf("db.SizeEstimate of an empty range returned %d", emptySize)
+	}
+}
+
+// TestSnapshot ...
+func TestSnapshot(t *testing.T, db Database) {
+	key1 := []byte("hello1")
+	value1 := []byte("world1")
+	key2 := []byte("hello2")
+	value2 := []byte("world2")
+	key3 := []byte("hello3")
+	value3 := []byte("world3")
+
+	if err := db.Put(key1, value1); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+	if err := db.Put(key2, value2); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	snapshot, err := db.NewSnapshot()
+	if err != nil {
+		t.Fatalf("Unexpected error on db.NewSnapshot: %s", err)
+	}
+	defer snapshot.Release()
+
+	// None of these writes should be visible through the snapshot
+	if err := db.Put(key1, value2); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+	if err := db.Delete(key2); err != nil {
+		t.Fatalf("Unexpected error on db.Delete: %s", err)
+	}
+	if err := db.Put(key3, value3); err != nil {
+		t.Fatalf("Unexpected error on db.Put: %s", err)
+	}
+
+	if v, err := snapshot.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on snapshot.Get: %s", err)
+	} else if !bytes.Equal(value1, v) {
+		t.Fatalf("snapshot.Get: Returned: 0x%x ; Expected: 0x%x", v, value1)
+	}
+	if has, err := snapshot.Has(key2); err != nil {
+		t.Fatalf("Unexpected error on snapshot.Has: %s", err)
+	} else if !has {
+		t.Fatalf("snapshot.Has unexpectedly returned false on key %s", key2)
+	}
+	if has, err := snapshot.Has(key3); err != nil {
+		t.Fatalf("Unexpected error on snapshot.Has: %s", err)
+	} else if has {
+		t.Fatalf("snapshot.Has unexpectedly returned true on key %s", key3)
+	}
+	if _, err := snapshot.Get(key3); err != ErrNotFound {
+		t.Fatalf("Expected error %s on snapshot.Get but got %s", ErrNotFound, err)
+	}
+
+	iterator := snapshot.NewIteratorWithPrefix([]byte("hello"))
+	expected := [][2][]byte{{key1, value1}, {key2, value2}}
+	for _, kv := range expected {
+		if !iterator.Next() {
+			t.Fatalf("iterator.Next Returned: %v ; Expected: %v", false, true)
+		} else if key := iterator.Key(); !bytes.Equal(key, kv[0]) {
+			t.Fatalf("iterator.Key Returned: 0x%x ; Expected: 0x%x", key, kv[0])
+		} else if value := iterator.Value(); !bytes.Equal(value, kv[1]) {
+			t.Fatalf("iterator.Value Returned: 0x%x ; Expected: 0x%x", value, kv[1])
+		}
+	}
+	if iterator.Next() {
+		t.Fatalf("iterator.Next Returned: %v ; Expected: %v", true, false)
+	} else if err := iterator.Error(); err != nil {
+		t.Fatalf("iterator.Error Returned: %s ; Expected: nil", err)
+	}
+	iterator.Release()
+
+	// The database itself should see the new writes
+	if v, err := db.Get(key1); err != nil {
+		t.Fatalf("Unexpected error on db.Get: %s", err)
+	} else if !bytes.Equal(value2, v) {
+		t.Fatalf("db.Get: Returned: 0x%x ; Expected: 0x%x", v, value2)
+	}
+
+	snapshot.Release()
+	if _, err := snapshot.Get(key1); err != ErrClosed {
+		t.Fatalf("Expected error %s on snapshot.Get after release but got %s", ErrClosed, err)
+	}
+}
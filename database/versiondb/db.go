@@ -117,26 +117,7 @@ func (db *Database) NewIteratorWithStartAndPrefix(start, prefix []byte) database
 	if db.mem == nil {
 		return &nodb.Iterator{Err: database.ErrClosed}
 	}
-
-	startString := string(start)
-	prefixString := string(prefix)
-	keys := make([]string, 0, len(db.mem))
-	for key := range db.mem {
-		if strings.HasPrefix(key, prefixString) && key >= startString {
-			keys = append(keys, key)
-		}
-	}
-	sort.Strings(keys) // Keys need to be in sorted order
-	values := make([]valueDelete, 0, len(keys))
-	for _, key := range keys {
-		values = append(values, db.mem[key])
-	}
-
-	return &iterator{
-		Iterator: db.db.NewIteratorWithStartAndPrefix(start, prefix),
-		keys:     keys,
-		values:   values,
-	}
+	return newIterator(db.mem, db.db.NewIteratorWithStartAndPrefix(start, prefix), start, prefix)
 }
 
 // Stat implements the database.Database interface
@@ -161,6 +142,57 @@ func (db *Database) Compact(start, limit []byte) error {
 	return db.db.Compact(start, limit)
 }
 
+// Count implements the database.Database interface. Pending writes are
+// included in the count.
+func (db *Database) Count(prefix []byte) (int, error) {
+	return database.CountWithIterator(db, prefix)
+}
+
+// SizeEstimate implements the database.Database interface. Pending deletes
+// aren't subtracted from the underlying database's estimate.
+func (db *Database) SizeEstimate(start, limit []byte) (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.mem == nil {
+		return 0, database.ErrClosed
+	}
+	size, err := db.db.SizeEstimate(start, limit)
+	if err != nil {
+		return 0, err
+	}
+	for key, val := range db.mem {
+		if !val.delete && key >= string(start) && (limit == nil || key < string(limit)) {
+			size += uint64(len(key) + len(val.value))
+		}
+	}
+	return size, nil
+}
+
+// NewSnapshot implements the database.Database interface. Writes that haven't
+// been committed yet are visible through the snapshot.
+func (db *Database) NewSnapshot() (database.Snapshot, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.mem == nil {
+		return nil, database.ErrClosed
+	}
+	snap, err := db.db.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	mem := make(map[string]valueDelete, len(db.mem))
+	for key, val := range db.mem {
+		mem[key] = val
+	}
+	return &snapshot{
+		mem:      mem,
+		snapshot: snap,
+	}, nil
+}
+
 // SetDatabase changes the underlying database to the specified database
 func (db *Database) SetDatabase(newDB database.Database) error {
 	db.lock.Lock()
@@ -330,6 +362,80 @@ func (b *batch) Replay(w database.KeyValueWriter) error {
 // Inner returns itself
 func (b *batch) Inner() database.Batch { return b }
 
+// snapshot is a copy of the uncommitted writes on top of a snapshot of the
+// underlying database
+type snapshot struct {
+	lock     sync.RWMutex
+	mem      map[string]valueDelete
+	snapshot database.Snapshot
+}
+
+// Has implements the database.Snapshot interface
+func (s *snapshot) Has(key []byte) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil {
+		return false, database.ErrClosed
+	}
+	if val, has := s.mem[string(key)]; has {
+		return !val.delete, nil
+	}
+	return s.snapshot.Has(key)
+}
+
+// Get implements the database.Snapshot interface
+func (s *snapshot) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil {
+		return nil, database.ErrClosed
+	}
+	if val, has := s.mem[string(key)]; has {
+		if val.delete {
+			return nil, database.ErrNotFound
+		}
+		return utils.CopyBytes(val.value), nil
+	}
+	return s.snapshot.Get(key)
+}
+
+// NewIterator implements the database.Snapshot interface
+func (s *snapshot) NewIterator() database.Iterator { return s.NewIteratorWithStartAndPrefix(nil, nil) }
+
+// NewIteratorWithStart implements the database.Snapshot interface
+func (s *snapshot) NewIteratorWithStart(start []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(start, nil)
+}
+
+// NewIteratorWithPrefix implements the database.Snapshot interface
+func (s *snapshot) NewIteratorWithPrefix(prefix []byte) database.Iterator {
+	return s.NewIteratorWithStartAndPrefix(nil, prefix)
+}
+
+// NewIteratorWithStartAndPrefix implements the database.Snapshot interface
+func (s *snapshot) NewIteratorWithStartAndPrefix(start, prefix []byte) database.Iterator {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.mem == nil {
+		return &nodb.Iterator{Err: database.ErrClosed}
+	}
+	return newIterator(s.mem, s.snapshot.NewIteratorWithStartAndPrefix(start, prefix), start, prefix)
+}
+
+// Release implements the database.Snapshot interface
+func (s *snapshot) Release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.mem != nil {
+		s.mem = nil
+		s.snapshot.Release()
+	}
+}
+
 // iterator walks over both the in memory database and the underlying database
 // at the same time.
 type iterator struct {
@@ -343,6 +449,31 @@ type iterator struct {
 	initialized, exhausted bool
 }
 
+// newIterator returns an iterator over the keys of [mem] that are after [start]
+// and start with [prefix], merged with [it], which must iterate over the
+// underlying database with the same start and prefix.
+func newIterator(mem map[string]valueDelete, it database.Iterator, start, prefix []byte) *iterator {
+	startString := string(start)
+	prefixString := string(prefix)
+	keys := make([]string, 0, len(mem))
+	for key := range mem {
+		if strings.HasPrefix(key, prefixString) && key >= startString {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys) // Keys need to be in sorted order
+	values := make([]valueDelete, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, mem[key])
+	}
+
+	return &iterator{
+		Iterator: it,
+		keys:     keys,
+		values:   values,
+	}
+}
+
 // Next moves the iterator to the next key/value pair. It returns whether the
 // iterator is exhausted. We must pay careful attention to set the proper values
 // based on if the in memory db or the underlying db should be read next
@@ -6,9 +6,11 @@ package genesis
 import (
 	"fmt"
 	"math"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/constants"
+	"github.com/ava-labs/gecko/utils/forks"
 	"github.com/ava-labs/gecko/utils/formatting"
 )
 
@@ -24,6 +26,10 @@ type Config struct {
 	ParsedMintAddresses, ParsedFundedAddresses, ParsedStakerIDs []ids.ShortID
 	EVMBytes                                                    []byte
 	Message                                                     string
+
+	// ForkActivations maps the name of each network upgrade to the time it
+	// activates
+	ForkActivations map[string]time.Time
 }
 
 func (c *Config) init() error {
@@ -702,3 +708,9 @@ func GetConfig(networkID uint32) *Config {
 		return &CustomConfig
 	}
 }
+
+// ForkSchedule returns the times that network upgrades activate on the network
+// with ID [networkID]
+func ForkSchedule(networkID uint32) *forks.Schedule {
+	return forks.NewSchedule(GetConfig(networkID).ForkActivations)
+}
@@ -0,0 +1,104 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// BigBitSet is a set of uints that, unlike BitSet, isn't limited to the range
+// [0, 64). It uses one bit per index up to the largest index added, so it's
+// much smaller than a map-based set when the indices are dense, such as the
+// indices of validators in a fixed validator set. The zero value is the empty
+// set.
+type BigBitSet []uint64
+
+// NewBigBitSet returns an empty set with room for indices in [0, size) before
+// it needs to grow
+func NewBigBitSet(size uint) BigBitSet { return make(BigBitSet, 0, (size+63)/64) }
+
+// Add [i] to the set of ints
+func (bs *BigBitSet) Add(i uint) {
+	word := int(i / 64)
+	if word >= len(*bs) {
+		if word < cap(*bs) {
+			// Words past the length may hold elements that were since removed
+			oldLen := len(*bs)
+			*bs = (*bs)[:word+1]
+			for j := oldLen; j < word; j++ {
+				(*bs)[j] = 0
+			}
+			(*bs)[word] = 0
+		} else {
+			grown := make(BigBitSet, word+1)
+			copy(grown, *bs)
+			*bs = grown
+		}
+	}
+	(*bs)[word] |= 1 << (i % 64)
+}
+
+// Union adds all the elements in [s] to this set
+func (bs *BigBitSet) Union(s BigBitSet) {
+	if len(s) > len(*bs) {
+		grown := make(BigBitSet, len(s))
+		copy(grown, *bs)
+		*bs = grown
+	}
+	for i, word := range s {
+		(*bs)[i] |= word
+	}
+}
+
+// Intersection takes the intersection of [s] with this set
+func (bs *BigBitSet) Intersection(s BigBitSet) {
+	if len(*bs) > len(s) {
+		*bs = (*bs)[:len(s)]
+	}
+	for i := range *bs {
+		(*bs)[i] &= s[i]
+	}
+}
+
+// Difference removes all the elements in [s] from this set
+func (bs BigBitSet) Difference(s BigBitSet) {
+	for i := 0; i < len(bs) && i < len(s); i++ {
+		bs[i] &^= s[i]
+	}
+}
+
+// Remove [i] from the set of ints
+func (bs BigBitSet) Remove(i uint) {
+	if word := int(i / 64); word < len(bs) {
+		bs[word] &^= 1 << (i % 64)
+	}
+}
+
+// Clear removes all elements from this set
+func (bs *BigBitSet) Clear() { *bs = (*bs)[:0] }
+
+// Contains returns true if [i] was previously added to this set
+func (bs BigBitSet) Contains(i uint) bool {
+	word := int(i / 64)
+	return word < len(bs) && bs[word]&(1<<(i%64)) != 0
+}
+
+// Len returns the number of elements in this set
+func (bs BigBitSet) Len() int {
+	size := 0
+	for _, word := range bs {
+		size += bits.OnesCount64(word)
+	}
+	return size
+}
+
+func (bs BigBitSet) String() string {
+	sb := strings.Builder{}
+	for i := len(bs) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("%016x", bs[i]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// denseSetLen is the number of validators in the dense population benchmarks
+const denseSetLen = 2000
+
+func BenchmarkBigBitSetAddDense(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		bs := NewBigBitSet(denseSetLen)
+		for i := uint(0); i < denseSetLen; i++ {
+			bs.Add(i)
+		}
+	}
+}
+
+func BenchmarkSetAddDense(b *testing.B) {
+	validatorIDs := denseIDs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		set := Set{}
+		for _, id := range validatorIDs {
+			set.Add(id)
+		}
+	}
+}
+
+func BenchmarkBigBitSetContainsDense(b *testing.B) {
+	bs := NewBigBitSet(denseSetLen)
+	for i := uint(0); i < denseSetLen; i += 2 {
+		bs.Add(i)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := uint(0); i < denseSetLen; i++ {
+			bs.Contains(i)
+		}
+	}
+}
+
+func BenchmarkSetContainsDense(b *testing.B) {
+	validatorIDs := denseIDs()
+	set := Set{}
+	for i := 0; i < denseSetLen; i += 2 {
+		set.Add(validatorIDs[i])
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for _, id := range validatorIDs {
+			set.Contains(id)
+		}
+	}
+}
+
+func denseIDs() []ID {
+	validatorIDs := make([]ID, denseSetLen)
+	for i := range validatorIDs {
+		var idBytes [32]byte
+		binary.BigEndian.PutUint64(idBytes[:], uint64(i))
+		validatorIDs[i] = NewID(idBytes)
+	}
+	return validatorIDs
+}
@@ -0,0 +1,128 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import "testing"
+
+func TestBigBitSet(t *testing.T) {
+	var bs BigBitSet
+
+	if bs.Len() != 0 {
+		t.Fatalf("Empty set's len should be 0")
+	} else if bs.Contains(1000) {
+		t.Fatalf("Empty set shouldn't contain any elements")
+	}
+
+	bs.Add(5)
+	bs.Add(1000)
+	bs.Add(1000)
+	if bs.Len() != 2 {
+		t.Fatalf("Wrong set length")
+	} else if !bs.Contains(5) {
+		t.Fatalf("Set should contain element")
+	} else if !bs.Contains(1000) {
+		t.Fatalf("Set should contain element")
+	} else if bs.Contains(64 + 5) {
+		t.Fatalf("Set shouldn't contain element")
+	}
+
+	bs.Remove(1000)
+	bs.Remove(5000)
+	if bs.Len() != 1 {
+		t.Fatalf("Wrong set length")
+	} else if bs.Contains(1000) {
+		t.Fatalf("Set shouldn't contain element")
+	}
+
+	bs.Add(1000)
+	bs.Clear()
+	if bs.Len() != 0 {
+		t.Fatalf("Cleared set's len should be 0")
+	} else if bs.Contains(5) {
+		t.Fatalf("Cleared set shouldn't contain element")
+	}
+
+	bs.Add(999)
+	if bs.Contains(1000) {
+		t.Fatalf("Cleared set shouldn't contain element")
+	}
+}
+
+func TestBigBitSetUnion(t *testing.T) {
+	bs1 := NewBigBitSet(64)
+	bs1.Add(1)
+	bs1.Add(2)
+
+	var bs2 BigBitSet
+	bs2.Add(2)
+	bs2.Add(200)
+
+	bs1.Union(bs2)
+	if bs1.Len() != 3 {
+		t.Fatalf("Wrong set length")
+	}
+	for _, i := range []uint{1, 2, 200} {
+		if !bs1.Contains(i) {
+			t.Fatalf("Set should contain %d", i)
+		}
+	}
+	if bs2.Len() != 2 {
+		t.Fatalf("Union shouldn't modify its argument")
+	}
+}
+
+func TestBigBitSetIntersection(t *testing.T) {
+	var bs1 BigBitSet
+	bs1.Add(1)
+	bs1.Add(70)
+	bs1.Add(200)
+
+	var bs2 BigBitSet
+	bs2.Add(1)
+	bs2.Add(70)
+	bs2.Add(71)
+
+	bs1.Intersection(bs2)
+	if bs1.Len() != 2 {
+		t.Fatalf("Wrong set length")
+	} else if !bs1.Contains(1) || !bs1.Contains(70) {
+		t.Fatalf("Set should contain the shared elements")
+	} else if bs1.Contains(200) || bs1.Contains(71) {
+		t.Fatalf("Set shouldn't contain elements that weren't shared")
+	}
+
+	// Adding after shrinking shouldn't resurrect removed elements
+	bs1.Add(250)
+	if bs1.Contains(200) {
+		t.Fatalf("Set shouldn't contain element")
+	}
+}
+
+func TestBigBitSetDifference(t *testing.T) {
+	var bs1 BigBitSet
+	bs1.Add(1)
+	bs1.Add(100)
+
+	var bs2 BigBitSet
+	bs2.Add(100)
+	bs2.Add(500)
+
+	bs1.Difference(bs2)
+	if bs1.Len() != 1 {
+		t.Fatalf("Wrong set length")
+	} else if !bs1.Contains(1) {
+		t.Fatalf("Set should contain element")
+	}
+}
+
+func TestBigBitSetString(t *testing.T) {
+	var bs BigBitSet
+	bs.Add(0)
+	bs.Add(64)
+
+	expected := "00000000000000010000000000000001"
+	if str := bs.String(); str != expected {
+		t.Fatalf("Expected %s but got %s", expected, str)
+	}
+}
@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+// CappedSet is a set of at most a fixed number of IDs. Once the set is full,
+// adding a new ID evicts the ID that was added the longest time ago.
+type CappedSet struct {
+	set Set
+	// ring buffer of the IDs in the set, in insertion order
+	order []ID
+	// index in [order] of the oldest ID
+	head int
+}
+
+// NewCappedSet returns a set that holds at most [size] IDs
+func NewCappedSet(size int) *CappedSet {
+	if size <= 0 {
+		size = 1
+	}
+	return &CappedSet{
+		set:   make(Set, size),
+		order: make([]ID, 0, size),
+	}
+}
+
+// Add [id] to the set. If the set is full, the oldest ID is evicted. If [id]
+// is already in the set, nothing happens.
+func (cs *CappedSet) Add(id ID) {
+	if cs.set.Contains(id) {
+		return
+	}
+	cs.set.Add(id)
+
+	if len(cs.order) < cap(cs.order) {
+		cs.order = append(cs.order, id)
+		return
+	}
+
+	cs.set.Remove(cs.order[cs.head])
+	cs.order[cs.head] = id
+	cs.head = (cs.head + 1) % len(cs.order)
+}
+
+// Contains returns true if [id] is in the set
+func (cs *CappedSet) Contains(id ID) bool { return cs.set.Contains(id) }
+
+// Len returns the number of IDs in the set
+func (cs *CappedSet) Len() int { return len(cs.order) }
+
+// Oldest returns the ID that will be evicted next. Returns false if the set is
+// empty.
+func (cs *CappedSet) Oldest() (ID, bool) {
+	if len(cs.order) == 0 {
+		return ID{}, false
+	}
+	return cs.order[cs.head], true
+}
@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"testing"
+)
+
+func TestCappedSetEviction(t *testing.T) {
+	id0 := NewID([32]byte{0})
+	id1 := NewID([32]byte{1})
+	id2 := NewID([32]byte{2})
+	id3 := NewID([32]byte{3})
+
+	cs := NewCappedSet(2)
+	if _, ok := cs.Oldest(); ok {
+		t.Fatalf("Empty set shouldn't have an oldest ID")
+	}
+
+	cs.Add(id0)
+	cs.Add(id1)
+	if cs.Len() != 2 {
+		t.Fatalf("Set should have 2 IDs but has %d", cs.Len())
+	}
+	if oldest, _ := cs.Oldest(); !oldest.Equals(id0) {
+		t.Fatalf("Oldest should be %s but is %s", id0, oldest)
+	}
+
+	cs.Add(id2)
+	if cs.Len() != 2 {
+		t.Fatalf("Set should be capped at 2 IDs but has %d", cs.Len())
+	}
+	if cs.Contains(id0) {
+		t.Fatalf("Oldest ID should have been evicted")
+	}
+	if !cs.Contains(id1) || !cs.Contains(id2) {
+		t.Fatalf("Newer IDs shouldn't have been evicted")
+	}
+	if oldest, _ := cs.Oldest(); !oldest.Equals(id1) {
+		t.Fatalf("Oldest should be %s but is %s", id1, oldest)
+	}
+
+	cs.Add(id3)
+	if cs.Contains(id1) {
+		t.Fatalf("Oldest ID should have been evicted")
+	}
+	if oldest, _ := cs.Oldest(); !oldest.Equals(id2) {
+		t.Fatalf("Oldest should be %s but is %s", id2, oldest)
+	}
+}
+
+func TestCappedSetReAdd(t *testing.T) {
+	id0 := NewID([32]byte{0})
+	id1 := NewID([32]byte{1})
+	id2 := NewID([32]byte{2})
+
+	cs := NewCappedSet(2)
+	cs.Add(id0)
+	cs.Add(id1)
+
+	// Re-adding doesn't grow the set or refresh the ID's position
+	cs.Add(id0)
+	if cs.Len() != 2 {
+		t.Fatalf("Set should have 2 IDs but has %d", cs.Len())
+	}
+	if oldest, _ := cs.Oldest(); !oldest.Equals(id0) {
+		t.Fatalf("Oldest should still be %s but is %s", id0, oldest)
+	}
+
+	cs.Add(id2)
+	if cs.Contains(id0) || !cs.Contains(id1) || !cs.Contains(id2) {
+		t.Fatalf("Wrong ID was evicted")
+	}
+}
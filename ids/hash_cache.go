@@ -0,0 +1,71 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+type hashCacheEntry struct {
+	key string
+	id  ID
+}
+
+// HashCache memoizes the IDs produced by hashing byte slices. This avoids
+// recomputing the hash of inputs that are hashed repeatedly, such as well-known
+// chain IDs. At most [size] inputs are remembered. Once the cache is full, the
+// least recently used input is evicted.
+type HashCache struct {
+	lock      sync.Mutex
+	size      int
+	entryMap  map[string]*list.Element
+	entryList *list.List
+}
+
+// NewHashCache returns a cache that remembers at most [size] inputs
+func NewHashCache(size int) *HashCache {
+	if size <= 0 {
+		size = 1
+	}
+	return &HashCache{
+		size:      size,
+		entryMap:  make(map[string]*list.Element, size),
+		entryList: list.New(),
+	}
+}
+
+// ComputeID returns the ID of the sha256 hash of [bytes]
+func (c *HashCache) ComputeID(bytes []byte) ID {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if e, ok := c.entryMap[string(bytes)]; ok {
+		c.entryList.MoveToBack(e)
+		return e.Value.(*hashCacheEntry).id
+	}
+
+	if c.entryList.Len() >= c.size {
+		e := c.entryList.Front()
+		c.entryList.Remove(e)
+		delete(c.entryMap, e.Value.(*hashCacheEntry).key)
+	}
+
+	entry := &hashCacheEntry{
+		key: string(bytes),
+		id:  NewID(hashing.ComputeHash256Array(bytes)),
+	}
+	c.entryMap[entry.key] = c.entryList.PushBack(entry)
+	return entry.id
+}
+
+// Len returns the number of inputs currently remembered
+func (c *HashCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.entryList.Len()
+}
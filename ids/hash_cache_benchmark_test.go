@@ -0,0 +1,36 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+func hashCacheBenchmarkInputs() [][]byte {
+	inputs := make([][]byte, 16)
+	for i := range inputs {
+		inputs[i] = make([]byte, 256)
+		inputs[i][0] = byte(i)
+	}
+	return inputs
+}
+
+func BenchmarkComputeIDUncached(b *testing.B) {
+	inputs := hashCacheBenchmarkInputs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		NewID(hashing.ComputeHash256Array(inputs[n%len(inputs)]))
+	}
+}
+
+func BenchmarkComputeIDCached(b *testing.B) {
+	inputs := hashCacheBenchmarkInputs()
+	cache := NewHashCache(len(inputs))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		cache.ComputeID(inputs[n%len(inputs)])
+	}
+}
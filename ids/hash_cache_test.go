@@ -0,0 +1,52 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+func TestHashCache(t *testing.T) {
+	cache := NewHashCache(2)
+
+	inputs := [][]byte{{0}, {1}, {2}}
+	for _, input := range inputs {
+		expected := NewID(hashing.ComputeHash256Array(input))
+		if id := cache.ComputeID(input); !id.Equals(expected) {
+			t.Fatalf("Wrong ID. Expected %s ; Returned %s", expected, id)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Cache should be bounded to 2 entries but has %d", cache.Len())
+	}
+	if _, ok := cache.entryMap[string(inputs[0])]; ok {
+		t.Fatalf("Least recently used input should have been evicted")
+	}
+
+	// Mark [1] as recently used so that [2] is evicted next
+	expected := NewID(hashing.ComputeHash256Array(inputs[1]))
+	if id := cache.ComputeID(inputs[1]); !id.Equals(expected) {
+		t.Fatalf("Wrong cached ID. Expected %s ; Returned %s", expected, id)
+	}
+	cache.ComputeID(inputs[0])
+	if _, ok := cache.entryMap[string(inputs[2])]; ok {
+		t.Fatalf("Least recently used input should have been evicted")
+	}
+	if _, ok := cache.entryMap[string(inputs[1])]; !ok {
+		t.Fatalf("Recently used input shouldn't have been evicted")
+	}
+}
+
+func TestHashCacheCopiesInput(t *testing.T) {
+	cache := NewHashCache(1)
+
+	input := []byte{0}
+	expected := cache.ComputeID(input)
+	input[0] = 1
+	if id := cache.ComputeID([]byte{0}); !id.Equals(expected) {
+		t.Fatalf("Modifying the input changed the cached ID")
+	}
+}
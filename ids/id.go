@@ -6,6 +6,8 @@ package ids
 import (
 	"bytes"
 	"encoding/hex"
+	"hash/fnv"
+	"math/bits"
 	"sort"
 
 	"github.com/ava-labs/gecko/utils"
@@ -126,6 +128,41 @@ func (id ID) Bit(i uint) int {
 	return int(b)
 }
 
+// XOR returns the bitwise exclusive or of this id and [oID]. Interpreted as a
+// big-endian number, this is the Kademlia distance between the ids, so ids can
+// be ordered by their distance to a target by sorting the XOR results.
+func (id ID) XOR(oID ID) ID {
+	xor := [32]byte{}
+	for i, b := range id.ID {
+		xor[i] = b ^ oID.ID[i]
+	}
+	return NewID(xor)
+}
+
+// BitLen returns the minimum number of bits needed to represent this id as a
+// big-endian number. The BitLen of the XOR of two ids is the index of the
+// Kademlia bucket that one id falls into relative to the other. The BitLen of
+// the empty id is 0.
+func (id ID) BitLen() int {
+	for i, b := range id.ID {
+		if b != 0 {
+			return (len(id.ID)-i)*BitsPerByte - bits.LeadingZeros8(b)
+		}
+	}
+	return 0
+}
+
+// Bucket returns the bucket in [0, n) that this id is assigned to when
+// sharding over [n] buckets. Unlike using a single byte of the id, every byte
+// is hashed, so ids are evenly distributed even if they aren't random. The
+// bucket only depends on the id and [n], so it is the same across runs and
+// nodes. [n] must be positive.
+func (id ID) Bucket(n int) int {
+	hash := fnv.New64a()
+	_, _ = hash.Write(id.Bytes())
+	return int(hash.Sum64() % uint64(n))
+}
+
 // Hex returns a hex encoded string of this id.
 func (id ID) Hex() string { return hex.EncodeToString(id.Bytes()) }
 
@@ -5,6 +5,8 @@ package ids
 
 import (
 	"bytes"
+	"encoding/binary"
+	"math/rand"
 	"reflect"
 	"testing"
 )
@@ -64,6 +66,87 @@ func TestIDBit(t *testing.T) {
 	}
 }
 
+func TestIDXOR(t *testing.T) {
+	id0 := NewID([32]byte{0xf0, 0x0f, 31: 0x01})
+	id1 := NewID([32]byte{0xff, 0x0f, 31: 0x03})
+
+	if xor := id0.XOR(id0); !xor.Equals(Empty) {
+		t.Fatalf("The distance from an id to itself should be zero but was %s", xor)
+	}
+	if xor := id0.XOR(id1); !xor.Equals(NewID([32]byte{0x0f, 31: 0x02})) {
+		t.Fatalf("Wrong XOR result: %s", xor.Hex())
+	}
+	if !id0.XOR(id1).Equals(id1.XOR(id0)) {
+		t.Fatalf("XOR should be symmetric")
+	}
+	if !id0.Equals(NewID([32]byte{0xf0, 0x0f, 31: 0x01})) {
+		t.Fatalf("XOR shouldn't modify the original id")
+	}
+}
+
+func TestIDBitLen(t *testing.T) {
+	tests := []struct {
+		id     ID
+		bitLen int
+	}{
+		{id: Empty, bitLen: 0},
+		{id: NewID([32]byte{31: 0x01}), bitLen: 1},
+		{id: NewID([32]byte{31: 0xff}), bitLen: 8},
+		{id: NewID([32]byte{30: 0x01, 31: 0xff}), bitLen: 9},
+		{id: NewID([32]byte{0x01}), bitLen: 249},
+		{id: NewID([32]byte{0x80}), bitLen: 256},
+	}
+	for _, test := range tests {
+		if bitLen := test.id.BitLen(); bitLen != test.bitLen {
+			t.Fatalf("BitLen of %s should be %d but was %d", test.id.Hex(), test.bitLen, bitLen)
+		}
+	}
+
+	// Ids that share a longer prefix with the target are closer to it
+	target := NewID([32]byte{0xaa})
+	near := NewID([32]byte{0xab})
+	far := NewID([32]byte{0x2a})
+	if near.XOR(target).BitLen() >= far.XOR(target).BitLen() {
+		t.Fatalf("%s should be closer to %s than %s is", near.Hex(), target.Hex(), far.Hex())
+	}
+}
+
+func TestIDBucket(t *testing.T) {
+	// The assigned buckets must not change between runs or releases
+	if bucket := NewID([32]byte{0x01}).Bucket(1000); bucket != 956 {
+		t.Fatalf("Bucket should have been 956 but was %d", bucket)
+	}
+	if bucket := Empty.Bucket(1000); bucket != 933 {
+		t.Fatalf("Bucket should have been 933 but was %d", bucket)
+	}
+
+	const (
+		numBuckets    = 16
+		idsPerBucket  = 1000
+		maxDifference = idsPerBucket / 5
+	)
+	r := rand.New(rand.NewSource(0))
+	randomCounts := make([]int, numBuckets)
+	sequentialCounts := make([]int, numBuckets)
+	for i := 0; i < numBuckets*idsPerBucket; i++ {
+		random := [32]byte{}
+		_, _ = r.Read(random[:])
+		randomCounts[NewID(random).Bucket(numBuckets)]++
+
+		// Ids that only differ in their last bytes should be spread out too
+		sequential := [32]byte{}
+		binary.BigEndian.PutUint32(sequential[28:], uint32(i))
+		sequentialCounts[NewID(sequential).Bucket(numBuckets)]++
+	}
+	for bucket := 0; bucket < numBuckets; bucket++ {
+		for _, count := range []int{randomCounts[bucket], sequentialCounts[bucket]} {
+			if count < idsPerBucket-maxDifference || count > idsPerBucket+maxDifference {
+				t.Fatalf("Bucket %d was assigned %d ids but should have been assigned about %d", bucket, count, idsPerBucket)
+			}
+		}
+	}
+}
+
 func TestFromString(t *testing.T) {
 	key := [32]byte{'a', 'v', 'a', ' ', 'l', 'a', 'b', 's'}
 	id := NewID(key)
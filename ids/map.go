@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// MapEntry is a key-value pair of a Map
+type MapEntry struct {
+	ID    ID          `serialize:"true" json:"id"`
+	Value interface{} `serialize:"true" json:"value"`
+}
+
+// Map maps IDs to values. Unlike a native map, its entries are always iterated
+// over in order of their IDs, so the order, and the serialization of the map,
+// doesn't depend on the order the entries were put in. The zero value is an
+// empty map.
+type Map struct {
+	values map[[32]byte]interface{}
+	keys   []ID // sorted
+}
+
+// Put maps [id] to [value], replacing the previous value of [id], if any
+func (m *Map) Put(id ID, value interface{}) {
+	if m.values == nil {
+		m.values = make(map[[32]byte]interface{})
+	}
+	key := id.Key()
+	if _, exists := m.values[key]; !exists {
+		i := m.search(id)
+		m.keys = append(m.keys, ID{})
+		copy(m.keys[i+1:], m.keys[i:])
+		m.keys[i] = id
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of [id], and true if [id] is in the map
+func (m *Map) Get(id ID) (interface{}, bool) {
+	value, exists := m.values[id.Key()]
+	return value, exists
+}
+
+// Delete removes [id] from the map, if it's in the map
+func (m *Map) Delete(id ID) {
+	key := id.Key()
+	if _, exists := m.values[key]; !exists {
+		return
+	}
+	delete(m.values, key)
+	i := m.search(id)
+	copy(m.keys[i:], m.keys[i+1:])
+	m.keys[len(m.keys)-1] = ID{}
+	m.keys = m.keys[:len(m.keys)-1]
+}
+
+// Len returns the number of IDs in the map
+func (m *Map) Len() int { return len(m.keys) }
+
+// Iterate calls [f] with every entry of the map, in order of the IDs, until
+// [f] returns false. The map must not be modified by [f].
+func (m *Map) Iterate(f func(id ID, value interface{}) bool) {
+	for _, id := range m.keys {
+		if !f(id, m.values[id.Key()]) {
+			return
+		}
+	}
+}
+
+// Entries returns the entries of the map in order of their IDs
+func (m *Map) Entries() []MapEntry {
+	entries := make([]MapEntry, len(m.keys))
+	for i, id := range m.keys {
+		entries[i] = MapEntry{
+			ID:    id,
+			Value: m.values[id.Key()],
+		}
+	}
+	return entries
+}
+
+// MarshalJSON marshals the map as a list of its entries, in order of their IDs
+func (m *Map) MarshalJSON() ([]byte, error) { return json.Marshal(m.Entries()) }
+
+// search returns the index [id] is at, or should be inserted at, in [m.keys]
+func (m *Map) search(id ID) int {
+	idBytes := id.Bytes()
+	return sort.Search(len(m.keys), func(i int) bool {
+		return bytes.Compare(m.keys[i].Bytes(), idBytes) >= 0
+	})
+}
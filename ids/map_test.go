@@ -0,0 +1,128 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	id0 := NewID([32]byte{0})
+	id1 := NewID([32]byte{1})
+
+	m := Map{}
+	if _, ok := m.Get(id0); ok {
+		t.Fatalf("Empty map shouldn't contain %s", id0)
+	}
+
+	m.Put(id1, 1)
+	m.Put(id0, 0)
+	if m.Len() != 2 {
+		t.Fatalf("Map should have 2 entries but has %d", m.Len())
+	}
+	if value, ok := m.Get(id0); !ok || value != 0 {
+		t.Fatalf("Wrong value for %s: %v", id0, value)
+	}
+
+	m.Put(id0, 2)
+	if m.Len() != 2 {
+		t.Fatalf("Replacing a value shouldn't change the length but it is %d", m.Len())
+	}
+	if value, ok := m.Get(id0); !ok || value != 2 {
+		t.Fatalf("Wrong value for %s: %v", id0, value)
+	}
+
+	m.Delete(id0)
+	m.Delete(id0)
+	if _, ok := m.Get(id0); ok {
+		t.Fatalf("%s should have been deleted", id0)
+	}
+	if m.Len() != 1 {
+		t.Fatalf("Map should have 1 entry but has %d", m.Len())
+	}
+
+	visited := []ID(nil)
+	m.Iterate(func(id ID, value interface{}) bool {
+		visited = append(visited, id)
+		return true
+	})
+	if !Equals(visited, []ID{id1}) {
+		t.Fatalf("Iterated over %v but should have iterated over %v", visited, []ID{id1})
+	}
+}
+
+func TestMapIterateStops(t *testing.T) {
+	m := Map{}
+	for i := 0; i < 5; i++ {
+		m.Put(NewID([32]byte{byte(i)}), i)
+	}
+
+	numVisited := 0
+	m.Iterate(func(ID, interface{}) bool {
+		numVisited++
+		return numVisited < 3
+	})
+	if numVisited != 3 {
+		t.Fatalf("Iteration should have stopped after 3 entries but visited %d", numVisited)
+	}
+}
+
+func TestMapDeterministicOrder(t *testing.T) {
+	numIDs := 100
+	idList := make([]ID, numIDs)
+	for i := range idList {
+		idList[i] = GenerateTestID()
+	}
+	deleted := idList[:numIDs/4]
+
+	sorted := append([]ID(nil), idList[numIDs/4:]...)
+	SortIDs(sorted)
+
+	expectedJSON := []byte(nil)
+	for seed := int64(0); seed < 50; seed++ {
+		r := rand.New(rand.NewSource(seed))
+
+		m := Map{}
+		for _, i := range r.Perm(numIDs) {
+			m.Put(idList[i], idList[i].String())
+		}
+		for _, i := range r.Perm(len(deleted)) {
+			m.Delete(deleted[i])
+		}
+
+		iterated := []ID(nil)
+		m.Iterate(func(id ID, value interface{}) bool {
+			if value != id.String() {
+				t.Fatalf("Wrong value for %s: %v", id, value)
+			}
+			iterated = append(iterated, id)
+			return true
+		})
+		if !Equals(iterated, sorted) {
+			t.Fatalf("Seed %d: iteration order wasn't sorted", seed)
+		}
+
+		entries := m.Entries()
+		if len(entries) != len(sorted) {
+			t.Fatalf("Seed %d: should have %d entries but has %d", seed, len(sorted), len(entries))
+		}
+		for i, entry := range entries {
+			if !entry.ID.Equals(sorted[i]) {
+				t.Fatalf("Seed %d: entry %d is %s but should be %s", seed, i, entry.ID, sorted[i])
+			}
+		}
+
+		mapJSON, err := m.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expectedJSON == nil {
+			expectedJSON = mapJSON
+		} else if !bytes.Equal(mapJSON, expectedJSON) {
+			t.Fatalf("Seed %d: serialization differed:\n%s\n%s", seed, mapJSON, expectedJSON)
+		}
+	}
+}
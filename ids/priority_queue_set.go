@@ -0,0 +1,123 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"container/heap"
+)
+
+// PriorityQueueSet is a set of IDs that are dequeued in order of priority,
+// rather than in fifo order. The ID whose priority is least according to the
+// set's less function is popped first.
+type PriorityQueueSet struct {
+	entries priorityEntries
+	indices map[[32]byte]*priorityEntry
+}
+
+// NewPriorityQueueSet returns an empty set whose IDs are popped in the order
+// defined by [less]
+func NewPriorityQueueSet(less func(a, b uint64) bool) *PriorityQueueSet {
+	return &PriorityQueueSet{
+		entries: priorityEntries{less: less},
+		indices: make(map[[32]byte]*priorityEntry),
+	}
+}
+
+// Push [id] with [priority]. If [id] is already in the set, it isn't added
+// again, its priority isn't changed, and false is returned.
+func (pqs *PriorityQueueSet) Push(id ID, priority uint64) bool {
+	key := id.Key()
+	if _, exists := pqs.indices[key]; exists {
+		return false
+	}
+
+	entry := &priorityEntry{
+		id:       id,
+		priority: priority,
+	}
+	pqs.indices[key] = entry
+	heap.Push(&pqs.entries, entry)
+	return true
+}
+
+// Pop removes and returns the ID that is first in priority order. Returns
+// false if the set is empty.
+func (pqs *PriorityQueueSet) Pop() (ID, bool) {
+	if len(pqs.entries.entries) == 0 {
+		return ID{}, false
+	}
+
+	entry := heap.Pop(&pqs.entries).(*priorityEntry)
+	delete(pqs.indices, entry.id.Key())
+	return entry.id, true
+}
+
+// Peek returns the ID that is first in priority order without removing it.
+// Returns false if the set is empty.
+func (pqs *PriorityQueueSet) Peek() (ID, bool) {
+	if len(pqs.entries.entries) == 0 {
+		return ID{}, false
+	}
+	return pqs.entries.entries[0].id, true
+}
+
+// Update the priority of [id]. Returns false if [id] isn't in the set.
+func (pqs *PriorityQueueSet) Update(id ID, priority uint64) bool {
+	entry, exists := pqs.indices[id.Key()]
+	if !exists {
+		return false
+	}
+
+	entry.priority = priority
+	heap.Fix(&pqs.entries, entry.index)
+	return true
+}
+
+// Contains returns true if [id] is in the set
+func (pqs *PriorityQueueSet) Contains(id ID) bool {
+	_, exists := pqs.indices[id.Key()]
+	return exists
+}
+
+// Len returns the number of IDs in the set
+func (pqs *PriorityQueueSet) Len() int { return len(pqs.entries.entries) }
+
+type priorityEntry struct {
+	id       ID
+	priority uint64
+	// position of this entry in the heap
+	index int
+}
+
+// priorityEntries implements heap.Interface
+type priorityEntries struct {
+	less    func(a, b uint64) bool
+	entries []*priorityEntry
+}
+
+func (pe *priorityEntries) Len() int { return len(pe.entries) }
+
+func (pe *priorityEntries) Less(i, j int) bool {
+	return pe.less(pe.entries[i].priority, pe.entries[j].priority)
+}
+
+func (pe *priorityEntries) Swap(i, j int) {
+	pe.entries[i], pe.entries[j] = pe.entries[j], pe.entries[i]
+	pe.entries[i].index = i
+	pe.entries[j].index = j
+}
+
+func (pe *priorityEntries) Push(x interface{}) {
+	entry := x.(*priorityEntry)
+	entry.index = len(pe.entries)
+	pe.entries = append(pe.entries, entry)
+}
+
+func (pe *priorityEntries) Pop() interface{} {
+	newLen := len(pe.entries) - 1
+	entry := pe.entries[newLen]
+	pe.entries[newLen] = nil
+	pe.entries = pe.entries[:newLen]
+	return entry
+}
@@ -0,0 +1,113 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ids
+
+import (
+	"testing"
+)
+
+func lessUint64(a, b uint64) bool { return a < b }
+
+func TestPriorityQueueSetOrdering(t *testing.T) {
+	pqs := NewPriorityQueueSet(lessUint64)
+	if _, ok := pqs.Pop(); ok {
+		t.Fatal("Popped from an empty set")
+	}
+
+	priorities := []uint64{5, 1, 4, 2, 3}
+	for _, priority := range priorities {
+		if !pqs.Push(NewID([32]byte{byte(priority)}), priority) {
+			t.Fatalf("Failed to push ID with priority %d", priority)
+		}
+	}
+	if pqs.Len() != len(priorities) {
+		t.Fatalf("Expected %d IDs but found %d", len(priorities), pqs.Len())
+	}
+
+	for expected := uint64(1); expected <= 5; expected++ {
+		expectedID := NewID([32]byte{byte(expected)})
+		if peeked, ok := pqs.Peek(); !ok || !peeked.Equals(expectedID) {
+			t.Fatalf("Expected to peek %s but got %s", expectedID, peeked)
+		}
+		if popped, ok := pqs.Pop(); !ok || !popped.Equals(expectedID) {
+			t.Fatalf("Expected to pop %s but got %s", expectedID, popped)
+		}
+		if pqs.Contains(expectedID) {
+			t.Fatalf("%s should have been removed", expectedID)
+		}
+	}
+	if pqs.Len() != 0 {
+		t.Fatal("The set should be empty")
+	}
+}
+
+func TestPriorityQueueSetComparator(t *testing.T) {
+	pqs := NewPriorityQueueSet(func(a, b uint64) bool { return a > b })
+	low := NewID([32]byte{1})
+	high := NewID([32]byte{2})
+	pqs.Push(low, 1)
+	pqs.Push(high, 2)
+
+	if popped, _ := pqs.Pop(); !popped.Equals(high) {
+		t.Fatalf("Expected the highest priority first, but got %s", popped)
+	}
+}
+
+func TestPriorityQueueSetDedupe(t *testing.T) {
+	pqs := NewPriorityQueueSet(lessUint64)
+	id := NewID([32]byte{1})
+	other := NewID([32]byte{2})
+
+	if !pqs.Push(id, 3) {
+		t.Fatal("Failed to push a new ID")
+	}
+	if pqs.Push(id, 1) {
+		t.Fatal("Pushed the same ID twice")
+	}
+	pqs.Push(other, 2)
+
+	// The duplicate push shouldn't have changed [id]'s priority
+	if popped, _ := pqs.Pop(); !popped.Equals(other) {
+		t.Fatalf("Expected %s first but got %s", other, popped)
+	}
+	if popped, _ := pqs.Pop(); !popped.Equals(id) {
+		t.Fatalf("Expected %s second but got %s", id, popped)
+	}
+	if _, ok := pqs.Pop(); ok {
+		t.Fatal("The ID should only have been popped once")
+	}
+
+	// Once popped, the ID can be pushed again
+	if !pqs.Push(id, 1) {
+		t.Fatal("Failed to push a popped ID")
+	}
+}
+
+func TestPriorityQueueSetUpdate(t *testing.T) {
+	pqs := NewPriorityQueueSet(lessUint64)
+	ids := []ID{}
+	for i := 0; i < 5; i++ {
+		id := NewID([32]byte{byte(i)})
+		ids = append(ids, id)
+		pqs.Push(id, uint64(i))
+	}
+
+	// Move the last ID to the front, and the first ID to the back
+	if !pqs.Update(ids[4], 0) {
+		t.Fatal("Failed to update a pushed ID")
+	}
+	if !pqs.Update(ids[0], 10) {
+		t.Fatal("Failed to update a pushed ID")
+	}
+	if pqs.Update(NewID([32]byte{10}), 0) {
+		t.Fatal("Updated an ID that wasn't pushed")
+	}
+
+	expected := []ID{ids[4], ids[1], ids[2], ids[3], ids[0]}
+	for _, expectedID := range expected {
+		if popped, ok := pqs.Pop(); !ok || !popped.Equals(expectedID) {
+			t.Fatalf("Expected to pop %s but got %s", expectedID, popped)
+		}
+	}
+}
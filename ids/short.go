@@ -6,6 +6,7 @@ package ids
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -18,6 +19,11 @@ import (
 // ShortEmpty is a useful all zero value
 var ShortEmpty = ShortID{ID: &[20]byte{}}
 
+var (
+	errEmptyHRP   = errors.New("bech32 HRP is empty")
+	errInvalidHRP = errors.New("bech32 HRP must only contain lowercase printable ASCII characters")
+)
+
 // ShortID wraps a 20 byte hash as an identifier
 type ShortID struct {
 	ID *[20]byte `serialize:"true"`
@@ -52,6 +58,20 @@ func ShortFromPrefixedString(idStr, prefix string) (ShortID, error) {
 	return ShortFromString(strings.TrimPrefix(idStr, prefix))
 }
 
+// ShortFromBech32 is the inverse of ShortID.Bech32. It returns the ID and the
+// human readable part of the address.
+func ShortFromBech32(addrStr string) (ShortID, string, error) {
+	hrp, addrBytes, err := formatting.ParseBech32(addrStr)
+	if err != nil {
+		return ShortID{}, "", err
+	}
+	if err := verifyHRP(hrp); err != nil {
+		return ShortID{}, "", err
+	}
+	id, err := ToShortID(addrBytes)
+	return id, hrp, err
+}
+
 // MarshalJSON ...
 func (id ShortID) MarshalJSON() ([]byte, error) {
 	if id.IsZero() {
@@ -119,6 +139,28 @@ func (id ShortID) PrefixedString(prefix string) string {
 	return prefix + id.String()
 }
 
+// Bech32 returns the bech32 encoding of this id with the human readable part
+// [hrp]
+func (id ShortID) Bech32(hrp string) (string, error) {
+	if err := verifyHRP(hrp); err != nil {
+		return "", err
+	}
+	return formatting.FormatBech32(hrp, id.Bytes())
+}
+
+// verifyHRP returns an error if [hrp] isn't a valid bech32 human readable part
+func verifyHRP(hrp string) error {
+	if len(hrp) == 0 {
+		return errEmptyHRP
+	}
+	for _, c := range hrp {
+		if c < 33 || c > 126 || (c >= 'A' && c <= 'Z') {
+			return errInvalidHRP
+		}
+	}
+	return nil
+}
+
 type sortShortIDData []ShortID
 
 func (ids sortShortIDData) Less(i, j int) bool {
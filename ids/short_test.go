@@ -5,6 +5,8 @@ package ids
 
 import (
 	"testing"
+
+	"github.com/ava-labs/gecko/utils/formatting"
 )
 
 func TestShortString(t *testing.T) {
@@ -52,3 +54,60 @@ func TestIsUniqueShortIDs(t *testing.T) {
 		t.Fatal("should not be unique")
 	}
 }
+
+func TestShortBech32(t *testing.T) {
+	id := NewShortID([20]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20})
+
+	tests := []struct {
+		hrp  string
+		addr string
+	}{
+		{hrp: "avax", addr: "avax1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc52qphlp"},
+		{hrp: "fuji", addr: "fuji1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5xj9gn7"},
+	}
+	for _, test := range tests {
+		addr, err := id.Bech32(test.hrp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != test.addr {
+			t.Fatalf("Expected %q but got %q", test.addr, addr)
+		}
+
+		parsedID, hrp, err := ShortFromBech32(addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hrp != test.hrp {
+			t.Fatalf("Expected HRP %q but got %q", test.hrp, hrp)
+		}
+		if !parsedID.Equals(id) {
+			t.Fatalf("ShortFromBech32 did not produce the identical ID")
+		}
+	}
+}
+
+func TestShortBech32Invalid(t *testing.T) {
+	id := NewShortID([20]byte{1})
+
+	if _, err := id.Bech32(""); err == nil {
+		t.Fatal("Using an empty HRP did not cause an error")
+	}
+	if _, err := id.Bech32("AVAX"); err == nil {
+		t.Fatal("Using an uppercase HRP did not cause an error")
+	}
+
+	// The last character of the checksum has been modified
+	if _, _, err := ShortFromBech32("avax1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc52qphlq"); err == nil {
+		t.Fatal("Using an invalid checksum did not cause an error")
+	}
+
+	// Valid checksum, but the payload is only 1 byte long
+	addr, err := formatting.FormatBech32("avax", []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ShortFromBech32(addr); err == nil {
+		t.Fatal("Using a payload of the wrong length did not cause an error")
+	}
+}
@@ -11,14 +11,18 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/database/leveldb"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/ipcs"
 	"github.com/ava-labs/gecko/nat"
+	"github.com/ava-labs/gecko/network"
 	"github.com/ava-labs/gecko/node"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/staking"
@@ -29,6 +33,7 @@ import (
 	"github.com/ava-labs/gecko/utils/sampler"
 	"github.com/ava-labs/gecko/utils/units"
 	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/version"
 )
 
 const (
@@ -54,9 +59,13 @@ var (
 )
 
 var (
-	errBootstrapMismatch    = errors.New("more bootstrap IDs provided than bootstrap IPs")
-	errStakingRequiresTLS   = errors.New("if staking is enabled, network TLS must also be enabled")
-	errInvalidStakerWeights = errors.New("staking weights must be positive")
+	errBootstrapMismatch     = errors.New("more bootstrap IDs provided than bootstrap IPs")
+	errBootstrapHostNeedsTLS = errors.New("bootstrap hostnames can only be used if network TLS is enabled")
+	errStakingRequiresTLS    = errors.New("if staking is enabled, network TLS must also be enabled")
+	errInvalidStakerWeights  = errors.New("staking weights must be positive")
+	errInvalidAcceptInterval = errors.New("snow-accept-interval must be positive when acceptance is limited")
+	errInvalidResolveFreq    = errors.New("bootstrap-resolve-frequency must be positive")
+	errInvalidVertexInterval = errors.New("snow-avalanche-vertex-interval must be positive when vertex building is limited")
 )
 
 // GetIPs returns the default IPs for each network
@@ -145,6 +154,116 @@ func GetDefaultBootstraps(networkID uint32, count int) ([]string, []string) {
 	return sampledIPs, sampledIDs
 }
 
+// parseNodeIDs parses a comma separated list of prefixed node IDs
+func parseNodeIDs(idsStr string) (ids.ShortSet, error) {
+	nodeIDs := ids.ShortSet{}
+	for _, id := range strings.Split(idsStr, ",") {
+		if id == "" {
+			continue
+		}
+		nodeID, err := ids.ShortFromPrefixedString(id, constants.NodeIDPrefix)
+		if err != nil {
+			return nil, err
+		}
+		nodeIDs.Add(nodeID)
+	}
+	return nodeIDs, nil
+}
+
+// parseTokens parses a comma separated list of API tokens
+func parseTokens(tokensStr string) []string {
+	tokens := []string(nil)
+	for _, token := range strings.Split(tokensStr, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// parseRateLimits parses a comma separated list of API rate limits of the form
+// "base=rate:burst", such as "bc/X=10:20"
+func parseRateLimits(limitsStr string, perClient bool) (map[string]api.RateLimit, error) {
+	limits := map[string]api.RateLimit(nil)
+	for _, limitStr := range parseTokens(limitsStr) {
+		parts := strings.Split(limitStr, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid rate limit %q", limitStr)
+		}
+		limit, err := parseRateLimit(parts[1], perClient)
+		if err != nil {
+			return nil, err
+		}
+		if limits == nil {
+			limits = make(map[string]api.RateLimit)
+		}
+		limits[parts[0]] = limit
+	}
+	return limits, nil
+}
+
+// parseRateLimit parses an API rate limit of the form "rate:burst"
+func parseRateLimit(limitStr string, perClient bool) (api.RateLimit, error) {
+	parts := strings.Split(limitStr, ":")
+	if len(parts) != 2 {
+		return api.RateLimit{}, fmt.Errorf("invalid rate limit %q", limitStr)
+	}
+	rate, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || rate <= 0 {
+		return api.RateLimit{}, fmt.Errorf("invalid rate in rate limit %q", limitStr)
+	}
+	burst, err := strconv.Atoi(parts[1])
+	if err != nil || burst <= 0 {
+		return api.RateLimit{}, fmt.Errorf("invalid burst in rate limit %q", limitStr)
+	}
+	return api.RateLimit{
+		Rate:      rate,
+		Burst:     burst,
+		PerClient: perClient,
+	}, nil
+}
+
+// parseGossipQuotas parses a comma separated list of subnet gossip quotas of
+// the form "subnetID=bytesPerSecond"
+func parseGossipQuotas(quotasStr string) (map[[32]byte]uint64, error) {
+	quotas := map[[32]byte]uint64(nil)
+	for _, quotaStr := range parseTokens(quotasStr) {
+		parts := strings.Split(quotaStr, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid gossip quota %q", quotaStr)
+		}
+		subnetID, err := ids.FromString(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet ID in gossip quota %q: %w", quotaStr, err)
+		}
+		bytesPerSecond, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bandwidth in gossip quota %q: %w", quotaStr, err)
+		}
+		if quotas == nil {
+			quotas = make(map[[32]byte]uint64)
+		}
+		quotas[subnetID.Key()] = bytesPerSecond
+	}
+	return quotas, nil
+}
+
+// parseIPs parses a comma separated list of IPs without ports
+func parseIPs(ipsStr string) ([]net.IP, error) {
+	ips := []net.IP(nil)
+	for _, ipStr := range strings.Split(ipsStr, ",") {
+		if ipStr == "" {
+			continue
+		}
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip %q", ipStr)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
 // Parse the CLI arguments
 func init() {
 	errs := &wrappers.Errs{}
@@ -158,7 +277,7 @@ func init() {
 	fs := flag.NewFlagSet("gecko", flag.ContinueOnError)
 
 	// If this is true, print the version and quit.
-	version := fs.Bool("version", false, "If true, print version and quit")
+	printVersion := fs.Bool("version", false, "If true, print version and quit")
 
 	// NetworkID:
 	networkName := fs.String("network-id", defaultNetworkName, "Network ID this node will connect to")
@@ -181,6 +300,7 @@ func init() {
 
 	// IP:
 	consensusIP := fs.String("public-ip", "", "Public IP of this node")
+	fs.DurationVar(&Config.IPUpdateFrequency, "public-ip-update-frequency", node.DefaultIPUpdateFrequency, "Frequency at which this node checks whether its public IP changed, to announce the new IP to its peers. Ignored if --public-ip is provided. If 0, the IP isn't checked")
 
 	// HTTP Server:
 	httpHost := fs.String("http-host", "127.0.0.1", "Address of the HTTP server")
@@ -188,15 +308,39 @@ func init() {
 	fs.BoolVar(&Config.EnableHTTPS, "http-tls-enabled", false, "Upgrade the HTTP server to HTTPs")
 	fs.StringVar(&Config.HTTPSKeyFile, "http-tls-key-file", "", "TLS private key file for the HTTPs server")
 	fs.StringVar(&Config.HTTPSCertFile, "http-tls-cert-file", "", "TLS certificate file for the HTTPs server")
+	fs.BoolVar(&Config.EnableGzip, "http-gzip-enabled", false, "Gzip compress HTTP responses for clients that accept it")
+	fs.IntVar(&Config.GzipThreshold, "http-gzip-threshold", api.DefaultGzipThreshold, "Minimum size, in bytes, of an HTTP response to be gzip compressed")
 
 	// Bootstrapping:
-	bootstrapIPs := fs.String("bootstrap-ips", "default", "Comma separated list of bootstrap peer ips to connect to. Example: 127.0.0.1:9630,127.0.0.1:9631")
+	bootstrapIPs := fs.String("bootstrap-ips", "default", "Comma separated list of bootstrap peer ips, or hostnames, to connect to. Example: 127.0.0.1:9630,beacon.example.com:9631")
 	bootstrapIDs := fs.String("bootstrap-ids", "default", "Comma separated list of bootstrap peer ids to connect to. Example: JR4dVmy6ffUGAKCBDkyCbeZbyHQBeDsET,8CrVPQZ4VSqgL8zTdvL14G8HqAfrBr4z")
+	fs.IntVar(&Config.MinConnectedBeacons, "bootstrap-min-connected-beacons", 0, "Minimum number of bootstrap peers that must be connected before a chain starts consensus")
+	fs.DurationVar(&Config.BeaconTimeout, "bootstrap-beacon-timeout", 0, "Time to wait for bootstrap peers to connect before a chain starts consensus anyway. If 0, chains wait indefinitely")
+	fs.DurationVar(&Config.BootstrapResolveFrequency, "bootstrap-resolve-frequency", network.DefaultBootstrapResolveFrequency, "Frequency at which the hostnames of bootstrap peers are re-resolved")
+
+	// Access list:
+	allowedIDs := fs.String("network-allowed-ids", "", "Comma separated list of node ids that may connect to this node. If empty, and no allowed ips are provided, all nodes may connect")
+	allowedIPs := fs.String("network-allowed-ips", "", "Comma separated list of ips that may connect to this node. If empty, and no allowed ids are provided, all nodes may connect")
+	deniedIDs := fs.String("network-denied-ids", "", "Comma separated list of node ids that may not connect to this node")
+	deniedIPs := fs.String("network-denied-ips", "", "Comma separated list of ips that may not connect to this node")
+
+	// Connection limits:
+	fs.IntVar(&Config.ConnectionLimits.MaxConns, "network-max-inbound-connections", 0, "Maximum number of inbound connections to accept. If 0, the number isn't limited")
+	fs.IntVar(&Config.ConnectionLimits.MaxConnsPerIP, "network-max-inbound-connections-per-ip", 0, "Maximum number of inbound connections to accept from a single ip. If 0, the number isn't limited")
+	fs.DurationVar(&Config.HandshakeTimeout, "network-handshake-timeout", network.DefaultHandshakeTimeout, "Time a peer has to finish its handshake before the connection is closed. If 0, handshakes never time out")
+	fs.DurationVar(&Config.MessageReadTimeout, "network-message-read-timeout", network.DefaultMessageReadTimeout, "Time a peer has to finish sending a message once it has started before the connection is closed. If 0, only the ping timeout applies")
+	fs.DurationVar(&Config.KeepAlivePeriod, "network-keepalive-period", 0, "Period between TCP keepalive probes. If 0, the default period is used")
+	minVersion := fs.String("network-minimum-version", "", "Minimum version, e.g. avalanche/0.6.0, a peer must run to connect. If empty, only compatibility with this node's version is checked")
+	fs.DurationVar(&Config.IdleTimeout, "network-idle-timeout", 0, "Time a peer may go without sending messages, other than pings and pongs, before the connection is closed. If 0, idle peers aren't disconnected")
+	gossipQuotas := fs.String("network-subnet-gossip-quotas", "", "Comma separated list of \"subnetID=bytesPerSecond\" limits on the bandwidth the chains of a subnet may use to gossip containers")
 
 	// Staking:
 	consensusPort := fs.Uint("staking-port", 9651, "Port of the consensus server")
 	fs.BoolVar(&Config.EnableStaking, "staking-enabled", true, "Enable staking. If enabled, Network TLS is required.")
 	fs.BoolVar(&Config.EnableP2PTLS, "p2p-tls-enabled", true, "Require TLS to authenticate network communication")
+	tlsMinVersion := fs.String("p2p-tls-min-version", "", "Minimum TLS version to negotiate with peers. If empty, uses the Go default. Otherwise, should be one of {1.0, 1.1, 1.2, 1.3}")
+	tlsCipherSuites := fs.String("p2p-tls-cipher-suites", "", "Comma separated list of TLS cipher suites that may be negotiated with peers. If empty, uses the Go defaults. Example: TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256")
+	fs.BoolVar(&Config.EnableCompression, "p2p-compression-enabled", false, "Compress large messages sent to peers that support compression")
 	fs.StringVar(&Config.StakingKeyFile, "staking-tls-key-file", defaultStakingKeyPath, "TLS private key for staking")
 	fs.StringVar(&Config.StakingCertFile, "staking-tls-cert-file", defaultStakingCertPath, "TLS certificate for staking")
 	fs.Uint64Var(&Config.DisabledStakingWeight, "staking-disabled-weight", 1, "Weight to provide to each peer when staking is disabled")
@@ -211,6 +355,7 @@ func init() {
 	// Logging:
 	logsDir := fs.String("log-dir", "", "Logging directory for Avalanche")
 	logLevel := fs.String("log-level", "info", "The log level. Should be one of {verbo, debug, info, warn, error, fatal, off}")
+	fs.IntVar(&loggingConfig.RecentLines, "log-recent-lines", 1000, "Number of the most recently logged lines of each logger to keep in memory, so they can be read through the admin API")
 	logDisplayLevel := fs.String("log-display-level", "", "The log display level. If left blank, will inherit the value of log-level. Otherwise, should be one of {verbo, debug, info, warn, error, fatal, off}")
 	logDisplayHighlight := fs.String("log-display-highlight", "auto", "Whether to color/highlight display logs. Default highlights when the output is a terminal. Otherwise, should be one of {auto, plain, colors}")
 
@@ -221,6 +366,13 @@ func init() {
 	fs.IntVar(&Config.ConsensusParams.Parents, "snow-avalanche-num-parents", 5, "Number of vertexes for reference from each new vertex")
 	fs.IntVar(&Config.ConsensusParams.BatchSize, "snow-avalanche-batch-size", 30, "Number of operations to batch in each new vertex")
 	fs.IntVar(&Config.ConsensusParams.ConcurrentRepolls, "snow-concurrent-repolls", 1, "Minimum number of concurrent polls for finalizing consensus")
+	fs.IntVar(&Config.MaxAcceptedPerInterval, "snow-max-accepted-per-interval", 0, "Maximum number of blocks a snowman chain accepts every snow-accept-interval. If 0, acceptance isn't limited")
+	fs.DurationVar(&Config.AcceptInterval, "snow-accept-interval", time.Second, "Interval over which snow-max-accepted-per-interval is enforced")
+	fs.DurationVar(&Config.MaxIdleInterval, "snow-max-idle-interval", 0, "Time a snowman chain may go without building a block before it builds one anyway. If 0, blocks are only built when there are pending transactions")
+	fs.BoolVar(&Config.UnsafeAllowForceAccept, "snow-unsafe-allow-force-accept", false, "If true, snowman chains allow blocks to be accepted without consensus. Only for recovery")
+	fs.IntVar(&Config.MaxVerticesPerInterval, "snow-avalanche-max-vertices-per-interval", 0, "Maximum number of vertices an avalanche chain builds every snow-avalanche-vertex-interval. If 0, vertex building isn't limited")
+	fs.DurationVar(&Config.VertexInterval, "snow-avalanche-vertex-interval", time.Second, "Interval over which snow-avalanche-max-vertices-per-interval is enforced")
+	fs.IntVar(&Config.MaxProcessingVertices, "snow-avalanche-max-processing-vertices", 0, "Maximum number of vertices an avalanche chain may have processing before it stops building new ones. If 0, processing vertices aren't limited")
 
 	// Enable/Disable APIs:
 	fs.BoolVar(&Config.AdminAPIEnabled, "api-admin-enabled", false, "If true, this node exposes the Admin API")
@@ -228,7 +380,13 @@ func init() {
 	fs.BoolVar(&Config.KeystoreAPIEnabled, "api-keystore-enabled", true, "If true, this node exposes the Keystore API")
 	fs.BoolVar(&Config.MetricsAPIEnabled, "api-metrics-enabled", true, "If true, this node exposes the Metrics API")
 	fs.BoolVar(&Config.HealthAPIEnabled, "api-health-enabled", true, "If true, this node exposes the Health API")
+	fs.BoolVar(&Config.EventsAPIEnabled, "api-events-enabled", false, "If true, this node exposes the Events API, which pushes accepted decisions to websocket subscribers")
 	fs.BoolVar(&Config.IPCAPIEnabled, "api-ipcs-enabled", false, "If true, IPCs can be opened")
+	adminAuthTokens := fs.String("api-admin-auth-tokens", "", "Comma separated list of bearer tokens that may call the Admin API. If empty, no token is required")
+	keystoreAuthTokens := fs.String("api-keystore-auth-tokens", "", "Comma separated list of bearer tokens that may call the Keystore API. If empty, no token is required")
+	apiRateLimit := fs.String("api-rate-limit", "", "Calls per second and burst size, as \"rate:burst\", allowed to each API endpoint. If empty, endpoints aren't limited")
+	apiRateLimits := fs.String("api-rate-limits", "", "Comma separated list of \"base=rate:burst\" limits for the endpoints under a route group, such as \"bc/X=10:20\", overriding api-rate-limit")
+	apiRateLimitPerClient := fs.Bool("api-rate-limit-per-client", false, "If true, API rate limits apply to each client IP separately")
 
 	// Throughput Server
 	throughputPort := fs.Uint("xput-server-port", 9652, "Port of the deprecated throughput test server")
@@ -240,7 +398,7 @@ func init() {
 
 	ferr := fs.Parse(os.Args[1:])
 
-	if *version { // If --version used, print version and exit
+	if *printVersion { // If --version used, print version and exit
 		networkID, err := genesis.NetworkID(defaultNetworkName)
 		if errs.Add(err); err != nil {
 			return
@@ -295,6 +453,8 @@ func init() {
 	} else {
 		Config.Nat = nat.NewNoRouter()
 		ip = net.ParseIP(*consensusIP)
+		// The provided IP is assumed to be static
+		Config.IPUpdateFrequency = 0
 	}
 
 	if ip == nil {
@@ -315,16 +475,24 @@ func init() {
 		*bootstrapIPs = strings.Join(defaultBootstrapIPs, ",")
 	}
 	for _, ip := range strings.Split(*bootstrapIPs, ",") {
-		if ip != "" {
-			addr, err := utils.ToIPDesc(ip)
-			if err != nil {
-				errs.Add(fmt.Errorf("couldn't parse ip: %w", err))
-				return
-			}
+		if ip == "" {
+			continue
+		}
+		addr, err := utils.ToIPDesc(ip)
+		if err == nil {
 			Config.BootstrapPeers = append(Config.BootstrapPeers, &node.Peer{
 				IP: addr,
 			})
+			continue
 		}
+		// Entries that aren't IPs are treated as hostnames to resolve
+		if host, _, splitErr := net.SplitHostPort(ip); splitErr != nil || host == "" || net.ParseIP(host) != nil {
+			errs.Add(fmt.Errorf("couldn't parse ip: %w", err))
+			return
+		}
+		Config.BootstrapPeers = append(Config.BootstrapPeers, &node.Peer{
+			Host: ip,
+		})
 	}
 
 	if *bootstrapIDs == "default" {
@@ -344,7 +512,28 @@ func init() {
 		errs.Add(errInvalidStakerWeights)
 	}
 
+	if Config.BootstrapResolveFrequency <= 0 {
+		errs.Add(errInvalidResolveFreq)
+	}
+
+	if Config.MaxAcceptedPerInterval > 0 && Config.AcceptInterval <= 0 {
+		errs.Add(errInvalidAcceptInterval)
+	}
+
+	if Config.MaxVerticesPerInterval > 0 && Config.VertexInterval <= 0 {
+		errs.Add(errInvalidVertexInterval)
+	}
+
 	if Config.EnableP2PTLS {
+		if Config.TLSParams.MinVersion, err = network.ParseTLSVersion(*tlsMinVersion); err != nil {
+			errs.Add(err)
+			return
+		}
+		if Config.TLSParams.CipherSuites, err = network.ParseCipherSuites(*tlsCipherSuites); err != nil {
+			errs.Add(err)
+			return
+		}
+
 		i := 0
 		for _, id := range strings.Split(*bootstrapIDs, ",") {
 			if id != "" {
@@ -367,10 +556,47 @@ func init() {
 		}
 	} else {
 		for _, peer := range Config.BootstrapPeers {
+			// Without TLS, peers are identified by their IP
+			if peer.Host != "" {
+				errs.Add(errBootstrapHostNeedsTLS)
+				return
+			}
 			peer.ID = ids.NewShortID(hashing.ComputeHash160Array([]byte(peer.IP.String())))
 		}
 	}
 
+	// Access list:
+	if Config.AccessList.AllowedIDs, err = parseNodeIDs(*allowedIDs); err != nil {
+		errs.Add(fmt.Errorf("couldn't parse allowed node id: %w", err))
+		return
+	}
+	if Config.AccessList.DeniedIDs, err = parseNodeIDs(*deniedIDs); err != nil {
+		errs.Add(fmt.Errorf("couldn't parse denied node id: %w", err))
+		return
+	}
+	if Config.AccessList.AllowedIPs, err = parseIPs(*allowedIPs); err != nil {
+		errs.Add(fmt.Errorf("couldn't parse allowed ip: %w", err))
+		return
+	}
+	if Config.AccessList.DeniedIPs, err = parseIPs(*deniedIPs); err != nil {
+		errs.Add(fmt.Errorf("couldn't parse denied ip: %w", err))
+		return
+	}
+
+	// Minimum peer version:
+	if *minVersion != "" {
+		if Config.MinimumVersion, err = version.NewDefaultParser().Parse(*minVersion); err != nil {
+			errs.Add(fmt.Errorf("couldn't parse minimum version: %w", err))
+			return
+		}
+	}
+
+	// Subnet gossip quotas:
+	if Config.SubnetGossipQuotas, err = parseGossipQuotas(*gossipQuotas); err != nil {
+		errs.Add(err)
+		return
+	}
+
 	// Plugins
 	if _, err := os.Stat(Config.PluginDir); os.IsNotExist(err) {
 		for _, dir := range defaultPluginDirs {
@@ -439,6 +665,24 @@ func init() {
 	// Router used for consensus
 	Config.ConsensusRouter = &router.ChainRouter{}
 
+	// API authentication
+	Config.AdminAPIAuthTokens = parseTokens(*adminAuthTokens)
+	Config.KeystoreAPIAuthTokens = parseTokens(*keystoreAuthTokens)
+
+	// API rate limits
+	if *apiRateLimit != "" {
+		limit, err := parseRateLimit(*apiRateLimit, *apiRateLimitPerClient)
+		if err != nil {
+			errs.Add(err)
+			return
+		}
+		Config.APIRateLimit = &limit
+	}
+	if Config.APIRateLimits, err = parseRateLimits(*apiRateLimits, *apiRateLimitPerClient); err != nil {
+		errs.Add(err)
+		return
+	}
+
 	// IPCs
 	if *ipcsChainIDs != "" {
 		Config.IPCDefaultChainIDs = strings.Split(*ipcsChainIDs, ",")
@@ -0,0 +1,44 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// AccessList restricts which peers the network may be connected to. The zero
+// value allows every peer.
+type AccessList struct {
+	// If either allow list is non-empty, a peer may only connect if its node
+	// ID is in AllowedIDs or its IP is in AllowedIPs
+	AllowedIDs ids.ShortSet
+	AllowedIPs []net.IP
+
+	// A peer whose node ID is in DeniedIDs, or whose IP is in DeniedIPs, may
+	// never connect
+	DeniedIDs ids.ShortSet
+	DeniedIPs []net.IP
+}
+
+// Allowed returns true if a peer with node ID [id] at [ip] may connect
+func (a *AccessList) Allowed(id ids.ShortID, ip net.IP) bool {
+	if a.DeniedIDs.Contains(id) || containsIP(a.DeniedIPs, ip) {
+		return false
+	}
+	if a.AllowedIDs.Len() == 0 && len(a.AllowedIPs) == 0 {
+		return true
+	}
+	return a.AllowedIDs.Contains(id) || containsIP(a.AllowedIPs, ip)
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, listedIP := range ips {
+		if listedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
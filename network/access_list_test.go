@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestAccessListEmpty(t *testing.T) {
+	a := AccessList{}
+	assert.True(t, a.Allowed(ids.NewShortID([20]byte{1}), net.IPv4(1, 2, 3, 4)))
+}
+
+func TestAccessListDenied(t *testing.T) {
+	id0 := ids.NewShortID([20]byte{0})
+	id1 := ids.NewShortID([20]byte{1})
+	ip0 := net.IPv4(1, 2, 3, 4)
+	ip1 := net.IPv4(5, 6, 7, 8)
+
+	a := AccessList{
+		DeniedIDs: ids.ShortSet{},
+		DeniedIPs: []net.IP{ip1},
+	}
+	a.DeniedIDs.Add(id1)
+
+	assert.True(t, a.Allowed(id0, ip0))
+	assert.False(t, a.Allowed(id1, ip0))
+	assert.False(t, a.Allowed(id0, ip1))
+}
+
+func TestAccessListAllowed(t *testing.T) {
+	id0 := ids.NewShortID([20]byte{0})
+	id1 := ids.NewShortID([20]byte{1})
+	id2 := ids.NewShortID([20]byte{2})
+	ip0 := net.IPv4(1, 2, 3, 4)
+	ip1 := net.IPv4(5, 6, 7, 8)
+
+	a := AccessList{
+		AllowedIDs: ids.ShortSet{},
+		AllowedIPs: []net.IP{ip1},
+		DeniedIDs:  ids.ShortSet{},
+	}
+	a.AllowedIDs.Add(id0, id2)
+	a.DeniedIDs.Add(id2)
+
+	assert.True(t, a.Allowed(id0, ip0))
+	assert.True(t, a.Allowed(id1, ip1))
+	assert.False(t, a.Allowed(id1, ip0))
+
+	// Being denied takes precedence over being allowed
+	assert.False(t, a.Allowed(id2, ip0))
+}
@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/math"
+)
+
+// beaconGate is a handler that calls [start] once enough beacons are
+// connected, or once its timeout passes. A beacon is counted once, no matter
+// how many times it's reported as connected.
+type beaconGate struct {
+	lock sync.Mutex
+	log  logging.Logger
+
+	beacons    validators.Set
+	reqWeight  uint64
+	minBeacons int
+
+	connected ids.ShortSet
+	weight    uint64
+
+	// set once [start] has been called
+	done    bool
+	timer   *time.Timer
+	waiting prometheus.Gauge
+	start   func()
+}
+
+// newBeaconGate returns a gate that calls [start] once the connected beacons
+// have at least [reqWeight] weight and there are at least [minBeacons] of them.
+// [waiting] is incremented until [start] is called.
+func newBeaconGate(
+	log logging.Logger,
+	beacons validators.Set,
+	reqWeight uint64,
+	minBeacons int,
+	waiting prometheus.Gauge,
+	start func(),
+) *beaconGate {
+	// Waiting for more beacons than exist would always time out
+	if numBeacons := beacons.Len(); minBeacons > numBeacons {
+		minBeacons = numBeacons
+	}
+	waiting.Inc()
+	return &beaconGate{
+		log:        log,
+		beacons:    beacons,
+		reqWeight:  reqWeight,
+		minBeacons: minBeacons,
+		waiting:    waiting,
+		start:      start,
+	}
+}
+
+// startTimeout calls [start] after [timeout] if enough beacons haven't
+// connected by then
+func (g *beaconGate) startTimeout(timeout time.Duration) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if !g.done {
+		g.timer = time.AfterFunc(timeout, g.timeout)
+	}
+}
+
+func (g *beaconGate) timeout() {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.done {
+		return
+	}
+	g.log.Warn("starting consensus with %d beacons, of weight %d, connected after waiting for %d beacons of weight %d",
+		g.connected.Len(), g.weight, g.minBeacons, g.reqWeight)
+	g.finish()
+}
+
+// Connected implements the Handler interface
+func (g *beaconGate) Connected(vdrID ids.ShortID) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.done {
+		return true
+	}
+	vdr, ok := g.beacons.Get(vdrID)
+	if !ok || g.connected.Contains(vdrID) {
+		return false
+	}
+	g.connected.Add(vdrID)
+	// If the weight overflows, the required weight was surpassed
+	weight, err := math.Add64(g.weight, vdr.Weight())
+	if err != nil {
+		weight = g.reqWeight
+	}
+	g.weight = weight
+
+	if g.weight >= g.reqWeight && g.connected.Len() >= g.minBeacons {
+		g.finish()
+	}
+	return g.done
+}
+
+// Disconnected implements the Handler interface
+func (g *beaconGate) Disconnected(vdrID ids.ShortID) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.done {
+		return true
+	}
+	if !g.connected.Contains(vdrID) {
+		return false
+	}
+	g.connected.Remove(vdrID)
+	if vdr, ok := g.beacons.Get(vdrID); ok {
+		// The beacon's weight may have changed since it connected, so Sub64
+		// may underflow, in which case it returns 0
+		g.weight, _ = math.Sub64(g.weight, vdr.Weight())
+	}
+	return false
+}
+
+// finish calls [start]. Assumes the lock is held.
+func (g *beaconGate) finish() {
+	g.done = true
+	if g.timer != nil {
+		g.timer.Stop()
+	}
+	g.waiting.Dec()
+	go g.start()
+}
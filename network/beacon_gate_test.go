@@ -0,0 +1,107 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestBeaconGateDefersStart(t *testing.T) {
+	vdrID0 := ids.NewShortID([20]byte{0})
+	vdrID1 := ids.NewShortID([20]byte{1})
+	vdrID2 := ids.NewShortID([20]byte{2})
+	vdrID3 := ids.NewShortID([20]byte{3})
+
+	beacons := validators.NewSet()
+	beacons.Add(validators.NewValidator(vdrID0, 10))
+	beacons.Add(validators.NewValidator(vdrID1, 1))
+	beacons.Add(validators.NewValidator(vdrID2, 1))
+
+	waiting := prometheus.NewGauge(prometheus.GaugeOpts{Name: "waiting"})
+	started := make(chan struct{}, 1)
+	gate := newBeaconGate(logging.NoLog{}, beacons, 10, 2, waiting, func() {
+		started <- struct{}{}
+	})
+	assert.Equal(t, float64(1), testutil.ToFloat64(waiting))
+
+	// Beacons that disconnected don't count
+	assert.False(t, gate.Connected(vdrID1))
+	assert.False(t, gate.Disconnected(vdrID1))
+	assert.False(t, gate.Disconnected(vdrID1))
+	// The weight requirement is met, but not enough beacons are connected
+	assert.False(t, gate.Connected(vdrID0))
+	// Reconnecting the same beacon doesn't count twice
+	assert.False(t, gate.Connected(vdrID0))
+	// Nodes that aren't beacons don't count
+	assert.False(t, gate.Connected(vdrID3))
+
+	select {
+	case <-started:
+		t.Fatal("Consensus started before enough beacons were connected")
+	default:
+	}
+	assert.Equal(t, float64(1), testutil.ToFloat64(waiting))
+
+	assert.True(t, gate.Connected(vdrID2))
+	<-started
+	assert.Equal(t, float64(0), testutil.ToFloat64(waiting))
+
+	// Once started, the gate should be removed
+	assert.True(t, gate.Connected(vdrID1))
+	assert.True(t, gate.Disconnected(vdrID1))
+}
+
+func TestBeaconGateMinBeaconsCapped(t *testing.T) {
+	vdrID := ids.NewShortID([20]byte{0})
+
+	beacons := validators.NewSet()
+	beacons.Add(validators.NewValidator(vdrID, 1))
+
+	waiting := prometheus.NewGauge(prometheus.GaugeOpts{Name: "waiting"})
+	started := make(chan struct{}, 1)
+	gate := newBeaconGate(logging.NoLog{}, beacons, 1, 5, waiting, func() {
+		started <- struct{}{}
+	})
+
+	assert.True(t, gate.Connected(vdrID))
+	<-started
+}
+
+func TestBeaconGateTimeout(t *testing.T) {
+	vdrID := ids.NewShortID([20]byte{0})
+
+	beacons := validators.NewSet()
+	beacons.Add(validators.NewValidator(vdrID, 1))
+
+	waiting := prometheus.NewGauge(prometheus.GaugeOpts{Name: "waiting"})
+	started := make(chan struct{}, 1)
+	gate := newBeaconGate(logging.NoLog{}, beacons, 1, 1, waiting, func() {
+		started <- struct{}{}
+	})
+	gate.startTimeout(time.Millisecond)
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Consensus should have started once the timeout passed")
+	}
+	assert.Equal(t, float64(0), testutil.ToFloat64(waiting))
+
+	// The beacon connecting afterwards shouldn't start consensus again
+	assert.True(t, gate.Connected(vdrID))
+	select {
+	case <-started:
+		t.Fatal("Consensus was started twice")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -0,0 +1,199 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// DefaultBootstrapResolveFrequency is how often bootstrap hostnames are
+// re-resolved by default
+const DefaultBootstrapResolveFrequency = 5 * time.Minute
+
+// Resolver looks up the IPs of a hostname. The lookup should be abandoned once
+// [ctx] is cancelled.
+type Resolver interface {
+	LookupIP(ctx context.Context, host string) ([]net.IP, error)
+}
+
+// DNSResolver looks up hostnames with the system's resolver
+type DNSResolver struct{}
+
+// LookupIP implements the Resolver interface
+func (DNSResolver) LookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		ips[i] = addr.IP
+	}
+	return ips, nil
+}
+
+type bootstrapHost struct {
+	addr string // host:port, as provided
+	host string
+	port uint16
+}
+
+// BootstrapResolver periodically resolves the hostnames of bootstrap peers and
+// tracks the IPs they resolve to, so that a bootstrap peer whose IP changes is
+// still found. IPs that no host resolves to anymore are untracked. Both IPv4
+// and IPv6 addresses are tracked.
+type BootstrapResolver struct {
+	log      logging.Logger
+	resolver Resolver
+	track    func(ip utils.IPDesc)
+	untrack  func(ip utils.IPDesc)
+	hosts    []bootstrapHost
+	repeater *timer.Repeater
+
+	// Cancelled by Stop, so that a lookup in progress doesn't delay it
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock sync.Mutex
+	ips  map[string][]utils.IPDesc
+}
+
+// NewBootstrapResolver returns a resolver that resolves [hosts], each of the
+// form host:port, every [frequency] and passes the resolved IPs to [track].
+// IPs that were previously resolved, but aren't anymore, are passed to
+// [untrack].
+func NewBootstrapResolver(
+	log logging.Logger,
+	resolver Resolver,
+	track func(ip utils.IPDesc),
+	untrack func(ip utils.IPDesc),
+	hosts []string,
+	frequency time.Duration,
+) (*BootstrapResolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &BootstrapResolver{
+		log:      log,
+		resolver: resolver,
+		track:    track,
+		untrack:  untrack,
+		ctx:      ctx,
+		cancel:   cancel,
+		ips:      make(map[string][]utils.IPDesc),
+	}
+	for _, addr := range hosts {
+		host, portStr, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse bootstrap host %q: %w", addr, err)
+		}
+		port, err := strconv.ParseUint(portStr, 10 /*=base*/, 16 /*=size*/)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse port of bootstrap host %q: %w", addr, err)
+		}
+		r.hosts = append(r.hosts, bootstrapHost{
+			addr: addr,
+			host: host,
+			port: uint16(port),
+		})
+	}
+	r.repeater = timer.NewRepeater(r.Resolve, frequency)
+	return r, nil
+}
+
+// Dispatch resolves the hosts immediately and then every time the frequency
+// passes, until Stop is called
+func (r *BootstrapResolver) Dispatch() {
+	r.Resolve()
+	r.repeater.Dispatch()
+}
+
+// Stop re-resolving the hosts. A lookup in progress is abandoned. Dispatch must
+// have been called before this is called.
+func (r *BootstrapResolver) Stop() {
+	r.cancel()
+	r.repeater.Stop()
+}
+
+// Resolve every host once. If a host can't be resolved, the IPs it previously
+// resolved to are kept.
+func (r *BootstrapResolver) Resolve() {
+	for _, host := range r.hosts {
+		resolved, err := r.resolver.LookupIP(r.ctx, host.host)
+		if r.ctx.Err() != nil {
+			// Stopped while resolving
+			return
+		}
+		if err != nil {
+			r.log.Warn("failed to resolve bootstrap host %s: %s", host.addr, err)
+			continue
+		}
+
+		ips := make([]utils.IPDesc, len(resolved))
+		for i, ip := range resolved {
+			ips[i] = utils.IPDesc{
+				IP:   ip,
+				Port: host.port,
+			}
+		}
+
+		r.lock.Lock()
+		previous := r.ips[host.addr]
+		r.ips[host.addr] = ips
+		removed := []utils.IPDesc(nil)
+		for _, ip := range previous {
+			if !r.resolvedByAnyHost(ip) {
+				removed = append(removed, ip)
+			}
+		}
+		r.lock.Unlock()
+
+		for _, ip := range removed {
+			r.log.Info("bootstrap host %s no longer resolves to %s", host.addr, ip)
+			r.untrack(ip)
+		}
+		for _, ip := range ips {
+			if !containsIPDesc(previous, ip) {
+				r.log.Info("bootstrap host %s resolved to %s", host.addr, ip)
+			}
+			// Tracking an IP that is already tracked or connected is a no-op,
+			// so this also reconnects to peers that have since disconnected
+			r.track(ip)
+		}
+	}
+}
+
+// assumes the lock is held
+func (r *BootstrapResolver) resolvedByAnyHost(ip utils.IPDesc) bool {
+	for _, ips := range r.ips {
+		if containsIPDesc(ips, ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IPs returns the IPs that [host] most recently resolved to
+func (r *BootstrapResolver) IPs(host string) []utils.IPDesc {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.ips[host]
+}
+
+func containsIPDesc(ips []utils.IPDesc, ip utils.IPDesc) bool {
+	for _, other := range ips {
+		if other.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+var errNoSuchHost = errors.New("no such host")
+
+type testResolver struct {
+	lock sync.Mutex
+	ips  map[string][]net.IP
+}
+
+func (r *testResolver) set(host string, ips ...net.IP) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if len(ips) == 0 {
+		delete(r.ips, host)
+	} else {
+		r.ips[host] = ips
+	}
+}
+
+func (r *testResolver) LookupIP(_ context.Context, host string) ([]net.IP, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ips, ok := r.ips[host]
+	if !ok {
+		return nil, errNoSuchHost
+	}
+	return ips, nil
+}
+
+// blockingResolver blocks every lookup until it is cancelled
+type blockingResolver struct{ started chan struct{} }
+
+func (r *blockingResolver) LookupIP(ctx context.Context, _ string) ([]net.IP, error) {
+	r.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type testTracker struct {
+	lock      sync.Mutex
+	tracked   []utils.IPDesc
+	untracked []utils.IPDesc
+}
+
+func (t *testTracker) Track(ip utils.IPDesc) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.tracked = append(t.tracked, ip)
+}
+
+func (t *testTracker) Untrack(ip utils.IPDesc) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.untracked = append(t.untracked, ip)
+}
+
+func (t *testTracker) IPs() []utils.IPDesc {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return append([]utils.IPDesc(nil), t.tracked...)
+}
+
+func (t *testTracker) UntrackedIPs() []utils.IPDesc {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return append([]utils.IPDesc(nil), t.untracked...)
+}
+
+func TestBootstrapResolverReresolves(t *testing.T) {
+	ip0 := net.IPv4(1, 2, 3, 4)
+	ip1 := net.ParseIP("2001:db8::1")
+	resolver := &testResolver{ips: map[string][]net.IP{}}
+	resolver.set("beacon.example.com", ip0)
+	tracker := &testTracker{}
+
+	r, err := NewBootstrapResolver(
+		logging.NoLog{},
+		resolver,
+		tracker.Track,
+		tracker.Untrack,
+		[]string{"beacon.example.com:9651"},
+		time.Hour,
+	)
+	assert.NoError(t, err)
+
+	r.Resolve()
+	desc0 := utils.IPDesc{IP: ip0, Port: 9651}
+	assert.Equal(t, []utils.IPDesc{desc0}, r.IPs("beacon.example.com:9651"))
+	assert.Equal(t, []utils.IPDesc{desc0}, tracker.IPs())
+
+	// The beacon moved to an IPv6 address
+	resolver.set("beacon.example.com", ip1)
+	r.Resolve()
+	desc1 := utils.IPDesc{IP: ip1, Port: 9651}
+	assert.Equal(t, []utils.IPDesc{desc1}, r.IPs("beacon.example.com:9651"))
+	assert.Equal(t, []utils.IPDesc{desc0, desc1}, tracker.IPs())
+	assert.Equal(t, []utils.IPDesc{desc0}, tracker.UntrackedIPs())
+
+	// Failing to resolve the host keeps the previous IPs
+	resolver.set("beacon.example.com")
+	r.Resolve()
+	assert.Equal(t, []utils.IPDesc{desc1}, r.IPs("beacon.example.com:9651"))
+	assert.Equal(t, []utils.IPDesc{desc0, desc1}, tracker.IPs())
+	assert.Equal(t, []utils.IPDesc{desc0}, tracker.UntrackedIPs())
+}
+
+func TestBootstrapResolverKeepsSharedIPs(t *testing.T) {
+	ip0 := net.IPv4(1, 2, 3, 4)
+	ip1 := net.IPv4(5, 6, 7, 8)
+	resolver := &testResolver{ips: map[string][]net.IP{}}
+	resolver.set("beacon0.example.com", ip0)
+	resolver.set("beacon1.example.com", ip0)
+	tracker := &testTracker{}
+
+	r, err := NewBootstrapResolver(
+		logging.NoLog{},
+		resolver,
+		tracker.Track,
+		tracker.Untrack,
+		[]string{"beacon0.example.com:9651", "beacon1.example.com:9651"},
+		time.Hour,
+	)
+	assert.NoError(t, err)
+	r.Resolve()
+
+	// The IP is still resolved by the other host, so it must stay tracked
+	resolver.set("beacon0.example.com", ip1)
+	r.Resolve()
+	assert.Empty(t, tracker.UntrackedIPs())
+
+	resolver.set("beacon1.example.com", ip1)
+	r.Resolve()
+	assert.Equal(t, []utils.IPDesc{{IP: ip0, Port: 9651}}, tracker.UntrackedIPs())
+}
+
+func TestBootstrapResolverStopAbandonsLookup(t *testing.T) {
+	resolver := &blockingResolver{started: make(chan struct{}, 1)}
+	r, err := NewBootstrapResolver(
+		logging.NoLog{},
+		resolver,
+		func(utils.IPDesc) {},
+		func(utils.IPDesc) {},
+		[]string{"beacon.example.com:9651"},
+		time.Hour,
+	)
+	assert.NoError(t, err)
+
+	go r.Dispatch()
+	<-resolver.started
+
+	stopped := make(chan struct{})
+	go func() {
+		r.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop should have cancelled the lookup in progress")
+	}
+}
+
+func TestBootstrapResolverDispatch(t *testing.T) {
+	resolver := &testResolver{ips: map[string][]net.IP{}}
+	resolver.set("beacon.example.com", net.IPv4(1, 2, 3, 4))
+
+	tracked := make(chan utils.IPDesc, 1)
+	r, err := NewBootstrapResolver(
+		logging.NoLog{},
+		resolver,
+		func(ip utils.IPDesc) {
+			select {
+			case tracked <- ip:
+			default:
+			}
+		},
+		func(utils.IPDesc) {},
+		[]string{"beacon.example.com:9651"},
+		time.Hour,
+	)
+	assert.NoError(t, err)
+
+	go r.Dispatch()
+
+	// Dispatch resolves immediately, rather than after the frequency passes
+	select {
+	case ip := <-tracked:
+		assert.Equal(t, "1.2.3.4:9651", ip.String())
+	case <-time.After(5 * time.Second):
+		t.Fatal("should have resolved the host on dispatch")
+	}
+	r.Stop()
+}
+
+func TestBootstrapResolverInvalidHost(t *testing.T) {
+	for _, host := range []string{"beacon.example.com", "beacon.example.com:port", "beacon.example.com:65536"} {
+		_, err := NewBootstrapResolver(
+			logging.NoLog{},
+			DNSResolver{},
+			func(utils.IPDesc) {},
+			func(utils.IPDesc) {},
+			[]string{host},
+			time.Hour,
+		)
+		assert.Error(t, err, host)
+	}
+}
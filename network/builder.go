@@ -39,6 +39,20 @@ func (m Builder) Ping() (Msg, error) { return m.Pack(Ping, nil) }
 // Pong message
 func (m Builder) Pong() (Msg, error) { return m.Pack(Pong, nil) }
 
+// Capabilities message
+func (m Builder) Capabilities(flags uint32) (Msg, error) {
+	return m.Pack(Capabilities, map[Field]interface{}{CapabilityFlags: flags})
+}
+
+// SignedIP message
+func (m Builder) SignedIP(ip utils.IPDesc, timestamp uint64, sig []byte) (Msg, error) {
+	return m.Pack(SignedIP, map[Field]interface{}{
+		IP:        ip,
+		MyTime:    timestamp,
+		Signature: sig,
+	})
+}
+
 // GetAcceptedFrontier message
 func (m Builder) GetAcceptedFrontier(chainID ids.ID, requestID uint32, deadline uint64) (Msg, error) {
 	return m.Pack(GetAcceptedFrontier, map[Field]interface{}{
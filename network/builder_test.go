@@ -96,6 +96,31 @@ func TestBuildPeerList(t *testing.T) {
 	assert.Equal(t, ips, parsedMsg.Get(Peers))
 }
 
+func TestBuildSignedIP(t *testing.T) {
+	ip := utils.IPDesc{
+		IP:   net.IPv6loopback,
+		Port: 12345,
+	}
+	timestamp := uint64(2)
+	sig := []byte{1, 2, 3}
+
+	msg, err := TestBuilder.SignedIP(ip, timestamp, sig)
+	assert.NoError(t, err)
+	assert.NotNil(t, msg)
+	assert.Equal(t, SignedIP, msg.Op())
+	assert.Equal(t, ip, msg.Get(IP))
+	assert.Equal(t, timestamp, msg.Get(MyTime))
+	assert.Equal(t, sig, msg.Get(Signature))
+
+	parsedMsg, err := TestBuilder.Parse(msg.Bytes())
+	assert.NoError(t, err)
+	assert.NotNil(t, parsedMsg)
+	assert.Equal(t, SignedIP, parsedMsg.Op())
+	assert.Equal(t, ip, parsedMsg.Get(IP))
+	assert.Equal(t, timestamp, parsedMsg.Get(MyTime))
+	assert.Equal(t, sig, parsedMsg.Get(Signature))
+}
+
 func TestBuildGetAcceptedFrontier(t *testing.T) {
 	chainID := ids.Empty.Prefix(0)
 	requestID := uint32(5)
@@ -4,8 +4,12 @@
 package network
 
 import (
+	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math"
 
 	"github.com/ava-labs/gecko/utils/wrappers"
@@ -15,8 +19,13 @@ var (
 	errBadLength    = errors.New("stream has unexpected length")
 	errMissingField = errors.New("message missing field")
 	errBadOp        = errors.New("input field has invalid operation")
+	errTooLarge     = errors.New("decompressed message is too large")
 )
 
+// compressionThreshold is the minimum size, in bytes, of a message for it to
+// be compressed. Compressing smaller messages isn't worth the overhead.
+const compressionThreshold = 1 << 10
+
 // Codec defines the serialization and deserialization of network messages
 type Codec struct{}
 
@@ -45,10 +54,62 @@ func (Codec) Pack(op Op, fields map[Field]interface{}) (Msg, error) {
 	}, p.Err
 }
 
+// Compress returns the bytes to send to a peer that accepts compressed
+// messages. If compressing the message wouldn't make it smaller, the
+// uncompressed bytes are returned.
+func (Codec) Compress(m Msg) []byte {
+	uncompressed := m.Bytes()
+	if len(uncompressed) < compressionThreshold {
+		return uncompressed
+	}
+
+	// Messages are frequently sent to multiple peers, so only compress them
+	// once
+	cachedMsg, cacheable := m.(*msg)
+	if cacheable && cachedMsg.compressed != nil {
+		return cachedMsg.compressed
+	}
+
+	buf := bytes.Buffer{}
+	buf.WriteByte(byte(Compressed))
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(uncompressed); err != nil {
+		return uncompressed
+	}
+	if err := w.Close(); err != nil {
+		return uncompressed
+	}
+
+	compressed := buf.Bytes()
+	if len(compressed) >= len(uncompressed) {
+		compressed = uncompressed
+	}
+	if cacheable {
+		cachedMsg.compressed = compressed
+	}
+	return compressed
+}
+
 // Parse attempts to convert bytes into a message.
-// The first byte of the message is the opcode of the message.
-func (Codec) Parse(b []byte) (Msg, error) {
+// The first byte of the message is the opcode of the message. If the opcode is
+// Compressed, the remaining bytes are decompressed and parsed as a message.
+// The decompressed message may be at most DefaultMaxMessageSize bytes.
+func (c Codec) Parse(b []byte) (Msg, error) { return c.ParseLimited(b, DefaultMaxMessageSize) }
+
+// ParseLimited is Parse, except that a compressed message may decompress to at
+// most [maxSize] bytes.
+func (Codec) ParseLimited(b []byte, maxSize uint32) (Msg, error) {
 	p := wrappers.Packer{Bytes: b}
+	if op, err := p.Peek(); err == nil && Op(op) == Compressed {
+		p.Skip(wrappers.ByteLen)
+		decompressed, err := decompress(b[p.Offset:], maxSize)
+		if err != nil {
+			return nil, err
+		}
+		b = decompressed
+		p = wrappers.Packer{Bytes: b}
+	}
+
 	op := Op(p.UnpackByte())
 	message, ok := Messages[op]
 	if !ok {
@@ -70,3 +131,19 @@ func (Codec) Parse(b []byte) (Msg, error) {
 		bytes:  b,
 	}, p.Err
 }
+
+// decompress [b], returning an error if the result is larger than [maxSize]
+func decompress(b []byte, maxSize uint32) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	decompressed, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(decompressed) > int(maxSize) {
+		return nil, errTooLarge
+	}
+	return decompressed, r.Close()
+}
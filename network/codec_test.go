@@ -4,10 +4,14 @@
 package network
 
 import (
+	"bytes"
+	"compress/gzip"
 	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 var (
@@ -33,3 +37,74 @@ func TestCodecParseExtraSpace(t *testing.T) {
 	_, err := TestCodec.Parse([]byte{byte(GetVersion), 0x00})
 	assert.Error(t, err)
 }
+
+func TestCodecCompressRoundTrip(t *testing.T) {
+	container := bytes.Repeat([]byte{1, 2, 3, 4}, compressionThreshold)
+	msg, err := TestCodec.Pack(Put, map[Field]interface{}{
+		ChainID:        make([]byte, hashing.HashLen),
+		RequestID:      uint32(1),
+		ContainerID:    make([]byte, hashing.HashLen),
+		ContainerBytes: container,
+	})
+	assert.NoError(t, err)
+
+	compressed := TestCodec.Compress(msg)
+	assert.Equal(t, Compressed, Op(compressed[0]))
+	assert.Less(t, len(compressed), len(msg.Bytes()))
+
+	// The compressed bytes should be cached
+	assert.Equal(t, compressed, TestCodec.Compress(msg))
+
+	parsedMsg, err := TestCodec.Parse(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, Put, parsedMsg.Op())
+	assert.Equal(t, msg.Bytes(), parsedMsg.Bytes())
+	assert.Equal(t, container, parsedMsg.Get(ContainerBytes))
+}
+
+func TestCodecCompressSmallMessage(t *testing.T) {
+	msg, err := TestCodec.Pack(Ping, nil)
+	assert.NoError(t, err)
+
+	uncompressed := TestCodec.Compress(msg)
+	assert.Equal(t, msg.Bytes(), uncompressed)
+
+	parsedMsg, err := TestCodec.Parse(uncompressed)
+	assert.NoError(t, err)
+	assert.Equal(t, Ping, parsedMsg.Op())
+}
+
+func TestCodecParseCompressedTooLarge(t *testing.T) {
+	buf := bytes.Buffer{}
+	buf.WriteByte(byte(Compressed))
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write(make([]byte, DefaultMaxMessageSize+1))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	_, err = TestCodec.Parse(buf.Bytes())
+	assert.Error(t, err)
+}
+
+func TestCodecParseInvalidCompressed(t *testing.T) {
+	_, err := TestCodec.Parse([]byte{byte(Compressed), 0x00})
+	assert.Error(t, err)
+}
+
+func TestCodecParseLimited(t *testing.T) {
+	container := bytes.Repeat([]byte{1, 2, 3, 4}, compressionThreshold)
+	msg, err := TestCodec.Pack(Put, map[Field]interface{}{
+		ChainID:        make([]byte, hashing.HashLen),
+		RequestID:      uint32(1),
+		ContainerID:    make([]byte, hashing.HashLen),
+		ContainerBytes: container,
+	})
+	assert.NoError(t, err)
+	compressed := TestCodec.Compress(msg)
+
+	_, err = TestCodec.ParseLimited(compressed, uint32(len(msg.Bytes())))
+	assert.NoError(t, err)
+
+	_, err = TestCodec.ParseLimited(compressed, uint32(len(msg.Bytes())-1))
+	assert.Error(t, err)
+}
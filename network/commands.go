@@ -25,6 +25,16 @@ const (
 	ContainerBytes                   // Used for gossiping
 	ContainerIDs                     // Used for querying
 	MultiContainerBytes              // Used in MultiPut
+	CapabilityFlags                  // Used in Capabilities
+	Signature                        // Used in SignedIP
+)
+
+// Capabilities that a peer may advertise in a Capabilities message
+const (
+	// CompressionCapability signals that the peer accepts Compressed messages
+	CompressionCapability uint32 = 1 << iota
+	// SignedIPCapability signals that the peer accepts SignedIP messages
+	SignedIPCapability
 )
 
 // Packer returns the packer function that can be used to pack this field.
@@ -56,6 +66,10 @@ func (f Field) Packer() func(*wrappers.Packer, interface{}) {
 		return wrappers.TryPackHashes
 	case MultiContainerBytes:
 		return wrappers.TryPack2DBytes
+	case CapabilityFlags:
+		return wrappers.TryPackInt
+	case Signature:
+		return wrappers.TryPackBytes
 	default:
 		return nil
 	}
@@ -90,6 +104,10 @@ func (f Field) Unpacker() func(*wrappers.Packer) interface{} {
 		return wrappers.TryUnpackHashes
 	case MultiContainerBytes:
 		return wrappers.TryUnpack2DBytes
+	case CapabilityFlags:
+		return wrappers.TryUnpackInt
+	case Signature:
+		return wrappers.TryUnpackBytes
 	default:
 		return nil
 	}
@@ -123,6 +141,10 @@ func (f Field) String() string {
 		return "Container IDs"
 	case MultiContainerBytes:
 		return "MultiContainerBytes"
+	case CapabilityFlags:
+		return "CapabilityFlags"
+	case Signature:
+		return "Signature"
 	default:
 		return "Unknown Field"
 	}
@@ -167,6 +189,12 @@ func (op Op) String() string {
 		return "pull_query"
 	case Chits:
 		return "chits"
+	case Capabilities:
+		return "capabilities"
+	case Compressed:
+		return "compressed"
+	case SignedIP:
+		return "signed_ip"
 	default:
 		return "Unknown Op"
 	}
@@ -194,6 +222,14 @@ const (
 	PushQuery
 	PullQuery
 	Chits
+	// Handshake:
+	Capabilities
+	// Compressed messages wrap another message. They are only sent to peers
+	// that have advertised the CompressionCapability.
+	Compressed
+	// SignedIP announces a new IP of the sender, signed by its staking key.
+	// It is only sent to peers that have advertised the SignedIPCapability.
+	SignedIP
 )
 
 // Defines the messages that can be sent/received with this network
@@ -219,5 +255,8 @@ var (
 		PushQuery: {ChainID, RequestID, Deadline, ContainerID, ContainerBytes},
 		PullQuery: {ChainID, RequestID, Deadline, ContainerID},
 		Chits:     {ChainID, RequestID, ContainerIDs},
+		// Handshake:
+		Capabilities: {CapabilityFlags},
+		SignedIP:     {IP, MyTime, Signature},
 	}
 )
@@ -0,0 +1,104 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/ava-labs/gecko/utils"
+)
+
+var (
+	errTooManyConns       = errors.New("too many inbound connections")
+	errTooManyConnsFromIP = errors.New("too many inbound connections from IP")
+)
+
+// ConnectionLimits bounds the number of inbound connections the network holds
+// open, so that a single host can't exhaust this node's file descriptors. A
+// limit of zero is unlimited, so the zero value doesn't limit any connections.
+type ConnectionLimits struct {
+	// Maximum number of inbound connections
+	MaxConns int
+	// Maximum number of inbound connections from a single IP
+	MaxConnsPerIP int
+}
+
+// connCounter tracks the inbound connections that count against the
+// connection limits. It has its own lock, as connections may be closed while
+// the network's stateLock is held.
+type connCounter struct {
+	lock   sync.Mutex
+	limits ConnectionLimits
+	total  int
+	perIP  map[string]int
+}
+
+// setLimits changes the limits that new connections are checked against.
+// Connections that were already admitted are unaffected.
+func (c *connCounter) setLimits(limits ConnectionLimits) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.limits = limits
+}
+
+// admit returns a connection that releases its slot when it is closed, or an
+// error describing which limit accepting [conn] would exceed
+func (c *connCounter) admit(conn net.Conn) (net.Conn, error) {
+	ip := remoteIP(conn)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	switch {
+	case c.limits.MaxConns > 0 && c.total >= c.limits.MaxConns:
+		return nil, fmt.Errorf("%w: limit is %d", errTooManyConns, c.limits.MaxConns)
+	case c.limits.MaxConnsPerIP > 0 && c.perIP[ip] >= c.limits.MaxConnsPerIP:
+		return nil, fmt.Errorf("%w %s: limit is %d", errTooManyConnsFromIP, ip, c.limits.MaxConnsPerIP)
+	}
+
+	if c.perIP == nil {
+		c.perIP = make(map[string]int)
+	}
+	c.total++
+	c.perIP[ip]++
+	return &limitedConn{
+		Conn:    conn,
+		release: func() { c.release(ip) },
+	}, nil
+}
+
+func (c *connCounter) release(ip string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.total--
+	if c.perIP[ip]--; c.perIP[ip] <= 0 {
+		delete(c.perIP, ip)
+	}
+}
+
+// remoteIP returns the IP, without the port, that [conn] is from
+func remoteIP(conn net.Conn) string {
+	addr := conn.RemoteAddr().String()
+	if ip, err := utils.ToIPDesc(addr); err == nil {
+		return ip.IP.String()
+	}
+	return addr
+}
+
+// limitedConn releases its slot in the connection limits once it is closed
+type limitedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *limitedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
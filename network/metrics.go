@@ -6,12 +6,15 @@ package network
 import (
 	"fmt"
 
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 type messageMetrics struct {
 	numSent, numFailed, numReceived prometheus.Counter
+	sentBytes, receivedBytes        prometheus.Counter
+	handleTime                      prometheus.Histogram
 }
 
 func (mm *messageMetrics) initialize(msgType Op, registerer prometheus.Registerer) error {
@@ -33,6 +36,25 @@ func (mm *messageMetrics) initialize(msgType Op, registerer prometheus.Registere
 			Name:      fmt.Sprintf("%s_received", msgType),
 			Help:      fmt.Sprintf("Number of %s messages received", msgType),
 		})
+	mm.sentBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      fmt.Sprintf("%s_sent_bytes", msgType),
+			Help:      fmt.Sprintf("Number of bytes of %s messages queued to be sent", msgType),
+		})
+	mm.receivedBytes = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      fmt.Sprintf("%s_received_bytes", msgType),
+			Help:      fmt.Sprintf("Number of bytes of %s messages received", msgType),
+		})
+	mm.handleTime = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "gecko",
+			Name:      fmt.Sprintf("%s_handle_time", msgType),
+			Help:      fmt.Sprintf("Time spent handling %s messages before they're passed to a chain, in nanoseconds", msgType),
+			Buckets:   timer.NanosecondsBuckets,
+		})
 
 	if err := registerer.Register(mm.numSent); err != nil {
 		return fmt.Errorf("failed to register sent statistics of %s due to %s",
@@ -46,11 +68,34 @@ func (mm *messageMetrics) initialize(msgType Op, registerer prometheus.Registere
 		return fmt.Errorf("failed to register received statistics of %s due to %s",
 			msgType, err)
 	}
+	if err := registerer.Register(mm.sentBytes); err != nil {
+		return fmt.Errorf("failed to register sent bytes statistics of %s due to %s",
+			msgType, err)
+	}
+	if err := registerer.Register(mm.receivedBytes); err != nil {
+		return fmt.Errorf("failed to register received bytes statistics of %s due to %s",
+			msgType, err)
+	}
+	if err := registerer.Register(mm.handleTime); err != nil {
+		return fmt.Errorf("failed to register handle time statistics of %s due to %s",
+			msgType, err)
+	}
 	return nil
 }
 
 type metrics struct {
-	numPeers prometheus.Gauge
+	numPeers          prometheus.Gauge
+	backpressured     prometheus.Counter
+	numDeferredBytes  prometheus.Gauge
+	peerViolations    prometheus.Counter
+	peerEvictions     prometheus.Counter
+	rejectedConns     prometheus.Counter
+	versionMismatches prometheus.Counter
+	beaconWaits       prometheus.Gauge
+
+	// outbound gossip of each subnet, labeled by subnet ID
+	subnetGossipBytes     *prometheus.CounterVec
+	subnetGossipThrottled *prometheus.CounterVec
 
 	getVersion, version,
 	getPeerlist, peerlist,
@@ -58,7 +103,8 @@ type metrics struct {
 	getAcceptedFrontier, acceptedFrontier,
 	getAccepted, accepted,
 	get, getAncestors, put, multiPut,
-	pushQuery, pullQuery, chits messageMetrics
+	pushQuery, pullQuery, chits,
+	capabilities, signedIP messageMetrics
 }
 
 func (m *metrics) initialize(registerer prometheus.Registerer) error {
@@ -69,11 +115,114 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 			Help:      "Number of network peers",
 		})
 
+	m.backpressured = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "backpressured",
+			Help:      "Number of times messages from a peer were deferred because a chain was falling behind",
+		})
+
+	m.numDeferredBytes = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "gecko",
+			Name:      "deferred_bytes",
+			Help:      "Number of bytes of messages deferred until the chain they're destined for catches up",
+		})
+
+	m.peerViolations = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "peer_violations",
+			Help:      "Number of protocol violations committed by peers",
+		})
+
+	m.peerEvictions = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "peer_evictions",
+			Help:      "Number of peers disconnected because their reputation was too low",
+		})
+
+	m.rejectedConns = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "rejected_connections",
+			Help:      "Number of inbound connections rejected for exceeding the connection limits",
+		})
+
+	m.versionMismatches = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "version_mismatches",
+			Help:      "Number of peers disconnected because their version was incompatible or below the minimum",
+		})
+
+	m.beaconWaits = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "gecko",
+			Name:      "beacon_waits",
+			Help:      "Number of pending waits for beacons to connect before starting consensus",
+		})
+
+	m.subnetGossipBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "subnet_gossip_bytes",
+			Help:      "Number of bytes of containers gossiped by the chains of each subnet",
+		},
+		[]string{"subnet"},
+	)
+
+	m.subnetGossipThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "gecko",
+			Name:      "subnet_gossip_throttled",
+			Help:      "Number of containers not gossiped because their subnet was over its gossip quota",
+		},
+		[]string{"subnet"},
+	)
+
 	errs := wrappers.Errs{}
 	if err := registerer.Register(m.numPeers); err != nil {
 		errs.Add(fmt.Errorf("failed to register peers statistics due to %s",
 			err))
 	}
+	if err := registerer.Register(m.backpressured); err != nil {
+		errs.Add(fmt.Errorf("failed to register backpressured statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.numDeferredBytes); err != nil {
+		errs.Add(fmt.Errorf("failed to register deferred_bytes statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.peerViolations); err != nil {
+		errs.Add(fmt.Errorf("failed to register peer violations statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.peerEvictions); err != nil {
+		errs.Add(fmt.Errorf("failed to register peer evictions statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.rejectedConns); err != nil {
+		errs.Add(fmt.Errorf("failed to register rejected connections statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.versionMismatches); err != nil {
+		errs.Add(fmt.Errorf("failed to register version mismatches statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.beaconWaits); err != nil {
+		errs.Add(fmt.Errorf("failed to register beacon waits statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.subnetGossipBytes); err != nil {
+		errs.Add(fmt.Errorf("failed to register subnet gossip bytes statistics due to %s",
+			err))
+	}
+	if err := registerer.Register(m.subnetGossipThrottled); err != nil {
+		errs.Add(fmt.Errorf("failed to register subnet gossip throttled statistics due to %s",
+			err))
+	}
 
 	errs.Add(m.getVersion.initialize(GetVersion, registerer))
 	errs.Add(m.version.initialize(Version, registerer))
@@ -92,6 +241,8 @@ func (m *metrics) initialize(registerer prometheus.Registerer) error {
 	errs.Add(m.pushQuery.initialize(PushQuery, registerer))
 	errs.Add(m.pullQuery.initialize(PullQuery, registerer))
 	errs.Add(m.chits.initialize(Chits, registerer))
+	errs.Add(m.capabilities.initialize(Capabilities, registerer))
+	errs.Add(m.signedIP.initialize(SignedIP, registerer))
 
 	return errs.Err
 }
@@ -132,6 +283,10 @@ func (m *metrics) message(msgType Op) *messageMetrics {
 		return &m.pullQuery
 	case Chits:
 		return &m.chits
+	case Capabilities:
+		return &m.capabilities
+	case SignedIP:
+		return &m.signedIP
 	default:
 		return nil
 	}
@@ -14,6 +14,9 @@ type msg struct {
 	op     Op
 	fields map[Field]interface{}
 	bytes  []byte
+
+	// the compressed representation of [bytes], if it has been calculated
+	compressed []byte
 }
 
 // Field returns the value of the specified field in this message
@@ -4,6 +4,7 @@
 package network
 
 import (
+	"crypto"
 	"fmt"
 	"math/rand"
 	"net"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/ava-labs/gecko/api/health"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/networking/sender"
 	"github.com/ava-labs/gecko/snow/triggers"
@@ -33,6 +35,8 @@ const (
 	defaultInitialReconnectDelay                     = time.Second
 	defaultMaxReconnectDelay                         = time.Hour
 	DefaultMaxMessageSize                     uint32 = 1 << 21
+	DefaultHandshakeTimeout                          = 30 * time.Second
+	DefaultMessageReadTimeout                        = 30 * time.Second
 	defaultSendQueueSize                             = 1 << 10
 	defaultMaxNetworkPendingSendBytes                = 1 << 29 // 512MB
 	defaultNetworkPendingSendBytesToRateLimit        = defaultMaxNetworkPendingSendBytes / 4
@@ -45,6 +49,15 @@ const (
 	defaultGossipSize                                = 50
 	defaultPingPongTimeout                           = time.Minute
 	defaultPingFrequency                             = 3 * defaultPingPongTimeout / 4
+	defaultMaxPeerViolations                         = 10
+	defaultPeerViolationDecay                        = 10 * time.Minute
+	defaultMinGossipScore                            = 0.5
+	defaultMaxDeferredBytes                          = 1 << 26 // 64MB
+
+	// Gossip quotas are enforced over the last [gossipQuotaWindow], which
+	// expires in [gossipQuotaBuckets] steps
+	gossipQuotaWindow  = 10 * time.Second
+	gossipQuotaBuckets = 10
 )
 
 // Network defines the functionality of the networking library.
@@ -67,19 +80,116 @@ type Network interface {
 
 	// Attempt to connect to this IP. Thread safety must be managed internally
 	// to the network. The network will never stop attempting to connect to this
-	// IP.
+	// IP, unless it is untracked.
 	Track(ip utils.IPDesc)
 
+	// Stop attempting to connect to this IP. If this IP is already connected
+	// to, the connection isn't closed. Thread safety must be managed
+	// internally to the network.
+	Untrack(ip utils.IPDesc)
+
 	// Register a new handler that is called whenever a peer is connected to or
 	// disconnected to. If the handler returns true, then it will never be
 	// called again. Thread safety must be managed internally in the network.
 	// The handler will initially be called with this local node's ID.
 	RegisterHandler(h Handler)
 
+	// Send [msg] to the peer with ID [validatorID]. Returns true iff the
+	// message was queued to be written to the peer. The message is dropped,
+	// and false is returned, if the peer isn't connected or its send queue is
+	// full. Thread safety must be managed internally to the network.
+	Send(validatorID ids.ShortID, msg Msg) bool
+
 	// Returns the description of the nodes this network is currently connected
 	// to externally. Thread safety must be managed internally to the network.
 	Peers() []PeerID
 
+	// Restrict which peers may connect to this node. Existing connections to
+	// peers that are no longer allowed are closed. Thread safety must be
+	// managed internally to the network.
+	SetAccessList(accessList AccessList)
+
+	// Limit the number of inbound connections this node accepts. Connections
+	// that were already accepted are unaffected. Thread safety must be managed
+	// internally to the network.
+	SetConnectionLimits(limits ConnectionLimits)
+
+	// Close connections that haven't finished upgrading and exchanging
+	// versions within [timeout]. If [timeout] isn't positive, handshakes never
+	// time out. Connections that were already accepted are unaffected. Thread
+	// safety must be managed internally to the network.
+	SetHandshakeTimeout(timeout time.Duration)
+
+	// Close connections that start sending a message but don't finish it
+	// within [timeout], so that a peer can't hold a connection open by
+	// sending a message slowly. If [timeout] isn't positive, messages may take
+	// as long as the ping timeout allows. Connections that were already
+	// accepted are unaffected. Thread safety must be managed internally to the
+	// network.
+	SetMessageReadTimeout(timeout time.Duration)
+
+	// Send TCP keepalive probes on idle connections every [period]. If
+	// [period] isn't positive, the default keepalive settings are used.
+	// Connections that were already accepted are unaffected. Thread safety
+	// must be managed internally to the network.
+	SetKeepAlivePeriod(period time.Duration)
+
+	// Close connections to peers that haven't sent any messages, other than
+	// pings and pongs, within [timeout]. If [timeout] isn't positive, idle
+	// peers aren't disconnected. Connections that were already accepted are
+	// unaffected. Thread safety must be managed internally to the network.
+	SetIdleTimeout(timeout time.Duration)
+
+	// Disconnect from peers whose version is before [minVersion], in addition
+	// to peers whose version isn't compatible with ours. If [minVersion] is
+	// nil, only compatibility is checked. Connections that already finished
+	// their handshake are unaffected. Thread safety must be managed internally
+	// to the network.
+	SetMinimumVersion(minVersion version.Version)
+
+	// Require at least [minBeacons] beacons to be connected before AwaitBeacons
+	// starts a chain, in addition to its weight requirement. If [timeout] is
+	// positive, chains are started after waiting [timeout] even if the
+	// requirements weren't met. Chains that are already waiting are
+	// unaffected. Thread safety must be managed internally to the network.
+	SetBeaconGate(minBeacons int, timeout time.Duration)
+
+	// Account the containers gossiped by each chain to the subnet [lookup]
+	// reports for it. Until this is called, gossip isn't accounted to subnets
+	// and gossip quotas aren't enforced. Thread safety must be managed
+	// internally to the network.
+	SetSubnetLookup(lookup snow.SubnetLookup)
+
+	// Limit the containers gossiped by the chains of [subnetID] to about
+	// [bytesPerSecond] of outbound bandwidth, averaged over the last ten
+	// seconds. Containers gossiped while the subnet is over its quota are
+	// dropped, so that one busy subnet can't starve the others. If
+	// [bytesPerSecond] is 0, the subnet's gossip isn't limited. Thread safety
+	// must be managed internally to the network.
+	SetGossipQuota(subnetID ids.ID, bytesPerSecond uint64)
+
+	// Call [start] once the connected peers in [beacons] have at least
+	// [reqWeight] weight, subject to the beacon gate. Thread safety must be
+	// managed internally to the network.
+	AwaitBeacons(beacons validators.Set, reqWeight uint64, start func())
+
+	// Lower the reputation of [validatorID] because it misbehaved, for
+	// example by sending conflicting votes. If the validator isn't connected,
+	// this is a no-op. Thread safety must be managed internally to the
+	// network.
+	Penalize(validatorID ids.ShortID, reason string)
+
+	// Set the key that this node's IP announcements are signed with. It should
+	// be the private key of this node's staking certificate. Thread safety must
+	// be managed internally to the network.
+	SetStakingKey(key crypto.Signer)
+
+	// Announce that this node can now be reached at [ip]. Peers that verify
+	// the announcement against this node's staking key will reconnect to [ip]
+	// rather than the IP they knew. Returns an error if no staking key was
+	// set. Thread safety must be managed internally to the network.
+	UpdateIP(ip utils.IPDesc) error
+
 	// Close this network and all existing connections it has. Thread safety
 	// must be managed internally to the network. Calling close multiple times
 	// will return a nil error.
@@ -104,6 +214,10 @@ type network struct {
 	beacons        validators.Set // set of beacons in the Avalanche network
 	router         router.Router  // router must be thread safe
 
+	// peers running at least this version are sent our capabilities. If nil,
+	// compression is disabled.
+	compressionVersion version.Version
+
 	nodeID uint32
 
 	clock         timer.Clock
@@ -124,11 +238,47 @@ type network struct {
 	gossipSize                         int
 	pingPongTimeout                    time.Duration
 	pingFrequency                      time.Duration
-
-	executor timer.Executor
+	handshakeTimeout                   time.Duration
+	msgReadTimeout                     time.Duration
+	keepAlivePeriod                    time.Duration
+	idleTimeout                        time.Duration
+	minVersion                         version.Version
+	minBeacons                         int
+	beaconTimeout                      time.Duration
+
+	// maps chains to subnets so that gossip can be accounted to subnets. The
+	// bandwidth of subnets with a quota, in bytes per second, is tracked by
+	// [gossipMeters].
+	subnetLookup snow.SubnetLookup
+	gossipQuotas map[[32]byte]uint64
+	gossipMeters map[[32]byte]*timer.WeightedMeter
+
+	// a peer that commits [maxPeerViolations] protocol violations within
+	// [peerViolationDecay] is disconnected. Peers with a score below
+	// [minGossipScore] aren't gossiped to.
+	maxPeerViolations  int
+	peerViolationDecay time.Duration
+	minGossipScore     float64
+
+	// messages for a chain that is falling behind are deferred, rather than
+	// handled, until the chain catches up. Once [maxDeferredBytes] are deferred
+	// across all peers, peers with deferred messages stop reading from their
+	// connections until [deferredHandled] signals that some were handled.
+	// [deferredBytes] is only modified with the state lock held.
+	maxDeferredBytes int
+	deferredBytes    int
+	deferredHandled  *sync.Cond
+
+	// signs announcements of this node's IP. [ipTimestamp] is the timestamp
+	// of the latest announcement, so that peers can ignore stale ones.
+	stakingKey  crypto.Signer
+	ipTimestamp uint64
 
 	b Builder
 
+	// inbound connections that count against the connection limits
+	conns connCounter
+
 	stateLock       sync.Mutex
 	pendingBytes    int
 	closed          bool
@@ -136,9 +286,10 @@ type network struct {
 	connectedIPs    map[string]struct{}
 	retryDelay      map[string]time.Duration
 	// TODO: bound the size of [myIPs] to avoid DoS. LRU caching would be ideal
-	myIPs    map[string]struct{} // set of IPs that resulted in my ID.
-	peers    map[[20]byte]*peer
-	handlers []Handler
+	myIPs      map[string]struct{} // set of IPs that resulted in my ID.
+	peers      map[[20]byte]*peer
+	handlers   []Handler
+	accessList AccessList
 }
 
 // NewDefaultNetwork returns a new Network implementation with the provided
@@ -158,6 +309,7 @@ func NewDefaultNetwork(
 	vdrs validators.Set,
 	beacons validators.Set,
 	router router.Router,
+	compressionVersion version.Version,
 ) Network {
 	return NewNetwork(
 		registerer,
@@ -174,6 +326,7 @@ func NewDefaultNetwork(
 		vdrs,
 		beacons,
 		router,
+		compressionVersion,
 		defaultInitialReconnectDelay,
 		defaultMaxReconnectDelay,
 		DefaultMaxMessageSize,
@@ -208,6 +361,7 @@ func NewNetwork(
 	vdrs validators.Set,
 	beacons validators.Set,
 	router router.Router,
+	compressionVersion version.Version,
 	initialReconnectDelay,
 	maxReconnectDelay time.Duration,
 	maxMessageSize uint32,
@@ -238,6 +392,7 @@ func NewNetwork(
 		vdrs:                               vdrs,
 		beacons:                            beacons,
 		router:                             router,
+		compressionVersion:                 compressionVersion,
 		nodeID:                             rand.Uint32(),
 		initialReconnectDelay:              initialReconnectDelay,
 		maxReconnectDelay:                  maxReconnectDelay,
@@ -254,41 +409,50 @@ func NewNetwork(
 		gossipSize:                         gossipSize,
 		pingPongTimeout:                    pingPongTimeout,
 		pingFrequency:                      pingFrequency,
+		handshakeTimeout:                   DefaultHandshakeTimeout,
+		msgReadTimeout:                     DefaultMessageReadTimeout,
+		maxPeerViolations:                  defaultMaxPeerViolations,
+		peerViolationDecay:                 defaultPeerViolationDecay,
+		minGossipScore:                     defaultMinGossipScore,
+		maxDeferredBytes:                   defaultMaxDeferredBytes,
 		disconnectedIPs:                    make(map[string]struct{}),
 		connectedIPs:                       make(map[string]struct{}),
 		retryDelay:                         make(map[string]time.Duration),
+		gossipQuotas:                       make(map[[32]byte]uint64),
+		gossipMeters:                       make(map[[32]byte]*timer.WeightedMeter),
 		myIPs:                              map[string]struct{}{ip.String(): {}},
 		peers:                              make(map[[20]byte]*peer),
 	}
+	netw.deferredHandled = sync.NewCond(&netw.stateLock)
 	if err := netw.initialize(registerer); err != nil {
 		log.Warn("initializing network metrics failed with: %s", err)
 	}
-	netw.executor.Initialize()
 	netw.heartbeat()
 	return netw
 }
 
 // GetAcceptedFrontier implements the Sender interface.
-func (n *network) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) {
+func (n *network) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) ids.ShortSet {
 	msg, err := n.b.GetAcceptedFrontier(chainID, requestID, uint64(deadline.Sub(n.clock.Time())))
 	n.log.AssertNoError(err)
 
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
+	failed := ids.ShortSet{}
 	for _, validatorID := range validatorIDs.List() {
-		vID := validatorID
-		peer, sent := n.peers[vID.Key()]
+		peer, sent := n.peers[validatorID.Key()]
 		if sent {
 			sent = peer.send(msg)
 		}
 		if !sent {
-			n.executor.Add(func() { n.router.GetAcceptedFrontierFailed(vID, chainID, requestID) })
+			failed.Add(validatorID)
 			n.getAcceptedFrontier.numFailed.Inc()
 		} else {
 			n.getAcceptedFrontier.numSent.Inc()
 		}
 	}
+	return failed
 }
 
 // AcceptedFrontier implements the Sender interface.
@@ -323,7 +487,7 @@ func (n *network) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requ
 }
 
 // GetAccepted implements the Sender interface.
-func (n *network) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerIDs ids.Set) {
+func (n *network) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerIDs ids.Set) ids.ShortSet {
 	msg, err := n.b.GetAccepted(chainID, requestID, uint64(deadline.Sub(n.clock.Time())), containerIDs)
 	if err != nil {
 		n.log.Error("failed to build GetAccepted(%s, %d, %s): %s",
@@ -331,19 +495,15 @@ func (n *network) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, request
 			requestID,
 			containerIDs,
 			err)
-		for _, validatorID := range validatorIDs.List() {
-			vID := validatorID
-			n.executor.Add(func() { n.router.GetAcceptedFailed(vID, chainID, requestID) })
-		}
-		return
+		return validatorIDs
 	}
 
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
+	failed := ids.ShortSet{}
 	for _, validatorID := range validatorIDs.List() {
-		vID := validatorID
-		peer, sent := n.peers[vID.Key()]
+		peer, sent := n.peers[validatorID.Key()]
 		if sent {
 			sent = peer.send(msg)
 		}
@@ -353,12 +513,13 @@ func (n *network) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, request
 				chainID,
 				requestID,
 				containerIDs)
-			n.executor.Add(func() { n.router.GetAcceptedFailed(vID, chainID, requestID) })
+			failed.Add(validatorID)
 			n.getAccepted.numFailed.Inc()
 		} else {
 			n.getAccepted.numSent.Inc()
 		}
 	}
+	return failed
 }
 
 // Accepted implements the Sender interface.
@@ -393,11 +554,11 @@ func (n *network) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID ui
 }
 
 // GetAncestors implements the Sender interface.
-func (n *network) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) {
+func (n *network) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) bool {
 	msg, err := n.b.GetAncestors(chainID, requestID, uint64(deadline.Sub(n.clock.Time())), containerID)
 	if err != nil {
 		n.log.Error("failed to build GetAncestors message: %s", err)
-		return
+		return false
 	}
 
 	n.stateLock.Lock()
@@ -413,11 +574,11 @@ func (n *network) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestI
 			chainID,
 			requestID,
 			containerID)
-		n.executor.Add(func() { n.router.GetAncestorsFailed(validatorID, chainID, requestID) })
 		n.getAncestors.numFailed.Inc()
 	} else {
 		n.getAncestors.numSent.Inc()
 	}
+	return sent
 }
 
 // MultiPut implements the Sender interface.
@@ -448,7 +609,7 @@ func (n *network) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID ui
 }
 
 // Get implements the Sender interface.
-func (n *network) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) {
+func (n *network) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) bool {
 	msg, err := n.b.Get(chainID, requestID, uint64(deadline.Sub(n.clock.Time())), containerID)
 	n.log.AssertNoError(err)
 
@@ -465,11 +626,11 @@ func (n *network) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32,
 			chainID,
 			requestID,
 			containerID)
-		n.executor.Add(func() { n.router.GetFailed(validatorID, chainID, requestID) })
 		n.get.numFailed.Inc()
 	} else {
 		n.get.numSent.Inc()
 	}
+	return sent
 }
 
 // Put implements the Sender interface.
@@ -506,7 +667,7 @@ func (n *network) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32,
 }
 
 // PushQuery implements the Sender interface.
-func (n *network) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, container []byte) {
+func (n *network) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, container []byte) ids.ShortSet {
 	msg, err := n.b.PushQuery(chainID, requestID, uint64(deadline.Sub(n.clock.Time())), containerID, container)
 
 	if err != nil {
@@ -517,19 +678,15 @@ func (n *network) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 			err,
 			len(container))
 		n.log.Verbo("container: %s", formatting.DumpBytes{Bytes: container})
-		for _, validatorID := range validatorIDs.List() {
-			vID := validatorID
-			n.executor.Add(func() { n.router.QueryFailed(vID, chainID, requestID) })
-		}
-		return // Packing message failed
+		return validatorIDs // Packing message failed
 	}
 
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
+	failed := ids.ShortSet{}
 	for _, validatorID := range validatorIDs.List() {
-		vID := validatorID
-		peer, sent := n.peers[vID.Key()]
+		peer, sent := n.peers[validatorID.Key()]
 		if sent {
 			sent = peer.send(msg)
 		}
@@ -540,25 +697,26 @@ func (n *network) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 				requestID,
 				containerID)
 			n.log.Verbo("container: %s", formatting.DumpBytes{Bytes: container})
-			n.executor.Add(func() { n.router.QueryFailed(vID, chainID, requestID) })
+			failed.Add(validatorID)
 			n.pushQuery.numFailed.Inc()
 		} else {
 			n.pushQuery.numSent.Inc()
 		}
 	}
+	return failed
 }
 
 // PullQuery implements the Sender interface.
-func (n *network) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) {
+func (n *network) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) ids.ShortSet {
 	msg, err := n.b.PullQuery(chainID, requestID, uint64(deadline.Sub(n.clock.Time())), containerID)
 	n.log.AssertNoError(err)
 
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
+	failed := ids.ShortSet{}
 	for _, validatorID := range validatorIDs.List() {
-		vID := validatorID
-		peer, sent := n.peers[vID.Key()]
+		peer, sent := n.peers[validatorID.Key()]
 		if sent {
 			sent = peer.send(msg)
 		}
@@ -568,12 +726,13 @@ func (n *network) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 				chainID,
 				requestID,
 				containerID)
-			n.executor.Add(func() { n.router.QueryFailed(vID, chainID, requestID) })
+			failed.Add(validatorID)
 			n.pullQuery.numFailed.Inc()
 		} else {
 			n.pullQuery.numSent.Inc()
 		}
 	}
+	return failed
 }
 
 // Chits implements the Sender interface.
@@ -607,6 +766,26 @@ func (n *network) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint3
 	}
 }
 
+// Send implements the Network interface
+func (n *network) Send(validatorID ids.ShortID, msg Msg) bool {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	peer, sent := n.peers[validatorID.Key()]
+	if sent {
+		sent = peer.send(msg)
+	}
+
+	if msgMetrics := n.message(msg.Op()); msgMetrics != nil {
+		if sent {
+			msgMetrics.numSent.Inc()
+		} else {
+			msgMetrics.numFailed.Inc()
+		}
+	}
+	return sent
+}
+
 // Gossip attempts to gossip the container to the network
 func (n *network) Gossip(chainID, containerID ids.ID, container []byte) {
 	if err := n.gossipContainer(chainID, containerID, container); err != nil {
@@ -643,6 +822,15 @@ func (n *network) Dispatch() error {
 			n.log.Debug("error during server accept: %s", err)
 			continue
 		}
+		n.keepAlive(conn)
+		admitted, err := n.conns.admit(conn)
+		if err != nil {
+			n.log.Debug("rejecting connection from %s: %s", conn.RemoteAddr(), err)
+			n.rejectedConns.Inc()
+			_ = conn.Close()
+			continue
+		}
+		conn = admitted
 		go n.upgrade(&peer{
 			net:  n,
 			conn: conn,
@@ -683,12 +871,183 @@ func (n *network) Peers() []PeerID {
 				Version:      peer.versionStr,
 				LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
 				LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
+				Score:        peer.Score(),
 			})
 		}
 	}
 	return peers
 }
 
+// SetAccessList implements the Network interface
+func (n *network) SetAccessList(accessList AccessList) {
+	n.stateLock.Lock()
+	n.accessList = accessList
+
+	peersToClose := []*peer(nil)
+	for _, peer := range n.peers {
+		if !n.allowed(peer) {
+			peersToClose = append(peersToClose, peer)
+		}
+	}
+	n.stateLock.Unlock()
+
+	for _, peer := range peersToClose {
+		n.log.Debug("disconnecting from %s because it is no longer allowed", peer.id)
+		peer.Close() // Grabs the stateLock
+	}
+}
+
+// SetConnectionLimits implements the Network interface
+func (n *network) SetConnectionLimits(limits ConnectionLimits) { n.conns.setLimits(limits) }
+
+// SetHandshakeTimeout implements the Network interface
+func (n *network) SetHandshakeTimeout(timeout time.Duration) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.handshakeTimeout = timeout
+}
+
+// SetMessageReadTimeout implements the Network interface
+func (n *network) SetMessageReadTimeout(timeout time.Duration) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.msgReadTimeout = timeout
+}
+
+// SetKeepAlivePeriod implements the Network interface
+func (n *network) SetKeepAlivePeriod(period time.Duration) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.keepAlivePeriod = period
+}
+
+// SetIdleTimeout implements the Network interface
+func (n *network) SetIdleTimeout(timeout time.Duration) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.idleTimeout = timeout
+}
+
+// SetMinimumVersion implements the Network interface
+func (n *network) SetMinimumVersion(minVersion version.Version) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.minVersion = minVersion
+}
+
+// SetBeaconGate implements the Network interface
+func (n *network) SetBeaconGate(minBeacons int, timeout time.Duration) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.minBeacons = minBeacons
+	n.beaconTimeout = timeout
+}
+
+// SetSubnetLookup implements the Network interface
+func (n *network) SetSubnetLookup(lookup snow.SubnetLookup) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.subnetLookup = lookup
+}
+
+// SetGossipQuota implements the Network interface
+func (n *network) SetGossipQuota(subnetID ids.ID, bytesPerSecond uint64) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	key := subnetID.Key()
+	if bytesPerSecond == 0 {
+		delete(n.gossipQuotas, key)
+		delete(n.gossipMeters, key)
+		return
+	}
+	n.gossipQuotas[key] = bytesPerSecond
+	if _, exists := n.gossipMeters[key]; !exists {
+		n.gossipMeters[key] = timer.NewWeightedMeter(&n.clock, gossipQuotaWindow, gossipQuotaBuckets)
+	}
+}
+
+// AwaitBeacons implements the Network interface
+func (n *network) AwaitBeacons(beacons validators.Set, reqWeight uint64, start func()) {
+	n.stateLock.Lock()
+	minBeacons, timeout := n.minBeacons, n.beaconTimeout
+	n.stateLock.Unlock()
+
+	gate := newBeaconGate(n.log, beacons, reqWeight, minBeacons, n.beaconWaits, start)
+	if timeout > 0 {
+		gate.startTimeout(timeout)
+	}
+	n.RegisterHandler(gate)
+}
+
+// Penalize implements the Network interface
+// assumes the stateLock is not held.
+func (n *network) Penalize(validatorID ids.ShortID, reason string) {
+	n.stateLock.Lock()
+	peer, connected := n.peers[validatorID.Key()]
+	n.stateLock.Unlock()
+
+	if connected {
+		peer.violation(reason)
+	}
+}
+
+// SetStakingKey implements the Network interface
+func (n *network) SetStakingKey(key crypto.Signer) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.stakingKey = key
+}
+
+// UpdateIP implements the Network interface
+func (n *network) UpdateIP(ip utils.IPDesc) error {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	if n.stakingKey == nil {
+		return errNoStakingKey
+	}
+
+	// Announcements made within the same second must still be ordered
+	timestamp := n.clock.Unix()
+	if timestamp <= n.ipTimestamp {
+		timestamp = n.ipTimestamp + 1
+	}
+	sig, err := signIP(n.stakingKey, ip, timestamp)
+	if err != nil {
+		return err
+	}
+	msg, err := n.b.SignedIP(ip, timestamp, sig)
+	if err != nil {
+		return err
+	}
+
+	n.log.Info("announcing my new ip %s", ip)
+	n.ip = ip
+	n.ipTimestamp = timestamp
+	n.myIPs[ip.String()] = struct{}{}
+
+	for _, peer := range n.peers {
+		if !peer.connected || !peer.signedIPs {
+			continue
+		}
+		if peer.send(msg) {
+			n.signedIP.numSent.Inc()
+		} else {
+			n.signedIP.numFailed.Inc()
+		}
+	}
+	return nil
+}
+
 // Close implements the Network interface
 func (n *network) Close() error {
 	n.stateLock.Lock()
@@ -723,6 +1082,17 @@ func (n *network) Track(ip utils.IPDesc) {
 	n.track(ip)
 }
 
+// Untrack implements the Network interface
+func (n *network) Untrack(ip utils.IPDesc) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	// connectTo stops retrying once the IP is no longer marked as disconnected
+	str := ip.String()
+	delete(n.disconnectedIPs, str)
+	delete(n.retryDelay, str)
+}
+
 // assumes the stateLock is not held.
 func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte) error {
 	msg, err := n.b.Put(chainID, constants.GossipMsgRequestID, containerID, container)
@@ -730,12 +1100,35 @@ func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte)
 		return fmt.Errorf("attempted to pack too large of a Put message.\nContainer length: %d", len(container))
 	}
 
+	// The lookup may grab the chain manager's lock, so it must be called
+	// without the stateLock held
+	n.stateLock.Lock()
+	lookup := n.subnetLookup
+	n.stateLock.Unlock()
+
+	subnetID := ids.ID{}
+	if lookup != nil {
+		if subnetID, err = lookup.SubnetID(chainID); err != nil {
+			n.log.Debug("couldn't account gossip of %s to a subnet: %s", chainID, err)
+			subnetID = ids.ID{}
+		}
+	}
+
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
+	if !subnetID.IsZero() && n.gossipThrottled(subnetID) {
+		n.subnetGossipThrottled.WithLabelValues(subnetID.String()).Inc()
+		n.log.Verbo("not gossiping %s as subnet %s is over its gossip quota", containerID, subnetID)
+		return nil
+	}
+
+	// Peers that have recently misbehaved are skipped
 	allPeers := make([]*peer, 0, len(n.peers))
 	for _, peer := range n.peers {
-		allPeers = append(allPeers, peer)
+		if peer.Score() >= n.minGossipScore {
+			allPeers = append(allPeers, peer)
+		}
 	}
 
 	numToGossip := n.gossipSize
@@ -751,16 +1144,38 @@ func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte)
 	if err != nil {
 		return err
 	}
+	numSent := 0
 	for _, index := range indices {
 		if allPeers[int(index)].send(msg) {
 			n.put.numSent.Inc()
+			numSent++
 		} else {
 			n.put.numFailed.Inc()
 		}
 	}
+
+	if !subnetID.IsZero() {
+		sentBytes := uint64(numSent * len(msg.Bytes()))
+		n.subnetGossipBytes.WithLabelValues(subnetID.String()).Add(float64(sentBytes))
+		if meter, exists := n.gossipMeters[subnetID.Key()]; exists {
+			meter.Add(sentBytes)
+		}
+	}
 	return nil
 }
 
+// gossipThrottled returns true if [subnetID] has used up its gossip quota.
+// assumes the stateLock is held.
+func (n *network) gossipThrottled(subnetID ids.ID) bool {
+	key := subnetID.Key()
+	meter, exists := n.gossipMeters[key]
+	if !exists {
+		return false
+	}
+	limit := n.gossipQuotas[key] * uint64(gossipQuotaWindow/time.Second)
+	return meter.Weight() >= limit
+}
+
 // assumes the stateLock is held.
 func (n *network) track(ip utils.IPDesc) {
 	if n.closed {
@@ -775,6 +1190,7 @@ func (n *network) track(ip utils.IPDesc) {
 		return
 	}
 	if _, ok := n.myIPs[str]; ok {
+		n.log.Debug("not connecting to %s because it is my IP", str)
 		return
 	}
 	n.disconnectedIPs[str] = struct{}{}
@@ -810,6 +1226,9 @@ func (n *network) gossip() {
 		stakers := []*peer(nil)
 		nonStakers := []*peer(nil)
 		for _, peer := range n.peers {
+			if peer.Score() < n.minGossipScore {
+				continue
+			}
 			if n.vdrs.Contains(peer.id) {
 				stakers = append(stakers, peer)
 			} else {
@@ -931,6 +1350,7 @@ func (n *network) attemptConnect(ip utils.IPDesc) error {
 	if err != nil {
 		return err
 	}
+	n.keepAlive(conn)
 	return n.upgrade(&peer{
 		net:  n,
 		ip:   ip,
@@ -938,17 +1358,60 @@ func (n *network) attemptConnect(ip utils.IPDesc) error {
 	}, n.clientUpgrader)
 }
 
+// keepAlive applies the keepalive period to [conn], if it's a TCP connection.
+// assumes the stateLock is not held.
+func (n *network) keepAlive(conn net.Conn) {
+	n.stateLock.Lock()
+	period := n.keepAlivePeriod
+	n.stateLock.Unlock()
+
+	tcpConn, ok := conn.(*net.TCPConn)
+	if period <= 0 || !ok {
+		return
+	}
+	if err := tcpConn.SetKeepAlive(true); err != nil {
+		n.log.Debug("failed to enable keepalives on %s: %s", conn.RemoteAddr(), err)
+		return
+	}
+	if err := tcpConn.SetKeepAlivePeriod(period); err != nil {
+		n.log.Debug("failed to set the keepalive period on %s: %s", conn.RemoteAddr(), err)
+	}
+}
+
 // assumes the stateLock is not held. Returns an error if the peer's connection
 // wasn't able to be upgraded.
 func (n *network) upgrade(p *peer, upgrader Upgrader) error {
+	n.stateLock.Lock()
+	handshakeTimeout := n.handshakeTimeout
+	n.stateLock.Unlock()
+	if handshakeTimeout > 0 {
+		// Closing the underlying connection also closes the upgraded one
+		rawConn := p.conn
+		p.handshakeTimer = timer.AfterFunc(&n.clock, handshakeTimeout, func() {
+			n.stateLock.Lock()
+			connected := p.connected
+			n.stateLock.Unlock()
+
+			if !connected {
+				n.log.Debug("closing connection to %s because its handshake didn't finish within %s",
+					rawConn.RemoteAddr(), handshakeTimeout)
+				_ = rawConn.Close()
+			}
+		})
+	}
+
 	id, conn, err := upgrader.Upgrade(p.conn)
 	if err != nil {
 		n.log.Verbo("failed to upgrade connection with %s", err)
+		p.stopHandshakeTimer()
+		_ = p.conn.Close()
 		return err
 	}
 	p.sender = make(chan []byte, n.sendQueueSize)
+	p.violations.Duration = n.peerViolationDecay
 	p.id = id
 	p.conn = conn
+	p.stakingKey = stakingKey(conn)
 
 	key := id.Key()
 
@@ -958,6 +1421,7 @@ func (n *network) upgrade(p *peer, upgrader Upgrader) error {
 	if n.closed {
 		// the network is closing, so make sure that no further reconnect
 		// attempts are made.
+		p.stopHandshakeTimer()
 		_ = p.conn.Close()
 		return nil
 	}
@@ -965,6 +1429,7 @@ func (n *network) upgrade(p *peer, upgrader Upgrader) error {
 	// if this connection is myself, then I should delete the connection and
 	// mark the IP as one of mine.
 	if id.Equals(n.id) {
+		n.log.Debug("closing connection to %s because it is to myself", p.conn.RemoteAddr())
 		if !p.ip.IsZero() {
 			// if n.ip is less useful than p.ip set it to this IP
 			if n.ip.IsZero() {
@@ -979,6 +1444,21 @@ func (n *network) upgrade(p *peer, upgrader Upgrader) error {
 		}
 		// don't attempt to reconnect to myself, so return nil even if closing
 		// returns an error
+		p.stopHandshakeTimer()
+		_ = p.conn.Close()
+		return nil
+	}
+
+	// If this peer isn't allowed to connect, then I should close the
+	// connection and not attempt to reconnect to it.
+	if !n.allowed(p) {
+		n.log.Debug("rejecting connection from %s because it isn't allowed", id)
+		if !p.ip.IsZero() {
+			str := p.ip.String()
+			delete(n.disconnectedIPs, str)
+			delete(n.retryDelay, str)
+		}
+		p.stopHandshakeTimer()
 		_ = p.conn.Close()
 		return nil
 	}
@@ -993,6 +1473,7 @@ func (n *network) upgrade(p *peer, upgrader Upgrader) error {
 		}
 		// I'm already connected to this peer, so don't attempt to reconnect to
 		// this ip, even if an error occurres during closing
+		p.stopHandshakeTimer()
 		_ = p.conn.Close()
 		return nil
 	}
@@ -1003,6 +1484,16 @@ func (n *network) upgrade(p *peer, upgrader Upgrader) error {
 	return nil
 }
 
+// assumes the stateLock is held. Returns true if the access list allows [p] to
+// be connected to.
+func (n *network) allowed(p *peer) bool {
+	ip := p.ip.IP
+	if remoteIP, err := utils.ToIPDesc(p.conn.RemoteAddr().String()); err == nil {
+		ip = remoteIP.IP
+	}
+	return n.accessList.Allowed(p.id, ip)
+}
+
 // assumes the stateLock is not held. Returns the ips of connections that have
 // valid IPs that are marked as validators.
 func (n *network) validatorIPs() []utils.IPDesc {
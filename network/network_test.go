@@ -4,6 +4,9 @@
 package network
 
 import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"net"
 	"sync"
@@ -11,6 +14,8 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/stretchr/testify/assert"
 
@@ -18,8 +23,10 @@ import (
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/constants"
 	"github.com/ava-labs/gecko/utils/hashing"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/version"
 )
 
@@ -28,6 +35,13 @@ var (
 	errRefused = errors.New("connection refused")
 )
 
+// errTimeout is returned by reads that pass their deadline
+type errTimeout struct{}
+
+func (errTimeout) Error() string   { return "i/o timeout" }
+func (errTimeout) Timeout() bool   { return true }
+func (errTimeout) Temporary() bool { return true }
+
 type testListener struct {
 	addr    net.Addr
 	inbound chan net.Conn
@@ -91,10 +105,24 @@ type testConn struct {
 	closed        chan struct{}
 	once          sync.Once
 
+	deadlineLock sync.Mutex
+	readDeadline time.Time
+
 	local, remote net.Addr
 }
 
 func (c *testConn) Read(b []byte) (int, error) {
+	c.deadlineLock.Lock()
+	deadline := c.readDeadline
+	c.deadlineLock.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
 	for len(c.partialRead) == 0 {
 		select {
 		case read, ok := <-c.pendingReads:
@@ -104,6 +132,8 @@ func (c *testConn) Read(b []byte) (int, error) {
 			c.partialRead = read
 		case _, _ = <-c.closed:
 			return 0, errClosed
+		case <-timeout:
+			return 0, errTimeout{}
 		}
 	}
 
@@ -137,9 +167,16 @@ func (c *testConn) Close() error {
 func (c *testConn) LocalAddr() net.Addr              { return c.local }
 func (c *testConn) RemoteAddr() net.Addr             { return c.remote }
 func (c *testConn) SetDeadline(time.Time) error      { return nil }
-func (c *testConn) SetReadDeadline(time.Time) error  { return nil }
 func (c *testConn) SetWriteDeadline(time.Time) error { return nil }
 
+func (c *testConn) SetReadDeadline(t time.Time) error {
+	c.deadlineLock.Lock()
+	defer c.deadlineLock.Unlock()
+
+	c.readDeadline = t
+	return nil
+}
+
 type testHandler struct {
 	connected    func(ids.ShortID) bool
 	disconnected func(ids.ShortID) bool
@@ -199,6 +236,7 @@ func TestNewDefaultNetwork(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net)
 
@@ -283,6 +321,7 @@ func TestEstablishConnection(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -301,6 +340,7 @@ func TestEstablishConnection(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -424,6 +464,7 @@ func TestDoubleTrack(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -442,6 +483,7 @@ func TestDoubleTrack(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -566,6 +608,7 @@ func TestDoubleClose(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -584,6 +627,7 @@ func TestDoubleClose(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -713,6 +757,7 @@ func TestRemoveHandlers(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -731,6 +776,7 @@ func TestRemoveHandlers(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -869,6 +915,7 @@ func TestTrackConnected(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -887,6 +934,7 @@ func TestTrackConnected(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -1012,6 +1060,7 @@ func TestTrackConnectedRace(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net0)
 
@@ -1030,6 +1079,7 @@ func TestTrackConnectedRace(t *testing.T) {
 		vdrs,
 		vdrs,
 		handler,
+		nil,
 	)
 	assert.NotNil(t, net1)
 
@@ -1050,3 +1100,1158 @@ func TestTrackConnectedRace(t *testing.T) {
 	err = net1.Close()
 	assert.NoError(t, err)
 }
+
+// newTestNetworks returns two dispatched networks that can dial each other. The
+// networks must be closed by the caller.
+func newTestNetworks(t *testing.T, compressionVersion0, compressionVersion1 version.Version) (*network, *network) {
+	log := logging.NoLog{}
+	networkID := uint32(0)
+	appVersion := version.NewDefaultVersion("app", 0, 1, 0)
+	versionParser := version.NewDefaultParser()
+
+	ip0 := utils.IPDesc{
+		IP:   net.IPv6loopback,
+		Port: 0,
+	}
+	ip1 := utils.IPDesc{
+		IP:   net.IPv6loopback,
+		Port: 1,
+	}
+
+	listener0 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller0 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	listener1 := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller1 := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 1,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+
+	caller0.outbounds[ip1.String()] = listener1
+	caller1.outbounds[ip0.String()] = listener0
+
+	serverUpgrader := NewIPUpgrader()
+	clientUpgrader := NewIPUpgrader()
+
+	vdrs := validators.NewSet()
+	handler := router.Router(nil)
+
+	net0 := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		ids.NewShortID(hashing.ComputeHash160Array([]byte(ip0.String()))),
+		ip0,
+		networkID,
+		appVersion,
+		versionParser,
+		listener0,
+		caller0,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		vdrs,
+		handler,
+		compressionVersion0,
+	)
+	assert.NotNil(t, net0)
+
+	net1 := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		ids.NewShortID(hashing.ComputeHash160Array([]byte(ip1.String()))),
+		ip1,
+		networkID,
+		appVersion,
+		versionParser,
+		listener1,
+		caller1,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		vdrs,
+		handler,
+		compressionVersion1,
+	)
+	assert.NotNil(t, net1)
+
+	go func() {
+		err := net0.Dispatch()
+		assert.Error(t, err)
+	}()
+	go func() {
+		err := net1.Dispatch()
+		assert.Error(t, err)
+	}()
+
+	return net0.(*network), net1.(*network)
+}
+
+// connectTestNetworks has [net0] connect to [net1] and waits until both
+// networks have registered the connection
+func connectTestNetworks(net0, net1 *network) {
+	var (
+		wg0 sync.WaitGroup
+		wg1 sync.WaitGroup
+	)
+	wg0.Add(1)
+	wg1.Add(1)
+
+	h0 := &testHandler{
+		connected: func(id ids.ShortID) bool {
+			if !id.Equals(net0.id) {
+				wg0.Done()
+				return true
+			}
+			return false
+		},
+	}
+	h1 := &testHandler{
+		connected: func(id ids.ShortID) bool {
+			if !id.Equals(net1.id) {
+				wg1.Done()
+				return true
+			}
+			return false
+		},
+	}
+
+	net0.RegisterHandler(h0)
+	net1.RegisterHandler(h1)
+
+	net0.Track(net1.ip)
+
+	wg0.Wait()
+	wg1.Wait()
+}
+
+// newConnectedTestNetworks returns two networks that have connected to each
+// other. The networks must be closed by the caller.
+func newConnectedTestNetworks(t *testing.T, compressionVersion0, compressionVersion1 version.Version) (*network, *network) {
+	net0, net1 := newTestNetworks(t, compressionVersion0, compressionVersion1)
+	connectTestNetworks(net0, net1)
+	return net0, net1
+}
+
+// peerAcceptsCompression returns whether [n]'s only peer has advertised that
+// it accepts compressed messages
+func peerAcceptsCompression(n *network) bool {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, peer := range n.peers {
+		return peer.compress
+	}
+	return false
+}
+
+// await polls [condition] until it returns true
+func await(t *testing.T, condition func() bool) {
+	for start := time.Now(); !condition(); time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("timed out waiting for condition")
+		}
+	}
+}
+
+func TestCompressionNegotiated(t *testing.T) {
+	compressionVersion := version.NewDefaultVersion("app", 0, 1, 0)
+	net0, net1 := newConnectedTestNetworks(t, compressionVersion, compressionVersion)
+
+	await(t, func() bool { return peerAcceptsCompression(net0) })
+	await(t, func() bool { return peerAcceptsCompression(net1) })
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestCompressionMixedCapabilities(t *testing.T) {
+	compressionVersion := version.NewDefaultVersion("app", 0, 1, 0)
+	net0, net1 := newConnectedTestNetworks(t, compressionVersion, nil)
+
+	// net0 advertises compression, but net1 has it disabled
+	await(t, func() bool { return testutil.ToFloat64(net1.capabilities.numReceived) == 1 })
+
+	assert.False(t, peerAcceptsCompression(net0))
+	assert.False(t, peerAcceptsCompression(net1))
+	assert.Equal(t, float64(0), testutil.ToFloat64(net1.capabilities.numSent))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// onlyPeer returns [n]'s only peer
+func onlyPeer(n *network) *peer {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, peer := range n.peers {
+		return peer
+	}
+	return nil
+}
+
+func TestMessageMetrics(t *testing.T) {
+	net0, net1 := newConnectedTestNetworks(t, nil, nil)
+
+	pingBytes := 0
+	if msg, err := net0.b.Ping(); assert.NoError(t, err) {
+		pingBytes = len(msg.Bytes())
+	}
+
+	peer := onlyPeer(net0)
+	if !assert.NotNil(t, peer) {
+		return
+	}
+	peer.Ping()
+
+	await(t, func() bool { return testutil.ToFloat64(net1.ping.numReceived) == 1 })
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(net0.ping.numSent))
+	assert.Equal(t, float64(pingBytes), testutil.ToFloat64(net0.ping.sentBytes))
+	assert.Equal(t, float64(pingBytes), testutil.ToFloat64(net1.ping.receivedBytes))
+
+	// The handle time is recorded once the ping has been handled
+	await(t, func() bool {
+		handleTime := dto.Metric{}
+		return net1.ping.handleTime.Write(&handleTime) == nil &&
+			handleTime.GetHistogram().GetSampleCount() == 1
+	})
+
+	// Other ops shouldn't be affected
+	assert.Equal(t, float64(0), testutil.ToFloat64(net0.get.sentBytes))
+	assert.Equal(t, float64(0), testutil.ToFloat64(net1.get.receivedBytes))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestCompressionOldPeer(t *testing.T) {
+	// The peers are running a version older than the compression version, so
+	// neither should be sent a capabilities message
+	compressionVersion := version.NewDefaultVersion("app", 0, 1, 1)
+	net0, net1 := newConnectedTestNetworks(t, compressionVersion, compressionVersion)
+
+	assert.False(t, peerAcceptsCompression(net0))
+	assert.False(t, peerAcceptsCompression(net1))
+	assert.Equal(t, float64(0), testutil.ToFloat64(net0.capabilities.numSent))
+	assert.Equal(t, float64(0), testutil.ToFloat64(net1.capabilities.numSent))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestAccessListRejectsDeniedPeer(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+
+	denied := ids.ShortSet{}
+	denied.Add(net0.id)
+	net1.SetAccessList(AccessList{DeniedIDs: denied})
+
+	connected := make(chan ids.ShortID, 1)
+	net1.RegisterHandler(&testHandler{
+		connected: func(id ids.ShortID) bool {
+			if !id.Equals(net1.id) {
+				connected <- id
+			}
+			return false
+		},
+	})
+
+	net0.Track(net1.ip)
+
+	select {
+	case id := <-connected:
+		t.Fatalf("denied peer %s was connected to", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestAccessListAllowsListedPeer(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+
+	allowed := ids.ShortSet{}
+	allowed.Add(net0.id)
+	net1.SetAccessList(AccessList{AllowedIDs: allowed})
+
+	connectTestNetworks(net0, net1)
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestAccessListDisconnectsNewlyDeniedPeer(t *testing.T) {
+	net0, net1 := newConnectedTestNetworks(t, nil, nil)
+
+	disconnected := make(chan struct{})
+	net1.RegisterHandler(&testHandler{
+		disconnected: func(id ids.ShortID) bool {
+			if id.Equals(net0.id) {
+				close(disconnected)
+				return true
+			}
+			return false
+		},
+	})
+
+	denied := ids.ShortSet{}
+	denied.Add(net0.id)
+	net1.SetAccessList(AccessList{DeniedIDs: denied})
+
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("newly denied peer wasn't disconnected")
+	}
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestPeerViolationsCauseEviction(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net1.maxPeerViolations = 3
+	connectTestNetworks(net0, net1)
+
+	disconnected := make(chan struct{})
+	net1.RegisterHandler(&testHandler{
+		disconnected: func(id ids.ShortID) bool {
+			if id.Equals(net0.id) {
+				close(disconnected)
+				return true
+			}
+			return false
+		},
+	})
+
+	net0.stateLock.Lock()
+	p0 := net0.peers[net1.id.Key()]
+	net0.stateLock.Unlock()
+	net1.stateLock.Lock()
+	p1 := net1.peers[net0.id.Key()]
+	net1.stateLock.Unlock()
+
+	assert.Equal(t, float64(1), p1.Score())
+
+	// Re-sending the version after the handshake completed is a protocol
+	// violation
+	p0.Version()
+	await(t, func() bool { return p1.Score() < 1 })
+	assert.Equal(t, float64(1), testutil.ToFloat64(net1.peerViolations))
+
+	peers := net1.Peers()
+	assert.Len(t, peers, 1)
+	assert.Equal(t, p1.Score(), peers[0].Score)
+
+	// Repeated violations drop the peer below the gossip threshold
+	p0.Version()
+	await(t, func() bool { return p1.Score() < net1.minGossipScore })
+
+	// Exhausting the peer's reputation disconnects it
+	p0.Version()
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("misbehaving peer wasn't evicted")
+	}
+	assert.Equal(t, float64(0), p1.Score())
+	assert.Equal(t, float64(1), testutil.ToFloat64(net1.peerEvictions))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestRequestedVersionIsNotViolation(t *testing.T) {
+	net0, net1 := newConnectedTestNetworks(t, nil, nil)
+
+	net0.stateLock.Lock()
+	p0 := net0.peers[net1.id.Key()]
+	net0.stateLock.Unlock()
+	net1.stateLock.Lock()
+	p1 := net1.peers[net0.id.Key()]
+	net1.stateLock.Unlock()
+
+	// A version sent in response to a request isn't a violation, even if the
+	// handshake already completed
+	p1.GetVersion()
+	await(t, func() bool {
+		net1.stateLock.Lock()
+		defer net1.stateLock.Unlock()
+
+		return p1.versionRequests == 0
+	})
+	assert.Equal(t, float64(1), p1.Score())
+
+	// An unrequested version is
+	p0.Version()
+	await(t, func() bool { return p1.Score() < 1 })
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// certUpgrader authenticates every connection as having been made by the owner
+// of [cert]
+type certUpgrader struct {
+	Upgrader
+	cert *x509.Certificate
+}
+
+func (u certUpgrader) Upgrade(conn net.Conn) (ids.ShortID, net.Conn, error) {
+	id, conn, err := u.Upgrader.Upgrade(conn)
+	return id, certConn{Conn: conn, cert: u.cert}, err
+}
+
+type certConn struct {
+	net.Conn
+	cert *x509.Certificate
+}
+
+func (c certConn) ConnectionState() tls.ConnectionState {
+	return tls.ConnectionState{PeerCertificates: []*x509.Certificate{c.cert}}
+}
+
+// peerIP returns the IP that [n] would reconnect to [id] at, if [n] is
+// connected to [id]
+func peerIP(n *network, id ids.ShortID) (utils.IPDesc, bool) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	p, ok := n.peers[id.Key()]
+	if !ok || !p.connected {
+		return utils.IPDesc{}, false
+	}
+	return p.ip, true
+}
+
+func TestSignedIPReconnect(t *testing.T) {
+	compressionVersion := version.NewDefaultVersion("app", 0, 1, 0)
+	net0, net1 := newTestNetworks(t, compressionVersion, compressionVersion)
+
+	// [net0] authenticates [net1] with [net1]'s staking certificate
+	stakingCert := newTestCert(t)
+	cert, err := x509.ParseCertificate(stakingCert.Certificate[0])
+	assert.NoError(t, err)
+	net0.serverUpgrader = certUpgrader{Upgrader: net0.serverUpgrader, cert: cert}
+	net0.clientUpgrader = certUpgrader{Upgrader: net0.clientUpgrader, cert: cert}
+	net1.SetStakingKey(stakingCert.PrivateKey.(crypto.Signer))
+
+	// [net1] will move to [newIP]
+	oldIP := net1.ip
+	newIP := utils.IPDesc{
+		IP:   net.IPv6loopback,
+		Port: 2,
+	}
+	dialer0 := net0.dialer.(*testDialer)
+	dialer0.outbounds[newIP.String()] = dialer0.outbounds[oldIP.String()]
+
+	connectTestNetworks(net0, net1)
+	await(t, func() bool {
+		net1.stateLock.Lock()
+		defer net1.stateLock.Unlock()
+
+		p, ok := net1.peers[net0.id.Key()]
+		return ok && p.signedIPs
+	})
+
+	ip, _ := peerIP(net0, net1.id)
+	assert.Equal(t, oldIP, ip)
+
+	// Forged announcements are rejected
+	net1.stateLock.Lock()
+	p1 := net1.peers[net0.id.Key()]
+	net1.stateLock.Unlock()
+	forged, err := net1.b.SignedIP(newIP, net1.clock.Unix(), []byte{1, 2, 3})
+	assert.NoError(t, err)
+	violations := testutil.ToFloat64(net0.peerViolations)
+	p1.Send(forged)
+	await(t, func() bool { return testutil.ToFloat64(net0.peerViolations) > violations })
+	ip, _ = peerIP(net0, net1.id)
+	assert.Equal(t, oldIP, ip)
+
+	// [net1]'s IP changes, so its old IP is no longer reachable
+	assert.NoError(t, net1.UpdateIP(newIP))
+	await(t, func() bool {
+		ip, _ := peerIP(net0, net1.id)
+		return ip.Equal(newIP)
+	})
+	delete(dialer0.outbounds, oldIP.String())
+	// Only [net0] can re-establish the connection
+	delete(net1.dialer.(*testDialer).outbounds, net0.ip.String())
+
+	reconnected := make(chan struct{})
+	disconnected := false
+	net0.RegisterHandler(&testHandler{
+		connected: func(id ids.ShortID) bool {
+			if id.Equals(net1.id) && disconnected {
+				close(reconnected)
+				return true
+			}
+			return false
+		},
+		disconnected: func(id ids.ShortID) bool {
+			disconnected = disconnected || id.Equals(net1.id)
+			return false
+		},
+	})
+
+	// Drop the connection on both ends
+	net0.stateLock.Lock()
+	p0 := net0.peers[net1.id.Key()]
+	net0.stateLock.Unlock()
+	p0.Close()
+	p1.Close()
+
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("didn't reconnect to the new ip")
+	}
+	ip, connected := peerIP(net0, net1.id)
+	assert.True(t, connected)
+	assert.Equal(t, newIP, ip)
+
+	net0.stateLock.Lock()
+	_, dialingOldIP := net0.disconnectedIPs[oldIP.String()]
+	net0.stateLock.Unlock()
+	assert.False(t, dialingOldIP)
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// dialFrom sends [n] a new inbound connection that appears to be from [addr],
+// and returns the local end of the connection
+func dialFrom(n *network, addr *net.TCPAddr) *testConn {
+	listener := n.listener.(*testListener)
+	server := &testConn{
+		pendingReads:  make(chan []byte, 1<<10),
+		pendingWrites: make(chan []byte, 1<<10),
+		closed:        make(chan struct{}),
+		local:         listener.addr,
+		remote:        addr,
+	}
+	listener.inbound <- server
+	return server
+}
+
+// numPeers returns the number of peers [n] has upgraded a connection with,
+// whether or not they finished the handshake
+func numPeers(n *network) int {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	return len(n.peers)
+}
+
+// isClosed returns true if [conn] has been closed
+func isClosed(conn *testConn) bool {
+	select {
+	case <-conn.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestConnectionLimitPerIP(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net1.SetConnectionLimits(ConnectionLimits{MaxConnsPerIP: 1})
+
+	ip := net.IPv4(10, 0, 0, 1)
+	conn0 := dialFrom(net1, &net.TCPAddr{IP: ip, Port: 1})
+	conn1 := dialFrom(net1, &net.TCPAddr{IP: ip, Port: 2})
+
+	await(t, func() bool { return testutil.ToFloat64(net1.rejectedConns) == 1 })
+	assert.False(t, isClosed(conn0))
+	assert.True(t, isClosed(conn1))
+
+	// Other IPs aren't affected
+	conn2 := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 1})
+	await(t, func() bool { return numPeers(net1) == 2 })
+	assert.False(t, isClosed(conn2))
+
+	// Once the first connection is closed, the IP may connect again
+	net1.stateLock.Lock()
+	for _, p := range net1.peers {
+		if p.conn.RemoteAddr().(*net.TCPAddr).IP.Equal(ip) {
+			_ = p.conn.Close()
+		}
+	}
+	net1.stateLock.Unlock()
+	await(t, func() bool {
+		net1.conns.lock.Lock()
+		defer net1.conns.lock.Unlock()
+		return net1.conns.perIP[ip.String()] == 0
+	})
+	conn3 := dialFrom(net1, &net.TCPAddr{IP: ip, Port: 3})
+	await(t, func() bool { return numPeers(net1) == 2 })
+	assert.False(t, isClosed(conn3))
+	assert.Equal(t, float64(1), testutil.ToFloat64(net1.rejectedConns))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestConnectionLimitTotal(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net1.SetConnectionLimits(ConnectionLimits{MaxConns: 2})
+
+	conn0 := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1})
+	conn1 := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 1})
+	conn2 := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 3), Port: 1})
+
+	await(t, func() bool { return testutil.ToFloat64(net1.rejectedConns) == 1 })
+	assert.False(t, isClosed(conn0))
+	assert.False(t, isClosed(conn1))
+	assert.True(t, isClosed(conn2))
+
+	// Lowering the limit doesn't close existing connections
+	net1.SetConnectionLimits(ConnectionLimits{MaxConns: 1})
+	await(t, func() bool { return numPeers(net1) == 2 })
+	assert.False(t, isClosed(conn0))
+	assert.False(t, isClosed(conn1))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestHandshakeTimeout(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net1.SetHandshakeTimeout(time.Hour)
+
+	// This peer never sends its version, so the handshake never finishes
+	conn := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1})
+	await(t, func() bool { return numPeers(net1) == 1 })
+
+	now := net1.clock.Time()
+	net1.clock.Set(now.Add(time.Hour - time.Second))
+	assert.False(t, isClosed(conn))
+
+	net1.clock.Set(now.Add(time.Hour))
+	assert.True(t, isClosed(conn))
+	await(t, func() bool { return numPeers(net1) == 0 })
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestMessageReadTimeout(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net1.SetMessageReadTimeout(50 * time.Millisecond)
+
+	conn := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1})
+	await(t, func() bool { return numPeers(net1) == 1 })
+
+	// This peer sends the length of a message, and then sends the message one
+	// byte at a time, too slowly to finish it in time
+	conn.pendingReads <- []byte{0, 0, 0, 100}
+	for i := 0; i < 100 && !isClosed(conn); i++ {
+		conn.pendingReads <- []byte{0}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	assert.True(t, isClosed(conn))
+	await(t, func() bool { return numPeers(net1) == 0 })
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// sendMsg writes [msg] to [conn] as if it was sent by the remote peer
+func sendMsg(conn *testConn, msg Msg) {
+	packer := wrappers.Packer{Bytes: make([]byte, len(msg.Bytes())+wrappers.IntLen)}
+	packer.PackBytes(msg.Bytes())
+	conn.pendingReads <- packer.Bytes
+}
+
+func TestIdleTimeout(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	idleTimeout := 100 * time.Millisecond
+	net1.SetIdleTimeout(idleTimeout)
+
+	ping, err := net1.b.Ping()
+	assert.NoError(t, err)
+	getVersion, err := net1.b.GetVersion()
+	assert.NoError(t, err)
+
+	idleConn := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1})
+	activeConn := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 1})
+	await(t, func() bool { return numPeers(net1) == 2 })
+
+	// Both peers ping regularly, but only the active peer sends other messages
+	for start := time.Now(); time.Since(start) < 2*idleTimeout; {
+		sendMsg(idleConn, ping)
+		sendMsg(activeConn, getVersion)
+		sendMsg(activeConn, ping)
+		time.Sleep(idleTimeout / 5)
+	}
+
+	await(t, func() bool { return isClosed(idleConn) })
+	assert.False(t, isClosed(activeConn))
+	await(t, func() bool { return numPeers(net1) == 1 })
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestHandshakeTimeoutConnected(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net0.SetHandshakeTimeout(time.Hour)
+	net1.SetHandshakeTimeout(time.Hour)
+	connectTestNetworks(net0, net1)
+
+	// Peers that finished their handshake shouldn't be closed
+	net1.clock.Set(net1.clock.Time().Add(2 * time.Hour))
+	peer := onlyPeer(net1)
+	if assert.NotNil(t, peer) {
+		net1.stateLock.Lock()
+		assert.False(t, peer.closed)
+		net1.stateLock.Unlock()
+
+		// The handshake deadline was stopped once the handshake finished
+		assert.False(t, peer.handshakeTimer.Stop())
+	}
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// sendVersion makes [conn] send a version message claiming [peerVersion] to
+// [n], as if it was sent by the remote peer
+func sendVersion(t *testing.T, n *network, conn *testConn, peerVersion version.Version) {
+	msg, err := n.b.Version(
+		n.networkID,
+		n.nodeID+1,
+		n.clock.Unix(),
+		utils.IPDesc{IP: net.IPv4(10, 0, 0, 1), Port: 1},
+		peerVersion.String(),
+	)
+	assert.NoError(t, err)
+	sendMsg(conn, msg)
+}
+
+func TestMinimumVersionRejected(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net1.version = version.NewDefaultVersion("app", 0, 1, 5)
+	net1.SetMinimumVersion(version.NewDefaultVersion("app", 0, 1, 3))
+
+	conn := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1})
+	await(t, func() bool { return numPeers(net1) == 1 })
+
+	// The peer is compatible, but older than the minimum version
+	sendVersion(t, net1, conn, version.NewDefaultVersion("app", 0, 1, 2))
+
+	await(t, func() bool { return isClosed(conn) })
+	await(t, func() bool { return numPeers(net1) == 0 })
+	assert.Equal(t, float64(1), testutil.ToFloat64(net1.versionMismatches))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestMinimumVersionAccepted(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net1.version = version.NewDefaultVersion("app", 0, 1, 5)
+	net1.SetMinimumVersion(version.NewDefaultVersion("app", 0, 1, 3))
+
+	conn := dialFrom(net1, &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1})
+	await(t, func() bool { return numPeers(net1) == 1 })
+
+	// The peer is older than us, but not older than the minimum version
+	sendVersion(t, net1, conn, version.NewDefaultVersion("app", 0, 1, 3))
+
+	peer := onlyPeer(net1)
+	if assert.NotNil(t, peer) {
+		await(t, func() bool {
+			net1.stateLock.Lock()
+			defer net1.stateLock.Unlock()
+
+			return peer.connected
+		})
+	}
+	assert.False(t, isClosed(conn))
+	assert.Equal(t, float64(0), testutil.ToFloat64(net1.versionMismatches))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestSendFullQueue(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	net1.sendQueueSize = 1
+
+	// Nothing is ever read from this connection, so once the first message
+	// is being written, the send queue fills up
+	listener := net1.listener.(*testListener)
+	conn := &testConn{
+		pendingReads:  make(chan []byte, 1<<10),
+		pendingWrites: make(chan []byte),
+		closed:        make(chan struct{}),
+		local:         listener.addr,
+		remote:        &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1},
+	}
+	listener.inbound <- conn
+	await(t, func() bool { return numPeers(net1) == 1 })
+
+	peer := onlyPeer(net1)
+	if !assert.NotNil(t, peer) {
+		return
+	}
+	net1.stateLock.Lock()
+	peerID := peer.id
+	net1.stateLock.Unlock()
+
+	ping, err := net1.b.Ping()
+	assert.NoError(t, err)
+
+	await(t, func() bool {
+		net1.Send(peerID, ping)
+		return len(peer.sender) == cap(peer.sender)
+	})
+	assert.False(t, net1.Send(peerID, ping))
+	assert.Less(t, float64(0), testutil.ToFloat64(net1.ping.numFailed))
+
+	// A request that can't be sent should be reported as failed, so that it
+	// can be failed without waiting for its timeout to expire
+	validatorIDs := ids.ShortSet{}
+	validatorIDs.Add(peerID)
+	failed := net1.PullQuery(validatorIDs, ids.Empty, 1, time.Now().Add(time.Hour), ids.Empty)
+	assert.True(t, failed.Contains(peerID))
+	assert.Equal(t, float64(1), testutil.ToFloat64(net1.pullQuery.numFailed))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// countingDialer counts the dials made to each IP
+type countingDialer struct {
+	Dialer
+
+	lock  sync.Mutex
+	dials map[string]int
+}
+
+func (d *countingDialer) Dial(ip utils.IPDesc) (net.Conn, error) {
+	d.lock.Lock()
+	d.dials[ip.String()]++
+	d.lock.Unlock()
+
+	return d.Dialer.Dial(ip)
+}
+
+func (d *countingDialer) numDials(ip utils.IPDesc) int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.dials[ip.String()]
+}
+
+func TestSelfConnection(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+
+	// [altIP] also reaches net0, but net0 doesn't know that
+	altIP := utils.IPDesc{IP: net.IPv4(10, 0, 0, 1), Port: 1}
+	caller := net0.dialer.(*testDialer)
+	caller.outbounds[altIP.String()] = net0.listener.(*testListener)
+	dialer := &countingDialer{
+		Dialer: caller,
+		dials:  make(map[string]int),
+	}
+	net0.dialer = dialer
+
+	isTracked := func(ip utils.IPDesc) bool {
+		net0.stateLock.Lock()
+		defer net0.stateLock.Unlock()
+
+		_, ok := net0.disconnectedIPs[ip.String()]
+		return ok
+	}
+	isMine := func(ip utils.IPDesc) bool {
+		net0.stateLock.Lock()
+		defer net0.stateLock.Unlock()
+
+		_, ok := net0.myIPs[ip.String()]
+		return ok
+	}
+
+	// Connecting to my advertised IP is short-circuited
+	net0.Track(net0.ip)
+	assert.False(t, isTracked(net0.ip))
+	assert.Equal(t, 0, dialer.numDials(net0.ip))
+
+	// Connecting to an IP that turns out to be mine is closed once my ID is
+	// seen, and the IP isn't connected to again
+	net0.Track(altIP)
+	await(t, func() bool { return isMine(altIP) })
+	assert.False(t, isTracked(altIP))
+	assert.Equal(t, 0, numPeers(net0))
+
+	net0.Track(altIP)
+	assert.False(t, isTracked(altIP))
+	assert.Equal(t, 1, dialer.numDials(altIP))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// putRouter counts the Put messages it receives for each chain
+type putRouter struct {
+	router.Router
+
+	lock sync.Mutex
+	puts map[[32]byte]int
+}
+
+func (r *putRouter) Put(_ ids.ShortID, chainID ids.ID, _ uint32, _ ids.ID, _ []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.puts[chainID.Key()]++
+}
+
+func (r *putRouter) Backpressure(ids.ID) <-chan struct{} { return nil }
+
+func (r *putRouter) numPuts(chainID ids.ID) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.puts[chainID.Key()]
+}
+
+// testSubnetLookup maps chains to subnets
+type testSubnetLookup map[[32]byte]ids.ID
+
+func (l testSubnetLookup) SubnetID(chainID ids.ID) (ids.ID, error) {
+	subnetID, ok := l[chainID.Key()]
+	if !ok {
+		return ids.ID{}, errors.New("unknown chain")
+	}
+	return subnetID, nil
+}
+
+func TestGossipQuota(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	r := &putRouter{puts: make(map[[32]byte]int)}
+	net1.router = r
+	connectTestNetworks(net0, net1)
+
+	busyChainID, busySubnetID := ids.GenerateTestID(), ids.GenerateTestID()
+	quietChainID, quietSubnetID := ids.GenerateTestID(), ids.GenerateTestID()
+	net0.SetSubnetLookup(testSubnetLookup{
+		busyChainID.Key():  busySubnetID,
+		quietChainID.Key(): quietSubnetID,
+	})
+	// A single container uses up the busy subnet's quota
+	net0.SetGossipQuota(busySubnetID, 1)
+
+	container := make([]byte, 100)
+	net0.Gossip(busyChainID, ids.GenerateTestID(), container)
+	net0.Gossip(busyChainID, ids.GenerateTestID(), container)
+	net0.Gossip(quietChainID, ids.GenerateTestID(), container)
+
+	await(t, func() bool { return r.numPuts(quietChainID) == 1 })
+	assert.Equal(t, 1, r.numPuts(busyChainID))
+	assert.Equal(t, float64(1), testutil.ToFloat64(net0.subnetGossipThrottled.WithLabelValues(busySubnetID.String())))
+	assert.Equal(t, float64(0), testutil.ToFloat64(net0.subnetGossipThrottled.WithLabelValues(quietSubnetID.String())))
+
+	msg, err := net0.b.Put(quietChainID, constants.GossipMsgRequestID, ids.GenerateTestID(), container)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(len(msg.Bytes())), testutil.ToFloat64(net0.subnetGossipBytes.WithLabelValues(quietSubnetID.String())))
+
+	// Removing the quota lifts the throttling
+	net0.SetGossipQuota(busySubnetID, 0)
+	net0.Gossip(busyChainID, ids.GenerateTestID(), container)
+	await(t, func() bool { return r.numPuts(busyChainID) == 2 })
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// backpressureRouter reports that the chains in [full] are falling behind
+type backpressureRouter struct {
+	*putRouter
+
+	fullLock sync.Mutex
+	full     map[[32]byte]chan struct{}
+	refill   map[[32]byte]bool
+}
+
+func (r *backpressureRouter) Backpressure(chainID ids.ID) <-chan struct{} {
+	r.fullLock.Lock()
+	defer r.fullLock.Unlock()
+
+	if drained, ok := r.full[chainID.Key()]; ok {
+		return drained
+	}
+	return nil
+}
+
+// drain reports that [chainID] has caught up
+func (r *backpressureRouter) drain(chainID ids.ID) {
+	r.fullLock.Lock()
+	defer r.fullLock.Unlock()
+
+	close(r.full[chainID.Key()])
+	delete(r.full, chainID.Key())
+}
+
+// fullAfterPut reports that [chainID] falls behind whenever it's sent a Put
+func (r *backpressureRouter) fullAfterPut(chainID ids.ID) {
+	r.fullLock.Lock()
+	defer r.fullLock.Unlock()
+
+	if r.refill == nil {
+		r.refill = make(map[[32]byte]bool)
+	}
+	r.refill[chainID.Key()] = true
+}
+
+func (r *backpressureRouter) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte) {
+	r.putRouter.Put(validatorID, chainID, requestID, containerID, container)
+
+	r.fullLock.Lock()
+	defer r.fullLock.Unlock()
+
+	if _, full := r.full[chainID.Key()]; r.refill[chainID.Key()] && !full {
+		r.full[chainID.Key()] = make(chan struct{})
+	}
+}
+
+func TestBackpressureDefersOnlySlowChain(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	slowChainID, fastChainID := ids.GenerateTestID(), ids.GenerateTestID()
+	r := &backpressureRouter{
+		putRouter: &putRouter{puts: make(map[[32]byte]int)},
+		full:      map[[32]byte]chan struct{}{slowChainID.Key(): make(chan struct{})},
+	}
+	net1.router = r
+	connectTestNetworks(net0, net1)
+
+	container := make([]byte, 100)
+	for i := 0; i < 3; i++ {
+		net0.Gossip(slowChainID, ids.GenerateTestID(), container)
+	}
+	net0.Gossip(fastChainID, ids.GenerateTestID(), container)
+
+	// The slow chain doesn't hold up the fast chain
+	await(t, func() bool { return r.numPuts(fastChainID) == 1 })
+	assert.Equal(t, 0, r.numPuts(slowChainID))
+	assert.Equal(t, float64(1), testutil.ToFloat64(net1.backpressured))
+	assert.NotZero(t, testutil.ToFloat64(net1.numDeferredBytes))
+
+	// Once the slow chain catches up, all of its deferred messages are handled
+	r.drain(slowChainID)
+	await(t, func() bool { return r.numPuts(slowChainID) == 3 })
+	await(t, func() bool { return testutil.ToFloat64(net1.numDeferredBytes) == 0 })
+
+	net0.Gossip(slowChainID, ids.GenerateTestID(), container)
+	await(t, func() bool { return r.numPuts(slowChainID) == 4 })
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+// pendingReads returns the number of writes to [n]'s connection to its only
+// peer that [n] hasn't started reading
+func pendingReads(n *network) int {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	for _, peer := range n.peers {
+		conn := peer.conn
+		if limited, ok := conn.(*limitedConn); ok {
+			conn = limited.Conn
+		}
+		return len(conn.(*testConn).pendingReads)
+	}
+	return 0
+}
+
+func TestBackpressurePausesReads(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	slowChainID, fastChainID := ids.GenerateTestID(), ids.GenerateTestID()
+	r := &backpressureRouter{
+		putRouter: &putRouter{puts: make(map[[32]byte]int)},
+		full:      map[[32]byte]chan struct{}{slowChainID.Key(): make(chan struct{})},
+	}
+	net1.router = r
+	net1.maxDeferredBytes = 1
+	connectTestNetworks(net0, net1)
+
+	container := make([]byte, 100)
+	net0.Gossip(slowChainID, ids.GenerateTestID(), container)
+	net0.Gossip(fastChainID, ids.GenerateTestID(), container)
+
+	// Deferring the first message reaches the limit, so the second message
+	// isn't read from the connection
+	await(t, func() bool { return pendingReads(net1) == 1 })
+	assert.NotZero(t, testutil.ToFloat64(net1.numDeferredBytes))
+	assert.Equal(t, 0, r.numPuts(fastChainID))
+
+	// Once the slow chain catches up, reading resumes
+	r.drain(slowChainID)
+	await(t, func() bool { return r.numPuts(fastChainID) == 1 })
+	assert.Equal(t, 1, r.numPuts(slowChainID))
+	assert.Equal(t, 0, pendingReads(net1))
+	assert.Zero(t, testutil.ToFloat64(net1.numDeferredBytes))
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
+
+func TestBackpressureRechecksBetweenDeferred(t *testing.T) {
+	net0, net1 := newTestNetworks(t, nil, nil)
+	chainID := ids.GenerateTestID()
+	r := &backpressureRouter{
+		putRouter: &putRouter{puts: make(map[[32]byte]int)},
+		full:      map[[32]byte]chan struct{}{chainID.Key(): make(chan struct{})},
+	}
+	net1.router = r
+	connectTestNetworks(net0, net1)
+
+	container := make([]byte, 100)
+	for i := 0; i < 3; i++ {
+		net0.Gossip(chainID, ids.GenerateTestID(), container)
+	}
+	await(t, func() bool { return testutil.ToFloat64(net1.numDeferredBytes) > 0 })
+
+	// The chain falls behind again as soon as it handles a message, so the
+	// deferred messages are handled one at a time
+	r.fullAfterPut(chainID)
+	r.drain(chainID)
+	await(t, func() bool { return r.numPuts(chainID) == 1 })
+	for i := 2; i <= 3; i++ {
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, i-1, r.numPuts(chainID))
+		r.drain(chainID)
+		await(t, func() bool { return r.numPuts(chainID) == i })
+	}
+
+	assert.NoError(t, net0.Close())
+	assert.NoError(t, net1.Close())
+}
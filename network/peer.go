@@ -5,6 +5,7 @@ package network
 
 import (
 	"bytes"
+	"crypto"
 	"math"
 	"net"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
@@ -49,16 +51,65 @@ type peer struct {
 	// the connection object that is used to read/write messages from
 	conn net.Conn
 
+	// closes the connection if the handshake doesn't finish in time. Set
+	// before the peer is started, and stopped once the handshake finishes or
+	// the peer is closed. Nil if handshakes don't time out.
+	handshakeTimer *timer.ManagedTimer
+
 	// version that the peer reported during the handshake
 	versionStr string
 
+	// if the peer has advertised that it accepts compressed messages, is only
+	// modified with the network state lock held.
+	compress bool
+
+	// if the peer has advertised that it accepts signed IP announcements, is
+	// only modified with the network state lock held.
+	signedIPs bool
+
+	// public key of the peer's staking certificate, if the connection was
+	// authenticated. Used to verify the peer's IP announcements.
+	stakingKey crypto.PublicKey
+
+	// timestamp of the latest IP announcement accepted from the peer, is only
+	// modified with the network state lock held.
+	ipTimestamp uint64
+
+	// number of version requests sent to the peer that it may still answer,
+	// is only modified with the network state lock held. Answers that arrive
+	// after the handshake completed aren't protocol violations.
+	versionRequests int
+
 	// unix time of the last message sent and received respectively
 	lastSent, lastReceived int64
+
+	// the peer is disconnected if it doesn't send any messages, other than
+	// pings and pongs, for [idleTimeout]. Set when the peer is started.
+	idleTimeout time.Duration
+	// time the peer last sent a message other than a ping or pong, is only
+	// accessed on the connection's reader routine.
+	lastActive time.Time
+
+	// protocol violations this peer has recently committed. Old violations
+	// are forgotten, so the peer's reputation recovers over time.
+	violations timer.TimedMeter
+
+	// messages from the peer for chains that are falling behind, keyed by
+	// chain ID. They are handled once the chain catches up.
+	deferredLock sync.Mutex
+	deferred     map[[32]byte][]Msg
+
+	// number of bytes of the peer's deferred messages, is only modified when
+	// the network state lock held.
+	deferredBytes int
 }
 
 // assume the stateLock is held
 func (p *peer) Start() {
-	go p.ReadMessages()
+	p.idleTimeout = p.net.idleTimeout
+	p.lastActive = p.net.clock.Time()
+
+	go p.ReadMessages(p.net.msgReadTimeout)
 	go p.WriteMessages()
 
 	// Initially send the version to the peer
@@ -104,7 +155,9 @@ func (p *peer) requestVersion() {
 }
 
 // attempt to read messages from the peer
-func (p *peer) ReadMessages() {
+// Once the length of a message has been read, the rest of the message must be
+// read within [msgTimeout], if it's positive.
+func (p *peer) ReadMessages(msgTimeout time.Duration) {
 	defer p.Close()
 
 	if err := p.conn.SetReadDeadline(p.net.clock.Time().Add(p.net.pingPongTimeout)); err != nil {
@@ -112,11 +165,34 @@ func (p *peer) ReadMessages() {
 		return
 	}
 
+	// Only shorten the read deadline if it's shorter than the ping timeout,
+	// which would be applied anyways
+	limitMsgs := msgTimeout > 0 && msgTimeout < p.net.pingPongTimeout
+	// true if the length of the pending message has been read
+	readingMsg := false
+	startMsg := func() bool {
+		if !limitMsgs || readingMsg {
+			return true
+		}
+		readingMsg = true
+		if err := p.conn.SetReadDeadline(p.net.clock.Time().Add(msgTimeout)); err != nil {
+			p.net.log.Verbo("error on setting the message read timeout %s", err)
+			return false
+		}
+		return true
+	}
+
 	pendingBuffer := wrappers.Packer{}
 	readBuffer := make([]byte, 1<<10)
 	for {
 		read, err := p.conn.Read(readBuffer)
 		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() && readingMsg {
+				p.net.log.Debug("closing connection to %s because a message wasn't received within %s",
+					p.id,
+					msgTimeout)
+				return
+			}
 			p.net.log.Verbo("error on connection read to %s %s", p.id, err)
 			return
 		}
@@ -138,6 +214,12 @@ func (p *peer) ReadMessages() {
 				return
 			}
 
+			// the length of the message may have been read, in which case the
+			// rest of the message must arrive soon
+			if len(pendingBuffer.Bytes) >= wrappers.IntLen && !startMsg() {
+				return
+			}
+
 			// we should try to read more bytes to finish the message
 			continue
 		}
@@ -148,6 +230,7 @@ func (p *peer) ReadMessages() {
 		pendingBuffer.Bytes = pendingBuffer.Bytes[pendingBuffer.Offset:]
 		// set the offset back to the start of the next message
 		pendingBuffer.Offset = 0
+		readingMsg = false
 
 		if uint32(len(msgBytes)) > p.net.maxMessageSize {
 			// if this message is longer than the max message length, then we
@@ -161,7 +244,7 @@ func (p *peer) ReadMessages() {
 			p.id,
 			formatting.DumpBytes{Bytes: msgBytes})
 
-		msg, err := p.net.b.Parse(msgBytes)
+		msg, err := p.net.b.ParseLimited(msgBytes, p.net.maxMessageSize)
 		if err != nil {
 			p.net.log.Debug("failed to parse new message from %s:\n%s\n%s",
 				p.id,
@@ -169,8 +252,23 @@ func (p *peer) ReadMessages() {
 				err)
 			return
 		}
+		if msgMetrics := p.net.message(msg.Op()); msgMetrics != nil {
+			msgMetrics.receivedBytes.Add(float64(len(msgBytes)))
+		}
 
 		p.handle(msg)
+
+		// stop reading from the connection while too many messages are
+		// deferred, so that the peer is throttled rather than buffered
+		if !p.awaitDeferred() {
+			return
+		}
+
+		// handling the message reset the read deadline, but the length of the
+		// next message may have already been read
+		if len(pendingBuffer.Bytes) >= wrappers.IntLen && !startMsg() {
+			return
+		}
 	}
 }
 
@@ -219,6 +317,9 @@ func (p *peer) send(msg Msg) bool {
 	}
 
 	msgBytes := msg.Bytes()
+	if p.compress {
+		msgBytes = p.net.b.Compress(msg)
+	}
 	newPendingBytes := p.net.pendingBytes + len(msgBytes)
 	newConnPendingBytes := p.pendingBytes + len(msgBytes)
 	if dropMsg := p.dropMessage(len(msgBytes), newConnPendingBytes, newPendingBytes); dropMsg {
@@ -230,6 +331,9 @@ func (p *peer) send(msg Msg) bool {
 	case p.sender <- msgBytes:
 		p.net.pendingBytes = newPendingBytes
 		p.pendingBytes = newConnPendingBytes
+		if msgMetrics := p.net.message(msg.Op()); msgMetrics != nil {
+			msgMetrics.sentBytes.Add(float64(len(msgBytes)))
+		}
 		return true
 	default:
 		p.net.log.Debug("dropping message to %s due to a full send queue", p.id)
@@ -251,13 +355,31 @@ func (p *peer) handle(msg Msg) {
 	}
 
 	op := msg.Op()
+	if p.idleTimeout > 0 {
+		if op != Ping && op != Pong {
+			p.lastActive = currentTime
+		} else if idle := currentTime.Sub(p.lastActive); idle >= p.idleTimeout {
+			// Peers that are still alive, but are only pinging us, are
+			// noticed when their next ping or pong arrives
+			p.net.log.Debug("disconnecting from %s because it has been idle for %s", p.id, idle)
+			p.Close()
+			return
+		}
+	}
+
 	msgMetrics := p.net.message(op)
 	if msgMetrics == nil {
 		p.net.log.Debug("dropping an unknown message from %s with op %s", p.id, op.String())
+		p.violation("unknown message")
 		return
 	}
 	msgMetrics.numReceived.Inc()
 
+	start := p.net.clock.Time()
+	defer func() {
+		msgMetrics.handleTime.Observe(float64(p.net.clock.Time().Sub(start)))
+	}()
+
 	switch op {
 	case Version:
 		p.version(msg)
@@ -271,6 +393,11 @@ func (p *peer) handle(msg Msg) {
 	case Pong:
 		p.pong(msg)
 		return
+	case Capabilities:
+		// the peer's version message may arrive after its capabilities, as
+		// the version is sent asynchronously
+		p.capabilities(msg)
+		return
 	}
 	if !p.connected {
 		p.net.log.Debug("dropping message from %s because the connection hasn't been established yet", p.id)
@@ -279,7 +406,16 @@ func (p *peer) handle(msg Msg) {
 		p.GetVersion()
 		return
 	}
-	switch op {
+	if chainIDBytes, ok := msg.Get(ChainID).([]byte); ok && p.deferIfBackpressured(chainIDBytes, msg) {
+		return
+	}
+	p.route(msg)
+}
+
+// route [msg], which must be neither a handshake nor a ping message
+// assumes the stateLock is not held
+func (p *peer) route(msg Msg) {
+	switch op := msg.Op(); op {
 	case GetPeerList:
 		p.getPeerList(msg)
 	case PeerList:
@@ -306,11 +442,128 @@ func (p *peer) handle(msg Msg) {
 		p.pullQuery(msg)
 	case Chits:
 		p.chits(msg)
+	case SignedIP:
+		p.signedIP(msg)
 	default:
 		p.net.log.Debug("dropping an unknown message from %s with op %s", p.id, op.String())
 	}
 }
 
+// deferIfBackpressured returns true if [msg] was deferred because the chain
+// it's destined for isn't keeping up with its incoming messages. Only that
+// chain's messages are held back, so pings and messages for other chains are
+// still handled.
+// assumes the stateLock is not held
+func (p *peer) deferIfBackpressured(chainIDBytes []byte, msg Msg) bool {
+	chainID, err := ids.ToID(chainIDBytes)
+	if err != nil {
+		return false
+	}
+	key := chainID.Key()
+
+	p.deferredLock.Lock()
+	defer p.deferredLock.Unlock()
+
+	// Once a message for the chain is deferred, the chain's later messages
+	// are too, so that they are handled in order
+	if deferred, ok := p.deferred[key]; ok {
+		p.deferred[key] = append(deferred, msg)
+		p.addDeferredBytes(len(msg.Bytes()))
+		return true
+	}
+
+	drained := p.net.router.Backpressure(chainID)
+	if drained == nil {
+		return false
+	}
+
+	p.net.log.Debug("deferring messages from %s until chain %s catches up", p.id, chainID)
+	p.net.backpressured.Inc()
+	if p.deferred == nil {
+		p.deferred = make(map[[32]byte][]Msg)
+	}
+	p.deferred[key] = []Msg{msg}
+	p.addDeferredBytes(len(msg.Bytes()))
+	go p.handleDeferred(chainID, drained)
+	return true
+}
+
+// handleDeferred routes the messages deferred for [chainID] once [drained] is
+// closed. The chain may fall behind again while they're routed, so its
+// backpressure is checked before each one.
+func (p *peer) handleDeferred(chainID ids.ID, drained <-chan struct{}) {
+	key := chainID.Key()
+
+	<-drained
+	for {
+		p.deferredLock.Lock()
+		deferred := p.deferred[key]
+		if len(deferred) == 0 {
+			delete(p.deferred, key)
+			p.deferredLock.Unlock()
+			return
+		}
+		// Messages that arrive while this one is being routed are still
+		// deferred, so that they are routed after it
+		msg := deferred[0]
+		deferred[0] = nil
+		p.deferred[key] = deferred[1:]
+		p.deferredLock.Unlock()
+
+		p.route(msg)
+		p.addDeferredBytes(-len(msg.Bytes()))
+
+		if drained := p.net.router.Backpressure(chainID); drained != nil {
+			<-drained
+		}
+	}
+}
+
+// addDeferredBytes adds [msgLen] bytes to the amount deferred by this peer, or
+// removes them if [msgLen] is negative. Peers waiting for deferred messages to
+// be handled are woken up.
+// assumes the stateLock is not held
+func (p *peer) addDeferredBytes(msgLen int) {
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	p.deferredBytes += msgLen
+	p.net.deferredBytes += msgLen
+	p.net.numDeferredBytes.Set(float64(p.net.deferredBytes))
+	if msgLen < 0 {
+		p.net.deferredHandled.Broadcast()
+	}
+}
+
+// awaitDeferred blocks while this peer has deferred messages and the network
+// has deferred at least [maxDeferredBytes]. Returns false if the peer was
+// closed.
+// assumes the stateLock is not held
+func (p *peer) awaitDeferred() bool {
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	waited := false
+	for !p.closed && p.deferredBytes > 0 && p.net.deferredBytes >= p.net.maxDeferredBytes {
+		waited = true
+		p.net.deferredHandled.Wait()
+	}
+	if p.closed {
+		return false
+	}
+	if !waited {
+		return true
+	}
+
+	// The connection wasn't read while waiting, so the peer shouldn't be
+	// timed out for it
+	if err := p.conn.SetReadDeadline(p.net.clock.Time().Add(p.net.pingPongTimeout)); err != nil {
+		p.net.log.Verbo("error on setting the connection read timeout %s", err)
+		return false
+	}
+	return true
+}
+
 func (p *peer) dropMessage(msgLen, connPendingLen, networkPendingLen int) bool {
 	return networkPendingLen > p.net.networkPendingSendBytesToRateLimit && // Check to see if we should be enforcing any rate limiting
 		uint32(p.pendingBytes) > p.net.maxMessageSize && // this connection should have a minimum allowed bandwidth
@@ -318,6 +571,35 @@ func (p *peer) dropMessage(msgLen, connPendingLen, networkPendingLen int) bool {
 			connPendingLen > p.net.maxNetworkPendingSendBytes/20) // Check to see if this connection is using too much memory
 }
 
+// Score returns this peer's reputation in [0, 1]. A peer that hasn't recently
+// committed any protocol violations has a score of 1, and a peer is evicted
+// once its score reaches 0.
+func (p *peer) Score() float64 {
+	if p.net.maxPeerViolations <= 0 {
+		return 1
+	}
+	score := 1 - float64(p.violations.Ticks())/float64(p.net.maxPeerViolations)
+	return math.Max(0, score)
+}
+
+// violation penalizes this peer for breaking the protocol, and disconnects from
+// it if its reputation has been exhausted.
+// assumes the stateLock is not held
+func (p *peer) violation(reason string) {
+	p.violations.Tick()
+	p.net.peerViolations.Inc()
+	if p.Score() > 0 {
+		return
+	}
+
+	p.net.log.Debug("disconnecting from %s due to too many protocol violations, most recently: %s",
+		p.id,
+		reason)
+	p.net.peerEvictions.Inc()
+	// Don't reconnect to the peer, it's likely to continue misbehaving
+	p.discardIP()
+}
+
 // assumes the stateLock is not held
 func (p *peer) Close() { p.once.Do(p.close) }
 
@@ -331,15 +613,30 @@ func (p *peer) close() {
 	}
 
 	p.closed = true
+	p.stopHandshakeTimer()
+	p.net.deferredHandled.Broadcast()
 	close(p.sender)
 	p.net.disconnected(p)
 }
 
+// stopHandshakeTimer stops the handshake deadline, if there is one
+func (p *peer) stopHandshakeTimer() {
+	if p.handshakeTimer != nil {
+		p.handshakeTimer.Stop()
+	}
+}
+
 // assumes the stateLock is not held
 func (p *peer) GetVersion() {
 	msg, err := p.net.b.GetVersion()
 	p.net.log.AssertNoError(err)
-	p.Send(msg)
+
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	if p.send(msg) {
+		p.versionRequests++
+	}
 }
 
 // assumes the stateLock is not held
@@ -409,6 +706,17 @@ func (p *peer) getVersion(_ Msg) { p.Version() }
 func (p *peer) version(msg Msg) {
 	if p.connected {
 		p.net.log.Verbo("dropping duplicated version message from %s", p.id)
+
+		p.net.stateLock.Lock()
+		requested := p.versionRequests > 0
+		if requested {
+			p.versionRequests--
+		}
+		p.net.stateLock.Unlock()
+
+		if !requested {
+			p.violation("duplicated version message")
+		}
 		return
 	}
 
@@ -468,8 +776,34 @@ func (p *peer) version(msg Msg) {
 	}
 
 	if err := p.net.version.Compatible(peerVersion); err != nil {
-		p.net.log.Debug("peer version not compatible due to %s", err)
+		p.net.log.Debug("peer %s version %s not compatible due to %s",
+			p.id,
+			peerVersion,
+			err)
 
+		p.net.versionMismatches.Inc()
+		p.discardIP()
+		return
+	}
+
+	p.net.stateLock.Lock()
+	minVersion := p.net.minVersion
+	p.net.stateLock.Unlock()
+
+	if minVersion != nil && peerVersion.Before(minVersion) {
+		if p.net.beacons.Contains(p.id) {
+			p.net.log.Warn("beacon %s has version %s, which is before the minimum version %s",
+				p.id,
+				peerVersion,
+				minVersion)
+		} else {
+			p.net.log.Debug("peer %s has version %s, which is before the minimum version %s",
+				p.id,
+				peerVersion,
+				minVersion)
+		}
+
+		p.net.versionMismatches.Inc()
 		p.discardIP()
 		return
 	}
@@ -506,7 +840,83 @@ func (p *peer) version(msg Msg) {
 	p.versionStr = peerVersion.String()
 
 	p.connected = true
+	p.stopHandshakeTimer()
 	p.net.connected(p)
+
+	// only peers that are new enough will understand the capabilities message
+	if p.net.compressionVersion != nil && !peerVersion.Before(p.net.compressionVersion) {
+		p.sendCapabilities()
+	}
+}
+
+// assumes the stateLock is held
+func (p *peer) sendCapabilities() {
+	msg, err := p.net.b.Capabilities(CompressionCapability | SignedIPCapability)
+	p.net.log.AssertNoError(err)
+	if p.send(msg) {
+		p.net.capabilities.numSent.Inc()
+	} else {
+		p.net.capabilities.numFailed.Inc()
+	}
+}
+
+// assumes the stateLock is not held
+func (p *peer) capabilities(msg Msg) {
+	flags := msg.Get(CapabilityFlags).(uint32)
+
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	p.compress = p.net.compressionVersion != nil && flags&CompressionCapability != 0
+	p.signedIPs = flags&SignedIPCapability != 0
+}
+
+// assumes the stateLock is not held
+func (p *peer) signedIP(msg Msg) {
+	ip := msg.Get(IP).(utils.IPDesc)
+	timestamp := msg.Get(MyTime).(uint64)
+	sig := msg.Get(Signature).([]byte)
+
+	if p.stakingKey == nil {
+		p.net.log.Debug("dropping ip announcement from %s because its connection isn't authenticated", p.id)
+		return
+	}
+	if err := verifyIP(p.stakingKey, ip, timestamp, sig); err != nil {
+		p.net.log.Debug("dropping ip announcement from %s: %s", p.id, err)
+		p.violation("invalid ip signature")
+		return
+	}
+	if ip.IsZero() || (!p.net.allowPrivateIPs && ip.IsPrivate()) {
+		p.net.log.Debug("dropping ip announcement of %s from %s", ip, p.id)
+		return
+	}
+
+	p.net.stateLock.Lock()
+	defer p.net.stateLock.Unlock()
+
+	if timestamp <= p.ipTimestamp {
+		p.net.log.Debug("dropping stale ip announcement of %s from %s", ip, p.id)
+		return
+	}
+	p.ipTimestamp = timestamp
+	if p.closed {
+		return
+	}
+
+	p.net.log.Debug("%s changed its ip from %s to %s", p.id, p.ip, ip)
+	if !p.ip.IsZero() {
+		// Stop attempting to reach the peer at its old IP
+		str := p.ip.String()
+		delete(p.net.connectedIPs, str)
+		delete(p.net.disconnectedIPs, str)
+		delete(p.net.retryDelay, str)
+	}
+	// If the connection closes, the peer will be reconnected to at its new IP
+	p.ip = ip
+	str := ip.String()
+	delete(p.net.disconnectedIPs, str)
+	delete(p.net.retryDelay, str)
+	p.net.connectedIPs[str] = struct{}{}
 }
 
 // assumes the stateLock is not held
@@ -555,6 +965,7 @@ func (p *peer) acceptedFrontier(msg Msg) {
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			p.violation("invalid container ID")
 			return
 		}
 		containerIDs.Add(containerID)
@@ -575,6 +986,7 @@ func (p *peer) getAccepted(msg Msg) {
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			p.violation("invalid container ID")
 			return
 		}
 		containerIDs.Add(containerID)
@@ -594,6 +1006,7 @@ func (p *peer) accepted(msg Msg) {
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			p.violation("invalid container ID")
 			return
 		}
 		containerIDs.Add(containerID)
@@ -683,6 +1096,7 @@ func (p *peer) chits(msg Msg) {
 		containerID, err := ids.ToID(containerIDBytes)
 		if err != nil {
 			p.net.log.Debug("error parsing ContainerID 0x%x: %s", containerIDBytes, err)
+			p.violation("invalid container ID")
 			return
 		}
 		containerIDs.Add(containerID)
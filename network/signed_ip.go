@@ -0,0 +1,90 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"net"
+
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	errNoStakingKey          = errors.New("no staking key to sign the IP with")
+	errUnsupportedStakingKey = errors.New("unsupported staking key type")
+	errInvalidIPSignature    = errors.New("invalid IP signature")
+
+	// signedIPDomain is prefixed to the signed bytes of IP announcements, so
+	// that a signature by a staking key over any other message can't be
+	// passed off as an IP announcement, or vice versa
+	signedIPDomain = []byte("avalanche signed ip announcement")
+)
+
+// ipHash returns the digest that is signed to announce that a node can be
+// reached at [ip] as of [timestamp]
+func ipHash(ip utils.IPDesc, timestamp uint64) []byte {
+	p := wrappers.Packer{MaxSize: len(signedIPDomain) + net.IPv6len + wrappers.ShortLen + wrappers.LongLen}
+	p.PackFixedBytes(signedIPDomain)
+	p.PackIP(ip)
+	p.PackLong(timestamp)
+	return hashing.ComputeHash256(p.Bytes)
+}
+
+// signIP signs that this node can be reached at [ip] as of [timestamp] with
+// [key], which should be the private key of this node's staking certificate
+func signIP(key crypto.Signer, ip utils.IPDesc, timestamp uint64) ([]byte, error) {
+	switch key.Public().(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key.Sign(rand.Reader, ipHash(ip, timestamp), crypto.SHA256)
+	default:
+		return nil, errUnsupportedStakingKey
+	}
+}
+
+// verifyIP returns nil if [sig] is a signature, by the private key of [key],
+// that its node can be reached at [ip] as of [timestamp]
+func verifyIP(key crypto.PublicKey, ip utils.IPDesc, timestamp uint64, sig []byte) error {
+	hash := ipHash(ip, timestamp)
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash, sig); err != nil {
+			return errInvalidIPSignature
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		ecdsaSig := struct{ R, S *big.Int }{}
+		if rest, err := asn1.Unmarshal(sig, &ecdsaSig); err != nil || len(rest) != 0 {
+			return errInvalidIPSignature
+		}
+		if !ecdsa.Verify(key, hash, ecdsaSig.R, ecdsaSig.S) {
+			return errInvalidIPSignature
+		}
+		return nil
+	default:
+		return errUnsupportedStakingKey
+	}
+}
+
+// stakingKey returns the public key of the certificate the peer on the other
+// end of [conn] authenticated with, or nil if [conn] isn't authenticated
+func stakingKey(conn net.Conn) crypto.PublicKey {
+	tlsConn, ok := conn.(interface{ ConnectionState() tls.ConnectionState })
+	if !ok {
+		return nil
+	}
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil
+	}
+	return certs[0].PublicKey
+}
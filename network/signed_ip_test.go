@@ -0,0 +1,75 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+func TestSignIP(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	ip := utils.IPDesc{IP: net.IPv6loopback, Port: 1}
+	otherIP := utils.IPDesc{IP: net.IPv6loopback, Port: 2}
+	for _, key := range []crypto.Signer{rsaKey, ecdsaKey} {
+		sig, err := signIP(key, ip, 1)
+		assert.NoError(t, err)
+		assert.NoError(t, verifyIP(key.Public(), ip, 1, sig))
+
+		// The signature commits to both the IP and the timestamp
+		assert.Equal(t, errInvalidIPSignature, verifyIP(key.Public(), otherIP, 1, sig))
+		assert.Equal(t, errInvalidIPSignature, verifyIP(key.Public(), ip, 2, sig))
+		assert.Equal(t, errInvalidIPSignature, verifyIP(key.Public(), ip, 1, sig[1:]))
+	}
+
+	// A signature must be verified against the signer's key
+	sig, err := signIP(rsaKey, ip, 1)
+	assert.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, errInvalidIPSignature, verifyIP(otherKey.Public(), ip, 1, sig))
+}
+
+func TestSignIPUnsupportedKey(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	ip := utils.IPDesc{IP: net.IPv6loopback, Port: 1}
+	_, err = signIP(key, ip, 1)
+	assert.Equal(t, errUnsupportedStakingKey, err)
+	assert.Equal(t, errUnsupportedStakingKey, verifyIP(key.Public(), ip, 1, nil))
+}
+
+func TestSignIPDomainSeparated(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	// A signature over the IP and timestamp without the domain tag isn't an
+	// IP announcement
+	ip := utils.IPDesc{IP: net.IPv6loopback, Port: 1}
+	p := wrappers.Packer{MaxSize: net.IPv6len + wrappers.ShortLen + wrappers.LongLen}
+	p.PackIP(ip)
+	p.PackLong(1)
+	assert.NoError(t, p.Err)
+
+	sig, err := key.Sign(rand.Reader, hashing.ComputeHash256(p.Bytes), crypto.SHA256)
+	assert.NoError(t, err)
+	assert.Equal(t, errInvalidIPSignature, verifyIP(key.Public(), ip, 1, sig))
+}
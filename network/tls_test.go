@@ -0,0 +1,110 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCert(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	certTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(0),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, certTemplate, certTemplate, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	return tls.Certificate{
+		Certificate: [][]byte{certBytes},
+		PrivateKey:  key,
+	}
+}
+
+// upgradeTestConns upgrades both ends of a connection and returns the errors
+// from the server and the client
+func upgradeTestConns(serverConfig, clientConfig *tls.Config) (error, error) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		_, _, err := NewTLSServerUpgrader(serverConfig).Upgrade(serverConn)
+		if err != nil {
+			// Unblock the client if the handshake failed
+			serverConn.Close()
+		}
+		serverErr <- err
+	}()
+
+	_, _, clientErr := NewTLSClientUpgrader(clientConfig).Upgrade(clientConn)
+	if clientErr != nil {
+		clientConn.Close()
+	}
+	return <-serverErr, clientErr
+}
+
+func TestTLSMinVersionRejectsOldPeer(t *testing.T) {
+	serverConfig := NewTLSConfig(newTestCert(t), TLSParams{MinVersion: tls.VersionTLS13})
+	clientConfig := NewTLSConfig(newTestCert(t), TLSParams{})
+	clientConfig.MaxVersion = tls.VersionTLS12
+
+	serverErr, clientErr := upgradeTestConns(serverConfig, clientConfig)
+	assert.Error(t, serverErr)
+	assert.Error(t, clientErr)
+}
+
+func TestTLSMinVersionAcceptsPeer(t *testing.T) {
+	serverConfig := NewTLSConfig(newTestCert(t), TLSParams{MinVersion: tls.VersionTLS13})
+	clientConfig := NewTLSConfig(newTestCert(t), TLSParams{MinVersion: tls.VersionTLS13})
+
+	serverErr, clientErr := upgradeTestConns(serverConfig, clientConfig)
+	assert.NoError(t, serverErr)
+	assert.NoError(t, clientErr)
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	version, err := ParseTLSVersion("1.3")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+
+	version, err = ParseTLSVersion("")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), version)
+
+	_, err = ParseTLSVersion("2.0")
+	assert.Error(t, err)
+}
+
+func TestParseCipherSuites(t *testing.T) {
+	suites, err := ParseCipherSuites("TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")
+	assert.NoError(t, err)
+	assert.Equal(t, []uint16{
+		tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+		tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	}, suites)
+
+	suites, err = ParseCipherSuites("")
+	assert.NoError(t, err)
+	assert.Empty(t, suites)
+
+	_, err = ParseCipherSuites("TLS_RSA_WITH_RC4_128_SHA")
+	assert.Error(t, err)
+}
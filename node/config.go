@@ -4,12 +4,17 @@
 package node
 
 import (
+	"time"
+
+	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/nat"
+	"github.com/ava-labs/gecko/network"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/version"
 )
 
 // Config contains all of the configurations of an Avalanche node.
@@ -35,6 +40,10 @@ type Config struct {
 	// Database to use for the node
 	DB database.Database
 
+	// How often [Nat] is asked for this node's public IP, so that peers can
+	// be told when it changes. If 0, the IP is assumed to never change.
+	IPUpdateFrequency time.Duration
+
 	// Staking configuration
 	StakingIP             utils.IPDesc
 	StakingLocalPort      uint16
@@ -45,16 +54,59 @@ type Config struct {
 	DisabledStakingWeight uint64
 	StakerMsgPortion      float64
 	StakerCPUPortion      float64
+	EnableCompression     bool
+	TLSParams             network.TLSParams
 
 	// Bootstrapping configuration
 	BootstrapPeers []*Peer
 
+	// How often the hostnames of bootstrap peers are re-resolved
+	BootstrapResolveFrequency time.Duration
+
+	// Restricts which peers may connect to this node
+	AccessList network.AccessList
+
+	// Limits the number of inbound connections this node accepts
+	ConnectionLimits network.ConnectionLimits
+
+	// Connections that don't finish their handshake within this time are
+	// closed. If 0, handshakes never time out.
+	HandshakeTimeout time.Duration
+
+	// Connections that start sending a message but don't finish it within
+	// this time are closed. If 0, only the ping timeout applies.
+	MessageReadTimeout time.Duration
+
+	// Period between TCP keepalive probes. If 0, the default is used.
+	KeepAlivePeriod time.Duration
+
+	// Peers that only send pings and pongs for this long are disconnected. If
+	// 0, idle peers aren't disconnected.
+	IdleTimeout time.Duration
+
+	// Maps subnet IDs to the outbound bandwidth, in bytes per second, their
+	// chains may use to gossip containers. Subnets without a quota aren't
+	// limited.
+	SubnetGossipQuotas map[[32]byte]uint64
+
+	// Peers with a version before this are disconnected. If nil, only
+	// compatibility with our version is checked.
+	MinimumVersion version.Version
+
+	// Chains don't start consensus until at least this many beacons are
+	// connected, or until BeaconTimeout passes, if it's positive. If either is
+	// set, the node doesn't shut down when it can't connect to its beacons.
+	MinConnectedBeacons int
+	BeaconTimeout       time.Duration
+
 	// HTTP configuration
 	HTTPHost      string
 	HTTPPort      uint16
 	EnableHTTPS   bool
 	HTTPSKeyFile  string
 	HTTPSCertFile string
+	EnableGzip    bool
+	GzipThreshold int
 
 	// Enable/Disable APIs
 	AdminAPIEnabled    bool
@@ -62,6 +114,17 @@ type Config struct {
 	KeystoreAPIEnabled bool
 	MetricsAPIEnabled  bool
 	HealthAPIEnabled   bool
+	EventsAPIEnabled   bool
+
+	// If non-empty, API calls must provide one of these bearer tokens
+	AdminAPIAuthTokens    []string
+	KeystoreAPIAuthTokens []string
+
+	// If non-nil, limits how often each API endpoint may be called, unless its
+	// route group has a limit in APIRateLimits
+	APIRateLimit *api.RateLimit
+	// Maps a route group, such as "admin" or "bc/X", to its rate limit
+	APIRateLimits map[string]api.RateLimit
 
 	// Logging configuration
 	LoggingConfig logging.Config
@@ -72,6 +135,30 @@ type Config struct {
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters
 
+	// Snowman chains accept at most MaxAcceptedPerInterval blocks every
+	// AcceptInterval. If MaxAcceptedPerInterval is 0, acceptance isn't limited.
+	MaxAcceptedPerInterval int
+	AcceptInterval         time.Duration
+
+	// Snowman chains that haven't built a block for this long build one even
+	// if their VM has no pending transactions. If 0, blocks are only built
+	// when there are pending transactions.
+	MaxIdleInterval time.Duration
+
+	// Allows recovery tooling to force snowman chains to accept blocks without
+	// consensus. This is unsafe.
+	UnsafeAllowForceAccept bool
+
+	// Avalanche chains build at most MaxVerticesPerInterval vertices every
+	// VertexInterval. If MaxVerticesPerInterval is 0, vertex building isn't
+	// limited.
+	MaxVerticesPerInterval int
+	VertexInterval         time.Duration
+
+	// Avalanche chains stop building vertices while this many are processing.
+	// If 0, the number of processing vertices isn't limited.
+	MaxProcessingVertices int
+
 	// Throughput configuration
 	ThroughputPort          uint16
 	ThroughputServerEnabled bool
@@ -4,6 +4,7 @@
 package node
 
 import (
+	"crypto"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/api/admin"
+	"github.com/ava-labs/gecko/api/events"
 	"github.com/ava-labs/gecko/api/health"
 	"github.com/ava-labs/gecko/api/info"
 	"github.com/ava-labs/gecko/api/keystore"
@@ -57,6 +59,15 @@ import (
 // Networking constants
 const (
 	TCP = "tcp"
+
+	// DefaultIPUpdateFrequency is how often this node checks whether its
+	// public IP changed by default
+	DefaultIPUpdateFrequency = 5 * time.Minute
+
+	// connectToBootstrapsTimeout is how long the node waits to connect to its
+	// bootstrap nodes before shutting down, unless the beacon gate is
+	// configured
+	connectToBootstrapsTimeout = 15 * time.Second
 )
 
 var (
@@ -65,6 +76,10 @@ var (
 	// Version is the version of this code
 	Version       = version.NewDefaultVersion("avalanche", 0, 6, 2)
 	versionParser = version.NewDefaultParser()
+
+	// CompressionVersion is the first version that accepts compressed messages.
+	// Compression is only negotiated once the node's Version reaches it.
+	CompressionVersion = version.NewDefaultVersion("avalanche", 0, 6, 3)
 )
 
 // Node is an instance of an Avalanche node.
@@ -104,6 +119,15 @@ type Node struct {
 	// this node's initial connections to the network
 	beacons validators.Set
 
+	// resolves the hostnames of beacons, if any were provided
+	bootstrapResolver *network.BootstrapResolver
+
+	// periodically checks whether this node's public IP changed, if enabled.
+	// [stakingIP] is the IP most recently announced to peers, and is only
+	// accessed by [ipUpdater].
+	ipUpdater *timer.Repeater
+	stakingIP utils.IPDesc
+
 	// current validators of the network
 	vdrs validators.Manager
 
@@ -130,23 +154,18 @@ func (n *Node) initNetworking() error {
 	}
 	dialer := network.NewDialer(TCP)
 
-	var serverUpgrader, clientUpgrader network.Upgrader
+	var (
+		serverUpgrader, clientUpgrader network.Upgrader
+		stakingKey                     crypto.Signer
+	)
 	if n.Config.EnableP2PTLS {
 		cert, err := tls.LoadX509KeyPair(n.Config.StakingCertFile, n.Config.StakingKeyFile)
 		if err != nil {
 			return err
 		}
+		stakingKey, _ = cert.PrivateKey.(crypto.Signer)
 
-		tlsConfig := &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			ClientAuth:   tls.RequireAnyClientCert,
-			// We do not use TLS's CA functionality, we just require an
-			// authenticated channel. Therefore, we can safely skip verification
-			// here.
-			//
-			// TODO: Security audit required
-			InsecureSkipVerify: true,
-		}
+		tlsConfig := network.NewTLSConfig(cert, n.Config.TLSParams)
 
 		serverUpgrader = network.NewTLSServerUpgrader(tlsConfig)
 		clientUpgrader = network.NewTLSClientUpgrader(tlsConfig)
@@ -160,6 +179,11 @@ func (n *Node) initNetworking() error {
 	n.vdrs = validators.NewManager()
 	n.vdrs.PutValidatorSet(constants.DefaultSubnetID, defaultSubnetValidators)
 
+	var compressionVersion version.Version
+	if n.Config.EnableCompression {
+		compressionVersion = CompressionVersion
+	}
+
 	n.Net = network.NewDefaultNetwork(
 		n.Config.ConsensusParams.Metrics,
 		n.Log,
@@ -175,7 +199,23 @@ func (n *Node) initNetworking() error {
 		defaultSubnetValidators,
 		n.beacons,
 		n.Config.ConsensusRouter,
+		compressionVersion,
 	)
+	n.Net.SetAccessList(n.Config.AccessList)
+	n.Net.SetConnectionLimits(n.Config.ConnectionLimits)
+	n.Net.SetHandshakeTimeout(n.Config.HandshakeTimeout)
+	n.Net.SetMessageReadTimeout(n.Config.MessageReadTimeout)
+	n.Net.SetKeepAlivePeriod(n.Config.KeepAlivePeriod)
+	n.Net.SetIdleTimeout(n.Config.IdleTimeout)
+	n.Net.SetMinimumVersion(n.Config.MinimumVersion)
+	n.Net.SetBeaconGate(n.Config.MinConnectedBeacons, n.Config.BeaconTimeout)
+	if stakingKey != nil {
+		n.Net.SetStakingKey(stakingKey)
+		if n.Config.Nat != nil && n.Config.IPUpdateFrequency > 0 {
+			n.stakingIP = n.Config.StakingIP
+			n.ipUpdater = timer.NewRepeater(n.updateIP, n.Config.IPUpdateFrequency)
+		}
+	}
 
 	if !n.Config.EnableStaking {
 		n.Net.RegisterHandler(&insecureValidatorManager{
@@ -184,6 +224,26 @@ func (n *Node) initNetworking() error {
 		})
 	}
 
+	bootstrapHosts := []string(nil)
+	for _, peer := range n.Config.BootstrapPeers {
+		if peer.Host != "" {
+			bootstrapHosts = append(bootstrapHosts, peer.Host)
+		}
+	}
+	if len(bootstrapHosts) > 0 {
+		n.bootstrapResolver, err = network.NewBootstrapResolver(
+			n.Log,
+			network.DNSResolver{},
+			n.Net.Track,
+			n.Net.Untrack,
+			bootstrapHosts,
+			n.Config.BootstrapResolveFrequency,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	n.nodeCloser = utils.HandleSignals(func(os.Signal) {
 		// errors are already logged internally if they are meaningful
 		_ = n.Net.Close()
@@ -192,6 +252,30 @@ func (n *Node) initNetworking() error {
 	return nil
 }
 
+// updateIP announces this node's public IP to its peers if it changed since it
+// was last announced
+func (n *Node) updateIP() {
+	ip, err := n.Config.Nat.ExternalIP()
+	if err != nil {
+		n.Log.Debug("couldn't get my public IP: %s", err)
+		return
+	}
+	if ip.Equal(n.stakingIP.IP) {
+		return
+	}
+
+	newIP := utils.IPDesc{
+		IP:   ip,
+		Port: n.stakingIP.Port,
+	}
+	n.Log.Info("my public IP changed from %s to %s", n.stakingIP, newIP)
+	if err := n.Net.UpdateIP(newIP); err != nil {
+		n.Log.Warn("couldn't announce my new IP %s: %s", newIP, err)
+		return
+	}
+	n.stakingIP = newIP
+}
+
 type insecureValidatorManager struct {
 	vdrs   validators.Set
 	weight uint64
@@ -229,9 +313,20 @@ func (n *Node) Dispatch() error {
 		_ = n.Net.Close() // If the server isn't up, shut down the node.
 	})
 
+	if n.ipUpdater != nil {
+		go n.ipUpdater.Dispatch()
+	}
+
 	// Add bootstrap nodes to the peer network
+	if n.bootstrapResolver != nil {
+		go n.bootstrapResolver.Dispatch()
+	}
 	for _, peer := range n.Config.BootstrapPeers {
-		if !peer.IP.Equal(n.Config.StakingIP) {
+		if peer.Host != "" {
+			// Tracked by the bootstrap resolver once resolved
+			continue
+		}
+		if !peer.IP.Equal(n.Config.StakingIP) && !peer.ID.Equals(n.ID) {
 			n.Net.Track(peer.IP)
 		} else {
 			n.Log.Error("can't add self as a bootstrapper")
@@ -369,28 +464,57 @@ func (n *Node) initChains(genesisBytes []byte, avaxAssetID ids.ID) error {
 		return nil
 	}
 
-	connectToBootstrapsTimeout := timer.NewTimer(func() {
+	shutdownTimeout := bootstrapShutdownTimeout(n.Config.MinConnectedBeacons, n.Config.BeaconTimeout)
+	if shutdownTimeout == 0 {
+		n.Net.AwaitBeacons(n.beacons, reqWeight, func() {
+			n.Log.Info("Connected to required bootstrap nodes. Starting Platform Chain...")
+		})
+		return nil
+	}
+
+	shutdownTimer := timer.NewTimer(func() {
 		n.Log.Fatal("Failed to connect to bootstrap nodes. Node shutting down...")
 		go n.Net.Close()
 	})
 
-	awaiter := chains.NewAwaiter(n.beacons, reqWeight, func() {
+	go shutdownTimer.Dispatch()
+	shutdownTimer.SetTimeoutIn(shutdownTimeout)
+
+	n.Net.AwaitBeacons(n.beacons, reqWeight, func() {
 		n.Log.Info("Connected to required bootstrap nodes. Starting Platform Chain...")
-		connectToBootstrapsTimeout.Cancel()
+		shutdownTimer.Cancel()
 	})
-
-	go connectToBootstrapsTimeout.Dispatch()
-	connectToBootstrapsTimeout.SetTimeoutIn(15 * time.Second)
-
-	n.Net.RegisterHandler(awaiter)
 	return nil
 }
 
+// bootstrapShutdownTimeout returns how long the node may take to connect to
+// its bootstrap nodes before it shuts down. If the beacon gate is configured,
+// it decides when consensus starts, either by waiting for [minBeacons] or by
+// giving up after [beaconTimeout], so the node never shuts down and 0 is
+// returned.
+func bootstrapShutdownTimeout(minBeacons int, beaconTimeout time.Duration) time.Duration {
+	if minBeacons > 0 || beaconTimeout > 0 {
+		return 0
+	}
+	return connectToBootstrapsTimeout
+}
+
 // initAPIServer initializes the server that handles HTTP calls
 func (n *Node) initAPIServer() {
 	n.Log.Info("Initializing API server")
 
 	n.APIServer.Initialize(n.Log, n.LogFactory, n.Config.HTTPHost, n.Config.HTTPPort)
+	if n.Config.EnableGzip {
+		n.APIServer.EnableGzip(n.Config.GzipThreshold)
+	}
+	n.APIServer.RequireAuth("admin", n.Config.AdminAPIAuthTokens...)
+	n.APIServer.RequireAuth("keystore", n.Config.KeystoreAPIAuthTokens...)
+	if n.Config.APIRateLimit != nil {
+		n.APIServer.SetRateLimit("", *n.Config.APIRateLimit)
+	}
+	for base, limit := range n.Config.APIRateLimits {
+		n.APIServer.SetRateLimit(base, limit)
+	}
 }
 
 // Create the vmManager, chainManager and register the following vms:
@@ -431,11 +555,25 @@ func (n *Node) initChainManager(avaxAssetID ids.ID) error {
 		avaxAssetID,
 		xChainID,
 		criticalChains,
+		genesis.ForkSchedule(n.Config.NetworkID),
+		n.Config.MaxAcceptedPerInterval,
+		n.Config.AcceptInterval,
+		n.Config.MaxIdleInterval,
+		n.Config.UnsafeAllowForceAccept,
+		n.Config.MaxVerticesPerInterval,
+		n.Config.VertexInterval,
+		n.Config.MaxProcessingVertices,
 	)
 	if err != nil {
 		return err
 	}
 
+	// Gossip is accounted to the subnets of the chains the manager creates
+	n.Net.SetSubnetLookup(n.chainManager)
+	for subnetID, bytesPerSecond := range n.Config.SubnetGossipQuotas {
+		n.Net.SetGossipQuota(ids.NewID(subnetID), bytesPerSecond)
+	}
+
 	vdrs := n.vdrs
 
 	// If staking is disabled, ignore updates to Subnets' validator sets
@@ -536,7 +674,7 @@ func (n *Node) initAdminAPI() error {
 		return nil
 	}
 	n.Log.Info("initializing admin API")
-	service, err := admin.NewService(n.Log, n.chainManager, &n.APIServer)
+	service, err := admin.NewService(n.Log, n.LogFactory, n.chainManager, &n.APIServer)
 	if err != nil {
 		return err
 	}
@@ -612,6 +750,22 @@ func (n *Node) initIPCAPI() error {
 	return n.APIServer.AddRoute(service, &sync.RWMutex{}, "ipcs", "", n.HTTPLog)
 }
 
+// initEventsAPI initializes the Events API service
+// Assumes n.Log, n.chainManager and n.DecisionDispatcher already initialized
+func (n *Node) initEventsAPI() error {
+	if !n.Config.EventsAPIEnabled {
+		n.Log.Info("skipping events API initialization because it has been disabled")
+		return nil
+	}
+	n.Log.Info("initializing events API")
+	service := events.NewService(n.Log)
+	if err := n.DecisionDispatcher.Register("events", service); err != nil {
+		return err
+	}
+	n.chainManager.AddRegistrant(service)
+	return n.APIServer.AddRoute(service.Handler(), &sync.RWMutex{}, "events", "", n.HTTPLog)
+}
+
 // Give chains and VMs aliases as specified by the genesis information
 func (n *Node) initAliases() error {
 	n.Log.Info("initializing aliases")
@@ -707,6 +861,9 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 	if err := n.initIPCAPI(); err != nil { // Start the IPC API
 		return fmt.Errorf("couldn't initialize ipc API: %w", err)
 	}
+	if err := n.initEventsAPI(); err != nil { // Start the Events API
+		return fmt.Errorf("couldn't initialize events API: %w", err)
+	}
 	if err := n.initAliases(); err != nil { // Set up aliases
 		return fmt.Errorf("couldn't initialize aliases: %w", err)
 	}
@@ -725,6 +882,12 @@ func (n *Node) Shutdown() {
 	// Close already logs its own error if one occurs, so the error is ignored
 	// here
 	_ = n.Net.Close()
+	if n.bootstrapResolver != nil {
+		n.bootstrapResolver.Stop()
+	}
+	if n.ipUpdater != nil {
+		n.ipUpdater.Stop()
+	}
 	n.chainManager.Shutdown()
 	utils.ClearSignals(n.nodeCloser)
 	n.Log.Info("node shut down successfully")
@@ -0,0 +1,45 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package node
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBootstrapShutdownTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		minBeacons    int
+		beaconTimeout time.Duration
+		expected      time.Duration
+	}{
+		{
+			name:     "gate not configured",
+			expected: connectToBootstrapsTimeout,
+		},
+		{
+			// The gate would start consensus after a minute, so shutting
+			// down after 15 seconds would preempt it
+			name:          "beacon timeout",
+			beaconTimeout: time.Minute,
+		},
+		{
+			// Consensus is deferred until enough beacons connect, rather than
+			// shutting the node down
+			name:       "min beacons",
+			minBeacons: 5,
+		},
+		{
+			name:          "min beacons and beacon timeout",
+			minBeacons:    5,
+			beaconTimeout: time.Second,
+		},
+	}
+	for _, test := range tests {
+		if timeout := bootstrapShutdownTimeout(test.minBeacons, test.beaconTimeout); timeout != test.expected {
+			t.Fatalf("%s: expected a shutdown timeout of %s but got %s", test.name, test.expected, timeout)
+		}
+	}
+}
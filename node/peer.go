@@ -12,6 +12,9 @@ import (
 type Peer struct {
 	// IP of the peer
 	IP utils.IPDesc
+	// If non-empty, the host:port of the peer, which is resolved periodically
+	// to find the peer's IPs. IP is ignored.
+	Host string
 	// ID of the peer that can be verified during a handshake
 	ID ids.ShortID
 }
@@ -53,6 +53,10 @@ type Consensus interface {
 	// Returns a set of vertex IDs that are preferred
 	Preferences() ids.Set
 
+	// NumProcessing returns the number of vertices that have been added but
+	// not yet accepted or rejected
+	NumProcessing() int
+
 	// RecordPoll collects the results of a network poll. If a result has not
 	// been added, the result is dropped. Returns if a critical error has
 	// occurred.
@@ -141,6 +141,9 @@ func (ta *Topological) Virtuous() ids.Set { return ta.virtuous }
 // Preferences implements the Avalanche interface
 func (ta *Topological) Preferences() ids.Set { return ta.preferred }
 
+// NumProcessing implements the Avalanche interface
+func (ta *Topological) NumProcessing() int { return len(ta.nodes) }
+
 // RecordPoll implements the Avalanche interface
 func (ta *Topological) RecordPoll(responses ids.UniqueBag) error {
 	// If it isn't possible to have alpha votes for any transaction, then we can
@@ -37,4 +37,12 @@ type Consensus interface {
 	// finalized. Note, it is possible that after returning finalized, a new
 	// decision may be added such that this instance is no longer finalized.
 	Finalized() bool
+
+	// ForceAccept accepts the processing block with the provided ID, along
+	// with all of its processing ancestors, without waiting for a poll to
+	// finalize it. Every block that conflicts with the accepted branch is
+	// rejected. This bypasses consensus and should only be used for recovery.
+	// Returns an error if the block isn't a processing descendant of the last
+	// accepted block, or if a critical error has occurred.
+	ForceAccept(ids.ID) error
 }
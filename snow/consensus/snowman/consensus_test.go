@@ -47,6 +47,8 @@ var (
 		ErrorOnAcceptTest,
 		ErrorOnRejectSiblingTest,
 		ErrorOnTransitiveRejectionTest,
+		ForceAcceptDescendantTest,
+		ForceAcceptNonDescendantTest,
 		RandomizedConsistencyTest,
 	}
 )
@@ -1181,6 +1183,137 @@ func ErrorOnTransitiveRejectionTest(t *testing.T, factory Factory) {
 	}
 }
 
+func ForceAcceptDescendantTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	ctx := snow.DefaultContextTest()
+	params := snowball.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 1,
+		Alpha:             1,
+		BetaVirtuous:      1,
+		BetaRogue:         2,
+		ConcurrentRepolls: 1,
+	}
+	sm.Initialize(ctx, params, GenesisID)
+
+	block0 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	block1 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(2),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	block2 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(3),
+			StatusV: choices.Processing,
+		},
+		ParentV: block1,
+	}
+	block3 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(4),
+			StatusV: choices.Processing,
+		},
+		ParentV: block2,
+	}
+
+	for _, blk := range []*TestBlock{block0, block1, block2, block3} {
+		if err := sm.Add(blk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := sm.ForceAccept(block2.ID()); err != nil {
+		t.Fatal(err)
+	}
+
+	if sm.Finalized() {
+		t.Fatalf("Finalized too early")
+	} else if pref := sm.Preference(); !block3.ID().Equals(pref) {
+		t.Fatalf("Preference returned the wrong block")
+	} else if status := block0.Status(); status != choices.Rejected {
+		t.Fatalf("Sibling of the accepted branch should have been rejected")
+	} else if status := block1.Status(); status != choices.Accepted {
+		t.Fatalf("Ancestor of the forced block should have been accepted")
+	} else if status := block2.Status(); status != choices.Accepted {
+		t.Fatalf("Forced block should have been accepted")
+	} else if status := block3.Status(); status != choices.Processing {
+		t.Fatalf("Descendant of the forced block should still be processing")
+	}
+}
+
+func ForceAcceptNonDescendantTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	ctx := snow.DefaultContextTest()
+	params := snowball.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 1,
+		Alpha:             1,
+		BetaVirtuous:      1,
+		BetaRogue:         2,
+		ConcurrentRepolls: 1,
+	}
+	sm.Initialize(ctx, params, GenesisID)
+
+	block0 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(1),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	block1 := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(2),
+			StatusV: choices.Processing,
+		},
+		ParentV: Genesis,
+	}
+	unknown := &TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(3),
+			StatusV: choices.Processing,
+		},
+		ParentV: block1,
+	}
+
+	if err := sm.Add(block0); err != nil {
+		t.Fatal(err)
+	} else if err := sm.Add(block1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sm.ForceAccept(unknown.ID()); err == nil {
+		t.Fatalf("Should have errored when forcing an unissued block")
+	} else if err := sm.ForceAccept(GenesisID); err == nil {
+		t.Fatalf("Should have errored when forcing the last accepted block")
+	}
+
+	if err := sm.ForceAccept(block0.ID()); err != nil {
+		t.Fatal(err)
+	} else if err := sm.ForceAccept(block1.ID()); err == nil {
+		t.Fatalf("Should have errored when forcing a rejected block")
+	}
+
+	if !sm.Finalized() {
+		t.Fatalf("Should have finalized")
+	} else if status := block0.Status(); status != choices.Accepted {
+		t.Fatalf("Forced block should have been accepted")
+	} else if status := block1.Status(); status != choices.Rejected {
+		t.Fatalf("Sibling of the forced block should have been rejected")
+	}
+}
+
 func RandomizedConsistencyTest(t *testing.T, factory Factory) {
 	numColors := 50
 	numNodes := 100
@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"fmt"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
@@ -186,6 +188,37 @@ func (ts *Topological) RecordPoll(voteBag ids.Bag) error {
 // Finalized implements the Snowman interface
 func (ts *Topological) Finalized() bool { return len(ts.blocks) == 1 }
 
+// ForceAccept implements the Snowman interface
+func (ts *Topological) ForceAccept(blkID ids.ID) error {
+	if blkID.Equals(ts.head) {
+		return fmt.Errorf("block %s is already the last accepted block", blkID)
+	}
+
+	// Walk from the block back to the last accepted block, tracking the
+	// branch that needs to be accepted.
+	branch := []ids.ID(nil)
+	for id := blkID; !id.Equals(ts.head); {
+		n, ok := ts.blocks[id.Key()]
+		if !ok || n.blk == nil {
+			return fmt.Errorf("block %s isn't a processing descendant of the last accepted block %s", blkID, ts.head)
+		}
+		branch = append(branch, id)
+		id = n.blk.Parent().ID()
+	}
+
+	// Accept the branch starting from the child of the last accepted block.
+	for i := len(branch) - 1; i >= 0; i-- {
+		headKey := ts.head.Key()
+		if err := ts.acceptChild(ts.blocks[headKey], branch[i]); err != nil {
+			return err
+		}
+		delete(ts.blocks, headKey)
+	}
+
+	ts.tail = ts.getPreferredDecendent(ts.head)
+	return nil
+}
+
 // takes in a list of votes and sets up the topological ordering. Returns the
 // reachable section of the graph annotated with the number of inbound edges and
 // the non-transitively applied votes. Also returns the list of leaf blocks.
@@ -428,8 +461,12 @@ func (ts *Topological) getPreferredDecendent(blkID ids.ID) ids.ID {
 // rejected, all their descendants will be rejected.
 func (ts *Topological) accept(n *snowmanBlock) error {
 	// We are finalizing the block's child, so we need to get the preference
-	pref := n.sb.Preference()
+	return ts.acceptChild(n, n.sb.Preference())
+}
 
+// acceptChild accepts the child [pref] of the provided snowman block and
+// rejects all of its siblings, along with their descendants.
+func (ts *Topological) acceptChild(n *snowmanBlock, pref ids.ID) error {
 	ts.ctx.Log.Verbo("Accepting block with ID %s", pref)
 
 	// Get the child and accept it
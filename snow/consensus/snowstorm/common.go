@@ -39,6 +39,9 @@ type common struct {
 	// keeps track of whether dependencies have been rejected
 	pendingReject events.Blocker
 
+	// reasons that recently rejected transactions were rejected
+	rejections rejectionLog
+
 	// track any errors that occurred during callbacks
 	errs wrappers.Errs
 }
@@ -49,6 +49,10 @@ type Consensus interface {
 	// changed. Returns if a critical error has occurred.
 	RecordPoll(ids.Bag) (bool, error)
 
+	// Returns why transaction <txID> was rejected, if it was rejected
+	// recently by this instance
+	RejectionReason(txID ids.ID) (string, bool)
+
 	// Returns true iff all remaining transactions are rogue. Note, it is
 	// possible that after returning quiesce, a new decision may be added such
 	// that this instance should no longer quiesce.
@@ -36,6 +36,9 @@ var (
 		ErrorOnAcceptedTest,
 		ErrorOnRejectingLowerConfidenceConflictTest,
 		ErrorOnRejectingHigherConfidenceConflictTest,
+		ConflictRejectionReasonTest,
+		DependencyRejectionReasonTest,
+		UnknownRejectionReasonTest,
 	}
 
 	Red, Green, Blue, Alpha *TestTx
@@ -871,6 +874,97 @@ func ErrorOnRejectingHigherConfidenceConflictTest(t *testing.T, factory Factory)
 	}
 }
 
+func ConflictRejectionReasonTest(t *testing.T, factory Factory) {
+	Setup()
+
+	graph := factory.New()
+
+	params := snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 1, BetaRogue: 1,
+	}
+	graph.Initialize(snow.DefaultContextTest(), params)
+
+	if err := graph.Add(Red); err != nil {
+		t.Fatal(err)
+	} else if err := graph.Add(Green); err != nil {
+		t.Fatal(err)
+	} else if _, ok := graph.RejectionReason(Green.ID()); ok {
+		t.Fatalf("Processing transaction shouldn't have a rejection reason")
+	}
+
+	r := ids.Bag{}
+	r.Add(Red.ID())
+	if _, err := graph.RecordPoll(r); err != nil {
+		t.Fatal(err)
+	} else if Green.Status() != choices.Rejected {
+		t.Fatalf("Wrong status. %s should be %s", Green.ID(), choices.Rejected)
+	} else if reason, ok := graph.RejectionReason(Green.ID()); !ok {
+		t.Fatalf("Rejected transaction should have a rejection reason")
+	} else if expected := conflictReason(Red.ID()); reason != expected {
+		t.Fatalf("Wrong rejection reason. Expected %q got %q", expected, reason)
+	} else if _, ok := graph.RejectionReason(Red.ID()); ok {
+		t.Fatalf("Accepted transaction shouldn't have a rejection reason")
+	}
+}
+
+func DependencyRejectionReasonTest(t *testing.T, factory Factory) {
+	Setup()
+
+	graph := factory.New()
+
+	purple := &TestTx{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(7),
+			StatusV: choices.Processing,
+		},
+		DependenciesV: []Tx{Red},
+	}
+	purple.InputIDsV.Add(ids.Empty.Prefix(8))
+
+	params := snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 1, BetaRogue: 1,
+	}
+	graph.Initialize(snow.DefaultContextTest(), params)
+
+	if err := graph.Add(Red); err != nil {
+		t.Fatal(err)
+	} else if err := graph.Add(Green); err != nil {
+		t.Fatal(err)
+	} else if err := graph.Add(purple); err != nil {
+		t.Fatal(err)
+	}
+
+	g := ids.Bag{}
+	g.Add(Green.ID())
+	if _, err := graph.RecordPoll(g); err != nil {
+		t.Fatal(err)
+	} else if purple.Status() != choices.Rejected {
+		t.Fatalf("Wrong status. %s should be %s", purple.ID(), choices.Rejected)
+	} else if reason, ok := graph.RejectionReason(purple.ID()); !ok {
+		t.Fatalf("Rejected transaction should have a rejection reason")
+	} else if expected := dependencyReason(Red.ID()); reason != expected {
+		t.Fatalf("Wrong rejection reason. Expected %q got %q", expected, reason)
+	}
+}
+
+func UnknownRejectionReasonTest(t *testing.T, factory Factory) {
+	Setup()
+
+	graph := factory.New()
+
+	params := snowball.Parameters{
+		Metrics: prometheus.NewRegistry(),
+		K:       1, Alpha: 1, BetaVirtuous: 1, BetaRogue: 1,
+	}
+	graph.Initialize(snow.DefaultContextTest(), params)
+
+	if reason, ok := graph.RejectionReason(ids.Empty.Prefix(9)); ok {
+		t.Fatalf("Unknown transaction shouldn't have a rejection reason but got %q", reason)
+	}
+}
+
 func StringTest(t *testing.T, factory Factory, prefix string) {
 	Setup()
 
@@ -270,7 +270,7 @@ func (dg *Directed) deferAcceptance(txNode *directedTx) {
 	dg.pendingAccept.Register(toAccept)
 }
 
-func (dg *Directed) reject(ids ...ids.ID) error {
+func (dg *Directed) reject(reason string, ids ...ids.ID) error {
 	for _, conflict := range ids {
 		conflictKey := conflict.Key()
 		conf := dg.txs[conflictKey]
@@ -288,6 +288,7 @@ func (dg *Directed) reject(ids ...ids.ID) error {
 		}
 		dg.ctx.DecisionDispatcher.Reject(dg.ctx.ChainID, conf.tx.ID(), conf.tx.Bytes())
 		dg.metrics.Rejected(conflict)
+		dg.rejections.add(conflict, reason)
 
 		dg.pendingAccept.Abandon(conflict)
 		dg.pendingReject.Fulfill(conflict)
@@ -381,12 +382,13 @@ func (a *directedAccepter) Update() {
 	a.dg.preferences.Remove(id)
 
 	// Reject the conflicts
-	if err := a.dg.reject(a.txNode.ins.List()...); err != nil {
+	reason := conflictReason(id)
+	if err := a.dg.reject(reason, a.txNode.ins.List()...); err != nil {
 		a.dg.errs.Add(err)
 		return
 	}
 	// Should normally be empty
-	if err := a.dg.reject(a.txNode.outs.List()...); err != nil {
+	if err := a.dg.reject(reason, a.txNode.outs.List()...); err != nil {
 		a.dg.errs.Add(err)
 		return
 	}
@@ -419,7 +421,7 @@ func (r *directedRejector) Fulfill(id ids.ID) {
 		return
 	}
 	r.rejected = true
-	r.dg.errs.Add(r.dg.reject(r.txNode.tx.ID()))
+	r.dg.errs.Add(r.dg.reject(dependencyReason(id), r.txNode.tx.ID()))
 }
 
 func (*directedRejector) Abandon(id ids.ID) {}
@@ -273,7 +273,7 @@ func (ig *Input) deferAcceptance(tn inputTx) {
 }
 
 // reject all the ids and remove them from their conflict sets
-func (ig *Input) reject(ids ...ids.ID) error {
+func (ig *Input) reject(reason string, ids ...ids.ID) error {
 	for _, conflict := range ids {
 		conflictKey := conflict.Key()
 		cn := ig.txs[conflictKey]
@@ -289,6 +289,7 @@ func (ig *Input) reject(ids ...ids.ID) error {
 		}
 		ig.ctx.DecisionDispatcher.Reject(ig.ctx.ChainID, cn.tx.ID(), cn.tx.Bytes())
 		ig.metrics.Rejected(conflict)
+		ig.rejections.add(conflict, reason)
 		ig.pendingAccept.Abandon(conflict)
 		ig.pendingReject.Fulfill(conflict)
 	}
@@ -457,7 +458,7 @@ func (a *inputAccepter) Update() {
 			conflicts.Union(inputNode.conflicts)
 		}
 	}
-	if err := a.ig.reject(conflicts.List()...); err != nil {
+	if err := a.ig.reject(conflictReason(id), conflicts.List()...); err != nil {
 		a.ig.errs.Add(err)
 		return
 	}
@@ -489,7 +490,7 @@ func (r *inputRejector) Fulfill(id ids.ID) {
 		return
 	}
 	r.rejected = true
-	r.ig.errs.Add(r.ig.reject(r.tn.tx.ID()))
+	r.ig.errs.Add(r.ig.reject(dependencyReason(id), r.tn.tx.ID()))
 }
 
 func (*inputRejector) Abandon(id ids.ID) {}
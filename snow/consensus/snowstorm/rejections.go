@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+)
+
+const (
+	// maxRejectionReasons is the number of recently rejected transactions
+	// whose rejection reasons are retained
+	maxRejectionReasons = 1024
+)
+
+// conflictReason is the reason given for rejecting a transaction because
+// [acceptedID], which spends one of the same inputs, was accepted
+func conflictReason(acceptedID ids.ID) string {
+	return fmt.Sprintf("conflicts with accepted transaction %s", acceptedID)
+}
+
+// dependencyReason is the reason given for rejecting a transaction because
+// [dependencyID], which it depends on, was rejected
+func dependencyReason(dependencyID ids.ID) string {
+	return fmt.Sprintf("depends on rejected transaction %s", dependencyID)
+}
+
+// rejectionLog is a bounded record of why transactions were rejected. Once
+// full, the oldest reasons are evicted first.
+type rejectionLog struct {
+	reasons map[[32]byte]string
+	// the IDs in [reasons], in the order they were added
+	order *utils.RingBuffer
+}
+
+func (l *rejectionLog) add(txID ids.ID, reason string) {
+	if l.reasons == nil {
+		l.reasons = make(map[[32]byte]string)
+		l.order = utils.NewRingBuffer(maxRejectionReasons)
+	}
+
+	if evicted, ok := l.order.Append(txID); ok {
+		delete(l.reasons, evicted.(ids.ID).Key())
+	}
+	l.reasons[txID.Key()] = reason
+}
+
+// RejectionReason implements the Consensus interface
+func (c *common) RejectionReason(txID ids.ID) (string, bool) {
+	reason, ok := c.rejections.reasons[txID.Key()]
+	return reason, ok
+}
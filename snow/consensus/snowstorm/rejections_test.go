@@ -0,0 +1,27 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowstorm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestRejectionLogEviction(t *testing.T) {
+	l := rejectionLog{}
+	for i := 0; i < maxRejectionReasons+1; i++ {
+		l.add(ids.Empty.Prefix(uint64(i)), "reason")
+	}
+
+	if _, ok := l.reasons[ids.Empty.Prefix(0).Key()]; ok {
+		t.Fatalf("Oldest rejection reason should have been evicted")
+	}
+	if _, ok := l.reasons[ids.Empty.Prefix(maxRejectionReasons).Key()]; !ok {
+		t.Fatalf("Newest rejection reason should have been retained")
+	}
+	if len(l.reasons) != maxRejectionReasons {
+		t.Fatalf("Retained %d rejection reasons but expected %d", len(l.reasons), maxRejectionReasons)
+	}
+}
@@ -8,14 +8,14 @@ import (
 	"net/http"
 	"sync"
 
-	stdatomic "sync/atomic"
-
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/ava-labs/gecko/chains/atomic"
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/triggers"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/forks"
 	"github.com/ava-labs/gecko/utils/logging"
 )
 
@@ -53,6 +53,10 @@ type Context struct {
 	XChainID    ids.ID
 	AVAXAssetID ids.ID
 
+	// Forks is the schedule of network upgrades that VMs should consult when
+	// deciding which rules apply to a block or transaction
+	Forks *forks.Schedule
+
 	Log                 logging.Logger
 	DecisionDispatcher  *triggers.EventDispatcher
 	ConsensusDispatcher *triggers.EventDispatcher
@@ -62,20 +66,20 @@ type Context struct {
 	BCLookup            AliasLookup
 	SNLookup            SubnetLookup
 
-	// Non-zero iff this chain bootstrapped. Should only be accessed atomically.
-	bootstrapped uint32
+	// true iff this chain bootstrapped
+	bootstrapped utils.AtomicBool
 	Namespace    string
 	Metrics      prometheus.Registerer
 }
 
 // IsBootstrapped returns true iff this chain is done bootstrapping
 func (ctx *Context) IsBootstrapped() bool {
-	return stdatomic.LoadUint32(&ctx.bootstrapped) > 0
+	return ctx.bootstrapped.Get()
 }
 
 // Bootstrapped marks this chain as done bootstrapping
 func (ctx *Context) Bootstrapped() {
-	stdatomic.StoreUint32(&ctx.bootstrapped, 1)
+	ctx.bootstrapped.Set(true)
 }
 
 // DefaultContextTest ...
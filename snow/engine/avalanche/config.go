@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/engine/avalanche/bootstrap"
 )
@@ -14,4 +16,17 @@ type Config struct {
 
 	Params    avalanche.Parameters
 	Consensus avalanche.Consensus
+
+	// MaxVerticesPerInterval is the maximum number of vertices that will be
+	// built every VertexInterval. Transactions that can't be issued because of
+	// this limit are held and batched into later vertices. If 0, the number of
+	// vertices built isn't limited.
+	MaxVerticesPerInterval int
+	VertexInterval         time.Duration
+
+	// MaxProcessingVertices is the maximum number of vertices that may be
+	// processing in consensus before this engine stops building new vertices.
+	// Transactions are held until enough of the frontier has been decided. If
+	// 0, the number of processing vertices isn't limited.
+	MaxProcessingVertices int
 }
@@ -10,7 +10,8 @@ import (
 )
 
 type metrics struct {
-	numVtxRequests, numPendingVts, numMissingTxs prometheus.Gauge
+	numVtxRequests, numPendingVts, numMissingTxs, maxProcessingVts, numDeferredTxs prometheus.Gauge
+	numDroppedTxs                                                                  prometheus.Counter
 }
 
 // Initialize implements the Engine interface
@@ -30,12 +31,31 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 		Name:      "missing_txs",
 		Help:      "Number of missing transactions",
 	})
+	m.maxProcessingVts = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "max_processing_vts",
+		Help:      "Maximum number of processing vertices before vertices stop being built, or 0 if unlimited",
+	})
+
+	m.numDeferredTxs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "deferred_txs",
+		Help:      "Number of transactions held back by the vertex limits",
+	})
+	m.numDroppedTxs = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dropped_deferred_txs",
+		Help:      "Number of transactions dropped because too many transactions were held back by the vertex limits",
+	})
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		registerer.Register(m.numVtxRequests),
 		registerer.Register(m.numPendingVts),
 		registerer.Register(m.numMissingTxs),
+		registerer.Register(m.maxProcessingVts),
+		registerer.Register(m.numDeferredTxs),
+		registerer.Register(m.numDroppedTxs),
 	)
 	return errs.Err
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/constants"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
@@ -28,6 +29,9 @@ const (
 	// TODO define this constant in one place rather than here and in snowman
 	// Max containers size in a MultiPut message
 	maxContainersLen = int(4 * network.DefaultMaxMessageSize / 5)
+
+	// Max number of transactions held back by the vertex limits
+	defaultMaxDeferredTxs = 1 << 12
 )
 
 // Transitive implements the Engine interface by attempting to fetch all
@@ -55,15 +59,38 @@ type Transitive struct {
 	// txBlocked tracks operations that are blocked on transactions
 	vtxBlocked, txBlocked events.Blocker
 
+	// limits the rate that vertices are built at
+	vtxGate *timer.Gate
+	clock   timer.Clock
+	// maximum number of processing vertices before no more vertices are built,
+	// or 0 if there's no maximum
+	maxProcessing int
+	// transactions that weren't put into a vertex because the rate limit or
+	// the processing limit was hit. They will be batched into the next vertex
+	// that is built. At most [maxDeferredTxs] transactions are held, and
+	// transactions are dropped once they are decided.
+	deferredTxs    []deferredTx
+	maxDeferredTxs int
+
 	errs wrappers.Errs
 }
 
+// deferredTx is a transaction held back by the vertex limits
+type deferredTx struct {
+	tx snowstorm.Tx
+	// true if the transaction was being forced to be issued
+	force bool
+}
+
 // Initialize implements the Engine interface
 func (t *Transitive) Initialize(config Config) error {
 	config.Ctx.Log.Info("Initializing consensus engine")
 
 	t.Params = config.Params
 	t.Consensus = config.Consensus
+	t.vtxGate = timer.NewGate(config.MaxVerticesPerInterval, config.VertexInterval)
+	t.maxProcessing = config.MaxProcessingVertices
+	t.maxDeferredTxs = defaultMaxDeferredTxs
 
 	factory := poll.NewEarlyTermNoTraversalFactory(int(config.Params.Alpha))
 	t.polls = poll.NewSet(factory,
@@ -75,6 +102,7 @@ func (t *Transitive) Initialize(config Config) error {
 	if err := t.metrics.Initialize(config.Params.Namespace, config.Params.Metrics); err != nil {
 		return err
 	}
+	t.maxProcessingVts.Set(float64(t.maxProcessing))
 
 	return t.Bootstrapper.Initialize(
 		config.Config,
@@ -103,6 +131,14 @@ func (t *Transitive) finishBootstrapping() error {
 
 // Gossip implements the Engine interface
 func (t *Transitive) Gossip() error {
+	// If consensus quiesced while transactions were held back by the vertex
+	// limits, this is the next chance to issue them
+	if len(t.deferredTxs) > 0 {
+		if err := t.batch(nil, false /*=force*/, false /*=empty*/); err != nil {
+			return err
+		}
+	}
+
 	edge := t.Manager.Edge()
 	if len(edge) == 0 {
 		t.Ctx.Log.Verbo("dropping gossip request as no vertices have been accepted")
@@ -501,44 +537,109 @@ func (t *Transitive) issue(vtx avalanche.Vertex) error {
 // Otherwise, some txs may not be put into vertices that are issued.
 // If [empty], will always result in a new poll.
 func (t *Transitive) batch(txs []snowstorm.Tx, force, empty bool) error {
-	batch := make([]snowstorm.Tx, 0, t.Params.BatchSize)
+	// Transactions may have been decided while they were deferred. Deferred
+	// transactions keep whether they were being forced.
+	pending := t.appendUndecided(nil, t.deferredTxs)
+	t.deferredTxs = nil
+	t.numDeferredTxs.Set(0)
+	for _, tx := range txs {
+		pending = append(pending, deferredTx{
+			tx:    tx,
+			force: force,
+		})
+	}
+
+	batch := make([]deferredTx, 0, t.Params.BatchSize)
 	issuedTxs := ids.Set{}
 	consumed := ids.Set{}
 	issued := false
 	orphans := t.Consensus.Orphans()
-	for _, tx := range txs {
+	for i, entry := range pending {
+		tx := entry.tx
 		inputs := tx.InputIDs()
 		overlaps := consumed.Overlaps(inputs) // See if this tx shares inputs with another one in this batch
-		if len(batch) >= t.Params.BatchSize || (force && overlaps) {
+		if len(batch) >= t.Params.BatchSize || (entry.force && overlaps) {
 			// The batch is big enough to issue, or we need to issue this batch
-			// because we're forcing each tx to be issued but adding [tx] to
-			// this batch would result in a vertex with conflicting txs.
+			// because we're forcing [tx] to be issued but adding [tx] to this
+			// batch would result in a vertex with conflicting txs.
+			if !t.canBuild() {
+				t.deferTxs(batch, pending[i:])
+				return t.repollIfEmpty(empty, issued)
+			}
 			if err := t.issueBatch(batch); err != nil {
 				return err
 			}
-			batch = make([]snowstorm.Tx, 0, t.Params.BatchSize)
+			batch = make([]deferredTx, 0, t.Params.BatchSize)
 			consumed.Clear()
 			issued = true
 			overlaps = false
 		}
 		if txID := tx.ID(); !overlaps && // should never allow conflicting txs in the same vertex
 			!issuedTxs.Contains(txID) && // shouldn't issue duplicated transactions to the same vertex
-			(force || t.Consensus.IsVirtuous(tx)) && // force allows for a conflict to be issued
+			(entry.force || t.Consensus.IsVirtuous(tx)) && // force allows for a conflict to be issued
 			(!t.Consensus.TxIssued(tx) || orphans.Contains(txID)) { // should only reissue orphaned txs
-			batch = append(batch, tx)
+			batch = append(batch, entry)
 			issuedTxs.Add(txID)
 			consumed.Union(inputs)
 		}
 	}
 
 	if len(batch) > 0 {
+		if !t.canBuild() {
+			t.deferTxs(batch, nil)
+			return t.repollIfEmpty(empty, issued)
+		}
 		return t.issueBatch(batch)
-	} else if empty && !issued {
+	}
+	return t.repollIfEmpty(empty, issued)
+}
+
+// canBuild returns true if a new vertex may be built now. The processing limit
+// is checked first so that a deferred vertex doesn't count against the rate
+// limit.
+func (t *Transitive) canBuild() bool {
+	if t.maxProcessing > 0 && t.Consensus.NumProcessing() >= t.maxProcessing {
+		return false
+	}
+	return t.vtxGate.Pass(t.clock.Time())
+}
+
+// repollIfEmpty issues a new poll if [empty] requires a poll and no vertex was
+// issued
+func (t *Transitive) repollIfEmpty(empty, issued bool) error {
+	if empty && !issued {
 		t.issueRepoll()
 	}
 	return nil
 }
 
+// deferTxs holds the transactions that couldn't be issued due to the vertex
+// limits so they can be batched into a later vertex. Decided transactions
+// aren't held, and once [maxDeferredTxs] transactions are held, the rest are
+// dropped.
+func (t *Transitive) deferTxs(batch, remaining []deferredTx) {
+	t.Ctx.Log.Verbo("deferring %d transactions due to the vertex limits", len(batch)+len(remaining))
+	t.deferredTxs = t.appendUndecided(t.deferredTxs, batch)
+	t.deferredTxs = t.appendUndecided(t.deferredTxs, remaining)
+	t.numDeferredTxs.Set(float64(len(t.deferredTxs)))
+}
+
+// appendUndecided appends the transactions in [txs] that haven't been decided
+// to [deferred], up to a total of [maxDeferredTxs] transactions
+func (t *Transitive) appendUndecided(deferred, txs []deferredTx) []deferredTx {
+	for i, tx := range txs {
+		if len(deferred) >= t.maxDeferredTxs {
+			t.Ctx.Log.Debug("dropping %d transactions because too many transactions are deferred", len(txs)-i)
+			t.numDroppedTxs.Add(float64(len(txs) - i))
+			break
+		}
+		if !tx.tx.Status().Decided() {
+			deferred = append(deferred, tx)
+		}
+	}
+	return deferred
+}
+
 // Issues a new poll for a preferred vertex in order to move consensus along
 func (t *Transitive) issueRepoll() {
 	preferredIDs := t.Consensus.Preferences().List()
@@ -577,8 +678,13 @@ func (t *Transitive) issueRepoll() {
 }
 
 // Puts a batch of transactions into a vertex and issues it into consensus.
-func (t *Transitive) issueBatch(txs []snowstorm.Tx) error {
-	t.Ctx.Log.Verbo("batching %d transactions into a new vertex", len(txs))
+func (t *Transitive) issueBatch(batch []deferredTx) error {
+	t.Ctx.Log.Verbo("batching %d transactions into a new vertex", len(batch))
+
+	txs := make([]snowstorm.Tx, len(batch))
+	for i, entry := range batch {
+		txs[i] = entry.tx
+	}
 
 	// Randomly select parents of this vertex from among the virtuous set
 	virtuousIDs := t.Consensus.Virtuous().List()
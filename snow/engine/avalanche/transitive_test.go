@@ -7,8 +7,10 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -3298,3 +3300,517 @@ func TestEngineDoubleChit(t *testing.T) {
 		t.Fatalf("Wrong tx status: %s ; expected: %s", status, choices.Accepted)
 	}
 }
+
+func TestEngineVertexRateLimit(t *testing.T) {
+	config := DefaultConfig()
+
+	config.Params.BatchSize = 2
+	config.MaxVerticesPerInterval = 1
+	config.VertexInterval = time.Minute
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	manager := &vertex.TestManager{T: t}
+	config.Manager = manager
+
+	manager.Default(true)
+
+	vm := &vertex.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	gTx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	txs := make([]snowstorm.Tx, 5)
+	for i := range txs {
+		tx := &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			DependenciesV: []snowstorm.Tx{gTx},
+		}
+		tx.InputIDsV.Add(ids.GenerateTestID())
+		txs[i] = tx
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVertexF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	built := [][]snowstorm.Tx(nil)
+	manager.BuildVertexF = func(_ ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error) {
+		built = append(built, txs)
+		return &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentsV: []avalanche.Vertex{gVtx},
+			HeightV:  1,
+			TxsV:     txs,
+			BytesV:   []byte{byte(len(built))},
+		}, nil
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	sender.CantPushQuery = false
+
+	// Only one vertex may be built in this interval
+	vm.PendingTxsF = func() []snowstorm.Tx { return txs[:3] }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 1 {
+		t.Fatalf("Expected 1 vertex to be built but %d were", len(built))
+	}
+	if len(built[0]) != 2 {
+		t.Fatalf("Expected the vertex to contain 2 txs but it contained %d", len(built[0]))
+	}
+
+	vm.PendingTxsF = func() []snowstorm.Tx { return txs[3:] }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 1 {
+		t.Fatalf("Vertex rate limit should have been enforced, but %d vertices were built", len(built))
+	}
+
+	// Once the interval has passed, the held txs should be batched together
+	// into a full vertex
+	te.clock.Set(now.Add(time.Minute))
+	vm.PendingTxsF = func() []snowstorm.Tx { return nil }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 2 {
+		t.Fatalf("Expected 2 vertices to be built but %d were", len(built))
+	}
+	if len(built[1]) != 2 {
+		t.Fatalf("Expected the held txs to be batched together, but the vertex contained %d txs", len(built[1]))
+	}
+	if !built[1][0].ID().Equals(txs[2].ID()) || !built[1][1].ID().Equals(txs[3].ID()) {
+		t.Fatalf("Held txs should be issued in the order they were received")
+	}
+}
+
+func TestEngineMaxProcessingVertices(t *testing.T) {
+	config := DefaultConfig()
+
+	config.Params.BatchSize = 2
+	config.MaxProcessingVertices = 1
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	manager := &vertex.TestManager{T: t}
+	config.Manager = manager
+
+	manager.Default(true)
+
+	vm := &vertex.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	gTx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	txs := make([]snowstorm.Tx, 4)
+	for i := range txs {
+		tx := &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			DependenciesV: []snowstorm.Tx{gTx},
+		}
+		tx.InputIDsV.Add(ids.GenerateTestID())
+		txs[i] = tx
+	}
+
+	built := []avalanche.Vertex(nil)
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVertexF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		for _, vtx := range built {
+			if id.Equals(vtx.ID()) {
+				return vtx, nil
+			}
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+	manager.BuildVertexF = func(_ ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error) {
+		vtx := &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentsV: []avalanche.Vertex{gVtx},
+			HeightV:  1,
+			TxsV:     txs,
+			BytesV:   []byte{byte(len(built))},
+		}
+		built = append(built, vtx)
+		return vtx, nil
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+
+	if max := testutil.ToFloat64(te.maxProcessingVts); max != 1 {
+		t.Fatalf("Expected the processing limit to be reported as 1 but was %f", max)
+	}
+
+	reqID := new(uint32)
+	sender.PushQueryF = func(_ ids.ShortSet, requestID uint32, _ ids.ID, _ []byte) {
+		*reqID = requestID
+	}
+
+	// Only one vertex may be processing at a time
+	vm.PendingTxsF = func() []snowstorm.Tx { return txs }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 1 {
+		t.Fatalf("Expected 1 vertex to be built but %d were", len(built))
+	}
+	if te.Consensus.NumProcessing() != 1 {
+		t.Fatalf("Expected 1 processing vertex but there were %d", te.Consensus.NumProcessing())
+	}
+
+	vm.PendingTxsF = func() []snowstorm.Tx { return nil }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 1 {
+		t.Fatalf("Processing limit should have been enforced, but %d vertices were built", len(built))
+	}
+
+	// Once the processing vertex is accepted, the held txs should be issued
+	votes := ids.Set{}
+	votes.Add(built[0].ID())
+	if err := te.Chits(vdr.ID(), *reqID, votes); err != nil {
+		t.Fatal(err)
+	}
+	if status := built[0].Status(); status != choices.Accepted {
+		t.Fatalf("Expected the vertex to be accepted but it was %s", status)
+	}
+	if len(built) != 2 {
+		t.Fatalf("Expected 2 vertices to be built but %d were", len(built))
+	}
+	builtTxs, err := built[1].Txs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(builtTxs) != 2 || !builtTxs[0].ID().Equals(txs[2].ID()) || !builtTxs[1].ID().Equals(txs[3].ID()) {
+		t.Fatalf("Expected the held txs to be issued once the frontier drained")
+	}
+}
+
+func TestEngineDeferredTxsBounded(t *testing.T) {
+	config := DefaultConfig()
+
+	config.Params.BatchSize = 1
+	config.MaxVerticesPerInterval = 1
+	config.VertexInterval = time.Minute
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	manager := &vertex.TestManager{T: t}
+	config.Manager = manager
+
+	manager.Default(true)
+
+	vm := &vertex.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	gTx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	txs := make([]*snowstorm.TestTx, 5)
+	pendingTxs := make([]snowstorm.Tx, len(txs))
+	for i := range txs {
+		tx := &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			DependenciesV: []snowstorm.Tx{gTx},
+		}
+		tx.InputIDsV.Add(ids.GenerateTestID())
+		txs[i] = tx
+		pendingTxs[i] = tx
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVertexF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	built := [][]snowstorm.Tx(nil)
+	manager.BuildVertexF = func(_ ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error) {
+		built = append(built, txs)
+		return &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentsV: []avalanche.Vertex{gVtx},
+			HeightV:  1,
+			TxsV:     txs,
+			BytesV:   []byte{byte(len(built))},
+		}, nil
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+	te.maxDeferredTxs = 2
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	sender.CantPushQuery = false
+
+	// Only the first tx is issued, and only two of the rest are held
+	vm.PendingTxsF = func() []snowstorm.Tx { return pendingTxs }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 1 {
+		t.Fatalf("Expected 1 vertex to be built but %d were", len(built))
+	}
+	if len(te.deferredTxs) != 2 {
+		t.Fatalf("Expected 2 txs to be held but %d were", len(te.deferredTxs))
+	}
+	if dropped := testutil.ToFloat64(te.numDroppedTxs); dropped != 2 {
+		t.Fatalf("Expected 2 txs to be dropped but %f were", dropped)
+	}
+
+	// A held tx that is decided in the meantime isn't issued
+	txs[1].StatusV = choices.Accepted
+
+	te.clock.Set(now.Add(time.Minute))
+	vm.PendingTxsF = func() []snowstorm.Tx { return nil }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 2 {
+		t.Fatalf("Expected 2 vertices to be built but %d were", len(built))
+	}
+	if len(built[1]) != 1 || !built[1][0].ID().Equals(txs[2].ID()) {
+		t.Fatalf("Expected only the undecided held tx to be issued")
+	}
+	if len(te.deferredTxs) != 0 {
+		t.Fatalf("Expected no txs to be held but %d were", len(te.deferredTxs))
+	}
+}
+
+func TestEngineDeferredTxKeepsForce(t *testing.T) {
+	config := DefaultConfig()
+
+	config.Params.BatchSize = 1
+	config.MaxVerticesPerInterval = 1
+	config.VertexInterval = time.Minute
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGetAcceptedFrontier = false
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	manager := &vertex.TestManager{T: t}
+	config.Manager = manager
+
+	manager.Default(true)
+
+	vm := &vertex.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+
+	gVtx := &avalanche.TestVertex{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	gTx := &snowstorm.TestTx{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	// Every tx conflicts with every other tx
+	conflictInput := ids.GenerateTestID()
+	txs := make([]*snowstorm.TestTx, 2)
+	for i := range txs {
+		tx := &snowstorm.TestTx{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			DependenciesV: []snowstorm.Tx{gTx},
+		}
+		tx.InputIDsV.Add(conflictInput)
+		txs[i] = tx
+	}
+
+	manager.EdgeF = func() []ids.ID { return []ids.ID{gVtx.ID()} }
+	manager.GetVertexF = func(id ids.ID) (avalanche.Vertex, error) {
+		if id.Equals(gVtx.ID()) {
+			return gVtx, nil
+		}
+		t.Fatalf("Unknown vertex")
+		panic("Should have errored")
+	}
+
+	built := [][]snowstorm.Tx(nil)
+	manager.BuildVertexF = func(_ ids.Set, txs []snowstorm.Tx) (avalanche.Vertex, error) {
+		built = append(built, txs)
+		return &avalanche.TestVertex{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentsV: []avalanche.Vertex{gVtx},
+			HeightV:  1,
+			TxsV:     txs,
+			BytesV:   []byte{byte(len(built))},
+		}, nil
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	sender.CantPushQuery = false
+
+	vm.PendingTxsF = func() []snowstorm.Tx { return []snowstorm.Tx{txs[0]} }
+	if err := te.Notify(common.PendingTxs); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 1 {
+		t.Fatalf("Expected 1 vertex to be built but %d were", len(built))
+	}
+	if te.Consensus.IsVirtuous(txs[1]) {
+		t.Fatalf("The second tx should conflict with the issued tx")
+	}
+
+	// The conflicting tx is forced, but the rate limit holds it back
+	if err := te.batch([]snowstorm.Tx{txs[1]}, true /*=force*/, false /*=empty*/); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 1 {
+		t.Fatalf("Vertex rate limit should have been enforced, but %d vertices were built", len(built))
+	}
+
+	// Once the interval has passed, the held tx is still forced
+	te.clock.Set(now.Add(time.Minute))
+	if err := te.batch(nil, false /*=force*/, false /*=empty*/); err != nil {
+		t.Fatal(err)
+	}
+	if len(built) != 2 {
+		t.Fatalf("Expected 2 vertices to be built but %d were", len(built))
+	}
+	if len(built[1]) != 1 || !built[1][0].ID().Equals(txs[1].ID()) {
+		t.Fatalf("Expected the forced tx to be issued")
+	}
+}
@@ -0,0 +1,110 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
+)
+
+// OverflowPolicy determines what happens when a block is accepted while a
+// subscriber's channel is full
+type OverflowPolicy int
+
+const (
+	// DropOldest removes the oldest unread block ID from the channel to make
+	// room for the newly accepted one
+	DropOldest OverflowPolicy = iota
+	// Block waits for the subscriber to read from the channel. Consensus on
+	// the chain is stalled until then, so the subscriber must keep reading
+	// until it unsubscribes.
+	Block
+)
+
+var errUnbufferedDropOldest = errors.New("dropping the oldest block ID requires a buffered channel")
+
+// subscriptionCounter ensures every subscription is registered with a unique
+// identifier
+var subscriptionCounter utils.Counter
+
+// acceptedSubscription forwards the IDs of accepted blocks to a channel
+type acceptedSubscription struct {
+	ch     chan ids.ID
+	policy OverflowPolicy
+
+	// closed when the subscription is cancelled, so that a blocked send can
+	// be abandoned
+	done chan struct{}
+	once sync.Once
+}
+
+// Accept implements the triggers.Acceptor interface
+func (s *acceptedSubscription) Accept(_, blkID ids.ID, _ []byte) error {
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+
+	switch s.policy {
+	case Block:
+		select {
+		case s.ch <- blkID:
+		case <-s.done:
+		}
+	default:
+		// [s.ch] is buffered, so dropping an ID makes room for [blkID]
+		for {
+			select {
+			case s.ch <- blkID:
+				return nil
+			default:
+			}
+			// The reader may have emptied the channel in the meantime, in
+			// which case nothing is dropped
+			select {
+			case <-s.ch:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+// SubscribeAccepted sends the ID of every block this engine accepts to [ch],
+// in the order the blocks were accepted. If [ch] is full, [policy] determines
+// whether the oldest unread ID is dropped or acceptance waits for [ch] to be
+// read from. DropOldest requires [ch] to be buffered. The returned function
+// cancels the subscription. It is safe to call at any time, more than once,
+// and doesn't close [ch].
+func (t *Transitive) SubscribeAccepted(ch chan ids.ID, policy OverflowPolicy) (func(), error) {
+	if policy == DropOldest && cap(ch) == 0 {
+		return nil, errUnbufferedDropOldest
+	}
+
+	sub := &acceptedSubscription{
+		ch:     ch,
+		policy: policy,
+		done:   make(chan struct{}),
+	}
+	chainID := t.Ctx.ChainID
+	identifier := fmt.Sprintf("snowman_accepted_%d", subscriptionCounter.Inc())
+	if err := t.Ctx.DecisionDispatcher.RegisterChain(chainID, identifier, sub); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		sub.once.Do(func() {
+			// Unblock any pending send before waiting on the dispatcher's lock
+			close(sub.done)
+			if err := t.Ctx.DecisionDispatcher.DeregisterChain(chainID, identifier); err != nil {
+				t.Ctx.Log.Warn("failed to unsubscribe from accepted blocks due to %s", err)
+			}
+		})
+	}, nil
+}
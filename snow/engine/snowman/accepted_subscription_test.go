@@ -0,0 +1,140 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+)
+
+// issueChain issues a chain of [length] blocks on top of genesis. Returns the
+// blocks and a function that votes for the last block, accepting all of them.
+func issueChain(t *testing.T, length int) (*Transitive, []*snowman.TestBlock, func()) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	blks := make([]*snowman.TestBlock, length)
+	parent := gBlk
+	for i := range blks {
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Processing,
+			},
+			ParentV: parent,
+			HeightV: uint64(i + 1),
+			BytesV:  []byte{byte(i + 1)},
+		}
+		parent = blks[i]
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		if blkID.Equals(gBlk.ID()) {
+			return gBlk, nil
+		}
+		for _, blk := range blks {
+			if blkID.Equals(blk.ID()) {
+				return blk, nil
+			}
+		}
+		t.Fatalf("Unknown block")
+		panic("Should have errored")
+	}
+
+	requestID := new(uint32)
+	sender.PushQueryF = func(_ ids.ShortSet, reqID uint32, _ ids.ID, _ []byte) { *requestID = reqID }
+	for _, blk := range blks {
+		if err := te.issue(blk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	votes := ids.Set{}
+	votes.Add(blks[length-1].ID())
+	lastRequestID := *requestID
+	return te, blks, func() {
+		if err := te.Chits(vdr.ID(), lastRequestID, votes); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSubscribeAccepted(t *testing.T) {
+	te, blks, accept := issueChain(t, 3)
+
+	all := make(chan ids.ID, len(blks))
+	if _, err := te.SubscribeAccepted(all, DropOldest); err != nil {
+		t.Fatal(err)
+	}
+	latest := make(chan ids.ID, 1)
+	if _, err := te.SubscribeAccepted(latest, DropOldest); err != nil {
+		t.Fatal(err)
+	}
+	cancelled := make(chan ids.ID, len(blks))
+	unsubscribe, err := te.SubscribeAccepted(cancelled, DropOldest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsubscribe()
+	unsubscribe()
+
+	accept()
+
+	for _, blk := range blks {
+		if status := blk.Status(); status != choices.Accepted {
+			t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Accepted)
+		}
+		if blkID := <-all; !blkID.Equals(blk.ID()) {
+			t.Fatalf("Expected %s to be accepted next, but got %s", blk.ID(), blkID)
+		}
+	}
+	if blkID := <-latest; !blkID.Equals(blks[len(blks)-1].ID()) {
+		t.Fatalf("Only the last accepted block should have been kept, but got %s", blkID)
+	}
+	if len(cancelled) != 0 {
+		t.Fatalf("Shouldn't have been notified after unsubscribing")
+	}
+}
+
+func TestSubscribeAcceptedBlock(t *testing.T) {
+	te, blks, accept := issueChain(t, 2)
+
+	ch := make(chan ids.ID)
+	unsubscribe, err := te.SubscribeAccepted(ch, Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		accept()
+		close(done)
+	}()
+
+	if blkID := <-ch; !blkID.Equals(blks[0].ID()) {
+		t.Fatalf("Expected %s to be accepted first, but got %s", blks[0].ID(), blkID)
+	}
+
+	// Acceptance is waiting for the second block to be read, which should be
+	// abandoned once the subscription is cancelled
+	unsubscribe()
+	<-done
+
+	if status := blks[1].Status(); status != choices.Accepted {
+		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Accepted)
+	}
+}
+
+func TestSubscribeAcceptedUnbufferedDropOldest(t *testing.T) {
+	_, _, _, _, te, _ := setup(t)
+
+	if _, err := te.SubscribeAccepted(make(chan ids.ID), DropOldest); err != errUnbufferedDropOldest {
+		t.Fatalf("Expected %s but got %v", errUnbufferedDropOldest, err)
+	}
+	if _, err := te.SubscribeAccepted(make(chan ids.ID), Block); err != nil {
+		t.Fatalf("Blocking subscriptions don't need a buffered channel but got %s", err)
+	}
+}
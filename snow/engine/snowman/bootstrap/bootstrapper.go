@@ -44,6 +44,10 @@ type Bootstrapper struct {
 
 	// true if all of the vertices in the original accepted frontier have been processed
 	processedStartingAcceptedFrontier bool
+
+	// Blocks that need to be fetched once there is room for more outstanding
+	// requests
+	needToFetch ids.Set
 }
 
 // Initialize this engine.
@@ -110,37 +114,39 @@ func (b *Bootstrapper) ForceAccepted(acceptedContainerIDs ids.Set) error {
 	}
 
 	b.processedStartingAcceptedFrontier = true
-	if numPending := b.OutstandingRequests.Len(); numPending == 0 {
-		return b.finish()
-	}
-	return nil
+	return b.checkFinish()
 }
 
-// Get block [blkID] and its ancestors from a validator
-func (b *Bootstrapper) fetch(blkID ids.ID) error {
-	// Make sure we haven't already requested this block
-	if b.OutstandingRequests.Contains(blkID) {
-		return nil
-	}
+// Get blocks [blkIDs] and their ancestors from validators. At most
+// common.MaxOutstandingRequests requests are outstanding at once, the rest are
+// sent as earlier requests are answered.
+func (b *Bootstrapper) fetch(blkIDs ...ids.ID) error {
+	b.needToFetch.Add(blkIDs...)
+	for b.needToFetch.Len() > 0 && b.OutstandingRequests.Len() < common.MaxOutstandingRequests {
+		blkID := b.needToFetch.CappedList(1)[0]
+		b.needToFetch.Remove(blkID)
+
+		// Make sure we haven't already requested this block
+		if b.OutstandingRequests.Contains(blkID) {
+			continue
+		}
 
-	// Make sure we don't already have this block
-	if _, err := b.VM.GetBlock(blkID); err == nil {
-		if numPending := b.OutstandingRequests.Len(); numPending == 0 && b.processedStartingAcceptedFrontier {
-			return b.finish()
+		// Make sure we don't already have this block
+		if _, err := b.VM.GetBlock(blkID); err == nil {
+			continue
 		}
-		return nil
-	}
 
-	validators, err := b.Validators.Sample(1) // validator to send request to
-	if err != nil {
-		return fmt.Errorf("Dropping request for %s as there are no validators", blkID)
-	}
-	validatorID := validators[0].ID()
-	b.RequestID++
+		validators, err := b.Validators.Sample(1) // validator to send request to
+		if err != nil {
+			return fmt.Errorf("Dropping request for %s as there are no validators", blkID)
+		}
+		validatorID := validators[0].ID()
+		b.RequestID++
 
-	b.OutstandingRequests.Add(validatorID, b.RequestID, blkID)
-	b.Sender.GetAncestors(validatorID, b.RequestID, blkID) // request block and ancestors
-	return nil
+		b.OutstandingRequests.Add(validatorID, b.RequestID, blkID)
+		b.Sender.GetAncestors(validatorID, b.RequestID, blkID) // request block and ancestors
+	}
+	return b.checkFinish()
 }
 
 // MultiPut handles the receipt of multiple containers. Should be received in response to a GetAncestors message to [vdr]
@@ -173,16 +179,35 @@ func (b *Bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, blks [][]byte
 		return b.fetch(wantedBlkID)
 	}
 
-	for _, blkBytes := range blks {
-		if _, err := b.VM.ParseBlock(blkBytes); err != nil { // persists the block
-			b.Ctx.Log.Debug("Failed to parse block: %s", err)
-			b.Ctx.Log.Verbo("block: %s", formatting.DumpBytes{Bytes: blkBytes})
+	// The blocks are expected to be ordered from the requested block back
+	// through its ancestors. Parse the oldest block first so that its missing
+	// ancestors are requested while the rest of this batch is processed,
+	// rather than after.
+	numBlks := len(blks)
+	if oldestBlk, err := b.parse(blks[numBlks-1]); err == nil && oldestBlk.Status() == choices.Processing {
+		if parent := oldestBlk.Parent(); parent.Status() == choices.Unknown {
+			if err := b.fetch(parent.ID()); err != nil {
+				return err
+			}
 		}
 	}
+	for i := 1; i < numBlks-1; i++ {
+		_, _ = b.parse(blks[i])
+	}
 
 	return b.process(wantedBlk)
 }
 
+// parse [blkBytes], which persists the block in the VM
+func (b *Bootstrapper) parse(blkBytes []byte) (snowman.Block, error) {
+	blk, err := b.VM.ParseBlock(blkBytes)
+	if err != nil {
+		b.Ctx.Log.Debug("Failed to parse block: %s", err)
+		b.Ctx.Log.Verbo("block: %s", formatting.DumpBytes{Bytes: blkBytes})
+	}
+	return blk, err
+}
+
 // GetAncestorsFailed is called when a GetAncestors message we sent fails
 func (b *Bootstrapper) GetAncestorsFailed(vdr ids.ShortID, requestID uint32) error {
 	blkID, ok := b.OutstandingRequests.Remove(vdr, requestID)
@@ -224,17 +249,21 @@ func (b *Bootstrapper) process(blk snowman.Block) error {
 
 	switch status := blk.Status(); status {
 	case choices.Unknown:
-		if err := b.fetch(blkID); err != nil {
-			return err
-		}
+		b.needToFetch.Add(blkID)
 	case choices.Rejected: // Should never happen
 		return fmt.Errorf("bootstrapping wants to accept %s, however it was previously rejected", blkID)
 	}
 
-	if numPending := b.OutstandingRequests.Len(); numPending == 0 && b.processedStartingAcceptedFrontier {
-		return b.finish()
+	// Send any requests that were waiting for an outstanding request to finish
+	return b.fetch()
+}
+
+// checkFinish finishes bootstrapping if every block has been fetched
+func (b *Bootstrapper) checkFinish() error {
+	if b.OutstandingRequests.Len() > 0 || b.needToFetch.Len() > 0 || !b.processedStartingAcceptedFrontier {
+		return nil
 	}
-	return nil
+	return b.finish()
 }
 
 func (b *Bootstrapper) finish() error {
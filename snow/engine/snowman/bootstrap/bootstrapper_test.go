@@ -808,3 +808,233 @@ func TestBootstrapperFinalized(t *testing.T) {
 		t.Fatalf("Block should be accepted")
 	}
 }
+
+// Fetching a long chain should overlap each request's round trip with the
+// processing of the previous batch
+func TestBootstrapperPipelinedFetch(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	const (
+		numBlks   = 21
+		batchSize = 4
+		latency   = 100 // simulated round trip time of a request
+		parseCost = 30  // simulated time to parse and persist a new block
+	)
+
+	blks := make([]*snowman.TestBlock, numBlks)
+	blks[0] = &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(0),
+			StatusV: choices.Accepted,
+		},
+		BytesV: []byte{0},
+	}
+	for i := 1; i < numBlks; i++ {
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i)),
+				StatusV: choices.Unknown,
+			},
+			ParentV: blks[i-1],
+			HeightV: uint64(i),
+			BytesV:  []byte{byte(i)},
+		}
+	}
+	tip := blks[numBlks-1]
+	tip.StatusV = choices.Processing
+
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("gecko_%s", config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := 0
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID().Equals(blkID) && blk.Status() != choices.Unknown {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		blk := blks[blkBytes[0]]
+		if blk.Status() == choices.Unknown {
+			// Only the first parse of a block persists it
+			now += parseCost
+			blk.StatusV = choices.Processing
+		}
+		return blk, nil
+	}
+
+	type response struct {
+		requestID uint32
+		blks      [][]byte
+		arrival   int
+	}
+	responses := []response(nil)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		height := 0
+		for i, blk := range blks {
+			if blk.ID().Equals(blkID) {
+				height = i
+			}
+		}
+		batch := [][]byte(nil)
+		for i := height; i > 0 && len(batch) < batchSize; i-- {
+			batch = append(batch, blks[i].Bytes())
+		}
+		responses = append(responses, response{
+			requestID: reqID,
+			blks:      batch,
+			arrival:   now + latency,
+		})
+	}
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(tip.ID())
+	if err := bs.ForceAccepted(acceptedIDs); err != nil {
+		t.Fatal(err)
+	}
+
+	numBatches := 0
+	for !*finished {
+		if len(responses) == 0 {
+			t.Fatalf("Bootstrapping stalled without any outstanding requests")
+		}
+		next := responses[0]
+		for _, response := range responses[1:] {
+			if response.arrival < next.arrival {
+				next = response
+			}
+		}
+		for i, response := range responses {
+			if response.requestID == next.requestID {
+				responses = append(responses[:i], responses[i+1:]...)
+				break
+			}
+		}
+
+		if next.arrival > now {
+			now = next.arrival
+		}
+		numBatches++
+		if err := bs.MultiPut(peerID, next.requestID, next.blks); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, blk := range blks {
+		if blk.Status() != choices.Accepted {
+			t.Fatalf("Block %d should be accepted", i)
+		}
+	}
+
+	// Without prefetching, each batch's request is only sent after the
+	// previous batch has been fully parsed
+	sequential := numBatches * (latency + batchSize*parseCost)
+	if now >= sequential {
+		t.Fatalf("Pipelined bootstrapping took %d but sequential bootstrapping takes %d", now, sequential)
+	}
+}
+
+// Responses to concurrent requests may arrive in any order
+func TestBootstrapperOutOfOrderMultiPut(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	blks := make([]*snowman.TestBlock, 5)
+	blks[0] = &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.Empty.Prefix(0),
+			StatusV: choices.Accepted,
+		},
+		BytesV: []byte{0},
+	}
+	for i := 1; i < len(blks); i++ {
+		blks[i] = &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.Empty.Prefix(uint64(i)),
+				StatusV: choices.Unknown,
+			},
+			ParentV: blks[i-1],
+			HeightV: uint64(i),
+			BytesV:  []byte{byte(i)},
+		}
+	}
+	blks[4].StatusV = choices.Processing
+
+	vm.CantBootstrapping = false
+	vm.CantBootstrapped = false
+
+	finished := new(bool)
+	bs := Bootstrapper{}
+	err := bs.Initialize(
+		config,
+		func() error { *finished = true; return nil },
+		fmt.Sprintf("gecko_%s", config.Ctx.ChainID),
+		prometheus.NewRegistry(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID().Equals(blkID) && blk.Status() != choices.Unknown {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		blk := blks[blkBytes[0]]
+		if blk.Status() == choices.Unknown {
+			blk.StatusV = choices.Processing
+		}
+		return blk, nil
+	}
+
+	requests := map[[32]byte]uint32{}
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, blkID ids.ID) {
+		requests[blkID.Key()] = reqID
+	}
+
+	// blk3 is missing below blk4, and blk2 is also in the accepted frontier,
+	// so both are requested at once
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blks[4].ID(), blks[2].ID())
+	if err := bs.ForceAccepted(acceptedIDs); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("Should have requested 2 blocks but requested %d", len(requests))
+	}
+
+	// The response to the later request arrives first
+	if err := bs.MultiPut(peerID, requests[blks[2].ID().Key()], [][]byte{{2}, {1}}); err != nil {
+		t.Fatal(err)
+	} else if *finished {
+		t.Fatalf("Bootstrapping shouldn't have finished while blk3 is missing")
+	}
+	if err := bs.MultiPut(peerID, requests[blks[3].ID().Key()], [][]byte{{3}}); err != nil {
+		t.Fatal(err)
+	} else if !*finished {
+		t.Fatalf("Bootstrapping should have finished")
+	}
+
+	for i, blk := range blks {
+		if blk.Status() != choices.Accepted {
+			t.Fatalf("Block %d should be accepted", i)
+		}
+	}
+}
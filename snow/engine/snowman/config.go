@@ -4,6 +4,9 @@
 package snowman
 
 import (
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/snowman/bootstrap"
@@ -15,4 +18,42 @@ type Config struct {
 
 	Params    snowball.Parameters
 	Consensus snowman.Consensus
+
+	// MaxAncestorsBytes is the maximum total size, in bytes, of the blocks
+	// sent in response to a GetAncestors request. If 0, defaults to
+	// maxContainersLen.
+	MaxAncestorsBytes int
+
+	// PreferenceLogSize is the number of preference changes retained for
+	// inspection. If 0, defaults to DefaultPreferenceLogSize.
+	PreferenceLogSize int
+
+	// MaxAcceptedPerInterval is the maximum number of blocks that will be
+	// accepted every AcceptInterval. Polls that finish once the limit has been
+	// reached are dropped and repolled, delaying acceptance until the next
+	// interval. A single poll may accept several blocks, so the limit can be
+	// overshot by the poll that reaches it. If 0, acceptance isn't limited.
+	MaxAcceptedPerInterval int
+	AcceptInterval         time.Duration
+
+	// Reputation is told about validators that send conflicting votes. If
+	// nil, misbehaving validators are only logged.
+	Reputation Reputation
+
+	// Blocks are only built when the VM reports pending transactions. If
+	// MaxIdleInterval is non-zero and no block has been built for that long,
+	// the VM is asked to build a block anyway so that the chain keeps making
+	// progress. The interval is checked whenever the engine gossips, so it is
+	// effectively rounded up to the gossip frequency.
+	MaxIdleInterval time.Duration
+
+	// UnsafeAllowForceAccept enables ForceAccept, which accepts blocks without
+	// consensus. This should only be set by recovery tooling.
+	UnsafeAllowForceAccept bool
+}
+
+// Reputation tracks how well behaved validators are
+type Reputation interface {
+	// Penalize [vdr] for misbehaving
+	Penalize(vdr ids.ShortID, reason string)
 }
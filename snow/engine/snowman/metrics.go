@@ -11,6 +11,7 @@ import (
 
 type metrics struct {
 	numRequests, numBlocked prometheus.Gauge
+	numConflictingVotes     prometheus.Counter
 }
 
 // Initialize the metrics
@@ -25,11 +26,17 @@ func (m *metrics) Initialize(namespace string, registerer prometheus.Registerer)
 		Name:      "blocked",
 		Help:      "Number of blocks that are pending issuance",
 	})
+	m.numConflictingVotes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "conflicting_votes",
+		Help:      "Number of votes dropped because the validator previously voted differently in the same poll",
+	})
 
 	errs := wrappers.Errs{}
 	errs.Add(
 		registerer.Register(m.numRequests),
 		registerer.Register(m.numBlocked),
+		registerer.Register(m.numConflictingVotes),
 	)
 	return errs.Err
 }
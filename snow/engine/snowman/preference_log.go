@@ -0,0 +1,102 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils"
+)
+
+const (
+	// DefaultPreferenceLogSize is the number of preference changes retained
+	// when the config doesn't specify otherwise
+	DefaultPreferenceLogSize = 128
+)
+
+// PreferenceChange records the engine switching its preferred block, along
+// with the poll that caused the switch.
+type PreferenceChange struct {
+	Time    time.Time
+	OldHead ids.ID
+	NewHead ids.ID
+	// Height of [NewHead], if its VM reports block heights
+	Height uint64
+	// Depth is the number of processing blocks from the last accepted block to
+	// [NewHead], inclusive
+	Depth int
+
+	// RequestID is the ID of the poll whose results changed the preference.
+	// Votes are the results of that poll, after bubbling.
+	RequestID uint32
+	Votes     ids.Bag
+}
+
+// heightReporter is implemented by blocks whose VM tracks block heights
+type heightReporter interface {
+	Height() uint64
+}
+
+// preferenceLog is a bounded log of the most recent preference changes. The
+// ring buffer has its own lock so that the log can be read without holding the
+// context lock.
+type preferenceLog struct {
+	changes *utils.RingBuffer
+}
+
+func (l *preferenceLog) initialize(size int) {
+	if size <= 0 {
+		size = DefaultPreferenceLogSize
+	}
+	l.changes = utils.NewRingBuffer(size)
+}
+
+func (l *preferenceLog) add(change PreferenceChange) { l.changes.Append(change) }
+
+// list returns the retained changes, oldest first
+func (l *preferenceLog) list() []PreferenceChange {
+	elements := l.changes.List()
+	changes := make([]PreferenceChange, len(elements))
+	for i, element := range elements {
+		changes[i] = element.(PreferenceChange)
+	}
+	return changes
+}
+
+// PreferenceChanges returns the most recent changes to this engine's preferred
+// block, oldest first. Safe to call without holding the context lock.
+func (t *Transitive) PreferenceChanges() []PreferenceChange { return t.prefLog.list() }
+
+// recordPreferenceChange logs the preference change, if any, caused by the
+// results of poll [requestID]
+func (t *Transitive) recordPreferenceChange(oldHead ids.ID, requestID uint32, votes ids.Bag) {
+	newHead := t.Consensus.Preference()
+	if newHead.Equals(oldHead) {
+		return
+	}
+
+	height, depth := uint64(0), 0
+	if blk, err := t.VM.GetBlock(newHead); err == nil {
+		if heightBlk, ok := blk.(heightReporter); ok {
+			height = heightBlk.Height()
+		}
+		for ; blk.Status() == choices.Processing; blk = blk.Parent() {
+			depth++
+		}
+	}
+
+	t.Ctx.Log.Debug("Preference changed from %s to %s, at height %d and depth %d, due to poll %d",
+		oldHead, newHead, height, depth, requestID)
+	t.prefLog.add(PreferenceChange{
+		Time:      time.Now(),
+		OldHead:   oldHead,
+		NewHead:   newHead,
+		Height:    height,
+		Depth:     depth,
+		RequestID: requestID,
+		Votes:     votes,
+	})
+}
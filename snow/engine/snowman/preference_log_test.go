@@ -0,0 +1,115 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package snowman
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+)
+
+func TestEngineRecordsPreferenceChange(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	blkA := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blkB := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{2},
+	}
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(gBlk.ID()):
+			return gBlk, nil
+		case blkID.Equals(blkA.ID()):
+			return blkA, nil
+		case blkID.Equals(blkB.ID()):
+			return blkB, nil
+		}
+		t.Fatalf("Unknown block")
+		panic("Should have errored")
+	}
+
+	queries := map[[32]byte]uint32{}
+	sender.PushQueryF = func(_ ids.ShortSet, requestID uint32, blkID ids.ID, _ []byte) {
+		queries[blkID.Key()] = requestID
+	}
+	sender.CantPullQuery = false
+
+	if err := te.issue(blkA); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.issue(blkB); err != nil {
+		t.Fatal(err)
+	}
+	if pref := te.Consensus.Preference(); !pref.Equals(blkA.ID()) {
+		t.Fatalf("Should initially prefer the first issued block")
+	}
+	if changes := te.PreferenceChanges(); len(changes) != 0 {
+		t.Fatalf("Issuing blocks shouldn't have been recorded as a poll changing the preference")
+	}
+
+	// Voting for the conflicting block should flip the preference
+	requestID := queries[blkA.ID().Key()]
+	votes := ids.Set{}
+	votes.Add(blkB.ID())
+	if err := te.Chits(vdr.ID(), requestID, votes); err != nil {
+		t.Fatal(err)
+	}
+	if pref := te.Consensus.Preference(); !pref.Equals(blkB.ID()) {
+		t.Fatalf("Preference should have changed to the voted for block")
+	}
+
+	changes := te.PreferenceChanges()
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 preference change to be recorded but got %d", len(changes))
+	}
+	change := changes[0]
+	switch {
+	case !change.OldHead.Equals(blkA.ID()):
+		t.Fatalf("Wrong old head recorded")
+	case !change.NewHead.Equals(blkB.ID()):
+		t.Fatalf("Wrong new head recorded")
+	case change.Height != 1:
+		t.Fatalf("Wrong height recorded: %d", change.Height)
+	case change.Depth != 1:
+		t.Fatalf("Wrong depth recorded: %d", change.Depth)
+	case change.RequestID != requestID:
+		t.Fatalf("Wrong poll recorded")
+	case change.Votes.Count(blkB.ID()) != 1:
+		t.Fatalf("Poll results weren't recorded")
+	}
+}
+
+func TestPreferenceLogBounded(t *testing.T) {
+	l := preferenceLog{}
+	l.initialize(2)
+
+	for i := uint32(0); i < 3; i++ {
+		l.add(PreferenceChange{RequestID: i})
+	}
+
+	changes := l.list()
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes to be retained but got %d", len(changes))
+	}
+	if changes[0].RequestID != 1 || changes[1].RequestID != 2 {
+		t.Fatalf("Oldest change should have been evicted, got %d and %d", changes[0].RequestID, changes[1].RequestID)
+	}
+}
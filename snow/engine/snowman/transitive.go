@@ -4,6 +4,7 @@
 package snowman
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/ava-labs/gecko/snow/events"
 	"github.com/ava-labs/gecko/utils/constants"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 )
 
@@ -25,8 +27,15 @@ const (
 	// TODO define this constant in one place rather than here and in snowman
 	// Max containers size in a MultiPut message
 	maxContainersLen = int(4 * network.DefaultMaxMessageSize / 5)
+
+	// Conflicting votes are logged at most [conflictLogBurst] times in a row,
+	// and then once every 1/[conflictLogRate] seconds
+	conflictLogRate  = 0.1
+	conflictLogBurst = 5
 )
 
+var errForceAcceptDisabled = errors.New("force accepting blocks is disabled")
+
 // Transitive implements the Engine interface by attempting to fetch all
 // transitive dependencies.
 type Transitive struct {
@@ -36,9 +45,25 @@ type Transitive struct {
 	Params    snowball.Parameters
 	Consensus snowman.Consensus
 
+	// maximum total size, in bytes, of the blocks sent in a MultiPut
+	maxAncestorsBytes int
+
 	// track outstanding preference requests
 	polls poll.Set
 
+	// responses received in each outstanding poll, used to detect validators
+	// that respond to the same poll more than once
+	responses map[uint32]map[[20]byte]ids.ID
+
+	// notified of validators that send conflicting votes, may be nil
+	reputation Reputation
+
+	// limits how often conflicting votes are logged
+	conflictLogs *timer.TokenBucket
+
+	// recent changes to the preferred block
+	prefLog preferenceLog
+
 	// blocks that have we have sent get requests for but haven't yet received
 	blkReqs common.Requests
 
@@ -49,6 +74,19 @@ type Transitive struct {
 	// issuing another block, responding to a query, or applying votes to consensus
 	blocked events.Blocker
 
+	// limits the rate at which blocks are accepted
+	limitAccepts bool
+	acceptGate   *timer.Gate
+	clock        timer.Clock
+
+	// if non-zero, the maximum time to go without asking the VM to build a
+	// block
+	maxIdleInterval time.Duration
+	lastBuildTime   time.Time
+
+	// true iff ForceAccept may be called
+	allowForceAccept bool
+
 	// errs tracks if an error has occurred in a callback
 	errs wrappers.Errs
 }
@@ -60,12 +98,25 @@ func (t *Transitive) Initialize(config Config) error {
 	t.Params = config.Params
 	t.Consensus = config.Consensus
 
+	t.maxAncestorsBytes = config.MaxAncestorsBytes
+	if t.maxAncestorsBytes <= 0 {
+		t.maxAncestorsBytes = maxContainersLen
+	}
+	t.prefLog.initialize(config.PreferenceLogSize)
+	t.limitAccepts = config.MaxAcceptedPerInterval > 0
+	t.acceptGate = timer.NewGate(config.MaxAcceptedPerInterval, config.AcceptInterval)
+
 	factory := poll.NewEarlyTermNoTraversalFactory(int(config.Params.Alpha))
 	t.polls = poll.NewSet(factory,
 		config.Ctx.Log,
 		config.Params.Namespace,
 		config.Params.Metrics,
 	)
+	t.responses = make(map[uint32]map[[20]byte]ids.ID)
+	t.reputation = config.Reputation
+	t.conflictLogs = timer.NewTokenBucket(&t.clock, conflictLogRate, conflictLogBurst)
+	t.maxIdleInterval = config.MaxIdleInterval
+	t.allowForceAccept = config.UnsafeAllowForceAccept
 
 	if err := t.metrics.Initialize(config.Params.Namespace, config.Params.Metrics); err != nil {
 		return err
@@ -111,12 +162,23 @@ func (t *Transitive) finishBootstrapping() error {
 		t.VM.SetPreference(lastAcceptedID)
 	}
 
+	t.lastBuildTime = t.clock.Time()
 	t.Ctx.Log.Info("bootstrapping finished with %s as the last accepted block", lastAcceptedID)
 	return nil
 }
 
 // Gossip implements the Engine interface
 func (t *Transitive) Gossip() error {
+	// make sure the chain doesn't stall if the VM stops reporting pending
+	// transactions
+	if t.maxIdleInterval > 0 && t.Ctx.IsBootstrapped() &&
+		t.clock.Time().Sub(t.lastBuildTime) >= t.maxIdleInterval {
+		t.Ctx.Log.Debug("no block has been built for %s, building one", t.maxIdleInterval)
+		if err := t.buildBlock(); err != nil {
+			return err
+		}
+	}
+
 	blkID := t.VM.LastAccepted()
 	blk, err := t.VM.GetBlock(blkID)
 	if err != nil {
@@ -162,6 +224,11 @@ func (t *Transitive) GetAncestors(vdr ids.ShortID, requestID uint32, blkID ids.I
 	ancestorsBytes := make([][]byte, 1, common.MaxContainersPerMultiPut) // First elt is byte repr. of blk, then its parents, then grandparent, etc.
 	ancestorsBytes[0] = blk.Bytes()
 	ancestorsBytesLen := len(blk.Bytes()) + wrappers.IntLen // length, in bytes, of all elements of ancestors
+	// Never send part of a block. If the requested block alone is over the limit, drop this request.
+	if ancestorsBytesLen > t.maxAncestorsBytes {
+		t.Ctx.Log.Debug("block %s is too large to send. dropping GetAncestors(%s, %d, %s)", blkID, vdr, requestID, blkID)
+		return nil
+	}
 
 	for numFetched := 1; numFetched < common.MaxContainersPerMultiPut && time.Since(startTime) < common.MaxTimeFetchingAncestors; numFetched++ {
 		blk = blk.Parent()
@@ -171,7 +238,7 @@ func (t *Transitive) GetAncestors(vdr ids.ShortID, requestID uint32, blkID ids.I
 		blkBytes := blk.Bytes()
 		// Ensure response size isn't too large. Include wrappers.IntLen because the size of the message
 		// is included with each container, and the size is repr. by an int.
-		if newLen := wrappers.IntLen + ancestorsBytesLen + len(blkBytes); newLen < maxContainersLen {
+		if newLen := wrappers.IntLen + ancestorsBytesLen + len(blkBytes); newLen <= t.maxAncestorsBytes {
 			ancestorsBytes = append(ancestorsBytes, blkBytes)
 			ancestorsBytesLen = newLen
 		} else { // reached maximum response size
@@ -318,6 +385,10 @@ func (t *Transitive) Chits(vdr ids.ShortID, requestID uint32, votes ids.Set) err
 
 	t.Ctx.Log.Verbo("Chits(%s, %d) contains vote for %s", vdr, requestID, blkID)
 
+	if !t.recordResponse(vdr, requestID, blkID) {
+		return nil
+	}
+
 	// Will record chits once [blkID] has been issued into consensus
 	v := &voter{
 		t:         t,
@@ -348,6 +419,10 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 		return nil
 	}
 
+	if !t.recordResponse(vdr, requestID, ids.Empty) {
+		return nil
+	}
+
 	t.blocked.Register(&voter{
 		t:         t,
 		vdr:       vdr,
@@ -356,6 +431,44 @@ func (t *Transitive) QueryFailed(vdr ids.ShortID, requestID uint32) error {
 	return t.errs.Err
 }
 
+// recordResponse notes that [vdr] responded to the poll [requestID] with a
+// vote for [blkID], or that its query failed if [blkID] is empty. Returns
+// false if [vdr] had already responded to the poll, in which case the response
+// should be ignored. If the responses conflict, [vdr] is penalized.
+func (t *Transitive) recordResponse(vdr ids.ShortID, requestID uint32, blkID ids.ID) bool {
+	responses, exists := t.responses[requestID]
+	if !exists {
+		// Responses to unknown polls are dropped by the poll set
+		return true
+	}
+
+	key := vdr.Key()
+	prevID, responded := responses[key]
+	if !responded {
+		responses[key] = blkID
+		return true
+	}
+
+	// A failed query followed by a late response isn't misbehavior
+	if prevID.IsZero() || blkID.IsZero() || prevID.Equals(blkID) {
+		t.Ctx.Log.Verbo("dropping duplicated response from %s to poll %d", vdr, requestID)
+		return false
+	}
+
+	t.numConflictingVotes.Inc()
+	if t.conflictLogs.Take() {
+		t.Ctx.Log.Warn("dropping vote for %s from %s in poll %d, as it previously voted for %s",
+			blkID,
+			vdr,
+			requestID,
+			prevID)
+	}
+	if t.reputation != nil {
+		t.reputation.Penalize(vdr, "conflicting votes")
+	}
+	return false
+}
+
 // Notify implements the Engine interface
 func (t *Transitive) Notify(msg common.Message) error {
 	// if the engine hasn't been bootstrapped, we shouldn't build/issue blocks from the VM
@@ -368,38 +481,46 @@ func (t *Transitive) Notify(msg common.Message) error {
 	switch msg {
 	case common.PendingTxs:
 		// the pending txs message means we should attempt to build a block.
-		blk, err := t.VM.BuildBlock()
-		if err != nil {
-			t.Ctx.Log.Debug("VM.BuildBlock errored with: %s", err)
-			return nil
-		}
+		return t.buildBlock()
+	default:
+		t.Ctx.Log.Warn("unexpected message from the VM: %s", msg)
+	}
+	return nil
+}
 
-		// a newly created block is expected to be processing. If this check
-		// fails, there is potentially an error in the VM this engine is running
-		if status := blk.Status(); status != choices.Processing {
-			t.Ctx.Log.Warn("attempting to issue a block with status: %s, expected Processing", status)
-		}
+// buildBlock asks the VM for a new block and issues it to consensus
+func (t *Transitive) buildBlock() error {
+	t.lastBuildTime = t.clock.Time()
 
-		// The newly created block should be built on top of the preferred block.
-		// Otherwise, the new block doesn't have the best chance of being confirmed.
-		parentID := blk.Parent().ID()
-		if pref := t.Consensus.Preference(); !parentID.Equals(pref) {
-			t.Ctx.Log.Warn("built block with parent: %s, expected %s", parentID, pref)
-		}
+	blk, err := t.VM.BuildBlock()
+	if err != nil {
+		t.Ctx.Log.Debug("VM.BuildBlock errored with: %s", err)
+		return nil
+	}
 
-		added, err := t.issueWithAncestors(blk)
-		if err != nil {
-			return err
-		}
+	// a newly created block is expected to be processing. If this check
+	// fails, there is potentially an error in the VM this engine is running
+	if status := blk.Status(); status != choices.Processing {
+		t.Ctx.Log.Warn("attempting to issue a block with status: %s, expected Processing", status)
+	}
 
-		// issuing the block shouldn't have any missing dependencies
-		if added {
-			t.Ctx.Log.Verbo("successfully issued new block from the VM")
-		} else {
-			t.Ctx.Log.Warn("VM.BuildBlock returned a block with unissued ancestors")
-		}
-	default:
-		t.Ctx.Log.Warn("unexpected message from the VM: %s", msg)
+	// The newly created block should be built on top of the preferred block.
+	// Otherwise, the new block doesn't have the best chance of being confirmed.
+	parentID := blk.Parent().ID()
+	if pref := t.Consensus.Preference(); !parentID.Equals(pref) {
+		t.Ctx.Log.Warn("built block with parent: %s, expected %s", parentID, pref)
+	}
+
+	added, err := t.issueWithAncestors(blk)
+	if err != nil {
+		return err
+	}
+
+	// issuing the block shouldn't have any missing dependencies
+	if added {
+		t.Ctx.Log.Verbo("successfully issued new block from the VM")
+	} else {
+		t.Ctx.Log.Warn("VM.BuildBlock returned a block with unissued ancestors")
 	}
 	return nil
 }
@@ -416,6 +537,41 @@ func (t *Transitive) repoll() {
 	}
 }
 
+// acceptLimited returns true if the accept rate limit has been reached, in
+// which case polls shouldn't be recorded until the current interval ends
+func (t *Transitive) acceptLimited() bool {
+	return !t.Consensus.Finalized() && !t.acceptGate.Open(t.clock.Time())
+}
+
+// processingPreferred returns the processing blocks on the preferred branch,
+// which are the only blocks the next poll can accept. Returns nil if
+// acceptance isn't limited.
+func (t *Transitive) processingPreferred() []snowman.Block {
+	if !t.limitAccepts {
+		return nil
+	}
+	var blks []snowman.Block
+	blk, err := t.VM.GetBlock(t.Consensus.Preference())
+	if err != nil {
+		return nil
+	}
+	for ; blk.Status() == choices.Processing; blk = blk.Parent() {
+		blks = append(blks, blk)
+	}
+	return blks
+}
+
+// countAccepted counts the blocks in [blks] that have been accepted against the
+// accept rate limit
+func (t *Transitive) countAccepted(blks []snowman.Block) {
+	now := t.clock.Time()
+	for _, blk := range blks {
+		if blk.Status() == choices.Accepted {
+			t.acceptGate.Pass(now)
+		}
+	}
+}
+
 // issueFromByID attempts to issue the branch ending with a block [blkID] into consensus.
 // If we do not have [blkID], request it.
 // Returns true if the block was issued, now or previously, to consensus.
@@ -543,6 +699,8 @@ func (t *Transitive) pullSample(blkID ids.ID) {
 
 	t.RequestID++
 	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+		t.responses[t.RequestID] = make(map[[20]byte]ids.ID)
+
 		vdrSet := ids.ShortSet{}
 		vdrSet.Add(vdrBag.List()...)
 
@@ -563,6 +721,8 @@ func (t *Transitive) pushSample(blk snowman.Block) {
 
 	t.RequestID++
 	if err == nil && t.polls.Add(t.RequestID, vdrBag) {
+		t.responses[t.RequestID] = make(map[[20]byte]ids.ID)
+
 		vdrSet := ids.ShortSet{}
 		vdrSet.Add(vdrBag.List()...)
 
@@ -649,6 +809,39 @@ func (t *Transitive) deliver(blk snowman.Block) error {
 	return t.errs.Err
 }
 
+// ForceAccept accepts the block [blkID], and all of its processing ancestors,
+// without waiting for consensus to finalize them. Conflicting blocks are
+// rejected. The block must have been issued into consensus and must descend
+// from the last accepted block. This is unsafe and is only allowed if the
+// engine was configured with UnsafeAllowForceAccept.
+func (t *Transitive) ForceAccept(blkID ids.ID) error {
+	if !t.allowForceAccept {
+		return errForceAcceptDisabled
+	}
+	if !t.Ctx.IsBootstrapped() {
+		return fmt.Errorf("can't force accept %s while bootstrapping", blkID)
+	}
+
+	blk, err := t.VM.GetBlock(blkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+	if status := blk.Status(); status != choices.Processing || !t.Consensus.Issued(blk) {
+		return fmt.Errorf("can't force accept block %s with status %s", blkID, status)
+	}
+
+	t.Ctx.Log.Warn("forcing acceptance of block %s", blkID)
+
+	processing := t.processingPreferred()
+	if err := t.Consensus.ForceAccept(blkID); err != nil {
+		return err
+	}
+	t.countAccepted(processing)
+
+	t.VM.SetPreference(t.Consensus.Preference())
+	return nil
+}
+
 // IsBootstrapped returns true iff this chain is done bootstrapping
 func (t *Transitive) IsBootstrapped() bool {
 	return t.Ctx.IsBootstrapped()
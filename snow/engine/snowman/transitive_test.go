@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -870,6 +871,133 @@ func TestVoteCanceling(t *testing.T) {
 	}
 }
 
+type testReputation struct {
+	penalized []ids.ShortID
+}
+
+func (r *testReputation) Penalize(vdr ids.ShortID, _ string) {
+	r.penalized = append(r.penalized, vdr)
+}
+
+func TestEngineConflictingVotes(t *testing.T) {
+	config := DefaultConfig()
+
+	config.Params = snowball.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 3,
+		Alpha:             2,
+		BetaVirtuous:      1,
+		BetaRogue:         2,
+		ConcurrentRepolls: 1,
+	}
+
+	reputation := &testReputation{}
+	config.Reputation = reputation
+
+	vdr0 := validators.GenerateRandomValidator(1)
+	vdr1 := validators.GenerateRandomValidator(1)
+	vdr2 := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr0)
+	vals.Add(vdr1)
+	vals.Add(vdr2)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+
+	vm := &block.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+	vm.CantSetPreference = false
+
+	gBlk := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.LastAcceptedF = func() ids.ID { return gBlk.ID() }
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		switch {
+		case id.Equals(gBlk.ID()):
+			return gBlk, nil
+		case id.Equals(blk.ID()):
+			return blk, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	sender.CantGetAcceptedFrontier = false
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+
+	vm.LastAcceptedF = nil
+	sender.CantGetAcceptedFrontier = true
+
+	queryRequestID := new(uint32)
+	sender.PushQueryF = func(inVdrs ids.ShortSet, requestID uint32, blkID ids.ID, blkBytes []byte) {
+		*queryRequestID = requestID
+	}
+
+	te.issue(blk)
+
+	// vdr0 first votes for a block that must be fetched before its vote can be
+	// applied
+	unknownBlkID := ids.GenerateTestID()
+	fetched := new(bool)
+	sender.GetF = func(_ ids.ShortID, _ uint32, blkID ids.ID) {
+		if !blkID.Equals(unknownBlkID) {
+			t.Fatalf("Fetched the wrong block")
+		}
+		*fetched = true
+	}
+	if err := te.Chits(vdr0.ID(), *queryRequestID, ids.Set{unknownBlkID.Key(): true}); err != nil {
+		t.Fatal(err)
+	}
+	if !*fetched {
+		t.Fatalf("Should have fetched the voted for block")
+	}
+
+	// The conflicting vote can be applied immediately, but must be ignored
+	if err := te.Chits(vdr0.ID(), *queryRequestID, ids.Set{blk.ID().Key(): true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(reputation.penalized) != 1 || !reputation.penalized[0].Equals(vdr0.ID()) {
+		t.Fatalf("Should have penalized %s for voting twice but penalized %v", vdr0.ID(), reputation.penalized)
+	}
+
+	// If vdr0's second vote had been counted, this would finish the poll
+	if err := te.Chits(vdr1.ID(), *queryRequestID, ids.Set{blk.ID().Key(): true}); err != nil {
+		t.Fatal(err)
+	}
+	if te.polls.Len() != 1 {
+		t.Fatalf("The conflicting vote shouldn't have been applied")
+	}
+	if len(reputation.penalized) != 1 {
+		t.Fatalf("Shouldn't have penalized a validator that only voted once")
+	}
+}
+
 func TestEngineNoQuery(t *testing.T) {
 	config := DefaultConfig()
 
@@ -1725,3 +1853,426 @@ func TestEngineDoubleChit(t *testing.T) {
 		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Accepted)
 	}
 }
+
+func TestEngineAcceptRateLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxAcceptedPerInterval = 1
+	config.AcceptInterval = time.Minute
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+
+	vm := &block.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+	vm.CantSetPreference = false
+
+	gBlk := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk0,
+		HeightV: 2,
+		BytesV:  []byte{2},
+	}
+
+	vm.LastAcceptedF = func() ids.ID { return gBlk.ID() }
+	sender.CantGetAcceptedFrontier = false
+
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		switch {
+		case id.Equals(gBlk.ID()):
+			return gBlk, nil
+		case id.Equals(blk0.ID()):
+			return blk0, nil
+		case id.Equals(blk1.ID()):
+			return blk1, nil
+		}
+		t.Fatalf("Unknown block")
+		panic("Should have errored")
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+
+	vm.LastAcceptedF = nil
+	sender.CantGetAcceptedFrontier = true
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	queryRequestIDs := map[[32]byte]uint32{}
+	sender.PushQueryF = func(_ ids.ShortSet, requestID uint32, blkID ids.ID, _ []byte) {
+		queryRequestIDs[blkID.Key()] = requestID
+	}
+	pullRequestID := new(uint32)
+	sender.PullQueryF = func(_ ids.ShortSet, requestID uint32, _ ids.ID) {
+		*pullRequestID = requestID
+	}
+
+	if err := te.issue(blk0); err != nil {
+		t.Fatal(err)
+	}
+	if err := te.issue(blk1); err != nil {
+		t.Fatal(err)
+	}
+
+	blk0Votes := ids.Set{}
+	blk0Votes.Add(blk0.ID())
+	if err := te.Chits(vdr.ID(), queryRequestIDs[blk0.ID().Key()], blk0Votes); err != nil {
+		t.Fatal(err)
+	}
+	if status := blk0.Status(); status != choices.Accepted {
+		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Accepted)
+	}
+
+	// The limit has been reached, so this poll should be dropped
+	blk1Votes := ids.Set{}
+	blk1Votes.Add(blk1.ID())
+	if err := te.Chits(vdr.ID(), queryRequestIDs[blk1.ID().Key()], blk1Votes); err != nil {
+		t.Fatal(err)
+	}
+	if status := blk1.Status(); status != choices.Processing {
+		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Processing)
+	}
+
+	// Once the interval has passed, the repoll should be able to accept blk1
+	te.clock.Set(now.Add(time.Minute))
+	if err := te.Chits(vdr.ID(), *pullRequestID, blk1Votes); err != nil {
+		t.Fatal(err)
+	}
+	if status := blk1.Status(); status != choices.Accepted {
+		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Accepted)
+	}
+}
+
+func TestEngineMaxIdleInterval(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxIdleInterval = time.Minute
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantGossip = false
+
+	vm := &block.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+	vm.CantSetPreference = false
+
+	gBlk := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	blk := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+
+	vm.LastAcceptedF = func() ids.ID { return gBlk.ID() }
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		if !id.Equals(gBlk.ID()) {
+			t.Fatalf("Unknown block")
+		}
+		return gBlk, nil
+	}
+	sender.CantGetAcceptedFrontier = false
+
+	te := &Transitive{}
+	te.Initialize(config)
+
+	now := time.Now()
+	te.clock.Set(now)
+
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+
+	sender.CantGetAcceptedFrontier = true
+
+	built := 0
+	vm.BuildBlockF = func() (snowman.Block, error) {
+		built++
+		return blk, nil
+	}
+	queried := false
+	sender.PushQueryF = func(_ ids.ShortSet, _ uint32, blkID ids.ID, _ []byte) {
+		if !blkID.Equals(blk.ID()) {
+			t.Fatalf("Queried for the wrong block")
+		}
+		queried = true
+	}
+
+	// The VM hasn't reported any pending transactions, so no block should be
+	// built before the idle interval has passed
+	te.clock.Set(now.Add(time.Minute / 2))
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if built != 0 {
+		t.Fatalf("Shouldn't have built a block before the idle interval")
+	}
+
+	te.clock.Set(now.Add(time.Minute))
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if built != 1 {
+		t.Fatalf("Should have built a block after the idle interval")
+	}
+	if !queried {
+		t.Fatalf("Should have queried for the built block")
+	}
+
+	// Building the block resets the idle interval
+	te.clock.Set(now.Add(3 * time.Minute / 2))
+	if err := te.Gossip(); err != nil {
+		t.Fatal(err)
+	}
+	if built != 1 {
+		t.Fatalf("Shouldn't have built another block before the idle interval")
+	}
+}
+
+func TestEngineForceAccept(t *testing.T) {
+	config := DefaultConfig()
+
+	vdr := validators.GenerateRandomValidator(1)
+
+	vals := validators.NewSet()
+	config.Validators = vals
+
+	vals.Add(vdr)
+
+	sender := &common.SenderTest{}
+	sender.T = t
+	config.Sender = sender
+
+	sender.Default(true)
+	sender.CantPushQuery = false
+
+	vm := &block.TestVM{}
+	vm.T = t
+	config.VM = vm
+
+	vm.Default(true)
+	vm.CantSetPreference = false
+
+	gBlk := &snowman.TestBlock{TestDecidable: choices.TestDecidable{
+		IDV:     ids.GenerateTestID(),
+		StatusV: choices.Accepted,
+	}}
+	blk0 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{1},
+	}
+	blk1 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: gBlk,
+		HeightV: 1,
+		BytesV:  []byte{2},
+	}
+	blk2 := &snowman.TestBlock{
+		TestDecidable: choices.TestDecidable{
+			IDV:     ids.GenerateTestID(),
+			StatusV: choices.Processing,
+		},
+		ParentV: blk1,
+		HeightV: 2,
+		BytesV:  []byte{3},
+	}
+
+	vm.LastAcceptedF = func() ids.ID { return gBlk.ID() }
+	sender.CantGetAcceptedFrontier = false
+
+	vm.GetBlockF = func(id ids.ID) (snowman.Block, error) {
+		switch {
+		case id.Equals(gBlk.ID()):
+			return gBlk, nil
+		case id.Equals(blk0.ID()):
+			return blk0, nil
+		case id.Equals(blk1.ID()):
+			return blk1, nil
+		case id.Equals(blk2.ID()):
+			return blk2, nil
+		}
+		return nil, errUnknownBlock
+	}
+
+	te := &Transitive{}
+	te.Initialize(config)
+	te.finishBootstrapping()
+	te.Ctx.Bootstrapped()
+
+	vm.LastAcceptedF = nil
+	sender.CantGetAcceptedFrontier = true
+
+	for _, blk := range []snowman.Block{blk0, blk1, blk2} {
+		if err := te.issue(blk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := te.ForceAccept(blk1.ID()); err != errForceAcceptDisabled {
+		t.Fatalf("Should have refused to force accept without the unsafe flag")
+	} else if status := blk1.Status(); status != choices.Processing {
+		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Processing)
+	}
+
+	te.allowForceAccept = true
+
+	if err := te.ForceAccept(ids.GenerateTestID()); err == nil {
+		t.Fatalf("Should have errored when forcing an unknown block")
+	} else if err := te.ForceAccept(gBlk.ID()); err == nil {
+		t.Fatalf("Should have errored when forcing the last accepted block")
+	}
+
+	if err := te.ForceAccept(blk1.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if status := blk0.Status(); status != choices.Rejected {
+		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Rejected)
+	} else if status := blk1.Status(); status != choices.Accepted {
+		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Accepted)
+	} else if status := blk2.Status(); status != choices.Processing {
+		t.Fatalf("Wrong status: %s ; expected: %s", status, choices.Processing)
+	} else if pref := te.Consensus.Preference(); !pref.Equals(blk2.ID()) {
+		t.Fatalf("Wrong preference: %s ; expected: %s", pref, blk2.ID())
+	}
+
+	if err := te.ForceAccept(blk0.ID()); err == nil {
+		t.Fatalf("Should have errored when forcing a rejected block")
+	}
+}
+
+func TestEngineGetAncestorsByteCap(t *testing.T) {
+	vdr, _, sender, vm, te, gBlk := setup(t)
+
+	const (
+		blkSize  = 1024
+		numBlks  = 10
+		maxBytes = 4*(blkSize+4) + blkSize/2
+	)
+	te.maxAncestorsBytes = maxBytes
+
+	blks := []snowman.Block{}
+	parent := gBlk
+	for i := 0; i < numBlks; i++ {
+		blk := &snowman.TestBlock{
+			TestDecidable: choices.TestDecidable{
+				IDV:     ids.GenerateTestID(),
+				StatusV: choices.Accepted,
+			},
+			ParentV: parent,
+			HeightV: uint64(i + 1),
+			BytesV:  bytes.Repeat([]byte{byte(i)}, blkSize),
+		}
+		blks = append(blks, blk)
+		parent = blk
+	}
+	tip := blks[len(blks)-1]
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		for _, blk := range blks {
+			if blk.ID().Equals(blkID) {
+				return blk, nil
+			}
+		}
+		return nil, errUnknownBlock
+	}
+
+	multiPut := new(bool)
+	sender.MultiPutF = func(inVdr ids.ShortID, requestID uint32, containers [][]byte) {
+		*multiPut = true
+		if !inVdr.Equals(vdr.ID()) {
+			t.Fatalf("Sent to the wrong validator")
+		}
+		if requestID != 1 {
+			t.Fatalf("Wrong requestID")
+		}
+		if len(containers) != 4 {
+			t.Fatalf("Should have sent 4 blocks but sent %d", len(containers))
+		}
+		size := 0
+		for i, container := range containers {
+			if !bytes.Equal(container, blks[numBlks-1-i].Bytes()) {
+				t.Fatalf("Sent the wrong block at index %d", i)
+			}
+			size += len(container) + 4
+		}
+		if size > maxBytes {
+			t.Fatalf("Sent %d bytes but the cap is %d", size, maxBytes)
+		}
+	}
+
+	if err := te.GetAncestors(vdr.ID(), 1, tip.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if !*multiPut {
+		t.Fatalf("Should have sent a MultiPut")
+	}
+
+	// A block larger than the cap must never be sent
+	te.maxAncestorsBytes = blkSize / 2
+	*multiPut = false
+	sender.MultiPutF = func(ids.ShortID, uint32, [][]byte) {
+		*multiPut = true
+	}
+	if err := te.GetAncestors(vdr.ID(), 2, tip.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if *multiPut {
+		t.Fatalf("Shouldn't have sent a block larger than the cap")
+	}
+}
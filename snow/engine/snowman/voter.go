@@ -43,16 +43,27 @@ func (v *voter) Update() {
 	if !finished {
 		return
 	}
+	delete(v.t.responses, v.requestID)
 
 	// To prevent any potential deadlocks with un-disclosed dependencies, votes
 	// must be bubbled to the nearest valid block
 	results = v.bubbleVotes(results)
 
+	if v.t.acceptLimited() {
+		v.t.Ctx.Log.Debug("Dropping poll [%d] due to the accept rate limit", v.requestID)
+		v.t.repoll()
+		return
+	}
+
 	v.t.Ctx.Log.Debug("Finishing poll [%d] with:\n%s", v.requestID, &results)
+	oldHead := v.t.Consensus.Preference()
+	processing := v.t.processingPreferred()
 	if err := v.t.Consensus.RecordPoll(results); err != nil {
 		v.t.errs.Add(err)
 		return
 	}
+	v.t.countAccepted(processing)
+	v.t.recordPreferenceChange(oldHead, v.requestID, results)
 
 	v.t.VM.SetPreference(v.t.Consensus.Preference())
 
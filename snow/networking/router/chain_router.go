@@ -323,6 +323,19 @@ func (sr *ChainRouter) Chits(validatorID ids.ShortID, chainID ids.ID, requestID
 	}
 }
 
+// Backpressure returns nil if the chain with ID [chainID] can accept more
+// messages, or if the chain is unknown. Otherwise, the returned channel is
+// closed once the chain has caught up on its queued messages.
+func (sr *ChainRouter) Backpressure(chainID ids.ID) <-chan struct{} {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	if chain, exists := sr.chains[chainID.Key()]; exists {
+		return chain.Backpressure()
+	}
+	return nil
+}
+
 // QueryFailed routes an incoming QueryFailed message from the validator with ID [validatorID]
 // to the consensus engine working on the chain with ID [chainID]
 func (sr *ChainRouter) QueryFailed(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
@@ -450,6 +450,11 @@ func (h *Handler) Notify(msg common.Message) {
 	})
 }
 
+// Backpressure returns nil if this handler can accept more messages. If the
+// handler's queue is full, the returned channel is closed once the engine has
+// caught up enough that the caller should resume sending messages.
+func (h *Handler) Backpressure() <-chan struct{} { return h.serviceQueue.Backpressure() }
+
 // Shutdown asynchronously shuts down the dispatcher.
 // The handler should never be invoked again after calling
 // Shutdown.
@@ -139,3 +139,65 @@ func TestHandlerClosesOnError(t *testing.T) {
 	case _, _ = <-closed:
 	}
 }
+
+func TestHandlerBackpressure(t *testing.T) {
+	engine := common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = snow.DefaultContextTest
+
+	unblock := make(chan struct{})
+	engine.GetAcceptedFrontierF = func(validatorID ids.ShortID, requestID uint32) error {
+		<-unblock
+		return nil
+	}
+
+	bufferSize := 8
+	handler := &Handler{}
+	vdrs := validators.NewSet()
+	handler.Initialize(
+		&engine,
+		vdrs,
+		nil,
+		bufferSize,
+		DefaultStakerPortion,
+		DefaultStakerPortion,
+		"",
+		prometheus.NewRegistry(),
+	)
+
+	if drained := handler.Backpressure(); drained != nil {
+		t.Fatalf("Empty handler shouldn't signal backpressure")
+	}
+
+	for i := 0; i < bufferSize; i++ {
+		vdrID := ids.NewShortID([20]byte{byte(i + 1)})
+		if !handler.GetAcceptedFrontier(vdrID, uint32(i), time.Time{}) {
+			t.Fatalf("Message %d should have been queued", i)
+		}
+	}
+
+	drained := handler.Backpressure()
+	if drained == nil {
+		t.Fatalf("Full handler should signal backpressure")
+	}
+
+	go handler.Dispatch()
+
+	select {
+	case <-drained:
+		t.Fatalf("Backpressure shouldn't be released while the engine is blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(unblock)
+
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatalf("Backpressure should have been released once the engine caught up")
+	}
+
+	if drained := handler.Backpressure(); drained != nil {
+		t.Fatalf("Drained handler shouldn't signal backpressure")
+	}
+}
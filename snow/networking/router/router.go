@@ -42,6 +42,10 @@ type ExternalRouter interface {
 	PushQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, container []byte)
 	PullQuery(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
 	Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set)
+
+	// Backpressure returns nil if the chain can accept more messages.
+	// Otherwise, the returned channel is closed once the chain has room again.
+	Backpressure(chainID ids.ID) <-chan struct{}
 }
 
 // InternalRouter deals with messages internal to this node
@@ -24,6 +24,7 @@ type messageQueue interface {
 	PushMessage(message) bool              // Push a message to the queue
 	UtilizeCPU(ids.ShortID, time.Duration) // Registers consumption of CPU time
 	EndInterval()                          // Register end of an interval of real time
+	Backpressure() <-chan struct{}         // Signal for when the queue has room again
 	Shutdown()
 }
 
@@ -45,6 +46,11 @@ type multiLevelQueue struct {
 
 	semaChan chan struct{}
 
+	// drained is non-nil while the queue is full, and is closed once the queue
+	// has been drained below [lowWaterMark]
+	drained      chan struct{}
+	lowWaterMark int
+
 	log     logging.Logger
 	metrics *metrics
 }
@@ -92,6 +98,7 @@ func newMultiLevelQueue(
 		log:           log,
 		metrics:       metrics,
 		bufferSize:    bufferSize,
+		lowWaterMark:  bufferSize / 2,
 		semaChan:      semaChan,
 	}, semaChan
 }
@@ -137,9 +144,29 @@ func (ml *multiLevelQueue) PopMessage() (message, error) {
 		ml.throttler.RemoveMessage(msg.validatorID)
 		ml.metrics.pending.Dec()
 	}
+	if ml.drained != nil && ml.pendingMessages <= ml.lowWaterMark {
+		close(ml.drained)
+		ml.drained = nil
+	}
 	return msg, err
 }
 
+// Backpressure returns nil if the queue has room for more messages. Otherwise,
+// it returns a channel that will be closed once enough messages have been
+// processed that the caller should resume pushing messages.
+func (ml *multiLevelQueue) Backpressure() <-chan struct{} {
+	ml.lock.Lock()
+	defer ml.lock.Unlock()
+
+	if ml.drained == nil && ml.pendingMessages < ml.bufferSize {
+		return nil
+	}
+	if ml.drained == nil {
+		ml.drained = make(chan struct{})
+	}
+	return ml.drained
+}
+
 // UtilizeCPU...
 func (ml *multiLevelQueue) UtilizeCPU(vdr ids.ShortID, duration time.Duration) {
 	ml.lock.Lock()
@@ -165,13 +192,17 @@ func (ml *multiLevelQueue) EndInterval() {
 	ml.intervalConsumption = 0
 }
 
-// Shutdown closes the sema channel
+// Shutdown closes the sema channel and releases anyone waiting on backpressure
 // After Shutdown is called, PushMessage must never be called on multiLevelQueue again
 func (ml *multiLevelQueue) Shutdown() {
 	ml.lock.Lock()
 	defer ml.lock.Unlock()
 
 	close(ml.semaChan)
+	if ml.drained != nil {
+		close(ml.drained)
+		ml.drained = nil
+	}
 }
 
 // popMessage grabs a message from the current queue. If none is
@@ -49,7 +49,8 @@ func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32
 		go s.router.GetAcceptedFrontier(s.ctx.NodeID, s.ctx.ChainID, requestID, currentDeadline)
 	}
 
-	s.sender.GetAcceptedFrontier(validatorIDs, s.ctx.ChainID, requestID, currentDeadline)
+	failed := s.sender.GetAcceptedFrontier(validatorIDs, s.ctx.ChainID, requestID, currentDeadline)
+	s.fail(failed, requestID)
 }
 
 // AcceptedFrontier ...
@@ -79,7 +80,8 @@ func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, contai
 		go s.router.GetAccepted(s.ctx.NodeID, s.ctx.ChainID, requestID, currentDeadline, containerIDs)
 	}
 
-	s.sender.GetAccepted(validatorIDs, s.ctx.ChainID, requestID, currentDeadline, containerIDs)
+	failed := s.sender.GetAccepted(validatorIDs, s.ctx.ChainID, requestID, currentDeadline, containerIDs)
+	s.fail(failed, requestID)
 }
 
 // Accepted ...
@@ -103,7 +105,9 @@ func (s *Sender) GetAncestors(validatorID ids.ShortID, requestID uint32, contain
 	deadline := s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
 		s.router.GetAncestorsFailed(validatorID, s.ctx.ChainID, requestID)
 	})
-	s.sender.GetAncestors(validatorID, s.ctx.ChainID, requestID, deadline, containerID)
+	if !s.sender.GetAncestors(validatorID, s.ctx.ChainID, requestID, deadline, containerID) {
+		s.timeouts.Fail(validatorID, s.ctx.ChainID, requestID)
+	}
 }
 
 // MultiPut sends a MultiPut message to the consensus engine running on the specified chain
@@ -132,7 +136,9 @@ func (s *Sender) Get(validatorID ids.ShortID, requestID uint32, containerID ids.
 	deadline := s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
 		s.router.GetFailed(validatorID, s.ctx.ChainID, requestID)
 	})
-	s.sender.Get(validatorID, s.ctx.ChainID, requestID, deadline, containerID)
+	if !s.sender.Get(validatorID, s.ctx.ChainID, requestID, deadline, containerID) {
+		s.timeouts.Fail(validatorID, s.ctx.ChainID, requestID)
+	}
 }
 
 // Put sends a Put message to the consensus engine running on the specified chain
@@ -172,7 +178,8 @@ func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 		go s.router.PushQuery(s.ctx.NodeID, s.ctx.ChainID, requestID, currentDeadline, containerID, container)
 	}
 
-	s.sender.PushQuery(validatorIDs, s.ctx.ChainID, requestID, currentDeadline, containerID, container)
+	failed := s.sender.PushQuery(validatorIDs, s.ctx.ChainID, requestID, currentDeadline, containerID, container)
+	s.fail(failed, requestID)
 }
 
 // PullQuery sends a PullQuery message to the consensus engines running on the specified chains
@@ -203,7 +210,8 @@ func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 		go s.router.PullQuery(s.ctx.NodeID, s.ctx.ChainID, requestID, currentDeadline, containerID)
 	}
 
-	s.sender.PullQuery(validatorIDs, s.ctx.ChainID, requestID, currentDeadline, containerID)
+	failed := s.sender.PullQuery(validatorIDs, s.ctx.ChainID, requestID, currentDeadline, containerID)
+	s.fail(failed, requestID)
 }
 
 // Chits sends chits
@@ -223,3 +231,11 @@ func (s *Sender) Gossip(containerID ids.ID, container []byte) {
 	s.ctx.Log.Verbo("Gossiping %s", containerID)
 	s.sender.Gossip(s.ctx.ChainID, containerID, container)
 }
+
+// fail the request [requestID] to each of [validatorIDs], because it couldn't
+// be sent to them, without waiting for the requests to time out
+func (s *Sender) fail(validatorIDs ids.ShortSet, requestID uint32) {
+	for _, validatorID := range validatorIDs.List() {
+		s.timeouts.Fail(validatorID, s.ctx.ChainID, requestID)
+	}
+}
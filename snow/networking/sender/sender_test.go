@@ -89,6 +89,71 @@ func TestTimeout(t *testing.T) {
 	}
 }
 
+func TestFailedSend(t *testing.T) {
+	tm := timeout.Manager{}
+	tm.Initialize("", prometheus.NewRegistry())
+	go tm.Dispatch()
+
+	chainRouter := router.ChainRouter{}
+	chainRouter.Initialize(logging.NoLog{}, &tm, time.Hour, time.Second)
+
+	unreachable := ids.NewShortID([20]byte{255})
+	reachable := ids.NewShortID([20]byte{254})
+
+	externalSender := ExternalSenderTest{T: t}
+	externalSender.PullQueryF = func(ids.ShortSet, ids.ID, uint32, time.Time, ids.ID) ids.ShortSet {
+		failed := ids.ShortSet{}
+		failed.Add(unreachable)
+		return failed
+	}
+
+	sender := Sender{}
+	sender.Initialize(snow.DefaultContextTest(), &externalSender, &chainRouter, &tm)
+
+	engine := common.EngineTest{T: t}
+	engine.Default(true)
+
+	engine.ContextF = snow.DefaultContextTest
+
+	failedVDRs := make(chan ids.ShortID, 2)
+	engine.QueryFailedF = func(validatorID ids.ShortID, _ uint32) error {
+		failedVDRs <- validatorID
+		return nil
+	}
+
+	handler := router.Handler{}
+	handler.Initialize(
+		&engine,
+		validators.NewSet(),
+		nil,
+		1,
+		router.DefaultStakerPortion,
+		router.DefaultStakerPortion,
+		"",
+		prometheus.NewRegistry(),
+	)
+	go handler.Dispatch()
+
+	chainRouter.AddChain(&handler)
+
+	vdrIDs := ids.ShortSet{}
+	vdrIDs.Add(unreachable)
+	vdrIDs.Add(reachable)
+
+	sender.PullQuery(vdrIDs, 0, ids.Empty)
+
+	// The query that couldn't be sent should fail well before the timeout
+	// expires, and the query that was sent shouldn't fail with it
+	select {
+	case vdrID := <-failedVDRs:
+		if !vdrID.Equals(unreachable) {
+			t.Fatalf("Query to %s shouldn't have failed", vdrID)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatalf("Query that couldn't be sent should have failed without waiting for its timeout")
+	}
+}
+
 func TestReliableMessages(t *testing.T) {
 	tm := timeout.Manager{}
 	tm.Initialize("", prometheus.NewRegistry())
@@ -10,7 +10,8 @@ import (
 	"github.com/ava-labs/gecko/ids"
 )
 
-// ExternalSenderTest is a test sender
+// ExternalSenderTest is a test sender. Requests are reported as sent unless
+// their F func reports otherwise.
 type ExternalSenderTest struct {
 	T *testing.T
 	B *testing.B
@@ -22,20 +23,20 @@ type ExternalSenderTest struct {
 	CantPullQuery, CantPushQuery, CantChits,
 	CantGossip bool
 
-	GetAcceptedFrontierF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time)
+	GetAcceptedFrontierF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) ids.ShortSet
 	AcceptedFrontierF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set)
 
-	GetAcceptedF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerIDs ids.Set)
+	GetAcceptedF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerIDs ids.Set) ids.ShortSet
 	AcceptedF    func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set)
 
-	GetAncestorsF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
+	GetAncestorsF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) bool
 	MultiPutF     func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containers [][]byte)
 
-	GetF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
+	GetF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) bool
 	PutF func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerID ids.ID, container []byte)
 
-	PushQueryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, container []byte)
-	PullQueryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID)
+	PushQueryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID, container []byte) ids.ShortSet
+	PullQueryF func(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerID ids.ID) ids.ShortSet
 	ChitsF     func(validatorID ids.ShortID, chainID ids.ID, requestID uint32, votes ids.Set)
 
 	GossipF func(chainID ids.ID, containerID ids.ID, container []byte)
@@ -65,14 +66,15 @@ func (s *ExternalSenderTest) Default(cant bool) {
 // GetAcceptedFrontier calls GetAcceptedFrontierF if it was initialized. If it
 // wasn't initialized and this function shouldn't be called and testing was
 // initialized, then testing will fail.
-func (s *ExternalSenderTest) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) {
+func (s *ExternalSenderTest) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time) ids.ShortSet {
 	if s.GetAcceptedFrontierF != nil {
-		s.GetAcceptedFrontierF(validatorIDs, chainID, requestID, deadline)
+		return s.GetAcceptedFrontierF(validatorIDs, chainID, requestID, deadline)
 	} else if s.CantGetAcceptedFrontier && s.T != nil {
 		s.T.Fatalf("Unexpectedly called GetAcceptedFrontier")
 	} else if s.CantGetAcceptedFrontier && s.B != nil {
 		s.B.Fatalf("Unexpectedly called GetAcceptedFrontier")
 	}
+	return nil
 }
 
 // AcceptedFrontier calls AcceptedFrontierF if it was initialized. If it wasn't
@@ -91,14 +93,15 @@ func (s *ExternalSenderTest) AcceptedFrontier(validatorID ids.ShortID, chainID i
 // GetAccepted calls GetAcceptedF if it was initialized. If it wasn't
 // initialized and this function shouldn't be called and testing was
 // initialized, then testing will fail.
-func (s *ExternalSenderTest) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerIDs ids.Set) {
+func (s *ExternalSenderTest) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, containerIDs ids.Set) ids.ShortSet {
 	if s.GetAcceptedF != nil {
-		s.GetAcceptedF(validatorIDs, chainID, requestID, deadline, containerIDs)
+		return s.GetAcceptedF(validatorIDs, chainID, requestID, deadline, containerIDs)
 	} else if s.CantGetAccepted && s.T != nil {
 		s.T.Fatalf("Unexpectedly called GetAccepted")
 	} else if s.CantGetAccepted && s.B != nil {
 		s.B.Fatalf("Unexpectedly called GetAccepted")
 	}
+	return nil
 }
 
 // Accepted calls AcceptedF if it was initialized. If it wasn't initialized and
@@ -117,14 +120,15 @@ func (s *ExternalSenderTest) Accepted(validatorID ids.ShortID, chainID ids.ID, r
 // GetAncestors calls GetAncestorsF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
-func (s *ExternalSenderTest) GetAncestors(vdr ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, vtxID ids.ID) {
+func (s *ExternalSenderTest) GetAncestors(vdr ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, vtxID ids.ID) bool {
 	if s.GetAncestorsF != nil {
-		s.GetAncestorsF(vdr, chainID, requestID, deadline, vtxID)
+		return s.GetAncestorsF(vdr, chainID, requestID, deadline, vtxID)
 	} else if s.CantGetAncestors && s.T != nil {
 		s.T.Fatalf("Unexpectedly called GetAncestors")
 	} else if s.CantGetAncestors && s.B != nil {
 		s.B.Fatalf("Unexpectedly called GetAncestors")
 	}
+	return true
 }
 
 // MultiPut calls MultiPutF if it was initialized. If it wasn't initialized and this
@@ -143,14 +147,15 @@ func (s *ExternalSenderTest) MultiPut(vdr ids.ShortID, chainID ids.ID, requestID
 // Get calls GetF if it was initialized. If it wasn't initialized and this
 // function shouldn't be called and testing was initialized, then testing will
 // fail.
-func (s *ExternalSenderTest) Get(vdr ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, vtxID ids.ID) {
+func (s *ExternalSenderTest) Get(vdr ids.ShortID, chainID ids.ID, requestID uint32, deadline time.Time, vtxID ids.ID) bool {
 	if s.GetF != nil {
-		s.GetF(vdr, chainID, requestID, deadline, vtxID)
+		return s.GetF(vdr, chainID, requestID, deadline, vtxID)
 	} else if s.CantGet && s.T != nil {
 		s.T.Fatalf("Unexpectedly called Get")
 	} else if s.CantGet && s.B != nil {
 		s.B.Fatalf("Unexpectedly called Get")
 	}
+	return true
 }
 
 // Put calls PutF if it was initialized. If it wasn't initialized and this
@@ -169,27 +174,29 @@ func (s *ExternalSenderTest) Put(vdr ids.ShortID, chainID ids.ID, requestID uint
 // PushQuery calls PushQueryF if it was initialized. If it wasn't initialized
 // and this function shouldn't be called and testing was initialized, then
 // testing will fail.
-func (s *ExternalSenderTest) PushQuery(vdrs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, vtxID ids.ID, vtx []byte) {
+func (s *ExternalSenderTest) PushQuery(vdrs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, vtxID ids.ID, vtx []byte) ids.ShortSet {
 	if s.PushQueryF != nil {
-		s.PushQueryF(vdrs, chainID, requestID, deadline, vtxID, vtx)
+		return s.PushQueryF(vdrs, chainID, requestID, deadline, vtxID, vtx)
 	} else if s.CantPushQuery && s.T != nil {
 		s.T.Fatalf("Unexpectedly called PushQuery")
 	} else if s.CantPushQuery && s.B != nil {
 		s.B.Fatalf("Unexpectedly called PushQuery")
 	}
+	return nil
 }
 
 // PullQuery calls PullQueryF if it was initialized. If it wasn't initialized
 // and this function shouldn't be called and testing was initialized, then
 // testing will fail.
-func (s *ExternalSenderTest) PullQuery(vdrs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, vtxID ids.ID) {
+func (s *ExternalSenderTest) PullQuery(vdrs ids.ShortSet, chainID ids.ID, requestID uint32, deadline time.Time, vtxID ids.ID) ids.ShortSet {
 	if s.PullQueryF != nil {
-		s.PullQueryF(vdrs, chainID, requestID, deadline, vtxID)
+		return s.PullQueryF(vdrs, chainID, requestID, deadline, vtxID)
 	} else if s.CantPullQuery && s.T != nil {
 		s.T.Fatalf("Unexpectedly called PullQuery")
 	} else if s.CantPullQuery && s.B != nil {
 		s.B.Fatalf("Unexpectedly called PullQuery")
 	}
+	return nil
 }
 
 // Chits calls ChitsF if it was initialized. If it wasn't initialized and this
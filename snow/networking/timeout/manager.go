@@ -26,6 +26,7 @@ func (m *Manager) Initialize(
 		500*time.Millisecond,
 		2,
 		time.Millisecond,
+		nil,
 		namespace,
 		registerer,
 	)
@@ -45,6 +46,13 @@ func (m *Manager) Cancel(validatorID ids.ShortID, chainID ids.ID, requestID uint
 	m.tm.Remove(createRequestID(validatorID, chainID, requestID))
 }
 
+// Fail the request with the specified parameters without waiting for its
+// timeout, because it's known to have failed. The timeout's handler is called
+// on the dispatch goroutine. Returns false if the request isn't outstanding.
+func (m *Manager) Fail(validatorID ids.ShortID, chainID ids.ID, requestID uint32) bool {
+	return m.tm.Fail(createRequestID(validatorID, chainID, requestID))
+}
+
 func createRequestID(validatorID ids.ShortID, chainID ids.ID, requestID uint32) ids.ID {
 	p := wrappers.Packer{Bytes: make([]byte, wrappers.IntLen)}
 	p.PackInt(requestID)
@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"sync/atomic"
+)
+
+// AtomicBool is a bool that is safe to access concurrently. The zero value is
+// false.
+type AtomicBool struct {
+	// Non-zero iff the value is true. Should only be accessed atomically.
+	value uint32
+}
+
+// Get returns the current value
+func (b *AtomicBool) Get() bool { return atomic.LoadUint32(&b.value) != 0 }
+
+// Set the value to [value]
+func (b *AtomicBool) Set(value bool) { atomic.StoreUint32(&b.value, boolToUint32(value)) }
+
+// Swap sets the value to [value] and returns the previous value. Only one of
+// several concurrent calls to Swap(true) will return false.
+func (b *AtomicBool) Swap(value bool) bool {
+	return atomic.SwapUint32(&b.value, boolToUint32(value)) != 0
+}
+
+func boolToUint32(value bool) uint32 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// Counter is a uint64 that is safe to increment concurrently. The zero value
+// is 0.
+type Counter struct {
+	// Should only be accessed atomically.
+	value uint64
+}
+
+// Inc increments the counter and returns the new value
+func (c *Counter) Inc() uint64 { return c.Add(1) }
+
+// Add [delta] to the counter and returns the new value
+func (c *Counter) Add(delta uint64) uint64 { return atomic.AddUint64(&c.value, delta) }
+
+// Value returns the current value
+func (c *Counter) Value() uint64 { return atomic.LoadUint64(&c.value) }
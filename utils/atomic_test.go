@@ -0,0 +1,88 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAtomicBool(t *testing.T) {
+	b := AtomicBool{}
+	if b.Get() {
+		t.Fatalf("zero value should be false")
+	}
+
+	b.Set(true)
+	if !b.Get() {
+		t.Fatalf("should be true after being set")
+	}
+
+	if prev := b.Swap(false); !prev {
+		t.Fatalf("Swap should have returned the previous value")
+	}
+	if b.Get() {
+		t.Fatalf("should be false after being swapped")
+	}
+}
+
+func TestAtomicBoolConcurrentSwap(t *testing.T) {
+	b := AtomicBool{}
+
+	const numGoroutines = 100
+	winners := Counter{}
+	wg := sync.WaitGroup{}
+	wg.Add(numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			defer wg.Done()
+
+			// Only the first swap should observe the flag as unset
+			if !b.Swap(true) {
+				winners.Inc()
+			}
+			_ = b.Get()
+		}()
+	}
+	wg.Wait()
+
+	if numWinners := winners.Value(); numWinners != 1 {
+		t.Fatalf("expected exactly 1 goroutine to set the flag but %d did", numWinners)
+	}
+	if !b.Get() {
+		t.Fatalf("should be true after being swapped")
+	}
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	c := Counter{}
+
+	const (
+		numGoroutines = 100
+		numIncrements = 100
+	)
+	wg := sync.WaitGroup{}
+	wg.Add(numGoroutines)
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < numIncrements; j++ {
+				if j%2 == 0 {
+					c.Inc()
+				} else {
+					c.Add(2)
+				}
+				_ = c.Value()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Each goroutine adds 1 and 2 alternately
+	expected := uint64(numGoroutines * numIncrements / 2 * 3)
+	if value := c.Value(); value != expected {
+		t.Fatalf("expected %d but got %d", expected, value)
+	}
+}
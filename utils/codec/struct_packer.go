@@ -0,0 +1,248 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"unicode"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	errNeedStructPointer = errors.New("argument must be a pointer to a struct")
+	errTooManyElements   = errors.New("slice length exceeds the remaining bytes")
+
+	idType      = reflect.TypeOf(ids.ID{})
+	shortIDType = reflect.TypeOf(ids.ShortID{})
+	packable    = reflect.TypeOf((*Packable)(nil)).Elem()
+
+	// Key: a struct type
+	// Value: the fieldPackers of the struct's serialized fields
+	structPackers sync.Map
+)
+
+// Packable is implemented by types that pack themselves. PackStruct and
+// UnpackStruct use these methods for fields whose types implement Packable,
+// which allows types that aren't otherwise supported to be used in a struct.
+type Packable interface {
+	Pack(*wrappers.Packer)
+	Unpack(*wrappers.Packer)
+}
+
+// PackStruct writes the fields of the struct pointed to by [value] that have
+// the tag `serialize:"true"` to [p], in the order they are declared.
+//
+// Unlike Codec, PackStruct doesn't write a version or support interfaces.
+// Supported field types are bool, string, uint[8,16,32,64], []byte, ids.ID,
+// ids.ShortID, slices of these types and types that implement Packable.
+// The layout of each struct type is computed once, so packing a value doesn't
+// need to inspect the type of each field.
+func PackStruct(p *wrappers.Packer, value interface{}) error {
+	v, packers, err := structFields(value)
+	if err != nil {
+		return err
+	}
+	for _, packer := range packers {
+		packer.pack(p, v.Field(packer.index))
+		if p.Errored() {
+			return p.Err
+		}
+	}
+	return nil
+}
+
+// UnpackStruct reads the fields of the struct pointed to by [dest] from [p].
+// It is the inverse of PackStruct.
+func UnpackStruct(p *wrappers.Packer, dest interface{}) error {
+	v, packers, err := structFields(dest)
+	if err != nil {
+		return err
+	}
+	for _, packer := range packers {
+		packer.unpack(p, v.Field(packer.index))
+		if p.Errored() {
+			return p.Err
+		}
+	}
+	return nil
+}
+
+type fieldPacker struct {
+	index  int
+	pack   func(*wrappers.Packer, reflect.Value)
+	unpack func(*wrappers.Packer, reflect.Value)
+}
+
+func structFields(value interface{}) (reflect.Value, []fieldPacker, error) {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, nil, errNeedStructPointer
+	}
+	v = v.Elem()
+	packers, err := getStructPackers(v.Type())
+	return v, packers, err
+}
+
+func getStructPackers(t reflect.Type) ([]fieldPacker, error) {
+	if packers, ok := structPackers.Load(t); ok { // use pre-computed result
+		return packers.([]fieldPacker), nil
+	}
+
+	packers := []fieldPacker(nil)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("serialize") != "true" { // Skip fields we don't need to serialize
+			continue
+		}
+		if unicode.IsLower(rune(field.Name[0])) { // Can only pack exported fields
+			return nil, fmt.Errorf("can't pack unexported field %s", field.Name)
+		}
+		pack, unpack, err := typePacker(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("can't pack field %s: %w", field.Name, err)
+		}
+		packers = append(packers, fieldPacker{
+			index:  i,
+			pack:   pack,
+			unpack: unpack,
+		})
+	}
+	structPackers.Store(t, packers) // cache result
+	return packers, nil
+}
+
+// typePacker returns functions that pack and unpack values of type [t]
+func typePacker(t reflect.Type) (
+	func(*wrappers.Packer, reflect.Value),
+	func(*wrappers.Packer, reflect.Value),
+	error,
+) {
+	switch {
+	case reflect.PtrTo(t).Implements(packable):
+		return func(p *wrappers.Packer, v reflect.Value) {
+				v.Addr().Interface().(Packable).Pack(p)
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				v.Addr().Interface().(Packable).Unpack(p)
+			}, nil
+	case t == idType:
+		return func(p *wrappers.Packer, v reflect.Value) {
+				id := v.Interface().(ids.ID)
+				if id.IsZero() {
+					p.Add(errMarshalNil)
+					return
+				}
+				p.PackFixedBytes(id.Bytes())
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				if idBytes := p.UnpackFixedBytes(hashing.HashLen); !p.Errored() {
+					id, err := ids.ToID(idBytes)
+					p.Add(err)
+					v.Set(reflect.ValueOf(id))
+				}
+			}, nil
+	case t == shortIDType:
+		return func(p *wrappers.Packer, v reflect.Value) {
+				id := v.Interface().(ids.ShortID)
+				if id.IsZero() {
+					p.Add(errMarshalNil)
+					return
+				}
+				p.PackFixedBytes(id.Bytes())
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				if idBytes := p.UnpackFixedBytes(hashing.AddrLen); !p.Errored() {
+					id, err := ids.ToShortID(idBytes)
+					p.Add(err)
+					v.Set(reflect.ValueOf(id))
+				}
+			}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return func(p *wrappers.Packer, v reflect.Value) {
+				p.PackBool(v.Bool())
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				v.SetBool(p.UnpackBool())
+			}, nil
+	case reflect.String:
+		return func(p *wrappers.Packer, v reflect.Value) {
+				p.PackStr(v.String())
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				v.SetString(p.UnpackStr())
+			}, nil
+	case reflect.Uint8:
+		return func(p *wrappers.Packer, v reflect.Value) {
+				p.PackByte(uint8(v.Uint()))
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				v.SetUint(uint64(p.UnpackByte()))
+			}, nil
+	case reflect.Uint16:
+		return func(p *wrappers.Packer, v reflect.Value) {
+				p.PackShort(uint16(v.Uint()))
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				v.SetUint(uint64(p.UnpackShort()))
+			}, nil
+	case reflect.Uint32:
+		return func(p *wrappers.Packer, v reflect.Value) {
+				p.PackInt(uint32(v.Uint()))
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				v.SetUint(uint64(p.UnpackInt()))
+			}, nil
+	case reflect.Uint64:
+		return func(p *wrappers.Packer, v reflect.Value) {
+				p.PackLong(v.Uint())
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				v.SetUint(p.UnpackLong())
+			}, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return func(p *wrappers.Packer, v reflect.Value) {
+					p.PackBytes(v.Bytes())
+				}, func(p *wrappers.Packer, v reflect.Value) {
+					if b := p.UnpackBytes(); !p.Errored() {
+						bytes := make([]byte, len(b))
+						copy(bytes, b)
+						v.SetBytes(bytes)
+					}
+				}, nil
+		}
+		if t.Elem().Kind() == reflect.Slice && t.Elem().Elem().Kind() != reflect.Uint8 {
+			return nil, nil, fmt.Errorf("can't pack nested slice type %s", t)
+		}
+		packElt, unpackElt, err := typePacker(t.Elem())
+		if err != nil {
+			return nil, nil, err
+		}
+		return func(p *wrappers.Packer, v reflect.Value) {
+				numElts := v.Len()
+				p.PackInt(uint32(numElts))
+				for i := 0; i < numElts && !p.Errored(); i++ {
+					packElt(p, v.Index(i))
+				}
+			}, func(p *wrappers.Packer, v reflect.Value) {
+				numElts := int(p.UnpackInt())
+				if p.Errored() {
+					return
+				}
+				// Every element takes at least one byte, so a malformed length
+				// can't cause a large allocation
+				if numElts > len(p.Bytes)-p.Offset {
+					p.Add(errTooManyElements)
+					return
+				}
+				slice := reflect.MakeSlice(t, numElts, numElts)
+				for i := 0; i < numElts && !p.Errored(); i++ {
+					unpackElt(p, slice.Index(i))
+				}
+				v.Set(slice)
+			}, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
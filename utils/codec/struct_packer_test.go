@@ -0,0 +1,132 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package codec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// Packs itself as a uint16 holding the sum of its fields
+type testPackable struct{ A, B uint8 }
+
+func (t *testPackable) Pack(p *wrappers.Packer) { p.PackShort(uint16(t.A) + uint16(t.B)) }
+func (t *testPackable) Unpack(p *wrappers.Packer) {
+	sum := p.UnpackShort()
+	t.A = uint8(sum / 2)
+	t.B = uint8(sum - sum/2)
+}
+
+type testMessage struct {
+	Byte      uint8        `serialize:"true"`
+	Short     uint16       `serialize:"true"`
+	Int       uint32       `serialize:"true"`
+	Long      uint64       `serialize:"true"`
+	Bool      bool         `serialize:"true"`
+	Str       string       `serialize:"true"`
+	Bytes     []byte       `serialize:"true"`
+	ID        ids.ID       `serialize:"true"`
+	ShortID   ids.ShortID  `serialize:"true"`
+	Longs     []uint64     `serialize:"true"`
+	Strs      []string     `serialize:"true"`
+	ByteSlice [][]byte     `serialize:"true"`
+	IDs       []ids.ID     `serialize:"true"`
+	Packable  testPackable `serialize:"true"`
+	Skipped   uint32
+}
+
+func TestPackStructRoundTrip(t *testing.T) {
+	msg := testMessage{
+		Byte:      1,
+		Short:     2,
+		Int:       3,
+		Long:      4,
+		Bool:      true,
+		Str:       "avalanche",
+		Bytes:     []byte{5, 6, 7},
+		ID:        ids.NewID([32]byte{8}),
+		ShortID:   ids.NewShortID([20]byte{9}),
+		Longs:     []uint64{10, 11},
+		Strs:      []string{"a", "", "b"},
+		ByteSlice: [][]byte{{12}, {}, {13, 14}},
+		IDs:       []ids.ID{ids.NewID([32]byte{15}), ids.NewID([32]byte{16})},
+		Packable:  testPackable{A: 17, B: 17},
+		Skipped:   18,
+	}
+
+	p := wrappers.Packer{MaxSize: 1024}
+	if err := PackStruct(&p, &msg); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := testMessage{}
+	if err := UnpackStruct(&wrappers.Packer{Bytes: p.Bytes}, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Skipped != 0 {
+		t.Fatalf("Untagged field should have been skipped")
+	}
+	parsed.Skipped = msg.Skipped
+	if !reflect.DeepEqual(msg, parsed) {
+		t.Fatalf("Unpacked struct doesn't match the original.\nExpected: %+v\nReturned: %+v", msg, parsed)
+	}
+}
+
+func TestUnpackStructMalformed(t *testing.T) {
+	msg := testMessage{
+		Str:     "avalanche",
+		ID:      ids.NewID([32]byte{1}),
+		ShortID: ids.NewShortID([20]byte{2}),
+		Longs:   []uint64{3, 4, 5},
+	}
+	p := wrappers.Packer{MaxSize: 1024}
+	if err := PackStruct(&p, &msg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every truncation of a valid message must fail to unpack
+	for i := 0; i < len(p.Bytes); i++ {
+		parsed := testMessage{}
+		if err := UnpackStruct(&wrappers.Packer{Bytes: p.Bytes[:i]}, &parsed); err == nil {
+			t.Fatalf("Should have errored unpacking a message truncated to %d bytes", i)
+		}
+	}
+
+	// A slice length larger than the message must fail without allocating
+	type lengthPrefixed struct {
+		Longs []uint64 `serialize:"true"`
+	}
+	parsed := lengthPrefixed{}
+	if err := UnpackStruct(&wrappers.Packer{Bytes: []byte{0xff, 0xff, 0xff, 0xff}}, &parsed); err == nil {
+		t.Fatalf("Should have errored due to an invalid slice length")
+	}
+}
+
+func TestPackStructInvalid(t *testing.T) {
+	p := wrappers.Packer{MaxSize: 1024}
+	if err := PackStruct(&p, testMessage{}); err == nil {
+		t.Fatalf("Should have errored due to a non-pointer argument")
+	}
+	if err := PackStruct(&p, &testMessage{}); err == nil {
+		t.Fatalf("Should have errored due to an unset ID")
+	}
+
+	type unexported struct {
+		a uint32 `serialize:"true"`
+	}
+	if err := PackStruct(&p, &unexported{}); err == nil {
+		t.Fatalf("Should have errored due to an unexported field")
+	}
+
+	type unsupported struct {
+		A int `serialize:"true"`
+	}
+	if err := PackStruct(&p, &unsupported{}); err == nil {
+		t.Fatalf("Should have errored due to an unsupported field type")
+	}
+}
@@ -0,0 +1,174 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+const (
+	// BLSPrivateKeyLen is the number of bytes in a BLS private key
+	BLSPrivateKeyLen = 32
+
+	// BLSPublicKeyLen is the number of bytes in a compressed BLS public key
+	BLSPublicKeyLen = 48
+
+	// BLSSignatureLen is the number of bytes in a compressed BLS signature
+	BLSSignatureLen = 96
+)
+
+var (
+	errInvalidBLSPublicKey  = errors.New("invalid BLS public key")
+	errInvalidBLSPrivateKey = errors.New("invalid BLS private key")
+	errInvalidBLSSignature  = errors.New("invalid BLS signature")
+
+	// blsDST is the domain separation tag of the ciphersuite, as defined by
+	// the IETF BLS signature draft, for signatures in G2 with public keys in
+	// G1 using the basic scheme
+	blsDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+
+	// blsOrder is the order of the BLS12-381 G1 and G2 subgroups
+	blsOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+)
+
+// FactoryBLS creates BLS keys. Public keys are points in G1 and signatures are
+// points in G2 of the BLS12-381 pairing. Messages are hashed to G2 as defined
+// by the IETF hash-to-curve draft.
+type FactoryBLS struct{}
+
+// NewPrivateKey implements the Factory interface
+func (*FactoryBLS) NewPrivateKey() (PrivateKey, error) {
+	sk, err := randomBLSScalar()
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKeyBLS{sk: sk}, nil
+}
+
+// ToPublicKey implements the Factory interface. Points that aren't in the G1
+// subgroup, and the identity, are rejected.
+func (*FactoryBLS) ToPublicKey(b []byte) (PublicKey, error) {
+	g1 := bls12381.NewG1()
+	pk, err := g1.FromCompressed(b)
+	if err != nil || g1.IsZero(pk) {
+		return nil, errInvalidBLSPublicKey
+	}
+	return &PublicKeyBLS{pk: pk, bytes: b}, nil
+}
+
+// ToPrivateKey implements the Factory interface
+func (*FactoryBLS) ToPrivateKey(b []byte) (PrivateKey, error) {
+	if len(b) != BLSPrivateKeyLen {
+		return nil, errWrongPrivateKeySize
+	}
+	sk := new(big.Int).SetBytes(b)
+	if sk.Sign() == 0 || sk.Cmp(blsOrder) >= 0 {
+		return nil, errInvalidBLSPrivateKey
+	}
+	return &PrivateKeyBLS{sk: sk}, nil
+}
+
+// PublicKeyBLS ...
+type PublicKeyBLS struct {
+	pk    *bls12381.PointG1
+	addr  ids.ShortID
+	bytes []byte
+}
+
+// Verify implements the PublicKey interface
+func (k *PublicKeyBLS) Verify(msg, sig []byte) bool {
+	g2 := bls12381.NewG2()
+	// FromCompressed rejects points that aren't in the G2 subgroup
+	s, err := g2.FromCompressed(sig)
+	if err != nil || g2.IsZero(s) {
+		return false
+	}
+	h, err := g2.HashToCurve(msg, blsDST)
+	if err != nil {
+		return false
+	}
+	// e(pk, H(msg)) == e(g1, sig)
+	e := bls12381.NewEngine()
+	e.AddPair(k.pk, h)
+	e.AddPairInv(e.G1.One(), s)
+	return e.Check()
+}
+
+// VerifyHash implements the PublicKey interface
+func (k *PublicKeyBLS) VerifyHash(hash, sig []byte) bool {
+	return k.Verify(hash, sig)
+}
+
+// Address implements the PublicKey interface
+func (k *PublicKeyBLS) Address() ids.ShortID {
+	if k.addr.IsZero() {
+		k.addr = PubkeyToAddress(k)
+	}
+	return k.addr
+}
+
+// Bytes implements the PublicKey interface
+func (k *PublicKeyBLS) Bytes() []byte {
+	if k.bytes == nil {
+		k.bytes = bls12381.NewG1().ToCompressed(k.pk)
+	}
+	return k.bytes
+}
+
+// PrivateKeyBLS ...
+type PrivateKeyBLS struct {
+	sk *big.Int
+	pk *PublicKeyBLS
+}
+
+// PublicKey implements the PrivateKey interface
+func (k *PrivateKeyBLS) PublicKey() PublicKey {
+	if k.pk == nil {
+		g1 := bls12381.NewG1()
+		k.pk = &PublicKeyBLS{pk: g1.MulScalarBig(g1.New(), g1.One(), k.sk)}
+	}
+	return k.pk
+}
+
+// Sign implements the PrivateKey interface
+func (k *PrivateKeyBLS) Sign(msg []byte) ([]byte, error) {
+	g2 := bls12381.NewG2()
+	h, err := g2.HashToCurve(msg, blsDST)
+	if err != nil {
+		return nil, err
+	}
+	return g2.ToCompressed(g2.MulScalarBig(g2.New(), h, k.sk)), nil
+}
+
+// SignHash implements the PrivateKey interface
+func (k *PrivateKeyBLS) SignHash(hash []byte) ([]byte, error) {
+	return k.Sign(hash)
+}
+
+// Bytes implements the PrivateKey interface
+func (k *PrivateKeyBLS) Bytes() []byte {
+	b := make([]byte, BLSPrivateKeyLen)
+	skBytes := k.sk.Bytes()
+	copy(b[BLSPrivateKeyLen-len(skBytes):], skBytes)
+	return b
+}
+
+// randomBLSScalar returns a uniformly random, non-zero scalar
+func randomBLSScalar() (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, blsOrder)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestBLSSignVerify(t *testing.T) {
+	f := FactoryBLS{}
+	key, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte{1, 2, 3}
+	sig, err := key.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub := key.PublicKey()
+	if !pub.Verify(msg, sig) {
+		t.Fatalf("Signature should have verified")
+	}
+	if pub.Verify([]byte{1, 2, 4}, sig) {
+		t.Fatalf("Signature shouldn't have verified a different message")
+	}
+
+	parsedKey, err := f.ToPrivateKey(key.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(parsedKey.PublicKey().Bytes(), pub.Bytes()) {
+		t.Fatalf("Parsed private key has the wrong public key")
+	}
+
+	parsedPub, err := f.ToPublicKey(pub.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsedPub.Verify(msg, sig) {
+		t.Fatalf("Signature should have verified with the parsed public key")
+	}
+}
+
+func TestBLSInvalidPublicKey(t *testing.T) {
+	f := FactoryBLS{}
+
+	// The compressed encoding of the identity
+	identity := make([]byte, BLSPublicKeyLen)
+	identity[0] = 0xc0
+	if _, err := f.ToPublicKey(identity); err == nil {
+		t.Fatalf("Should have errored due to the identity public key")
+	}
+
+	key, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.ToPublicKey(key.PublicKey().Bytes()[1:]); err == nil {
+		t.Fatalf("Should have errored due to a truncated public key")
+	}
+
+	// The first point on the curve y^2 = x^3 + 4 with a small x-coordinate.
+	// Almost no points on the curve are in the G1 subgroup, and this one isn't.
+	fieldPrime, _ := new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	for x := big.NewInt(1); ; x.Add(x, big.NewInt(1)) {
+		y := new(big.Int).Exp(x, big.NewInt(3), fieldPrime)
+		y.Add(y, big.NewInt(4))
+		if y.ModSqrt(y, fieldPrime) == nil {
+			continue
+		}
+
+		b := make([]byte, BLSPublicKeyLen)
+		x.FillBytes(b)
+		b[0] |= 0x80 // compressed
+		if _, err := f.ToPublicKey(b); err == nil {
+			t.Fatalf("Should have errored due to a point outside the subgroup")
+		}
+		break
+	}
+}
+
+func TestBLSInvalidSignature(t *testing.T) {
+	f := FactoryBLS{}
+	key, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity := make([]byte, BLSSignatureLen)
+	identity[0] = 0xc0
+	if key.PublicKey().Verify([]byte{1, 2, 3}, identity) {
+		t.Fatalf("The identity shouldn't be a valid signature")
+	}
+}
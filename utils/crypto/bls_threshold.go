@@ -0,0 +1,170 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+var (
+	errInvalidThreshold  = errors.New("threshold must be positive and at most the number of shares")
+	errBelowThreshold    = errors.New("not enough partial signatures to meet the threshold")
+	errInvalidShareIndex = errors.New("share index must be non-zero")
+	errDuplicateShare    = errors.New("duplicate partial signature")
+	errUnknownShare      = errors.New("no public key for partial signature")
+)
+
+// PrivateKeyShareBLS is one share of a BLS private key that was split so that
+// any [threshold] of the shares can produce a signature for the group.
+type PrivateKeyShareBLS struct {
+	PrivateKeyBLS
+
+	// Index is the point at which the secret sharing polynomial was evaluated
+	// to produce this share. It is never zero.
+	Index uint32
+}
+
+// SignPartial signs [msg] with this share
+func (k *PrivateKeyShareBLS) SignPartial(msg []byte) (PartialSignatureBLS, error) {
+	sig, err := k.Sign(msg)
+	return PartialSignatureBLS{
+		Index:     k.Index,
+		Signature: sig,
+	}, err
+}
+
+// PartialSignatureBLS is a signature produced by a single share of a threshold
+// key. It can be verified against the share's public key.
+type PartialSignatureBLS struct {
+	Index     uint32
+	Signature []byte
+}
+
+// NewThresholdKeysBLS generates a new group key split into [numShares] shares,
+// any [threshold] of which can produce a signature that verifies against the
+// returned group public key.
+func NewThresholdKeysBLS(threshold, numShares int) (PublicKey, []*PrivateKeyShareBLS, error) {
+	if threshold <= 0 || threshold > numShares {
+		return nil, nil, errInvalidThreshold
+	}
+
+	// f(x) = coefficients[0] + coefficients[1]*x + ... where f(0) is the group
+	// private key
+	coefficients := make([]*big.Int, threshold)
+	for i := range coefficients {
+		coefficient, err := randomBLSScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+		coefficients[i] = coefficient
+	}
+
+	shares := make([]*PrivateKeyShareBLS, numShares)
+	for i := range shares {
+		index := uint32(i + 1)
+		shares[i] = &PrivateKeyShareBLS{
+			PrivateKeyBLS: PrivateKeyBLS{sk: evaluatePolynomial(coefficients, index)},
+			Index:         index,
+		}
+	}
+
+	groupKey := (&PrivateKeyBLS{sk: coefficients[0]}).PublicKey()
+	return groupKey, shares, nil
+}
+
+// AggregateThresholdBLS combines partial signatures of [msg] from at least
+// [threshold] distinct shares into a signature that verifies against the group
+// public key. [shareKeys] maps the index of each share to its public key. Every
+// partial signature is verified against its share's key before any are
+// combined, so a single bad partial signature is reported rather than silently
+// producing an invalid group signature.
+func AggregateThresholdBLS(
+	threshold int,
+	msg []byte,
+	shareKeys map[uint32]PublicKey,
+	partials []PartialSignatureBLS,
+) ([]byte, error) {
+	if threshold <= 0 {
+		return nil, errInvalidThreshold
+	}
+
+	seen := make(map[uint32]bool, len(partials))
+	for _, partial := range partials {
+		switch {
+		case partial.Index == 0:
+			return nil, errInvalidShareIndex
+		case seen[partial.Index]:
+			return nil, fmt.Errorf("%w with index %d", errDuplicateShare, partial.Index)
+		}
+		seen[partial.Index] = true
+
+		shareKey, ok := shareKeys[partial.Index]
+		if !ok {
+			return nil, fmt.Errorf("%w with index %d", errUnknownShare, partial.Index)
+		}
+		if !shareKey.Verify(msg, partial.Signature) {
+			return nil, fmt.Errorf("%w with index %d", errInvalidBLSSignature, partial.Index)
+		}
+	}
+	if len(partials) < threshold {
+		return nil, errBelowThreshold
+	}
+
+	// Only [threshold] shares are needed to interpolate the group signature
+	partials = partials[:threshold]
+	indices := make([]*big.Int, threshold)
+	for i, partial := range partials {
+		indices[i] = new(big.Int).SetUint64(uint64(partial.Index))
+	}
+
+	g2 := bls12381.NewG2()
+	aggregate := g2.Zero()
+	for i, partial := range partials {
+		sig, err := g2.FromCompressed(partial.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("%w with index %d", errInvalidBLSSignature, partial.Index)
+		}
+		weighted := g2.MulScalarBig(g2.New(), sig, lagrangeCoefficientAtZero(indices, i))
+		g2.Add(aggregate, aggregate, weighted)
+	}
+	return g2.ToCompressed(aggregate), nil
+}
+
+// evaluatePolynomial returns f(x) mod the group order
+func evaluatePolynomial(coefficients []*big.Int, x uint32) *big.Int {
+	bigX := new(big.Int).SetUint64(uint64(x))
+	result := new(big.Int)
+	// Horner's method, starting from the highest degree coefficient
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result.Mul(result, bigX)
+		result.Add(result, coefficients[i])
+		result.Mod(result, blsOrder)
+	}
+	return result
+}
+
+// lagrangeCoefficientAtZero returns the weight of the share at [indices[i]]
+// when interpolating the polynomial at zero, which is the product over j != i
+// of x_j / (x_j - x_i) mod the group order
+func lagrangeCoefficientAtZero(indices []*big.Int, i int) *big.Int {
+	numerator := big.NewInt(1)
+	denominator := big.NewInt(1)
+	for j, xj := range indices {
+		if j == i {
+			continue
+		}
+		numerator.Mul(numerator, xj)
+		numerator.Mod(numerator, blsOrder)
+
+		diff := new(big.Int).Sub(xj, indices[i])
+		denominator.Mul(denominator, diff)
+		denominator.Mod(denominator, blsOrder)
+	}
+	denominator.ModInverse(denominator, blsOrder)
+	return numerator.Mul(numerator, denominator).Mod(numerator, blsOrder)
+}
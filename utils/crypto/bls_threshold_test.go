@@ -0,0 +1,150 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"testing"
+)
+
+// thresholdShareKeys maps the index of each share to its public key
+func thresholdShareKeys(shares []*PrivateKeyShareBLS) map[uint32]PublicKey {
+	shareKeys := make(map[uint32]PublicKey, len(shares))
+	for _, share := range shares {
+		shareKeys[share.Index] = share.PublicKey()
+	}
+	return shareKeys
+}
+
+func TestThresholdBLS(t *testing.T) {
+	threshold, numShares := 3, 5
+	groupKey, shares, err := NewThresholdKeysBLS(threshold, numShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != numShares {
+		t.Fatalf("Expected %d shares but got %d", numShares, len(shares))
+	}
+
+	msg := []byte("threshold")
+	shareKeys := thresholdShareKeys(shares)
+	partials := make([]PartialSignatureBLS, numShares)
+	for i, share := range shares {
+		partial, err := share.SignPartial(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !share.PublicKey().Verify(msg, partial.Signature) {
+			t.Fatalf("Partial signature %d should verify against its share's key", i)
+		}
+		partials[i] = partial
+	}
+
+	// Any [threshold] partial signatures should produce the group signature
+	for _, subset := range [][]int{{0, 1, 2}, {2, 3, 4}, {4, 0, 3}} {
+		selected := []PartialSignatureBLS(nil)
+		for _, i := range subset {
+			selected = append(selected, partials[i])
+		}
+		sig, err := AggregateThresholdBLS(threshold, msg, shareKeys, selected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !groupKey.Verify(msg, sig) {
+			t.Fatalf("Aggregate of shares %v should verify against the group key", subset)
+		}
+	}
+}
+
+func TestThresholdBLSBelowThreshold(t *testing.T) {
+	threshold, numShares := 3, 5
+	groupKey, shares, err := NewThresholdKeysBLS(threshold, numShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("threshold")
+	shareKeys := thresholdShareKeys(shares)
+	partials := []PartialSignatureBLS(nil)
+	for _, share := range shares[:threshold-1] {
+		partial, err := share.SignPartial(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partials = append(partials, partial)
+	}
+
+	if _, err := AggregateThresholdBLS(threshold, msg, shareKeys, partials); err == nil {
+		t.Fatalf("Should have errored due to too few partial signatures")
+	}
+
+	// Interpolating with fewer shares than the threshold shouldn't produce a
+	// valid group signature
+	sig, err := AggregateThresholdBLS(threshold-1, msg, shareKeys, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groupKey.Verify(msg, sig) {
+		t.Fatalf("Aggregate of too few shares shouldn't verify against the group key")
+	}
+
+	// Repeating a share shouldn't count towards the threshold
+	partials = append(partials, partials[0])
+	if _, err := AggregateThresholdBLS(threshold, msg, shareKeys, partials); err == nil {
+		t.Fatalf("Should have errored due to a duplicate partial signature")
+	}
+}
+
+func TestThresholdBLSInvalidPartials(t *testing.T) {
+	threshold, numShares := 2, 4
+	_, shares, err := NewThresholdKeysBLS(threshold, numShares)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("threshold")
+	shareKeys := thresholdShareKeys(shares)
+	partials := make([]PartialSignatureBLS, numShares)
+	for i, share := range shares {
+		partial, err := share.SignPartial(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partials[i] = partial
+	}
+
+	// A duplicate beyond the first [threshold] partial signatures is still
+	// detected
+	duplicated := []PartialSignatureBLS{partials[0], partials[1], partials[0]}
+	if _, err := AggregateThresholdBLS(threshold, msg, shareKeys, duplicated); err == nil {
+		t.Fatalf("Should have errored due to a duplicate partial signature")
+	}
+
+	// A partial signature of a different message is rejected
+	otherPartial, err := shares[2].SignPartial([]byte("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := []PartialSignatureBLS{partials[0], partials[1], otherPartial}
+	if _, err := AggregateThresholdBLS(threshold, msg, shareKeys, invalid); err == nil {
+		t.Fatalf("Should have errored due to an invalid partial signature")
+	}
+
+	// A partial signature attributed to the wrong share is rejected
+	misattributed := []PartialSignatureBLS{partials[0], {
+		Index:     partials[2].Index,
+		Signature: partials[1].Signature,
+	}}
+	if _, err := AggregateThresholdBLS(threshold, msg, shareKeys, misattributed); err == nil {
+		t.Fatalf("Should have errored due to a misattributed partial signature")
+	}
+
+	// A partial signature from an unknown share is rejected
+	unknown := []PartialSignatureBLS{partials[0], {
+		Index:     uint32(numShares + 1),
+		Signature: partials[1].Signature,
+	}}
+	if _, err := AggregateThresholdBLS(threshold, msg, shareKeys, unknown); err == nil {
+		t.Fatalf("Should have errored due to an unknown share")
+	}
+}
@@ -5,6 +5,7 @@ package crypto
 
 import (
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 // EnableCrypto ...
@@ -36,6 +37,12 @@ type PublicKey interface {
 	Bytes() []byte
 }
 
+// PubkeyToAddress returns the address of [pk], which is the ripemd160 hash of
+// the sha256 hash of its bytes
+func PubkeyToAddress(pk PublicKey) ids.ShortID {
+	return ids.NewShortID(hashing.ComputeHash160Array(hashing.ComputeHash256(pk.Bytes())))
+}
+
 // PrivateKey ...
 type PrivateKey interface {
 	PublicKey() PublicKey
@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+func TestPubkeyToAddressVector(t *testing.T) {
+	// Public key of the private key ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN,
+	// which funds local18jma8ppw3nhx5r4ap8clazz0dps7rv5u00z96u in the local
+	// genesis
+	pkBytes, _ := hex.DecodeString("0327448e78ffa8cdb24cf19be0204ad954b1bdb4db8c51183534c1eecf2ebd094e")
+	expected, _ := hex.DecodeString("3cb7d3842e8cee6a0ebd09f1fe884f6861e1b29c")
+
+	f := FactorySECP256K1R{}
+	pk, err := f.ToPublicKey(pkBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := PubkeyToAddress(pk); !bytes.Equal(addr.Bytes(), expected) {
+		t.Fatalf("PubkeyToAddress returned %x but should have returned %x", addr.Bytes(), expected)
+	}
+	if addr := pk.Address(); !bytes.Equal(addr.Bytes(), expected) {
+		t.Fatalf("Address returned %x but should have returned %x", addr.Bytes(), expected)
+	}
+}
+
+func TestPubkeyToAddressMatchesHashing(t *testing.T) {
+	factories := []Factory{
+		&FactorySECP256K1R{},
+		&FactoryED25519{},
+		&FactoryRSA{},
+		&FactoryRSAPSS{},
+		&FactoryBLS{},
+	}
+	for _, f := range factories {
+		sk, err := f.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pk := sk.PublicKey()
+		expected, err := ids.ToShortID(hashing.PubkeyBytesToAddress(pk.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr := PubkeyToAddress(pk); !addr.Equals(expected) {
+			t.Fatalf("%T: PubkeyToAddress returned %s but should have returned %s", f, addr, expected)
+		}
+		if addr := pk.Address(); !addr.Equals(expected) {
+			t.Fatalf("%T: Address returned %s but should have returned %s", f, addr, expected)
+		}
+	}
+}
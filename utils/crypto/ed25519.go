@@ -9,7 +9,6 @@ import (
 	"golang.org/x/crypto/ed25519"
 
 	"github.com/ava-labs/gecko/ids"
-	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 var (
@@ -61,11 +60,7 @@ func (k *PublicKeyED25519) VerifyHash(hash, sig []byte) bool {
 // Address implements the PublicKey interface
 func (k *PublicKeyED25519) Address() ids.ShortID {
 	if k.addr.IsZero() {
-		addr, err := ids.ToShortID(hashing.PubkeyBytesToAddress(k.Bytes()))
-		if err != nil {
-			panic(err)
-		}
-		k.addr = addr
+		k.addr = PubkeyToAddress(k)
 	}
 	return k.addr
 }
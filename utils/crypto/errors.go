@@ -8,6 +8,11 @@ import (
 )
 
 var (
-	errInvalidSigLen = errors.New("invalid signature length")
-	errMutatedSig    = errors.New("signature was mutated from its original format")
+	errInvalidSigLen       = errors.New("invalid signature length")
+	errMutatedSig          = errors.New("signature was mutated from its original format")
+	errInvalidPublicKeyLen = errors.New("invalid public key length")
+	errUnknownKeyVersion   = errors.New("unknown private key version")
+	errInsufficientEntropy = errors.New("insufficient entropy")
+	errUnknownSigner       = errors.New("signature isn't from a listed address")
+	errDuplicateSigner     = errors.New("address signed more than once")
 )
@@ -0,0 +1,42 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+)
+
+// VerifyMultisig returns true if [sigs] contains valid secp256k1 recoverable
+// signatures of [msg] from at least [threshold] of the addresses in [addrs].
+//
+// Every signature must be from one of [addrs], and no address may sign more
+// than once, so that one key can't be used to meet the threshold on its own.
+// An error is returned if a signature is malformed or breaks either rule.
+// Otherwise, false is returned if there are fewer than [threshold] signatures.
+func VerifyMultisig(threshold int, addrs []ids.ShortID, msg []byte, sigs [][]byte) (bool, error) {
+	listed := ids.ShortSet{}
+	listed.Add(addrs...)
+
+	factory := FactorySECP256K1R{}
+	hash := hashing.ComputeHash256(msg)
+	signers := ids.ShortSet{}
+	for i, sig := range sigs {
+		pk, err := factory.RecoverHashPublicKey(hash, sig)
+		if err != nil {
+			return false, fmt.Errorf("couldn't recover signer of signature %d: %w", i, err)
+		}
+		signer := pk.Address()
+		switch {
+		case !listed.Contains(signer):
+			return false, fmt.Errorf("%w: signature %d is from %s", errUnknownSigner, i, signer)
+		case signers.Contains(signer):
+			return false, fmt.Errorf("%w: signature %d is from %s", errDuplicateSigner, i, signer)
+		}
+		signers.Add(signer)
+	}
+	return signers.Len() >= threshold, nil
+}
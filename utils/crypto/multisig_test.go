@@ -0,0 +1,101 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package crypto
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// newMultisig returns [n] keys and their addresses
+func newMultisig(t *testing.T, n int) ([]PrivateKey, []ids.ShortID) {
+	f := FactorySECP256K1R{}
+	keys := make([]PrivateKey, n)
+	addrs := make([]ids.ShortID, n)
+	for i := range keys {
+		key, err := f.NewPrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+		addrs[i] = key.PublicKey().Address()
+	}
+	return keys, addrs
+}
+
+// sign returns the signatures of [msg] by each of [keys]
+func sign(t *testing.T, msg []byte, keys ...PrivateKey) [][]byte {
+	sigs := make([][]byte, len(keys))
+	for i, key := range keys {
+		sig, err := key.Sign(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs[i] = sig
+	}
+	return sigs
+}
+
+func TestVerifyMultisigThreshold(t *testing.T) {
+	keys, addrs := newMultisig(t, 3)
+	msg := []byte("hello")
+
+	// Signers don't need to be in the same order as the addresses
+	sigs := sign(t, msg, keys[2], keys[0])
+	if valid, err := VerifyMultisig(2, addrs, msg, sigs); err != nil {
+		t.Fatal(err)
+	} else if !valid {
+		t.Fatalf("Expected 2 signatures to meet a threshold of 2")
+	}
+
+	if valid, err := VerifyMultisig(3, addrs, msg, sigs); err != nil {
+		t.Fatal(err)
+	} else if valid {
+		t.Fatalf("Expected 2 signatures not to meet a threshold of 3")
+	}
+
+	if valid, err := VerifyMultisig(1, addrs, msg, nil); err != nil {
+		t.Fatal(err)
+	} else if valid {
+		t.Fatalf("Expected no signatures not to meet a threshold of 1")
+	}
+}
+
+func TestVerifyMultisigDuplicateSigner(t *testing.T) {
+	keys, addrs := newMultisig(t, 3)
+	msg := []byte("hello")
+
+	// The same key signing twice shouldn't count twice, even if the
+	// signatures differ
+	sigs := sign(t, msg, keys[0], keys[0])
+	if _, err := VerifyMultisig(2, addrs, msg, sigs); !errors.Is(err, errDuplicateSigner) {
+		t.Fatalf("Expected error %s but got %v", errDuplicateSigner, err)
+	}
+}
+
+func TestVerifyMultisigUnknownSigner(t *testing.T) {
+	keys, addrs := newMultisig(t, 3)
+	msg := []byte("hello")
+
+	// The last key isn't one of the listed addresses
+	sigs := sign(t, msg, keys...)
+	if _, err := VerifyMultisig(2, addrs[:2], msg, sigs); !errors.Is(err, errUnknownSigner) {
+		t.Fatalf("Expected error %s but got %v", errUnknownSigner, err)
+	}
+
+	// A signature of a different message recovers to a different address
+	sigs = sign(t, []byte("goodbye"), keys[0])
+	if _, err := VerifyMultisig(1, addrs, msg, sigs); !errors.Is(err, errUnknownSigner) {
+		t.Fatalf("Expected error %s but got %v", errUnknownSigner, err)
+	}
+}
+
+func TestVerifyMultisigMalformedSignature(t *testing.T) {
+	_, addrs := newMultisig(t, 1)
+	if _, err := VerifyMultisig(1, addrs, []byte("hello"), [][]byte{{1, 2, 3}}); err == nil {
+		t.Fatalf("Expected a malformed signature to fail verification")
+	}
+}
@@ -76,11 +76,7 @@ func (k *PublicKeyRSAPSS) VerifyHash(hash, sig []byte) bool {
 // Address implements the PublicKey interface
 func (k *PublicKeyRSAPSS) Address() ids.ShortID {
 	if k.addr.IsZero() {
-		addr, err := ids.ToShortID(hashing.PubkeyBytesToAddress(k.Bytes()))
-		if err != nil {
-			panic(err)
-		}
-		k.addr = addr
+		k.addr = PubkeyToAddress(k)
 	}
 	return k.addr
 }
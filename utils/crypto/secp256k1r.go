@@ -6,9 +6,12 @@ package crypto
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"sort"
 
 	stdecdsa "crypto/ecdsa"
+	"crypto/rand"
 
 	"github.com/decred/dcrd/dcrec/secp256k1/v3/ecdsa"
 
@@ -33,6 +36,11 @@ const (
 	// key
 	SECP256K1RPKLen = 33
 
+	// SECP256K1RSKVersion is the version of the format that secp2561k
+	// recoverable private keys are stored in. It is the first byte of the
+	// stored key.
+	SECP256K1RSKVersion byte = 0
+
 	// from the decred library:
 	// compactSigMagicOffset is a value used when creating the compact signature
 	// recovery code inherited from Bitcoin and has no meaning, but has been
@@ -43,25 +51,70 @@ const (
 )
 
 // FactorySECP256K1R ...
-type FactorySECP256K1R struct{ Cache cache.LRU }
+type FactorySECP256K1R struct {
+	Cache cache.LRU
+
+	// Entropy is the source of the key material used by NewPrivateKey. If
+	// nil, crypto/rand is used.
+	Entropy io.Reader
+}
+
+// NewPrivateKey implements the Factory interface. The key is read from the
+// factory's entropy source, re-reading whenever the bytes aren't a valid
+// secp256k1 key. If the source can't provide enough bytes, an error is
+// returned.
+func (f *FactorySECP256K1R) NewPrivateKey() (PrivateKey, error) {
+	entropy := f.Entropy
+	if entropy == nil {
+		entropy = rand.Reader
+	}
 
-// NewPrivateKey implements the Factory interface
-func (*FactorySECP256K1R) NewPrivateKey() (PrivateKey, error) {
-	k, err := secp256k1.GeneratePrivateKey()
-	return &PrivateKeySECP256K1R{sk: k}, err
+	keyBytes := [SECP256K1RSKLen]byte{}
+	for {
+		if _, err := io.ReadFull(entropy, keyBytes[:]); err != nil {
+			return nil, fmt.Errorf("%w: %s", errInsufficientEntropy, err)
+		}
+
+		var k secp256k1.ModNScalar
+		if overflow := k.SetBytes(&keyBytes); overflow == 0 && !k.IsZero() {
+			return &PrivateKeySECP256K1R{sk: secp256k1.NewPrivateKey(&k)}, nil
+		}
+	}
 }
 
-// ToPublicKey implements the Factory interface
+// ToPublicKey implements the Factory interface. Only compressed keys are
+// accepted, so that every public key has a single encoding and therefore a
+// single address. The key is rejected if it isn't a point on the curve. As
+// secp256k1 has a cofactor of 1, every point on the curve is in the correct
+// subgroup.
 func (*FactorySECP256K1R) ToPublicKey(b []byte) (PublicKey, error) {
+	if len(b) != SECP256K1RPKLen {
+		return nil, errInvalidPublicKeyLen
+	}
 	key, err := secp256k1.ParsePubKey(b)
+	if err != nil {
+		return nil, err
+	}
 	return &PublicKeySECP256K1R{
 		pk:    key,
-		bytes: b,
-	}, err
+		bytes: append([]byte(nil), b...),
+	}, nil
 }
 
-// ToPrivateKey implements the Factory interface
+// ToPrivateKey implements the Factory interface. [b] may either be a key
+// returned by Bytes, or one returned by VersionedBytes.
 func (*FactorySECP256K1R) ToPrivateKey(b []byte) (PrivateKey, error) {
+	switch len(b) {
+	case SECP256K1RSKLen:
+	case SECP256K1RSKLen + 1:
+		if version := b[0]; version != SECP256K1RSKVersion {
+			return nil, fmt.Errorf("%w: %d", errUnknownKeyVersion, version)
+		}
+		b = b[1:]
+	default:
+		return nil, errWrongPrivateKeySize
+	}
+	b = append([]byte(nil), b...)
 	return &PrivateKeySECP256K1R{
 		sk:    secp256k1.PrivKeyFromBytes(b),
 		bytes: b,
@@ -136,11 +189,7 @@ func (k *PublicKeySECP256K1R) ToECDSA() *stdecdsa.PublicKey {
 // Address implements the PublicKey interface
 func (k *PublicKeySECP256K1R) Address() ids.ShortID {
 	if k.addr.IsZero() {
-		addr, err := ids.ToShortID(hashing.PubkeyBytesToAddress(k.Bytes()))
-		if err != nil {
-			panic(err)
-		}
-		k.addr = addr
+		k.addr = PubkeyToAddress(k)
 	}
 	return k.addr
 }
@@ -173,7 +222,10 @@ func (k *PrivateKeySECP256K1R) Sign(msg []byte) ([]byte, error) {
 	return k.SignHash(hashing.ComputeHash256(msg))
 }
 
-// SignHash implements the PrivateKey interface
+// SignHash implements the PrivateKey interface. The nonce is derived from the
+// key and the hash as specified by RFC6979, so signing never depends on the
+// quality of the system's randomness and the same key and hash always produce
+// the same signature.
 func (k *PrivateKeySECP256K1R) SignHash(hash []byte) ([]byte, error) {
 	sig := ecdsa.SignCompact(k.sk, hash, false) // returns [v || r || s]
 	return rawSigToSig(sig)
@@ -192,6 +244,13 @@ func (k *PrivateKeySECP256K1R) Bytes() []byte {
 	return k.bytes
 }
 
+// VersionedBytes returns this key prefixed with SECP256K1RSKVersion. Keys
+// should be stored in this format, so that the format can be changed without
+// existing keys becoming ambiguous.
+func (k *PrivateKeySECP256K1R) VersionedBytes() []byte {
+	return append([]byte{SECP256K1RSKVersion}, k.Bytes()...)
+}
+
 // raw sig has format [v || r || s] whereas the sig has format [r || s || v]
 func rawSigToSig(sig []byte) ([]byte, error) {
 	if len(sig) != SECP256K1RSigLen {
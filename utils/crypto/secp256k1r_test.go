@@ -5,6 +5,9 @@ package crypto
 
 import (
 	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -98,3 +101,195 @@ func TestVerifyMutatedSignature(t *testing.T) {
 	_, err = factory.RecoverPublicKey(msg, sig)
 	assert.Error(t, err)
 }
+
+// Test vectors for RFC6979 nonces on secp256k1, with messages hashed with
+// SHA256, as used by Bitcoin implementations
+func TestSignRFC6979Vectors(t *testing.T) {
+	tests := []struct {
+		key, msg, r, s string
+	}{
+		{
+			key: "0000000000000000000000000000000000000000000000000000000000000001",
+			msg: "Satoshi Nakamoto",
+			r:   "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8",
+			s:   "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+		},
+		{
+			key: "0000000000000000000000000000000000000000000000000000000000000001",
+			msg: "All those moments will be lost in time, like tears in rain. Time to die...",
+			r:   "8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b",
+			s:   "547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc21",
+		},
+		{
+			key: "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			msg: "Satoshi Nakamoto",
+			r:   "fd567d121db66e382991534ada77a6bd3106f0a1098c231e47993447cd6af2d0",
+			s:   "6b39cd0eb1bc8603e159ef5c20a5c8ad685a45b06ce9bebed3f153d10d93bed5",
+		},
+	}
+	f := FactorySECP256K1R{}
+	for _, test := range tests {
+		keyBytes, _ := hex.DecodeString(test.key)
+		key, err := f.ToPrivateKey(keyBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := key.Sign([]byte(test.msg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r := hex.EncodeToString(sig[:32]); r != test.r {
+			t.Fatalf("Wrong r for %q. Expected %s ; Returned %s", test.msg, test.r, r)
+		}
+		if s := hex.EncodeToString(sig[32:64]); s != test.s {
+			t.Fatalf("Wrong s for %q. Expected %s ; Returned %s", test.msg, test.s, s)
+		}
+	}
+}
+
+func TestSignDeterministic(t *testing.T) {
+	f := FactorySECP256K1R{}
+	key, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("hello")
+	sig0, err := key.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig1, err := key.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig0, sig1) {
+		t.Fatalf("Signing the same message with the same key should produce the same signature")
+	}
+
+	sig2, err := key.Sign([]byte("world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sig0[:32], sig2[:32]) {
+		t.Fatalf("Signing different messages should use different nonces")
+	}
+}
+
+func TestToPublicKey(t *testing.T) {
+	f := FactorySECP256K1R{}
+
+	sk, err := f.NewPrivateKey()
+	assert.NoError(t, err)
+	pkBytes := sk.PublicKey().Bytes()
+
+	pk, err := f.ToPublicKey(pkBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, sk.PublicKey().Address(), pk.Address())
+
+	// Uncompressed keys would give the same key a second address
+	uncompressed := sk.(*PrivateKeySECP256K1R).sk.PubKey().SerializeUncompressed()
+	_, err = f.ToPublicKey(uncompressed)
+	assert.Error(t, err)
+
+	// The field prime isn't a valid x-coordinate
+	xTooBig, err := hex.DecodeString("02fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")
+	assert.NoError(t, err)
+	_, err = f.ToPublicKey(xTooBig)
+	assert.Error(t, err)
+
+	// x = 5 isn't on the curve, as 5^3 + 7 is a quadratic non-residue
+	notOnCurve := make([]byte, SECP256K1RPKLen)
+	notOnCurve[0] = 0x02
+	notOnCurve[SECP256K1RPKLen-1] = 5
+	_, err = f.ToPublicKey(notOnCurve)
+	assert.Error(t, err)
+}
+
+func TestToPublicKeyRandomBytes(t *testing.T) {
+	f := FactorySECP256K1R{}
+	r := rand.New(rand.NewSource(0))
+
+	for i := 0; i < 10000; i++ {
+		b := make([]byte, r.Intn(2*SECP256K1RPKLen+1))
+		_, _ = r.Read(b)
+		if len(b) > 0 && r.Intn(2) == 0 {
+			// Give half of the keys a valid prefix so that the point
+			// itself is checked
+			b[0] = 0x02 + byte(r.Intn(2))
+		}
+
+		pk, err := f.ToPublicKey(b)
+		switch {
+		case err != nil && pk != nil:
+			t.Fatalf("Returned a key along with the error %s", err)
+		case err != nil:
+		case len(b) != SECP256K1RPKLen:
+			t.Fatalf("Accepted a key of length %d", len(b))
+		case !bytes.Equal(pk.Bytes(), b):
+			t.Fatalf("Key bytes changed from %x to %x", b, pk.Bytes())
+		case !pk.(*PublicKeySECP256K1R).pk.IsOnCurve():
+			t.Fatalf("Accepted a key that isn't on the curve: %x", b)
+		}
+	}
+}
+
+func TestPrivateKeyVersionedBytes(t *testing.T) {
+	f := FactorySECP256K1R{}
+
+	skIntf, err := f.NewPrivateKey()
+	assert.NoError(t, err)
+	sk := skIntf.(*PrivateKeySECP256K1R)
+
+	versioned := sk.VersionedBytes()
+	assert.Len(t, versioned, SECP256K1RSKLen+1)
+	assert.Equal(t, SECP256K1RSKVersion, versioned[0])
+	assert.Equal(t, sk.Bytes(), versioned[1:])
+
+	// Both the legacy and the versioned formats should parse to the same key
+	for _, b := range [][]byte{sk.Bytes(), versioned} {
+		parsed, err := f.ToPrivateKey(b)
+		assert.NoError(t, err)
+		assert.Equal(t, sk.Bytes(), parsed.Bytes())
+		assert.Equal(t, versioned, parsed.(*PrivateKeySECP256K1R).VersionedBytes())
+		assert.Equal(t, sk.PublicKey().Address(), parsed.PublicKey().Address())
+	}
+
+	unknownVersion := append([]byte{SECP256K1RSKVersion + 1}, sk.Bytes()...)
+	_, err = f.ToPrivateKey(unknownVersion)
+	assert.Error(t, err)
+
+	_, err = f.ToPrivateKey(sk.Bytes()[1:])
+	assert.Error(t, err)
+}
+
+func TestNewPrivateKeyEntropy(t *testing.T) {
+	skBytes, err := hex.DecodeString("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The curve order isn't a valid key, so it must be skipped
+	invalidBytes, err := hex.DecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := FactorySECP256K1R{Entropy: bytes.NewReader(append(invalidBytes, skBytes...))}
+	sk, err := f.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sk.Bytes(), skBytes) {
+		t.Fatalf("Expected key 0x%x but got 0x%x", skBytes, sk.Bytes())
+	}
+
+	// The entropy has been exhausted
+	if _, err := f.NewPrivateKey(); !errors.Is(err, errInsufficientEntropy) {
+		t.Fatalf("Expected errInsufficientEntropy but got %v", err)
+	}
+
+	f.Entropy = bytes.NewReader(skBytes[:SECP256K1RSKLen-1])
+	if _, err := f.NewPrivateKey(); !errors.Is(err, errInsufficientEntropy) {
+		t.Fatalf("A short read shouldn't produce a key, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package forks
+
+import (
+	"time"
+)
+
+// Schedule maps the names of network upgrades to the times they activate, so
+// that every VM agrees on which rules apply at a given time.
+//
+// A nil *Schedule has no forks scheduled.
+type Schedule struct {
+	activations map[string]time.Time
+}
+
+// NewSchedule returns a schedule where the fork named [name] activates at
+// [activations][name]
+func NewSchedule(activations map[string]time.Time) *Schedule {
+	s := &Schedule{activations: make(map[string]time.Time, len(activations))}
+	for name, activation := range activations {
+		s.activations[name] = activation
+	}
+	return s
+}
+
+// ActivationTime returns the time the fork named [name] activates. Returns
+// false if the fork isn't scheduled.
+func (s *Schedule) ActivationTime(name string) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+	activation, ok := s.activations[name]
+	return activation, ok
+}
+
+// IsActivated returns true if the fork named [name] is active at time [t]. A
+// fork is active from its activation time onwards. Forks that aren't scheduled
+// are never active.
+func (s *Schedule) IsActivated(name string, t time.Time) bool {
+	activation, ok := s.ActivationTime(name)
+	return ok && !t.Before(activation)
+}
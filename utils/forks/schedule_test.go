@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package forks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleActivationBoundary(t *testing.T) {
+	activation := time.Unix(1600000000, 0)
+	s := NewSchedule(map[string]time.Time{"fork": activation})
+
+	if s.IsActivated("fork", activation.Add(-time.Nanosecond)) {
+		t.Fatalf("Fork shouldn't be active before its activation time")
+	}
+	if !s.IsActivated("fork", activation) {
+		t.Fatalf("Fork should be active at its activation time")
+	}
+	if !s.IsActivated("fork", activation.Add(time.Hour)) {
+		t.Fatalf("Fork should be active after its activation time")
+	}
+}
+
+func TestScheduleUnknownFork(t *testing.T) {
+	s := NewSchedule(map[string]time.Time{"fork": time.Unix(0, 0)})
+
+	if _, ok := s.ActivationTime("unknown"); ok {
+		t.Fatalf("Unknown fork shouldn't have an activation time")
+	}
+	if s.IsActivated("unknown", time.Now()) {
+		t.Fatalf("Unknown fork should never be active")
+	}
+
+	var nilSchedule *Schedule
+	if nilSchedule.IsActivated("fork", time.Now()) {
+		t.Fatalf("Nil schedule shouldn't have any active forks")
+	}
+}
+
+func TestScheduleCopiesActivations(t *testing.T) {
+	activations := map[string]time.Time{"fork": time.Unix(0, 0)}
+	s := NewSchedule(activations)
+	delete(activations, "fork")
+
+	if !s.IsActivated("fork", time.Now()) {
+		t.Fatalf("Schedule shouldn't be modified by changes to its input")
+	}
+}
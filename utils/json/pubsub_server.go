@@ -11,7 +11,7 @@ import (
 
 	"github.com/gorilla/websocket"
 
-	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/utils/logging"
 )
 
 const (
@@ -49,7 +49,7 @@ var (
 
 // PubSubServer maintains the set of active clients and sends messages to the clients.
 type PubSubServer struct {
-	ctx *snow.Context
+	log logging.Logger
 
 	lock     sync.Mutex
 	conns    map[*Connection]map[string]struct{}
@@ -57,9 +57,9 @@ type PubSubServer struct {
 }
 
 // NewPubSubServer ...
-func NewPubSubServer(ctx *snow.Context) *PubSubServer {
+func NewPubSubServer(log logging.Logger) *PubSubServer {
 	return &PubSubServer{
-		ctx:      ctx,
+		log:      log,
 		conns:    make(map[*Connection]map[string]struct{}),
 		channels: make(map[string]map[*Connection]struct{}),
 	}
@@ -68,7 +68,7 @@ func NewPubSubServer(ctx *snow.Context) *PubSubServer {
 func (s *PubSubServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	wsConn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		s.ctx.Log.Debug("Failed to upgrade %s", err)
+		s.log.Debug("Failed to upgrade %s", err)
 		return
 	}
 	conn := &Connection{s: s, conn: wsConn, send: make(chan interface{}, maxPendingMessages)}
@@ -82,7 +82,7 @@ func (s *PubSubServer) Publish(channel string, msg interface{}) {
 
 	conns, exists := s.channels[channel]
 	if !exists {
-		s.ctx.Log.Warn("attempted to publush to an unknown channel %s", channel)
+		s.log.Warn("attempted to publush to an unknown channel %s", channel)
 		return
 	}
 
@@ -95,7 +95,7 @@ func (s *PubSubServer) Publish(channel string, msg interface{}) {
 		select {
 		case conn.send <- pubMsg:
 		default:
-			s.ctx.Log.Verbo("dropping message to subscribed connection due to too many pending messages")
+			s.log.Verbo("dropping message to subscribed connection due to too many pending messages")
 		}
 	}
 }
@@ -128,13 +128,18 @@ func (s *PubSubServer) removeConnection(conn *Connection) {
 
 	channels, exists := s.conns[conn]
 	if !exists {
-		s.ctx.Log.Warn("attempted to remove an unknown connection")
+		s.log.Warn("attempted to remove an unknown connection")
 		return
 	}
 
 	for channel := range channels {
 		delete(s.channels[channel], conn)
 	}
+	delete(s.conns, conn)
+
+	// Stop the writePump. The connection was removed from every channel, so
+	// nothing will be published to it.
+	close(conn.send)
 }
 
 func (s *PubSubServer) addChannel(conn *Connection, channel string) {
@@ -221,7 +226,7 @@ func (c *Connection) readPump() {
 		err := c.conn.ReadJSON(&msg)
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				c.s.ctx.Log.Debug("Unexpected close in websockets: %s", err)
+				c.s.log.Debug("Unexpected close in websockets: %s", err)
 			}
 			break
 		}
@@ -250,7 +255,7 @@ func (c *Connection) writePump() {
 		select {
 		case message, ok := <-c.send:
 			if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
-				c.s.ctx.Log.Debug("failed to set the write deadline, closing the connection due to %s", err)
+				c.s.log.Debug("failed to set the write deadline, closing the connection due to %s", err)
 				return
 			}
 			if !ok {
@@ -265,7 +270,7 @@ func (c *Connection) writePump() {
 			}
 		case <-ticker.C:
 			if err := c.conn.SetWriteDeadline(time.Now().Add(writeWait)); err != nil {
-				c.s.ctx.Log.Debug("failed to set the write deadline, closing the connection due to %s", err)
+				c.s.log.Debug("failed to set the write deadline, closing the connection due to %s", err)
 				return
 			}
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
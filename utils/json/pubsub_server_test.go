@@ -0,0 +1,65 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package json
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestPubSubServerRemovesClosedConnections(t *testing.T) {
+	s := NewPubSubServer(logging.NoLog{})
+	if err := s.Register("accepted"); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.WriteJSON(&subscribe{Channel: "accepted"}); err != nil {
+		t.Fatal(err)
+	}
+
+	numSubscribers := func() int {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return len(s.channels["accepted"])
+	}
+	numConns := func() int {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return len(s.conns)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); numSubscribers() != 1; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Connection never subscribed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); numSubscribers() != 0 || numConns() != 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Closed connection was never removed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// Publishing after the connection was removed must not panic
+	s.Publish("accepted", "value")
+}
@@ -20,6 +20,9 @@ type Config struct {
 	LogLevel, DisplayLevel                                                                          Level
 	DisplayHighlight                                                                                Highlight
 	Directory, MsgPrefix                                                                            string
+	// RecentLines is the number of the most recently logged lines to retain in
+	// memory. If 0, no lines are retained.
+	RecentLines int
 }
 
 // DefaultConfig ...
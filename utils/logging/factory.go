@@ -4,69 +4,194 @@
 package logging
 
 import (
+	"fmt"
 	"path"
+	"sort"
+	"sync"
 )
 
+// MainLoggerName is the name of the logger returned by Factory.Make
+const MainLoggerName = "main"
+
 // Factory ...
 type Factory interface {
 	Make() (Logger, error)
 	MakeChain(chainID string, subdir string) (Logger, error)
 	MakeSubdir(subdir string) (Logger, error)
+
+	// SetLogLevels overrides the levels of the loggers named [name]
+	SetLogLevels(name string, logLevel, displayLevel Level) error
+	// ResetLogLevels removes the override of the loggers named [name], so
+	// they fall back to the default levels
+	ResetLogLevels(name string) error
+	// SetDefaultLogLevels sets the levels of every logger without an override
+	SetDefaultLogLevels(logLevel, displayLevel Level)
+	// GetLoggerNames returns the names of the loggers that have been made
+	GetLoggerNames() []string
+	// GetRecentLines returns the lines most recently logged by the loggers
+	// named [name], oldest first
+	GetRecentLines(name string) ([]string, error)
+
 	Close()
 }
 
+type levels struct{ log, display Level }
+
 // factory ...
 type factory struct {
+	lock   sync.Mutex
 	config Config
 
-	loggers []Logger
+	// logger name -> loggers with that name
+	loggers map[string][]Logger
+	// logger name -> levels that override the defaults in [config]
+	overrides map[string]levels
 }
 
 // NewFactory ...
 func NewFactory(config Config) Factory {
 	return &factory{
-		config: config,
+		config:    config,
+		loggers:   make(map[string][]Logger),
+		overrides: make(map[string]levels),
 	}
 }
 
 // Make ...
 func (f *factory) Make() (Logger, error) {
-	l, err := New(f.config)
-	if err == nil {
-		f.loggers = append(f.loggers, l)
-	}
-	return l, err
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.make(MainLoggerName, f.config)
 }
 
 // MakeChain ...
 func (f *factory) MakeChain(chainID string, subdir string) (Logger, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	config := f.config
 	config.MsgPrefix = chainID + " Chain"
 	config.Directory = path.Join(config.Directory, "chain", chainID, subdir)
-
-	log, err := New(config)
-	if err == nil {
-		f.loggers = append(f.loggers, log)
-	}
-	return log, err
+	return f.make(path.Join(chainID, subdir), config)
 }
 
 // MakeSubdir ...
 func (f *factory) MakeSubdir(subdir string) (Logger, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
 	config := f.config
 	config.Directory = path.Join(config.Directory, subdir)
+	return f.make(subdir, config)
+}
+
+// assumes the lock is held
+func (f *factory) make(name string, config Config) (Logger, error) {
+	if override, ok := f.overrides[name]; ok {
+		config.LogLevel = override.log
+		config.DisplayLevel = override.display
+	}
 
 	log, err := New(config)
 	if err == nil {
-		f.loggers = append(f.loggers, log)
+		f.loggers[name] = append(f.loggers[name], log)
 	}
 	return log, err
 }
 
+// SetLogLevels ...
+func (f *factory) SetLogLevels(name string, logLevel, displayLevel Level) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	loggers, ok := f.loggers[name]
+	if !ok {
+		return fmt.Errorf("unknown logger %q", name)
+	}
+	f.overrides[name] = levels{
+		log:     logLevel,
+		display: displayLevel,
+	}
+	setLevels(loggers, logLevel, displayLevel)
+	return nil
+}
+
+// ResetLogLevels ...
+func (f *factory) ResetLogLevels(name string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	loggers, ok := f.loggers[name]
+	if !ok {
+		return fmt.Errorf("unknown logger %q", name)
+	}
+	delete(f.overrides, name)
+	setLevels(loggers, f.config.LogLevel, f.config.DisplayLevel)
+	return nil
+}
+
+// SetDefaultLogLevels ...
+func (f *factory) SetDefaultLogLevels(logLevel, displayLevel Level) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.config.LogLevel = logLevel
+	f.config.DisplayLevel = displayLevel
+	for name, loggers := range f.loggers {
+		if _, overridden := f.overrides[name]; !overridden {
+			setLevels(loggers, logLevel, displayLevel)
+		}
+	}
+}
+
+// GetLoggerNames ...
+func (f *factory) GetLoggerNames() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	names := make([]string, 0, len(f.loggers))
+	for name := range f.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetRecentLines ...
+func (f *factory) GetRecentLines(name string) ([]string, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	loggers, ok := f.loggers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown logger %q", name)
+	}
+	lines := []string(nil)
+	for _, log := range loggers {
+		if recent, ok := log.(interface{ RecentLines() []string }); ok {
+			lines = append(lines, recent.RecentLines()...)
+		}
+	}
+	return lines, nil
+}
+
 // Close ...
 func (f *factory) Close() {
-	for _, log := range f.loggers {
-		log.Stop()
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, loggers := range f.loggers {
+		for _, log := range loggers {
+			log.Stop()
+		}
+	}
+	f.loggers = make(map[string][]Logger)
+}
+
+func setLevels(loggers []Logger, logLevel, displayLevel Level) {
+	for _, log := range loggers {
+		log.SetLogLevel(logLevel)
+		log.SetDisplayLevel(displayLevel)
 	}
-	f.loggers = nil
 }
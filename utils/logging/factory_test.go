@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package logging
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func assertLevels(t *testing.T, logger Logger, logLevel, displayLevel Level) {
+	log := logger.(*Log)
+	log.configLock.Lock()
+	defer log.configLock.Unlock()
+
+	if log.config.LogLevel != logLevel {
+		t.Fatalf("Expected log level %s but got %s", logLevel, log.config.LogLevel)
+	}
+	if log.config.DisplayLevel != displayLevel {
+		t.Fatalf("Expected display level %s but got %s", displayLevel, log.config.DisplayLevel)
+	}
+}
+
+func TestFactorySetLogLevels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config, err := DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Directory = dir
+	config.LogLevel = Info
+	config.DisplayLevel = Warn
+
+	f := NewFactory(config)
+	defer f.Close()
+
+	consensusLog, err := f.MakeChain("X", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	networkLog, err := f.Make()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.SetLogLevels("X", Debug, Debug); err != nil {
+		t.Fatal(err)
+	}
+	assertLevels(t, consensusLog, Debug, Debug)
+	assertLevels(t, networkLog, Info, Warn)
+
+	// Loggers made after the override should use it
+	laterLog, err := f.MakeChain("X", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertLevels(t, laterLog, Debug, Debug)
+
+	// Changing the defaults shouldn't modify overridden loggers
+	f.SetDefaultLogLevels(Error, Error)
+	assertLevels(t, consensusLog, Debug, Debug)
+	assertLevels(t, networkLog, Error, Error)
+
+	if err := f.ResetLogLevels("X"); err != nil {
+		t.Fatal(err)
+	}
+	assertLevels(t, consensusLog, Error, Error)
+
+	if err := f.SetLogLevels("unknown", Debug, Debug); err == nil {
+		t.Fatalf("Should have errored on an unknown logger")
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/ava-labs/gecko/utils"
 )
 
 // Log ...
@@ -28,6 +30,9 @@ type Log struct {
 	closed bool
 
 	writer RotatingWriter
+
+	// the most recently logged lines, or nil if they aren't retained
+	recent *utils.RingBuffer
 }
 
 // New ...
@@ -39,6 +44,9 @@ func New(config Config) (*Log, error) {
 		config: config,
 		writer: &fileWriter{},
 	}
+	if config.RecentLines > 0 {
+		l.recent = utils.NewRingBuffer(config.RecentLines)
+	}
 	l.needsFlush = sync.NewCond(&l.flushLock)
 
 	l.wg.Add(1)
@@ -51,20 +59,26 @@ func New(config Config) (*Log, error) {
 func (l *Log) run() {
 	defer l.wg.Done()
 
+	// The config may be modified while the log is running, so only read it
+	// with the config lock held
+	l.configLock.Lock()
+	config := l.config
+	l.configLock.Unlock()
+
 	l.writeLock.Lock()
 	defer l.writeLock.Unlock()
 
-	if err := l.writer.Initialize(l.config); err != nil {
+	if err := l.writer.Initialize(config); err != nil {
 		panic(err)
 	}
 
 	closed := false
-	nextRotation := time.Now().Add(l.config.RotationInterval)
+	nextRotation := time.Now().Add(config.RotationInterval)
 	currentSize := 0
 	for !closed {
 		l.writeLock.Unlock()
 		l.flushLock.Lock()
-		for l.size < l.config.FlushSize && !l.closed {
+		for l.size < config.FlushSize && !l.closed {
 			l.needsFlush.Wait()
 		}
 		closed = l.closed
@@ -79,13 +93,13 @@ func (l *Log) run() {
 			currentSize += n
 		}
 
-		if !l.config.DisableFlushOnWrite {
+		if !config.DisableFlushOnWrite {
 			// attempt to flush after the write
 			_ = l.writer.Flush()
 		}
 
-		if now := time.Now(); nextRotation.Before(now) || currentSize > l.config.FileSize {
-			nextRotation = now.Add(l.config.RotationInterval)
+		if now := time.Now(); nextRotation.Before(now) || currentSize > config.FileSize {
+			nextRotation = now.Add(config.RotationInterval)
 			currentSize = 0
 			// attempt to flush before closing
 			_ = l.writer.Flush()
@@ -144,6 +158,10 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 		l.size += len(output)
 		l.needsFlush.Signal()
 		l.flushLock.Unlock()
+
+		if l.recent != nil {
+			l.recent.Append(output)
+		}
 	}
 
 	if shouldDisplay {
@@ -179,6 +197,20 @@ func (l *Log) format(level Level, format string, args ...interface{}) string {
 		text)
 }
 
+// RecentLines returns the most recently logged lines, oldest first. Returns
+// nil if the logger wasn't configured to retain lines.
+func (l *Log) RecentLines() []string {
+	if l.recent == nil {
+		return nil
+	}
+	elements := l.recent.List()
+	lines := make([]string, len(elements))
+	for i, element := range elements {
+		lines[i] = element.(string)
+	}
+	return lines
+}
+
 // Fatal ...
 func (l *Log) Fatal(format string, args ...interface{}) { l.log(Fatal, format, args...) }
 
@@ -202,7 +234,7 @@ func (l *Log) AssertNoError(err error) {
 	if err != nil {
 		l.log(Fatal, "%s", err)
 	}
-	if l.config.Assertions && err != nil {
+	if l.assertionsEnabled() && err != nil {
 		l.Stop()
 		panic(err)
 	}
@@ -213,7 +245,7 @@ func (l *Log) AssertTrue(b bool, format string, args ...interface{}) {
 	if !b {
 		l.log(Fatal, format, args...)
 	}
-	if l.config.Assertions && !b {
+	if l.assertionsEnabled() && !b {
 		l.Stop()
 		panic(fmt.Sprintf(format, args...))
 	}
@@ -222,7 +254,7 @@ func (l *Log) AssertTrue(b bool, format string, args ...interface{}) {
 // AssertDeferredTrue ...
 func (l *Log) AssertDeferredTrue(f func() bool, format string, args ...interface{}) {
 	// Note, the logger will only be notified here if assertions are enabled
-	if l.config.Assertions && !f() {
+	if l.assertionsEnabled() && !f() {
 		err := fmt.Sprintf(format, args...)
 		l.log(Fatal, err)
 		l.Stop()
@@ -232,18 +264,26 @@ func (l *Log) AssertDeferredTrue(f func() bool, format string, args ...interface
 
 // AssertDeferredNoError ...
 func (l *Log) AssertDeferredNoError(f func() error) {
-	if l.config.Assertions {
+	if l.assertionsEnabled() {
 		err := f()
 		if err != nil {
 			l.log(Fatal, "%s", err)
 		}
-		if l.config.Assertions && err != nil {
+		if l.assertionsEnabled() && err != nil {
 			l.Stop()
 			panic(err)
 		}
 	}
 }
 
+// assertionsEnabled returns true if failed assertions should panic
+func (l *Log) assertionsEnabled() bool {
+	l.configLock.Lock()
+	defer l.configLock.Unlock()
+
+	return l.config.Assertions
+}
+
 // StopOnPanic ...
 func (l *Log) StopOnPanic() {
 	if r := recover(); r != nil {
@@ -1,6 +1,11 @@
 package logging
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
 
 func TestLog(t *testing.T) {
 	config, err := DefaultConfig()
@@ -26,3 +31,47 @@ func TestLog(t *testing.T) {
 		t.Fatalf("Exit function was never called")
 	}
 }
+
+func TestLogRecentLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config, err := DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Directory = dir
+	config.LogLevel = Info
+	config.DisableDisplaying = true
+	config.RecentLines = 2
+
+	f := NewFactory(config)
+	defer f.Close()
+
+	log, err := f.Make()
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Info("first")
+	log.Debug("filtered")
+	log.Info("second")
+	log.Warn("third")
+
+	lines, err := f.GetRecentLines(MainLoggerName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines but got %v", lines)
+	}
+	if !strings.Contains(lines[0], "second") || !strings.Contains(lines[1], "third") {
+		t.Fatalf("Expected the latest lines to be retained but got %v", lines)
+	}
+
+	if _, err := f.GetRecentLines("unknown"); err == nil {
+		t.Fatalf("Should have errored due to an unknown logger")
+	}
+}
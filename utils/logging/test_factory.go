@@ -17,3 +17,18 @@ func (NoFactory) MakeSubdir(string) (Logger, error) { return NoLog{}, nil }
 
 // Close ...
 func (NoFactory) Close() {}
+
+// SetLogLevels ...
+func (NoFactory) SetLogLevels(string, Level, Level) error { return nil }
+
+// ResetLogLevels ...
+func (NoFactory) ResetLogLevels(string) error { return nil }
+
+// SetDefaultLogLevels ...
+func (NoFactory) SetDefaultLogLevels(Level, Level) {}
+
+// GetLoggerNames ...
+func (NoFactory) GetLoggerNames() []string { return nil }
+
+// GetRecentLines ...
+func (NoFactory) GetRecentLines(string) ([]string, error) { return nil, nil }
@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"sync"
+)
+
+// RingBuffer retains the most recent elements appended to it, evicting the
+// oldest element once it is full. It is safe for concurrent use.
+type RingBuffer struct {
+	lock     sync.Mutex
+	elements []interface{}
+	// index in [elements] of the oldest element, once [elements] is full
+	next int
+	size int
+}
+
+// NewRingBuffer returns a buffer that retains at most [size] elements. If
+// [size] isn't positive, no elements are retained.
+func NewRingBuffer(size int) *RingBuffer {
+	if size < 0 {
+		size = 0
+	}
+	return &RingBuffer{
+		elements: make([]interface{}, 0, size),
+		size:     size,
+	}
+}
+
+// Append [element] to the buffer. If the buffer was full, the oldest element
+// is evicted and returned.
+func (b *RingBuffer) Append(element interface{}) (interface{}, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch {
+	case b.size == 0:
+		return element, true
+	case len(b.elements) < b.size:
+		b.elements = append(b.elements, element)
+		return nil, false
+	default:
+		evicted := b.elements[b.next]
+		b.elements[b.next] = element
+		b.next = (b.next + 1) % b.size
+		return evicted, true
+	}
+}
+
+// List returns the retained elements, oldest first
+func (b *RingBuffer) List() []interface{} {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	elements := make([]interface{}, 0, len(b.elements))
+	elements = append(elements, b.elements[b.next:]...)
+	return append(elements, b.elements[:b.next]...)
+}
+
+// Len returns the number of retained elements
+func (b *RingBuffer) Len() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.elements)
+}
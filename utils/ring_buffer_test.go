@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package utils
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRingBufferRetainsLatest(t *testing.T) {
+	b := NewRingBuffer(3)
+	if elements := b.List(); len(elements) != 0 {
+		t.Fatalf("Expected no elements but got %v", elements)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, evicted := b.Append(i); evicted {
+			t.Fatalf("Shouldn't have evicted an element before the buffer was full")
+		}
+	}
+	for i := 3; i < 5; i++ {
+		evicted, ok := b.Append(i)
+		if !ok {
+			t.Fatalf("Should have evicted an element once the buffer was full")
+		}
+		if evicted != i-3 {
+			t.Fatalf("Expected %d to be evicted but was %v", i-3, evicted)
+		}
+	}
+
+	elements := b.List()
+	expected := []int{2, 3, 4}
+	if len(elements) != len(expected) {
+		t.Fatalf("Expected %d elements but got %d", len(expected), len(elements))
+	}
+	for i, element := range elements {
+		if element != expected[i] {
+			t.Fatalf("Expected element %d to be %d but was %v", i, expected[i], element)
+		}
+	}
+	if l := b.Len(); l != len(expected) {
+		t.Fatalf("Expected length %d but got %d", len(expected), l)
+	}
+}
+
+func TestRingBufferEmpty(t *testing.T) {
+	b := NewRingBuffer(0)
+	if evicted, ok := b.Append(1); !ok || evicted != 1 {
+		t.Fatalf("An empty buffer should evict the appended element")
+	}
+	if elements := b.List(); len(elements) != 0 {
+		t.Fatalf("Expected no elements but got %v", elements)
+	}
+}
+
+func TestRingBufferConcurrentAppend(t *testing.T) {
+	size := 10
+	b := NewRingBuffer(size)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Append(i*100 + j)
+				_ = b.List()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if elements := b.List(); len(elements) != size {
+		t.Fatalf("Expected %d elements but got %d", size, len(elements))
+	}
+}
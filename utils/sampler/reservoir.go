@@ -0,0 +1,61 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sampler
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Reservoir samples a fixed number of indices uniformly, without replacement,
+// from a stream of indices of unknown length.
+//
+// Sampling is performed using Algorithm R. The first [size] indices are always
+// kept. The i-th index after that replaces a random kept index with
+// probability size/i.
+//
+// Add is performed in O(1) time.
+//
+// Memory usage is O(size).
+type Reservoir struct {
+	size      int
+	numSeen   int
+	reservoir []int
+	rng       *rand.Rand
+}
+
+// NewReservoir returns a new reservoir sampler that will keep [size] indices
+func NewReservoir(size int) (*Reservoir, error) {
+	if size < 0 {
+		return nil, errOutOfRange
+	}
+	return &Reservoir{
+		size:      size,
+		reservoir: make([]int, 0, size),
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// Seed the source of randomness so that the sampling is deterministic
+func (r *Reservoir) Seed(seed int64) { r.rng.Seed(seed) }
+
+// Add [index] to the stream
+func (r *Reservoir) Add(index int) {
+	r.numSeen++
+	if len(r.reservoir) < r.size {
+		r.reservoir = append(r.reservoir, index)
+		return
+	}
+	if j := r.rng.Intn(r.numSeen); j < r.size {
+		r.reservoir[j] = index
+	}
+}
+
+// Sample returns the indices that have been selected from the stream so far.
+// If fewer than size indices have been added, all of them are returned.
+func (r *Reservoir) Sample() []int {
+	sample := make([]int, len(r.reservoir))
+	copy(sample, r.reservoir)
+	return sample
+}
@@ -0,0 +1,79 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package sampler
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReservoirNegativeSize(t *testing.T) {
+	_, err := NewReservoir(-1)
+	assert.Error(t, err, "should have reported an out of range error")
+}
+
+func TestReservoirShortStream(t *testing.T) {
+	r, err := NewReservoir(5)
+	assert.NoError(t, err)
+
+	assert.Len(t, r.Sample(), 0, "shouldn't have selected any element")
+
+	r.Add(2)
+	r.Add(0)
+	r.Add(1)
+
+	sample := r.Sample()
+	sort.Ints(sample)
+	assert.Equal(t, []int{0, 1, 2}, sample, "should have selected every element")
+}
+
+func TestReservoirDeterministic(t *testing.T) {
+	r0, err := NewReservoir(3)
+	assert.NoError(t, err)
+	r1, err := NewReservoir(3)
+	assert.NoError(t, err)
+
+	r0.Seed(1)
+	r1.Seed(1)
+	for i := 0; i < 100; i++ {
+		r0.Add(i)
+		r1.Add(i)
+	}
+	assert.Equal(t, r0.Sample(), r1.Sample(), "should have selected the same elements")
+}
+
+func TestReservoirDistribution(t *testing.T) {
+	streamLength := 100
+	size := 10
+	numTrials := 20000
+
+	counts := make([]int, streamLength)
+	for trial := 0; trial < numTrials; trial++ {
+		r, err := NewReservoir(size)
+		assert.NoError(t, err)
+		r.Seed(int64(trial))
+
+		for i := 0; i < streamLength; i++ {
+			r.Add(i)
+		}
+
+		sample := r.Sample()
+		assert.Len(t, sample, size)
+		for _, index := range sample {
+			counts[index]++
+		}
+	}
+
+	// Each index should be selected with probability size/streamLength
+	expected := float64(numTrials*size) / float64(streamLength)
+	stdDev := math.Sqrt(expected * (1 - float64(size)/float64(streamLength)))
+	for index, count := range counts {
+		if math.Abs(float64(count)-expected) > 5*stdDev {
+			t.Fatalf("index %d was selected %d times but expected ~%.0f", index, count, expected)
+		}
+	}
+}
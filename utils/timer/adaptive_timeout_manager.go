@@ -5,10 +5,13 @@ package timer
 
 import (
 	"container/heap"
+	"context"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -18,6 +21,9 @@ type adaptiveTimeout struct {
 	handler  func()        // Function to execute if timed out
 	duration time.Duration // How long this timeout was set for
 	deadline time.Time     // When this timeout should be fired
+	extended time.Duration // How much later than [duration] the deadline is
+	category string        // Category of this timeout, if the manager has categories
+	failed   bool          // True if this timeout was expired early by Fail
 }
 
 // A timeoutQueue implements heap.Interface and holds adaptiveTimeouts.
@@ -47,27 +53,59 @@ func (tq *timeoutQueue) Pop() interface{} {
 	return item
 }
 
+// DecreaseWeight returns how much of the decrease value to subtract from the
+// current timeout duration when a request succeeds. [fraction] is the portion
+// of the request's timeout that elapsed before it succeeded, in [0, 1]. The
+// returned weight should be in [0, 1].
+type DecreaseWeight func(fraction float64) float64
+
+// StepDecreaseWeight decreases the timeout duration by the full decrease value
+// regardless of how long the request took
+func StepDecreaseWeight(float64) float64 { return 1 }
+
+// LinearDecreaseWeight decreases the timeout duration more for requests that
+// finished quickly. Requests that finish just before their deadline barely
+// decrease the timeout duration.
+func LinearDecreaseWeight(fraction float64) float64 { return 1 - fraction }
+
+// durationChange records a transition of the current timeout duration
+type durationChange struct{ old, new time.Duration }
+
 // AdaptiveTimeoutManager is a manager for timeouts.
 type AdaptiveTimeoutManager struct {
 	currentDurationMetric prometheus.Gauge
-
-	minimumDuration time.Duration
-	increaseRatio   float64
-	decreaseValue   time.Duration
-
+	numTimeoutsMetric     prometheus.Counter
+	numSuccessesMetric    prometheus.Counter
+
+	minimumDuration  time.Duration
+	increaseRatio    float64
+	decreaseValue    time.Duration
+	onDurationChange func(old, new time.Duration)
+	decreaseWeight   DecreaseWeight
+	parent           *AdaptiveTimeoutManager
+	batchWindow      time.Duration
+	batchHandler     func(handlers []func())
+
+	clock           MonotonicClock
 	lock            sync.Mutex
 	currentDuration time.Duration // Amount of time before a timeout
+	frozenUntil     time.Time     // The duration isn't adapted before this
+	durationChanges []durationChange
 	timeoutMap      map[[32]byte]*adaptiveTimeout
 	timeoutQueue    timeoutQueue
 	timer           *Timer // Timer that will fire to clear the timeouts
+	shutdown        bool   // If true, no more timeouts will be added
 }
 
 // Initialize is a constructor b/c Golang, in its wisdom, doesn't ... have them?
+// If [onDurationChange] is non-nil, it is called, without the lock held,
+// every time the current timeout duration changes.
 func (tm *AdaptiveTimeoutManager) Initialize(
 	initialDuration time.Duration,
 	minimumDuration time.Duration,
 	increaseRatio float64,
 	decreaseValue time.Duration,
+	onDurationChange func(old, new time.Duration),
 	namespace string,
 	registerer prometheus.Registerer,
 ) error {
@@ -76,13 +114,90 @@ func (tm *AdaptiveTimeoutManager) Initialize(
 		Name:      "network_timeout",
 		Help:      "Duration of current network timeouts in nanoseconds",
 	})
+	tm.numTimeoutsMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "network_timeouts",
+		Help:      "Number of requests that were removed after timing out",
+	})
+	tm.numSuccessesMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "network_successes",
+		Help:      "Number of requests that were removed before timing out",
+	})
 	tm.minimumDuration = minimumDuration
 	tm.increaseRatio = increaseRatio
 	tm.decreaseValue = decreaseValue
+	tm.onDurationChange = onDurationChange
+	tm.decreaseWeight = StepDecreaseWeight
 	tm.currentDuration = initialDuration
 	tm.timeoutMap = make(map[[32]byte]*adaptiveTimeout)
 	tm.timer = NewTimer(tm.Timeout)
-	return registerer.Register(tm.currentDurationMetric)
+	// Deadlines shouldn't be affected by the wall clock being stepped
+	tm.clock.Start()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(tm.currentDurationMetric),
+		registerer.Register(tm.numTimeoutsMetric),
+		registerer.Register(tm.numSuccessesMetric),
+	)
+	return errs.Err
+}
+
+// SetDecreaseWeight sets the function that weights how much a successful
+// request decreases the timeout duration. Defaults to StepDecreaseWeight.
+func (tm *AdaptiveTimeoutManager) SetDecreaseWeight(decreaseWeight DecreaseWeight) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.decreaseWeight = decreaseWeight
+}
+
+// SetBatching makes the manager fire timeouts in batches. When a timeout
+// fires, every timeout due within [window] of the current time is removed at
+// once, and the handlers are then called in a single pass without the lock
+// held. Timeouts may therefore fire up to [window] early. If [handler] is
+// non-nil, it is called once per batch with the handlers of the timed out
+// requests, and is responsible for calling them. A zero [window] disables
+// batching, which is the default.
+func (tm *AdaptiveTimeoutManager) SetBatching(window time.Duration, handler func(handlers []func())) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.batchWindow = window
+	tm.batchHandler = handler
+}
+
+// SetParent makes this manager never time out requests sooner than [parent]
+// currently would. That is, the minimum duration of this manager becomes the
+// larger of its own minimum and the parent's current duration. [parent] must
+// not, directly or indirectly, have this manager as a parent.
+func (tm *AdaptiveTimeoutManager) SetParent(parent *AdaptiveTimeoutManager) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.parent = parent
+}
+
+// CurrentDuration returns the duration that new timeouts are currently set for
+func (tm *AdaptiveTimeoutManager) CurrentDuration() time.Duration {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	return tm.currentDuration
+}
+
+// FreezeAdaptation stops the current timeout duration from being adapted for
+// [d]. Timeouts are still fired and removed as usual, but neither timeouts nor
+// successes change the duration. This should be used when the clock is known
+// to have jumped, so that the requests that appear to have timed out because
+// of the jump don't inflate the duration. Freezing again replaces the previous
+// window.
+func (tm *AdaptiveTimeoutManager) FreezeAdaptation(d time.Duration) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.frozenUntil = tm.clock.Time().Add(d)
 }
 
 // Dispatch ...
@@ -91,12 +206,73 @@ func (tm *AdaptiveTimeoutManager) Dispatch() { tm.timer.Dispatch() }
 // Stop executing timeouts
 func (tm *AdaptiveTimeoutManager) Stop() { tm.timer.Stop() }
 
-// Put puts hash into the hash map
+// ShutdownAndFire immediately calls the handler of every outstanding timeout
+// and then stops the timer. After this is called, Put is a no-op. Dispatch
+// must have been called before this is called.
+func (tm *AdaptiveTimeoutManager) ShutdownAndFire() {
+	tm.lock.Lock()
+	tm.shutdown = true
+	for tm.timeoutQueue.Len() > 0 {
+		timeout := heap.Pop(&tm.timeoutQueue).(*adaptiveTimeout)
+		delete(tm.timeoutMap, timeout.id.Key())
+
+		// Don't execute a callback with a lock held
+		tm.lock.Unlock()
+		timeout.handler()
+		tm.lock.Lock()
+	}
+	tm.lock.Unlock()
+
+	// The lock must be released before stopping the timer, as the dispatch
+	// thread may be waiting on it in [Timeout]
+	tm.timer.Stop()
+}
+
+// Put puts hash into the hash map. If the manager has been shutdown, this is a
+// no-op and the zero time is returned.
 func (tm *AdaptiveTimeoutManager) Put(id ids.ID, handler func()) time.Time {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
 
-	return tm.put(id, handler)
+	if tm.shutdown {
+		return time.Time{}
+	}
+
+	deadline := tm.put(id, handler).deadline
+	tm.flushDurationChanges()
+	return deadline
+}
+
+// PutCancellable is Put, but also returns a function that removes the
+// timeout, as Remove would. The function may be called any number of times.
+// Once the timeout has fired or been removed, calling it is a no-op, even if
+// [id] has since been put again.
+func (tm *AdaptiveTimeoutManager) PutCancellable(id ids.ID, handler func()) (time.Time, func()) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	if tm.shutdown {
+		return time.Time{}, func() {}
+	}
+
+	// The timeout must be taken from put, as flushDurationChanges releases
+	// the lock, during which [id] may be removed or put again
+	timeout := tm.put(id, handler)
+	deadline := timeout.deadline
+	tm.flushDurationChanges()
+
+	key := id.Key()
+	return deadline, func() {
+		tm.lock.Lock()
+		defer tm.lock.Unlock()
+
+		// Only remove the timeout this call put
+		if tm.timeoutMap[key] != timeout {
+			return
+		}
+		tm.remove(id, tm.clock.Time())
+		tm.flushDurationChanges()
+	}
 }
 
 // Remove the item that no longer needs to be there.
@@ -104,9 +280,96 @@ func (tm *AdaptiveTimeoutManager) Remove(id ids.ID) {
 	tm.lock.Lock()
 	defer tm.lock.Unlock()
 
-	currentTime := time.Now()
+	currentTime := tm.clock.Time()
 
 	tm.remove(id, currentTime)
+	tm.flushDurationChanges()
+}
+
+// Fail expires the outstanding timeout for [id] now, rather than waiting for
+// its deadline. This should be used when a request is known to have failed, for
+// example because it couldn't be sent. The handler is called by the dispatch
+// goroutine, as for any other expired timeout, so it never runs on the
+// caller's goroutine. The failure says nothing about how long requests take, so
+// the adaptive timeout duration isn't affected. Returns false if there is no
+// outstanding timeout for [id].
+func (tm *AdaptiveTimeoutManager) Fail(id ids.ID) bool {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	timeout, exists := tm.timeoutMap[id.Key()]
+	if !exists || timeout.failed {
+		return false
+	}
+	timeout.failed = true
+	timeout.deadline = tm.clock.Time()
+	heap.Fix(&tm.timeoutQueue, timeout.index)
+
+	tm.registerTimeout()
+	return true
+}
+
+// Extend pushes the deadline of the outstanding timeout for [id] back by [by].
+// The extension doesn't count as a timeout or a success, so the adaptive
+// timeout duration isn't affected. Returns false if there is no outstanding
+// timeout for [id].
+func (tm *AdaptiveTimeoutManager) Extend(id ids.ID, by time.Duration) bool {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	timeout, exists := tm.timeoutMap[id.Key()]
+	if !exists || timeout.failed {
+		return false
+	}
+	timeout.deadline = timeout.deadline.Add(by)
+	timeout.extended += by
+	heap.Fix(&tm.timeoutQueue, timeout.index)
+
+	tm.registerTimeout()
+	return true
+}
+
+// Context returns a context that is cancelled when the timeout for [id] fires.
+// Calling the returned cancel func removes the timeout, as Remove would, and
+// cancels the context. The context's deadline is the current deadline of the
+// timeout, so it moves back if the timeout is extended. If the manager has
+// been shutdown, the returned context is already cancelled.
+func (tm *AdaptiveTimeoutManager) Context(id ids.ID) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	tm.lock.Lock()
+	if tm.shutdown {
+		tm.lock.Unlock()
+		cancel()
+		return ctx, cancel
+	}
+	timeout := tm.put(id, cancel)
+	tm.flushDurationChanges()
+	tm.lock.Unlock()
+
+	return timeoutContext{Context: ctx, tm: tm, timeout: timeout}, func() {
+		// If the timeout already fired, [id] may have been put again since,
+		// so it must not be removed
+		if ctx.Err() == nil {
+			tm.Remove(id)
+		}
+		cancel()
+	}
+}
+
+// timeoutContext reports the deadline of a timeout. The deadline is enforced
+// by the manager rather than the context, as the manager's clock may be faked.
+type timeoutContext struct {
+	context.Context
+	tm      *AdaptiveTimeoutManager
+	timeout *adaptiveTimeout
+}
+
+func (ctx timeoutContext) Deadline() (time.Time, bool) {
+	ctx.tm.lock.Lock()
+	defer ctx.tm.lock.Unlock()
+
+	return ctx.timeout.deadline, true
 }
 
 // Timeout registers a timeout
@@ -118,7 +381,12 @@ func (tm *AdaptiveTimeoutManager) Timeout() {
 }
 
 func (tm *AdaptiveTimeoutManager) timeout() {
-	currentTime := time.Now()
+	currentTime := tm.clock.Time()
+	if tm.batchWindow > 0 {
+		tm.timeoutBatch(currentTime.Add(tm.batchWindow))
+		return
+	}
+
 	// removeExpiredHead returns nil once there is nothing left to remove
 	for {
 		timeout := tm.removeExpiredHead(currentTime)
@@ -126,6 +394,8 @@ func (tm *AdaptiveTimeoutManager) timeout() {
 			break
 		}
 
+		tm.flushDurationChanges()
+
 		// Don't execute a callback with a lock held
 		tm.lock.Unlock()
 		timeout()
@@ -134,10 +404,50 @@ func (tm *AdaptiveTimeoutManager) timeout() {
 	tm.registerTimeout()
 }
 
-func (tm *AdaptiveTimeoutManager) put(id ids.ID, handler func()) time.Time {
-	currentTime := time.Now()
+// timeoutBatch removes every timeout due by [batchTime] and then calls their
+// handlers in one pass. Assumes the lock is held.
+func (tm *AdaptiveTimeoutManager) timeoutBatch(batchTime time.Time) {
+	handlers := []func(){}
+	for {
+		handler := tm.removeExpiredHead(batchTime)
+		if handler == nil {
+			break
+		}
+		handlers = append(handlers, handler)
+	}
+	tm.registerTimeout()
+	tm.flushDurationChanges()
+
+	if len(handlers) == 0 {
+		return
+	}
+
+	// Don't execute callbacks with the lock held
+	batchHandler := tm.batchHandler
+	tm.lock.Unlock()
+	defer tm.lock.Lock()
+
+	if batchHandler != nil {
+		batchHandler(handlers)
+		return
+	}
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+func (tm *AdaptiveTimeoutManager) put(id ids.ID, handler func()) *adaptiveTimeout {
+	currentTime := tm.clock.Time()
 	tm.remove(id, currentTime)
 
+	// The parent's duration may have grown since this manager last adjusted
+	// its own
+	oldDuration := tm.currentDuration
+	if minimum := tm.minimum(); tm.currentDuration < minimum {
+		tm.currentDuration = minimum
+		tm.recordDurationChange(oldDuration)
+	}
+
 	timeout := &adaptiveTimeout{
 		id:       id,
 		handler:  handler,
@@ -148,7 +458,7 @@ func (tm *AdaptiveTimeoutManager) put(id ids.ID, handler func()) time.Time {
 	heap.Push(&tm.timeoutQueue, timeout)
 
 	tm.registerTimeout()
-	return timeout.deadline
+	return timeout
 }
 
 func (tm *AdaptiveTimeoutManager) remove(id ids.ID, currentTime time.Time) {
@@ -158,35 +468,132 @@ func (tm *AdaptiveTimeoutManager) remove(id ids.ID, currentTime time.Time) {
 		return
 	}
 
+	if timeout.failed {
+		// A failed request says nothing about how long requests take
+		delete(tm.timeoutMap, key)
+		heap.Remove(&tm.timeoutQueue, timeout.index)
+		return
+	}
+
+	oldDuration := tm.currentDuration
+	newDuration, timedOut := adaptDuration(
+		tm.currentDuration,
+		timeout,
+		currentTime,
+		tm.increaseRatio,
+		tm.decreaseValue,
+		tm.decreaseWeight,
+		tm.minimum,
+	)
+	if currentTime.Before(tm.frozenUntil) {
+		newDuration = oldDuration
+	}
+	tm.currentDuration = newDuration
+	if timedOut {
+		tm.numTimeoutsMetric.Inc()
+	} else {
+		tm.numSuccessesMetric.Inc()
+	}
+
+	tm.recordDurationChange(oldDuration)
+
+	// Remove the timeout from the map
+	delete(tm.timeoutMap, key)
+
+	// Remove the timeout from the queue
+	heap.Remove(&tm.timeoutQueue, timeout.index)
+}
+
+// adaptDuration returns the duration that new timeouts should be set for,
+// given that [timeout] was removed at [currentTime] while new timeouts were
+// set for [current]. Also returns true if [timeout] was removed because it
+// timed out.
+func adaptDuration(
+	current time.Duration,
+	timeout *adaptiveTimeout,
+	currentTime time.Time,
+	increaseRatio float64,
+	decreaseValue time.Duration,
+	decreaseWeight DecreaseWeight,
+	minimum func() time.Duration,
+) (time.Duration, bool) {
 	if timeout.deadline.Before(currentTime) {
 		// This request is being removed because it timed out.
-		if timeout.duration >= tm.currentDuration {
+		if timeout.duration >= current {
 			// If the current timeout duration is less than or equal to the
 			// timeout that was triggered, double the duration.
-			tm.currentDuration = time.Duration(float64(tm.currentDuration) * tm.increaseRatio)
+			current = time.Duration(float64(current) * increaseRatio)
 		}
-	} else {
-		// This request is being removed because it finished successfully.
-		if timeout.duration <= tm.currentDuration {
-			// If the current timeout duration is greater than or equal to the
-			// timeout that was fullfilled, reduce future timeouts.
-			tm.currentDuration -= tm.decreaseValue
-
-			if tm.currentDuration < tm.minimumDuration {
-				// Make sure that we never get stuck in a bad situation
-				tm.currentDuration = tm.minimumDuration
-			}
+		return current, true
+	}
+
+	// This request is being removed because it finished successfully.
+	if timeout.duration <= current {
+		// If the current timeout duration is greater than or equal to the
+		// timeout that was fullfilled, reduce future timeouts. The
+		// reduction is weighted by how quickly the request finished.
+		start := timeout.deadline.Add(-timeout.duration - timeout.extended)
+		elapsed := currentTime.Sub(start)
+		fraction := 1.
+		if timeout.duration > 0 {
+			fraction = float64(elapsed) / float64(timeout.duration)
+		}
+		weight := decreaseWeight(math.Max(0, math.Min(1, fraction)))
+		weight = math.Max(0, math.Min(1, weight))
+		current -= time.Duration(weight * float64(decreaseValue))
+
+		if minimum := minimum(); current < minimum {
+			// Make sure that we never get stuck in a bad situation
+			current = minimum
 		}
 	}
+	return current, false
+}
 
+// minimum returns the smallest duration that timeouts may currently be set
+// for. Assumes the lock is held.
+func (tm *AdaptiveTimeoutManager) minimum() time.Duration {
+	if tm.parent == nil {
+		return tm.minimumDuration
+	}
+	// Locks are only ever acquired from child to parent, so this can't
+	// deadlock
+	if parentDuration := tm.parent.CurrentDuration(); parentDuration > tm.minimumDuration {
+		return parentDuration
+	}
+	return tm.minimumDuration
+}
+
+// recordDurationChange updates the metrics and queues a notification if the
+// current duration is no longer [oldDuration]. Assumes the lock is held.
+func (tm *AdaptiveTimeoutManager) recordDurationChange(oldDuration time.Duration) {
 	// Make sure the metrics report the current timeouts
 	tm.currentDurationMetric.Set(float64(tm.currentDuration))
 
-	// Remove the timeout from the map
-	delete(tm.timeoutMap, key)
+	if tm.onDurationChange != nil && tm.currentDuration != oldDuration {
+		tm.durationChanges = append(tm.durationChanges, durationChange{
+			old: oldDuration,
+			new: tm.currentDuration,
+		})
+	}
+}
 
-	// Remove the timeout from the queue
-	heap.Remove(&tm.timeoutQueue, timeout.index)
+// flushDurationChanges reports the duration changes that have occurred since
+// the last flush. Assumes the lock is held. The lock is released while the
+// callback is executed so that the callback may call back into the manager.
+func (tm *AdaptiveTimeoutManager) flushDurationChanges() {
+	changes := tm.durationChanges
+	if len(changes) == 0 {
+		return
+	}
+	tm.durationChanges = nil
+
+	tm.lock.Unlock()
+	defer tm.lock.Lock()
+
+	for _, change := range changes {
+		tm.onDurationChange(change.old, change.new)
+	}
 }
 
 // Returns true if the head was removed, false otherwise
@@ -210,7 +617,7 @@ func (tm *AdaptiveTimeoutManager) registerTimeout() {
 		return
 	}
 
-	currentTime := time.Now()
+	currentTime := tm.clock.Time()
 	nextTimeout := tm.timeoutQueue[0]
 	timeToNextTimeout := nextTimeout.deadline.Sub(currentTime)
 	tm.timer.SetTimeoutIn(timeToNextTimeout)
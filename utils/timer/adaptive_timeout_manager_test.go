@@ -4,11 +4,13 @@
 package timer
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"github.com/ava-labs/gecko/ids"
 )
@@ -20,6 +22,7 @@ func TestAdaptiveTimeoutManager(t *testing.T) {
 		time.Millisecond,         // minimumDuration
 		2,                        // increaseRatio
 		time.Microsecond,         // decreaseValue
+		nil,                      // onDurationChange
 		"gecko",                  // namespace
 		prometheus.NewRegistry(), // registerer
 	)
@@ -54,3 +57,970 @@ func TestAdaptiveTimeoutManager(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestAdaptiveTimeoutManagerOnDurationChange(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+
+	var lock sync.Mutex
+	changes := []durationChange(nil)
+	onDurationChange := func(old, new time.Duration) {
+		// Re-entering the manager must not deadlock
+		tm.Remove(ids.Empty)
+
+		lock.Lock()
+		defer lock.Unlock()
+
+		changes = append(changes, durationChange{old: old, new: new})
+	}
+
+	tm.Initialize(
+		2*time.Millisecond,       // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		onDurationChange,         // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	// Succeeding decreases the duration from 2ms to 1ms
+	tm.Put(ids.NewID([32]byte{1}), func() {})
+	tm.Remove(ids.NewID([32]byte{1}))
+
+	// The duration is already at the minimum, so this isn't a transition
+	tm.Put(ids.NewID([32]byte{2}), func() {})
+	tm.Remove(ids.NewID([32]byte{2}))
+
+	// Timing out increases the duration from 1ms to 2ms
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	tm.Put(ids.NewID([32]byte{3}), wg.Done)
+	wg.Wait()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	expected := []durationChange{
+		{old: 2 * time.Millisecond, new: time.Millisecond},
+		{old: time.Millisecond, new: 2 * time.Millisecond},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d duration changes but got %d", len(expected), len(changes))
+	}
+	for i, change := range changes {
+		if change != expected[i] {
+			t.Fatalf("Expected change %d to be %v but got %v", i, expected[i], change)
+		}
+	}
+}
+
+func TestAdaptiveTimeoutManagerOutcomeMetrics(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	tm.Initialize(
+		time.Millisecond,         // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Microsecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	numSuccesses := 3
+	for i := 0; i < numSuccesses; i++ {
+		tm.Put(ids.NewID([32]byte{1, byte(i)}), func() {})
+		tm.Remove(ids.NewID([32]byte{1, byte(i)}))
+	}
+
+	numTimeouts := 2
+	wg := sync.WaitGroup{}
+	wg.Add(numTimeouts)
+	for i := 0; i < numTimeouts; i++ {
+		tm.Put(ids.NewID([32]byte{2, byte(i)}), wg.Done)
+	}
+	wg.Wait()
+
+	// Removing an unknown request shouldn't be counted
+	tm.Remove(ids.NewID([32]byte{3}))
+
+	if successes := testutil.ToFloat64(tm.numSuccessesMetric); successes != float64(numSuccesses) {
+		t.Fatalf("Expected %d successes but got %f", numSuccesses, successes)
+	}
+	if timeouts := testutil.ToFloat64(tm.numTimeoutsMetric); timeouts != float64(numTimeouts) {
+		t.Fatalf("Expected %d timeouts but got %f", numTimeouts, timeouts)
+	}
+}
+
+func TestAdaptiveTimeoutManagerShutdownAndFire(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Hour,                // initialDuration
+		time.Hour,                // minimumDuration
+		2,                        // increaseRatio
+		time.Microsecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go tm.Dispatch()
+
+	numTimeouts := 5
+	calls := make([]int, numTimeouts)
+	for i := 0; i < numTimeouts; i++ {
+		i := i
+		tm.Put(ids.NewID([32]byte{byte(i)}), func() {
+			calls[i]++
+			// Adding a timeout while shutting down should be ignored
+			tm.Put(ids.NewID([32]byte{byte(numTimeouts + i)}), func() {
+				t.Fatalf("Timeout added during shutdown was fired")
+			})
+		})
+	}
+
+	tm.ShutdownAndFire()
+
+	for i, numCalls := range calls {
+		if numCalls != 1 {
+			t.Fatalf("Handler %d was called %d times but should have been called once", i, numCalls)
+		}
+	}
+
+	if deadline := tm.Put(ids.NewID([32]byte{byte(2 * numTimeouts)}), func() {
+		t.Fatalf("Timeout added after shutdown was fired")
+	}); !deadline.IsZero() {
+		t.Fatalf("Put after shutdown should be a no-op")
+	}
+	if tm.timeoutQueue.Len() != 0 {
+		t.Fatalf("Timeouts were added after shutdown")
+	}
+}
+
+// simulateConstantLatency issues [numRequests] sequential requests that each
+// take [latency] to finish, if they don't time out first. Returns the number of
+// timeouts and the final timeout duration.
+func simulateConstantLatency(t *testing.T, decreaseWeight DecreaseWeight, latency time.Duration, numRequests int) (int, time.Duration) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		10*time.Millisecond,      // minimumDuration
+		2,                        // increaseRatio
+		50*time.Millisecond,      // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm.SetDecreaseWeight(decreaseWeight)
+
+	now := time.Unix(1000000, 0)
+	tm.clock.Set(now)
+
+	numTimeouts := 0
+	for i := 0; i < numRequests; i++ {
+		id := ids.NewID([32]byte{byte(i)})
+		deadline := tm.Put(id, func() {})
+		if finished := now.Add(latency); finished.After(deadline) {
+			numTimeouts++
+			now = deadline.Add(time.Nanosecond)
+		} else {
+			now = finished
+		}
+		tm.clock.Set(now)
+		tm.Remove(id)
+	}
+	return numTimeouts, tm.currentDuration
+}
+
+func TestAdaptiveTimeoutManagerWeightedDecrease(t *testing.T) {
+	latency := 100 * time.Millisecond
+	numRequests := 200
+
+	stepTimeouts, _ := simulateConstantLatency(t, StepDecreaseWeight, latency, numRequests)
+	linearTimeouts, linearDuration := simulateConstantLatency(t, LinearDecreaseWeight, latency, numRequests)
+
+	// Shrinking by a full step oscillates around the latency, timing out
+	// repeatedly
+	if stepTimeouts < numRequests/10 {
+		t.Fatalf("Step decreases should have oscillated but only timed out %d times", stepTimeouts)
+	}
+
+	// Weighting the decrease converges towards the latency without timing out
+	if linearTimeouts != 0 {
+		t.Fatalf("Weighted decreases shouldn't have timed out but timed out %d times", linearTimeouts)
+	}
+	if linearDuration < latency || linearDuration > latency+10*time.Millisecond {
+		t.Fatalf("Weighted decreases should have converged to about %s but converged to %s", latency, linearDuration)
+	}
+}
+
+func TestAdaptiveTimeoutManagerParentFloor(t *testing.T) {
+	newManager := func(initialDuration time.Duration) *AdaptiveTimeoutManager {
+		tm := &AdaptiveTimeoutManager{}
+		err := tm.Initialize(
+			initialDuration,          // initialDuration
+			time.Millisecond,         // minimumDuration
+			2,                        // increaseRatio
+			10*time.Millisecond,      // decreaseValue
+			nil,                      // onDurationChange
+			"gecko",                  // namespace
+			prometheus.NewRegistry(), // registerer
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	parent := newManager(20 * time.Millisecond)
+	child0 := newManager(50 * time.Millisecond)
+	child1 := newManager(5 * time.Millisecond)
+	child0.SetParent(parent)
+	child1.SetParent(parent)
+
+	now := time.Unix(1000000, 0)
+	for _, tm := range []*AdaptiveTimeoutManager{parent, child0, child1} {
+		tm.clock.Set(now)
+	}
+
+	// Successes shrink child0 down to the parent's duration, but no further
+	for i := 0; i < 10; i++ {
+		id := ids.NewID([32]byte{byte(i)})
+		child0.Put(id, func() {})
+		child0.Remove(id)
+	}
+	if duration := child0.CurrentDuration(); duration != 20*time.Millisecond {
+		t.Fatalf("Child should have been floored at 20ms but was %s", duration)
+	}
+
+	// child1 starts below the parent, so it is raised to the parent's duration
+	// when it sets its next timeout
+	deadline := child1.Put(ids.Empty, func() {})
+	if expected := now.Add(20 * time.Millisecond); !deadline.Equal(expected) {
+		t.Fatalf("Child's timeout should have been raised to the parent's: expected deadline %s but got %s", expected, deadline)
+	}
+	child1.Remove(ids.Empty)
+
+	// Raising the parent's duration raises the floor of both children
+	parent.Put(ids.Empty, func() {})
+	parent.clock.Set(now.Add(time.Second))
+	parent.Remove(ids.Empty)
+	if duration := parent.CurrentDuration(); duration != 40*time.Millisecond {
+		t.Fatalf("Parent should have doubled to 40ms but was %s", duration)
+	}
+	for i, child := range []*AdaptiveTimeoutManager{child0, child1} {
+		deadline := child.Put(ids.Empty, func() {})
+		if expected := now.Add(40 * time.Millisecond); !deadline.Equal(expected) {
+			t.Fatalf("Child %d should have been floored at the parent's new duration: expected deadline %s but got %s", i, expected, deadline)
+		}
+	}
+}
+
+func TestAdaptiveTimeoutManagerContextTimeout(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	tm.Initialize(
+		time.Millisecond,         // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Microsecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	ctx, cancel := tm.Context(ids.NewID([32]byte{1}))
+	defer cancel()
+
+	if _, ok := ctx.Deadline(); !ok {
+		t.Fatalf("Context should have a deadline")
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("Context should have been cancelled by the timeout")
+	}
+	if err := ctx.Err(); err != context.Canceled {
+		t.Fatalf("Expected %s but got %v", context.Canceled, err)
+	}
+	if timeouts := testutil.ToFloat64(tm.numTimeoutsMetric); timeouts != 1 {
+		t.Fatalf("Expected 1 timeout but got %f", timeouts)
+	}
+
+	// Cancelling after the timeout fired shouldn't remove a later timeout with
+	// the same ID
+	tm.Put(ids.NewID([32]byte{1}), func() {})
+	cancel()
+	if successes := testutil.ToFloat64(tm.numSuccessesMetric); successes != 0 {
+		t.Fatalf("Expected no successes but got %f", successes)
+	}
+}
+
+func TestAdaptiveTimeoutManagerContextCancel(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	tm.Initialize(
+		time.Hour,                // initialDuration
+		time.Hour,                // minimumDuration
+		2,                        // increaseRatio
+		time.Microsecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	ctx, cancel := tm.Context(ids.NewID([32]byte{1}))
+	cancel()
+
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatalf("Context should have been cancelled")
+	}
+
+	tm.lock.Lock()
+	numTimeouts := tm.timeoutQueue.Len()
+	tm.lock.Unlock()
+	if numTimeouts != 0 {
+		t.Fatalf("Cancelling should have removed the timeout")
+	}
+	if successes := testutil.ToFloat64(tm.numSuccessesMetric); successes != 1 {
+		t.Fatalf("Expected 1 success but got %f", successes)
+	}
+
+	// Cancelling again is a no-op
+	cancel()
+	if successes := testutil.ToFloat64(tm.numSuccessesMetric); successes != 1 {
+		t.Fatalf("Expected 1 success but got %f", successes)
+	}
+}
+
+func TestAdaptiveTimeoutManagerContextExtend(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000000, 0)
+	tm.clock.Set(now)
+
+	id := ids.NewID([32]byte{1})
+	ctx, cancel := tm.Context(id)
+	defer cancel()
+
+	if deadline, _ := ctx.Deadline(); !deadline.Equal(now.Add(time.Second)) {
+		t.Fatalf("Expected deadline %s but got %s", now.Add(time.Second), deadline)
+	}
+	if !tm.Extend(id, time.Second) {
+		t.Fatalf("Should have been able to extend an outstanding timeout")
+	}
+	if deadline, _ := ctx.Deadline(); !deadline.Equal(now.Add(2 * time.Second)) {
+		t.Fatalf("Expected extended deadline %s but got %s", now.Add(2*time.Second), deadline)
+	}
+}
+
+func TestAdaptiveTimeoutManagerPutCancellable(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	tm.Initialize(
+		time.Millisecond,         // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Microsecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	// Cancelling before the timeout fires removes it
+	cancelled := make(chan struct{})
+	if deadline, cancel := tm.PutCancellable(ids.NewID([32]byte{1}), func() { close(cancelled) }); deadline.IsZero() {
+		t.Fatal("Expected a deadline")
+	} else {
+		cancel()
+		cancel()
+	}
+	if successes := testutil.ToFloat64(tm.numSuccessesMetric); successes != 1 {
+		t.Fatalf("Expected 1 success but got %f", successes)
+	}
+
+	// Cancelling after the timeout fired is a no-op, even once the ID has been
+	// put again
+	id := ids.NewID([32]byte{2})
+	fired := make(chan struct{})
+	_, cancel := tm.PutCancellable(id, func() { close(fired) })
+	<-fired
+
+	// Make sure the new timeout doesn't fire during the test
+	tm.lock.Lock()
+	tm.currentDuration = time.Hour
+	tm.lock.Unlock()
+	tm.Put(id, func() {})
+	cancel()
+
+	tm.lock.Lock()
+	_, exists := tm.timeoutMap[id.Key()]
+	tm.lock.Unlock()
+	if !exists {
+		t.Fatal("Cancelling a fired timeout shouldn't remove the ID's new timeout")
+	}
+	if timeouts := testutil.ToFloat64(tm.numTimeoutsMetric); timeouts != 1 {
+		t.Fatalf("Expected 1 timeout but got %f", timeouts)
+	}
+
+	select {
+	case <-cancelled:
+		t.Fatal("The cancelled timeout shouldn't have fired")
+	default:
+	}
+}
+
+func TestAdaptiveTimeoutManagerPutCancellableDurationChange(t *testing.T) {
+	id := ids.NewID([32]byte{1})
+	tm := AdaptiveTimeoutManager{}
+	reput := true
+	err := tm.Initialize(
+		time.Second,      // initialDuration
+		time.Millisecond, // minimumDuration
+		2,                // increaseRatio
+		time.Millisecond, // decreaseValue
+		func(_, _ time.Duration) { // onDurationChange
+			// Put [id] again while PutCancellable has released the lock
+			if reput {
+				reput = false
+				tm.Put(id, func() {})
+			}
+		},
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tm.Put(id, func() {})
+	// Replacing the outstanding timeout changes the duration
+	_, cancel := tm.PutCancellable(id, func() {})
+	if reput {
+		t.Fatalf("The duration should have changed")
+	}
+
+	// The timeout put by the callback must not be removed
+	cancel()
+	tm.lock.Lock()
+	numTimeouts := tm.timeoutQueue.Len()
+	tm.lock.Unlock()
+	if numTimeouts != 1 {
+		t.Fatalf("Cancelling should only have removed its own timeout, but %d timeouts are outstanding", numTimeouts)
+	}
+}
+
+func TestAdaptiveTimeoutManagerContextShutdown(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	tm.Initialize(
+		time.Hour,                // initialDuration
+		time.Hour,                // minimumDuration
+		2,                        // increaseRatio
+		time.Microsecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	go tm.Dispatch()
+
+	ctx, cancel := tm.Context(ids.NewID([32]byte{1}))
+	defer cancel()
+
+	// Shutting down fires the outstanding timeout
+	tm.ShutdownAndFire()
+	if ctx.Err() == nil {
+		t.Fatalf("Context should have been cancelled by the shutdown")
+	}
+
+	// Contexts requested after the shutdown are already cancelled
+	ctx, cancel = tm.Context(ids.NewID([32]byte{2}))
+	defer cancel()
+	if ctx.Err() == nil {
+		t.Fatalf("Context should have been cancelled")
+	}
+}
+
+func TestAdaptiveTimeoutManagerExtend(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000000, 0)
+	tm.clock.Set(now)
+
+	fired := false
+	id := ids.NewID([32]byte{1})
+	deadline := tm.Put(id, func() { fired = true })
+
+	if !tm.Extend(id, time.Second) {
+		t.Fatalf("Should have been able to extend an outstanding timeout")
+	}
+
+	// Past the original deadline, but before the extended one
+	tm.clock.Set(deadline.Add(time.Millisecond))
+	tm.Timeout()
+	if fired {
+		t.Fatalf("Extended timeout shouldn't have fired before its new deadline")
+	}
+
+	tm.clock.Set(deadline.Add(time.Second + time.Millisecond))
+	tm.Timeout()
+	if !fired {
+		t.Fatalf("Extended timeout should have fired after its new deadline")
+	}
+
+	// Extending shouldn't have been counted, only the timeout itself
+	if timeouts := testutil.ToFloat64(tm.numTimeoutsMetric); timeouts != 1 {
+		t.Fatalf("Expected 1 timeout but got %f", timeouts)
+	}
+	if successes := testutil.ToFloat64(tm.numSuccessesMetric); successes != 0 {
+		t.Fatalf("Expected 0 successes but got %f", successes)
+	}
+}
+
+func TestAdaptiveTimeoutManagerExtendNotAdaptive(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000000, 0)
+	tm.clock.Set(now)
+
+	id := ids.NewID([32]byte{1})
+	tm.Put(id, func() {})
+	for i := 0; i < 5; i++ {
+		if !tm.Extend(id, time.Second) {
+			t.Fatalf("Should have been able to extend an outstanding timeout")
+		}
+	}
+	if duration := tm.CurrentDuration(); duration != time.Second {
+		t.Fatalf("Extending shouldn't have changed the duration, but it is %s", duration)
+	}
+	if successes := testutil.ToFloat64(tm.numSuccessesMetric); successes != 0 {
+		t.Fatalf("Expected 0 successes but got %f", successes)
+	}
+	if timeouts := testutil.ToFloat64(tm.numTimeoutsMetric); timeouts != 0 {
+		t.Fatalf("Expected 0 timeouts but got %f", timeouts)
+	}
+}
+
+func TestAdaptiveTimeoutManagerExtendMissing(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := ids.NewID([32]byte{1})
+	if tm.Extend(id, time.Second) {
+		t.Fatalf("Shouldn't have been able to extend a timeout that was never put")
+	}
+
+	tm.Put(id, func() {})
+	tm.Remove(id)
+	if tm.Extend(id, time.Second) {
+		t.Fatalf("Shouldn't have been able to extend a removed timeout")
+	}
+}
+
+func TestAdaptiveTimeoutManagerWallClockStep(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	tm.Initialize(
+		time.Minute,              // initialDuration
+		time.Minute,              // minimumDuration
+		2,                        // increaseRatio
+		time.Second,              // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	elapsed := time.Duration(0)
+	wall := time.Now()
+	tm.clock.elapsed = func() time.Duration { return elapsed }
+	tm.clock.wall = func() time.Time { return wall }
+
+	fired := false
+	tm.Put(ids.Empty, func() { fired = true })
+
+	// Stepping the wall clock forward past the deadline must not fire the
+	// timeout early
+	wall = wall.Add(time.Hour)
+	elapsed = time.Second
+	tm.Timeout()
+	if fired {
+		t.Fatal("Timeout fired after the wall clock was stepped")
+	}
+
+	// Nor should stepping it back delay the timeout
+	wall = wall.Add(-2 * time.Hour)
+	elapsed = time.Minute
+	tm.Timeout()
+	if !fired {
+		t.Fatal("Timeout should have fired once its duration elapsed")
+	}
+}
+
+func TestAdaptiveTimeoutManagerFail(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000000, 0)
+	tm.clock.Set(now)
+
+	fired := 0
+	id := ids.NewID([32]byte{1})
+	tm.Put(id, func() { fired++ })
+
+	// The handler should be left to the dispatcher, which should call it
+	// without waiting for the deadline
+	if !tm.Fail(id) {
+		t.Fatalf("Should have been able to fail an outstanding timeout")
+	}
+	if fired != 0 {
+		t.Fatalf("Failed timeout shouldn't fire on the caller's goroutine")
+	}
+	if tm.Fail(id) {
+		t.Fatalf("Shouldn't have been able to fail a timeout twice")
+	}
+	if tm.Extend(id, time.Second) {
+		t.Fatalf("Shouldn't have been able to extend a failed timeout")
+	}
+	tm.Timeout()
+	if fired != 1 {
+		t.Fatalf("Failed timeout should have fired once but fired %d times", fired)
+	}
+
+	tm.clock.Set(now.Add(2 * time.Second))
+	tm.Timeout()
+	if fired != 1 {
+		t.Fatalf("Failed timeout shouldn't fire again but fired %d times", fired)
+	}
+
+	// Failing isn't a timeout or a success, so the duration shouldn't change
+	if duration := tm.CurrentDuration(); duration != time.Second {
+		t.Fatalf("Expected duration %s but got %s", time.Second, duration)
+	}
+	if timeouts := testutil.ToFloat64(tm.numTimeoutsMetric); timeouts != 0 {
+		t.Fatalf("Expected 0 timeouts but got %f", timeouts)
+	}
+	if successes := testutil.ToFloat64(tm.numSuccessesMetric); successes != 0 {
+		t.Fatalf("Expected 0 successes but got %f", successes)
+	}
+}
+
+func TestAdaptiveTimeoutManagerFailDispatched(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Hour,                // initialDuration
+		time.Hour,                // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	fired := make(chan struct{})
+	id := ids.NewID([32]byte{1})
+	tm.Put(id, func() { close(fired) })
+	if !tm.Fail(id) {
+		t.Fatalf("Should have been able to fail an outstanding timeout")
+	}
+
+	select {
+	case <-fired:
+	case <-time.After(time.Minute):
+		t.Fatalf("Failed timeout should have fired without waiting for its deadline")
+	}
+}
+
+func TestAdaptiveTimeoutManagerBatching(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Second,              // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	batches := [][]func(){}
+	tm.SetBatching(10*time.Millisecond, func(handlers []func()) {
+		batches = append(batches, handlers)
+		for _, handler := range handlers {
+			handler()
+		}
+	})
+
+	now := time.Unix(1000000, 0)
+	fired := map[int]bool{}
+	for i, offset := range []time.Duration{0, time.Millisecond, 5 * time.Millisecond, 20 * time.Millisecond} {
+		i := i
+		tm.clock.Set(now.Add(offset))
+		tm.Put(ids.NewID([32]byte{byte(i)}), func() { fired[i] = true })
+	}
+
+	// The first three timeouts are due within the batching window of the
+	// first deadline
+	tm.clock.Set(now.Add(time.Second))
+	tm.Timeout()
+
+	if len(batches) != 1 {
+		t.Fatalf("Should have fired 1 batch but fired %d", len(batches))
+	}
+	if len(batches[0]) != 3 {
+		t.Fatalf("Batch should have held 3 timeouts but held %d", len(batches[0]))
+	}
+	for i := 0; i < 3; i++ {
+		if !fired[i] {
+			t.Fatalf("Timeout %d should have fired", i)
+		}
+	}
+	if fired[3] {
+		t.Fatalf("Timeout 3 shouldn't have fired yet")
+	}
+
+	// Timeouts fired early are still counted as timeouts
+	if duration := tm.CurrentDuration(); duration != 2*time.Second {
+		t.Fatalf("Duration should have been doubled to %s but is %s", 2*time.Second, duration)
+	}
+
+	tm.clock.Set(now.Add(time.Second + 20*time.Millisecond))
+	tm.Timeout()
+	if len(batches) != 2 || !fired[3] {
+		t.Fatalf("Timeout 3 should have fired in its own batch")
+	}
+}
+
+// benchmarkTimeouts fires [numTimeouts] timeouts that share a deadline. Each
+// handler needs the caller's lock, as handlers passed to the router do, and
+// the number of times that lock is acquired is reported.
+func benchmarkTimeouts(b *testing.B, numTimeouts int, batch bool) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Second,              // minimumDuration
+		1,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var (
+		lock     sync.Mutex
+		numLocks int
+		fired    int
+	)
+	handler := func() { fired++ }
+	lockedHandler := func() {
+		lock.Lock()
+		numLocks++
+		handler()
+		lock.Unlock()
+	}
+	if batch {
+		tm.SetBatching(time.Millisecond, func(handlers []func()) {
+			lock.Lock()
+			numLocks++
+			for _, handler := range handlers {
+				handler()
+			}
+			lock.Unlock()
+		})
+	}
+
+	now := time.Unix(1000000, 0)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		b.StopTimer()
+		tm.clock.Set(now)
+		for i := 0; i < numTimeouts; i++ {
+			if batch {
+				tm.Put(ids.NewID([32]byte{byte(i), byte(i >> 8)}), handler)
+			} else {
+				tm.Put(ids.NewID([32]byte{byte(i), byte(i >> 8)}), lockedHandler)
+			}
+		}
+		tm.clock.Set(now.Add(time.Second))
+		b.StartTimer()
+
+		tm.Timeout()
+	}
+	b.StopTimer()
+
+	if fired != b.N*numTimeouts {
+		b.Fatalf("Should have fired %d timeouts but fired %d", b.N*numTimeouts, fired)
+	}
+	b.ReportMetric(float64(numLocks)/float64(b.N), "locks/op")
+}
+
+func BenchmarkAdaptiveTimeoutManagerPerTimeout(b *testing.B) { benchmarkTimeouts(b, 256, false) }
+
+func BenchmarkAdaptiveTimeoutManagerBatched(b *testing.B) { benchmarkTimeouts(b, 256, true) }
+
+func TestAdaptiveTimeoutManagerFreezeAdaptation(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000000, 0)
+	tm.clock.Set(now)
+	tm.FreezeAdaptation(time.Minute)
+
+	fired := 0
+	tm.Put(ids.NewID([32]byte{1}), func() { fired++ })
+	tm.Put(ids.NewID([32]byte{2}), func() { fired++ })
+	tm.Put(ids.NewID([32]byte{3}), func() {})
+
+	// A clock step makes the outstanding requests time out
+	tm.clock.Set(now.Add(30 * time.Second))
+	tm.Timeout()
+	if fired != 2 {
+		t.Fatalf("Should have fired 2 timeouts but fired %d", fired)
+	}
+	if duration := tm.CurrentDuration(); duration != time.Second {
+		t.Fatalf("Duration shouldn't have changed during the freeze but is %s", duration)
+	}
+
+	// Successes don't change the duration either
+	tm.Put(ids.NewID([32]byte{4}), func() {})
+	tm.Remove(ids.NewID([32]byte{4}))
+	if duration := tm.CurrentDuration(); duration != time.Second {
+		t.Fatalf("Duration shouldn't have changed during the freeze but is %s", duration)
+	}
+
+	// Once the window passes, timeouts adapt the duration again
+	tm.clock.Set(now.Add(time.Minute))
+	tm.Put(ids.NewID([32]byte{5}), func() {})
+	tm.clock.Set(now.Add(time.Minute + 2*time.Second))
+	tm.Timeout()
+	if duration := tm.CurrentDuration(); duration != 2*time.Second {
+		t.Fatalf("Duration should have doubled after the freeze but is %s", duration)
+	}
+}
+
+func TestAdaptiveTimeoutManagerFreezeUsesRemovalTime(t *testing.T) {
+	tm := AdaptiveTimeoutManager{}
+	err := tm.Initialize(
+		time.Second,              // initialDuration
+		time.Millisecond,         // minimumDuration
+		2,                        // increaseRatio
+		time.Millisecond,         // decreaseValue
+		nil,                      // onDurationChange
+		"gecko",                  // namespace
+		prometheus.NewRegistry(), // registerer
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1000000, 0)
+	tm.clock.Set(now)
+	tm.FreezeAdaptation(time.Minute)
+
+	id := ids.NewID([32]byte{1})
+	tm.Put(id, func() {})
+
+	// The removal happened during the freeze, even though the clock has since
+	// moved past it
+	tm.clock.Set(now.Add(2 * time.Minute))
+	tm.lock.Lock()
+	tm.remove(id, now.Add(2*time.Second))
+	tm.lock.Unlock()
+
+	if duration := tm.CurrentDuration(); duration != time.Second {
+		t.Fatalf("Duration shouldn't have changed during the freeze but is %s", duration)
+	}
+}
@@ -0,0 +1,87 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// AlignedRepeater calls a handler at every multiple of a period, measured on
+// its clock. Unlike Repeater, the time spent executing the handler doesn't
+// delay the following calls. If the handler runs past one or more multiples of
+// the period, those calls are skipped rather than made in a burst.
+type AlignedRepeater struct {
+	handler func()
+	period  time.Duration
+	timeout chan struct{}
+	clock   Clock
+
+	// after returns a channel that is sent on after the duration elapses.
+	// Replaced in tests so that time can be faked.
+	after func(time.Duration) <-chan time.Time
+
+	lock     sync.Mutex
+	wg       sync.WaitGroup
+	finished bool
+}
+
+// NewAlignedRepeater returns a repeater that calls [handler] at every multiple
+// of [period]
+func NewAlignedRepeater(handler func(), period time.Duration) *AlignedRepeater {
+	repeater := &AlignedRepeater{
+		handler: handler,
+		period:  period,
+		timeout: make(chan struct{}, 1),
+		after:   time.After,
+	}
+	repeater.wg.Add(1)
+
+	return repeater
+}
+
+// Stop the repeater. Blocks until the dispatch thread has exited.
+func (r *AlignedRepeater) Stop() {
+	r.lock.Lock()
+	if !r.finished {
+		defer r.wg.Wait()
+	}
+	defer r.lock.Unlock()
+
+	r.finished = true
+	r.reset()
+}
+
+// Dispatch calls the handler until Stop is called
+func (r *AlignedRepeater) Dispatch() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	defer r.wg.Done()
+
+	for !r.finished {
+		now := r.clock.Time()
+		next := r.nextTick(now)
+		r.lock.Unlock()
+
+		select {
+		case <-r.timeout:
+		case <-r.after(next.Sub(now)):
+			r.handler()
+		}
+
+		r.lock.Lock()
+	}
+}
+
+// nextTick returns the first multiple of the period that is after [now]
+func (r *AlignedRepeater) nextTick(now time.Time) time.Time {
+	return now.Truncate(r.period).Add(r.period)
+}
+
+func (r *AlignedRepeater) reset() {
+	select {
+	case r.timeout <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,83 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAlignedRepeaterSlowHandler(t *testing.T) {
+	period := time.Second
+	start := time.Unix(1000000, 0).Add(period / 3)
+
+	// Simulated time the handler takes to run on each call
+	handlerDurations := []time.Duration{
+		period / 10,
+		5 * period / 2, // misses the next 2 ticks
+		period / 2,
+		period, // finishes exactly on the next tick, which is missed
+		0,
+	}
+	numTicks := len(handlerDurations)
+	ticks := make(chan time.Time, numTicks)
+
+	var repeater *AlignedRepeater
+	calls := 0
+	repeater = NewAlignedRepeater(func() {
+		now := repeater.clock.Time()
+		if calls < numTicks {
+			ticks <- now
+			repeater.clock.Set(now.Add(handlerDurations[calls]))
+		}
+		calls++
+	}, period)
+	repeater.clock.Set(start)
+	repeater.after = func(d time.Duration) <-chan time.Time {
+		// Advance the fake clock instead of waiting
+		now := repeater.clock.Time().Add(d)
+		repeater.clock.Set(now)
+
+		c := make(chan time.Time, 1)
+		c <- now
+		return c
+	}
+	go repeater.Dispatch()
+
+	expected := []time.Time{
+		start.Truncate(period).Add(period),
+		start.Truncate(period).Add(2 * period),
+		start.Truncate(period).Add(5 * period),
+		start.Truncate(period).Add(6 * period),
+		start.Truncate(period).Add(8 * period),
+	}
+	for i, expectedTick := range expected {
+		tick := <-ticks
+		if tick.Truncate(period) != tick {
+			t.Fatalf("Tick %d at %s isn't aligned to the period", i, tick)
+		}
+		if !tick.Equal(expectedTick) {
+			t.Fatalf("Tick %d should have been at %s but was at %s", i, expectedTick, tick)
+		}
+	}
+	repeater.Stop()
+}
+
+func TestAlignedRepeater(t *testing.T) {
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	val := new(int)
+	repeater := NewAlignedRepeater(func() {
+		if *val < 2 {
+			wg.Done()
+			*val++
+		}
+	}, time.Millisecond)
+	go repeater.Dispatch()
+
+	wg.Wait()
+	repeater.Stop()
+}
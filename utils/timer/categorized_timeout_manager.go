@@ -0,0 +1,227 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+// CategoryConfig describes how the timeout duration of a category adapts
+type CategoryConfig struct {
+	InitialDuration time.Duration
+	MinimumDuration time.Duration
+	IncreaseRatio   float64
+	DecreaseValue   time.Duration
+}
+
+type timeoutCategory struct {
+	config          CategoryConfig
+	currentDuration time.Duration
+}
+
+// CategorizedTimeoutManager is a manager for timeouts that are partitioned by
+// category, such as bootstrapping requests and queries. Each category adapts
+// its own timeout duration, so a burst of timeouts in one category doesn't
+// affect the others. All categories share one timer.
+type CategorizedTimeoutManager struct {
+	currentDurationMetric *prometheus.GaugeVec
+	numTimeoutsMetric     *prometheus.CounterVec
+	numSuccessesMetric    *prometheus.CounterVec
+
+	defaultConfig CategoryConfig
+
+	clock        MonotonicClock
+	lock         sync.Mutex
+	categories   map[string]*timeoutCategory
+	timeoutMap   map[[32]byte]*adaptiveTimeout
+	timeoutQueue timeoutQueue
+	timer        *Timer // Timer that will fire to clear the timeouts
+}
+
+// Initialize this manager. Categories that weren't configured with
+// SetCategory use [defaultConfig].
+func (tm *CategorizedTimeoutManager) Initialize(
+	defaultConfig CategoryConfig,
+	namespace string,
+	registerer prometheus.Registerer,
+) error {
+	tm.currentDurationMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "category_timeout",
+		Help:      "Duration of current timeouts of each category in nanoseconds",
+	}, []string{"category"})
+	tm.numTimeoutsMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "category_timeouts",
+		Help:      "Number of requests of each category that were removed after timing out",
+	}, []string{"category"})
+	tm.numSuccessesMetric = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "category_successes",
+		Help:      "Number of requests of each category that were removed before timing out",
+	}, []string{"category"})
+	tm.defaultConfig = defaultConfig
+	tm.categories = make(map[string]*timeoutCategory)
+	tm.timeoutMap = make(map[[32]byte]*adaptiveTimeout)
+	tm.timer = NewTimer(tm.Timeout)
+	// Deadlines shouldn't be affected by the wall clock being stepped
+	tm.clock.Start()
+
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(tm.currentDurationMetric),
+		registerer.Register(tm.numTimeoutsMetric),
+		registerer.Register(tm.numSuccessesMetric),
+	)
+	return errs.Err
+}
+
+// SetCategory configures how the timeout duration of [category] adapts. The
+// category's current duration is reset to [config]'s initial duration.
+func (tm *CategorizedTimeoutManager) SetCategory(category string, config CategoryConfig) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.categories[category] = &timeoutCategory{
+		config:          config,
+		currentDuration: config.InitialDuration,
+	}
+	tm.currentDurationMetric.WithLabelValues(category).Set(float64(config.InitialDuration))
+}
+
+// CurrentDuration returns the duration that new timeouts of [category] are
+// currently set for
+func (tm *CategorizedTimeoutManager) CurrentDuration(category string) time.Duration {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	return tm.category(category).currentDuration
+}
+
+// Dispatch ...
+func (tm *CategorizedTimeoutManager) Dispatch() { tm.timer.Dispatch() }
+
+// Stop executing timeouts
+func (tm *CategorizedTimeoutManager) Stop() { tm.timer.Stop() }
+
+// Put registers a timeout of [category] for [id], replacing any outstanding
+// timeout for [id], even if it was of another category. Returns the deadline
+// of the timeout.
+func (tm *CategorizedTimeoutManager) Put(category string, id ids.ID, handler func()) time.Time {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	currentTime := tm.clock.Time()
+	tm.remove(id, currentTime)
+
+	duration := tm.category(category).currentDuration
+	timeout := &adaptiveTimeout{
+		id:       id,
+		handler:  handler,
+		duration: duration,
+		deadline: currentTime.Add(duration),
+		category: category,
+	}
+	tm.timeoutMap[id.Key()] = timeout
+	heap.Push(&tm.timeoutQueue, timeout)
+
+	tm.registerTimeout()
+	return timeout.deadline
+}
+
+// Remove the timeout for [id], if there is one
+func (tm *CategorizedTimeoutManager) Remove(id ids.ID) {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	tm.remove(id, tm.clock.Time())
+}
+
+// Timeout fires the timeouts that have expired
+func (tm *CategorizedTimeoutManager) Timeout() {
+	tm.lock.Lock()
+	defer tm.lock.Unlock()
+
+	currentTime := tm.clock.Time()
+	for tm.timeoutQueue.Len() > 0 {
+		nextTimeout := tm.timeoutQueue[0]
+		if nextTimeout.deadline.After(currentTime) {
+			break
+		}
+		tm.remove(nextTimeout.id, currentTime)
+
+		// Don't execute a callback with a lock held
+		tm.lock.Unlock()
+		nextTimeout.handler()
+		tm.lock.Lock()
+	}
+	tm.registerTimeout()
+}
+
+// category returns [category], creating it with the default config if it
+// doesn't exist. Assumes the lock is held.
+func (tm *CategorizedTimeoutManager) category(category string) *timeoutCategory {
+	c, exists := tm.categories[category]
+	if !exists {
+		c = &timeoutCategory{
+			config:          tm.defaultConfig,
+			currentDuration: tm.defaultConfig.InitialDuration,
+		}
+		tm.categories[category] = c
+		tm.currentDurationMetric.WithLabelValues(category).Set(float64(c.currentDuration))
+	}
+	return c
+}
+
+// remove the timeout for [id] and adapt its category's duration. Assumes the
+// lock is held.
+func (tm *CategorizedTimeoutManager) remove(id ids.ID, currentTime time.Time) {
+	key := id.Key()
+	timeout, exists := tm.timeoutMap[key]
+	if !exists {
+		return
+	}
+
+	c := tm.category(timeout.category)
+	newDuration, timedOut := adaptDuration(
+		c.currentDuration,
+		timeout,
+		currentTime,
+		c.config.IncreaseRatio,
+		c.config.DecreaseValue,
+		StepDecreaseWeight,
+		func() time.Duration { return c.config.MinimumDuration },
+	)
+	c.currentDuration = newDuration
+	if timedOut {
+		tm.numTimeoutsMetric.WithLabelValues(timeout.category).Inc()
+	} else {
+		tm.numSuccessesMetric.WithLabelValues(timeout.category).Inc()
+	}
+	tm.currentDurationMetric.WithLabelValues(timeout.category).Set(float64(c.currentDuration))
+
+	delete(tm.timeoutMap, key)
+	heap.Remove(&tm.timeoutQueue, timeout.index)
+}
+
+// registerTimeout sets the timer to fire at the earliest deadline of any
+// category. Assumes the lock is held.
+func (tm *CategorizedTimeoutManager) registerTimeout() {
+	if tm.timeoutQueue.Len() == 0 {
+		// There are no pending timeouts
+		tm.timer.Cancel()
+		return
+	}
+
+	currentTime := tm.clock.Time()
+	nextTimeout := tm.timeoutQueue[0]
+	tm.timer.SetTimeoutIn(nextTimeout.deadline.Sub(currentTime))
+}
@@ -0,0 +1,129 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestCategorizedTimeoutManagerIndependentCategories(t *testing.T) {
+	tm := CategorizedTimeoutManager{}
+	if err := tm.Initialize(
+		CategoryConfig{
+			InitialDuration: time.Second,
+			MinimumDuration: time.Second,
+			IncreaseRatio:   2,
+			DecreaseValue:   time.Second,
+		},
+		"gecko",
+		prometheus.NewRegistry(),
+	); err != nil {
+		t.Fatal(err)
+	}
+	tm.SetCategory("query", CategoryConfig{
+		InitialDuration: 4 * time.Second,
+		MinimumDuration: time.Second,
+		IncreaseRatio:   2,
+		DecreaseValue:   time.Second,
+	})
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	now := time.Unix(0, 0)
+	tm.clock.Set(now)
+
+	if duration := tm.CurrentDuration("bootstrap"); duration != time.Second {
+		t.Fatalf("Expected the default initial duration but got %s", duration)
+	}
+	if duration := tm.CurrentDuration("query"); duration != 4*time.Second {
+		t.Fatalf("Expected the configured initial duration but got %s", duration)
+	}
+
+	// A burst of bootstrap timeouts should only grow the bootstrap duration
+	fired := 0
+	for i := 0; i < 3; i++ {
+		tm.Put("bootstrap", ids.NewID([32]byte{byte(i)}), func() { fired++ })
+	}
+	now = now.Add(2 * time.Second)
+	tm.clock.Set(now)
+	tm.Timeout()
+
+	if fired != 3 {
+		t.Fatalf("Expected 3 bootstrap timeouts to fire but %d did", fired)
+	}
+	if duration := tm.CurrentDuration("bootstrap"); duration != 2*time.Second {
+		t.Fatalf("Expected the bootstrap duration to double but got %s", duration)
+	}
+	if duration := tm.CurrentDuration("query"); duration != 4*time.Second {
+		t.Fatalf("Bootstrap timeouts changed the query duration to %s", duration)
+	}
+
+	// Successful queries should only shrink the query duration
+	queryID := ids.NewID([32]byte{10})
+	tm.Put("query", queryID, func() { t.Fatal("Query shouldn't have timed out") })
+	tm.Remove(queryID)
+
+	if duration := tm.CurrentDuration("query"); duration != 3*time.Second {
+		t.Fatalf("Expected the query duration to decrease but got %s", duration)
+	}
+	if duration := tm.CurrentDuration("bootstrap"); duration != 2*time.Second {
+		t.Fatalf("Queries changed the bootstrap duration to %s", duration)
+	}
+
+	// Deadlines use their category's duration
+	if deadline := tm.Put("query", queryID, func() {}); !deadline.Equal(now.Add(3 * time.Second)) {
+		t.Fatalf("Expected the query deadline to be %s but was %s", now.Add(3*time.Second), deadline)
+	}
+	if deadline := tm.Put("bootstrap", ids.Empty, func() {}); !deadline.Equal(now.Add(2 * time.Second)) {
+		t.Fatalf("Expected the bootstrap deadline to be %s but was %s", now.Add(2*time.Second), deadline)
+	}
+
+	if timeouts := testutil.ToFloat64(tm.numTimeoutsMetric.WithLabelValues("bootstrap")); timeouts != 3 {
+		t.Fatalf("Expected 3 bootstrap timeouts to be reported but %f were", timeouts)
+	}
+	if timeouts := testutil.ToFloat64(tm.numTimeoutsMetric.WithLabelValues("query")); timeouts != 0 {
+		t.Fatalf("Expected no query timeouts to be reported but %f were", timeouts)
+	}
+}
+
+func TestCategorizedTimeoutManagerSharedTimer(t *testing.T) {
+	tm := CategorizedTimeoutManager{}
+	if err := tm.Initialize(
+		CategoryConfig{
+			InitialDuration: time.Millisecond,
+			MinimumDuration: time.Millisecond,
+			IncreaseRatio:   2,
+			DecreaseValue:   time.Millisecond,
+		},
+		"gecko",
+		prometheus.NewRegistry(),
+	); err != nil {
+		t.Fatal(err)
+	}
+	go tm.Dispatch()
+	defer tm.Stop()
+
+	fired := make(chan string, 2)
+	tm.Put("bootstrap", ids.NewID([32]byte{1}), func() { fired <- "bootstrap" })
+	tm.Put("query", ids.NewID([32]byte{2}), func() { fired <- "query" })
+
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		select {
+		case category := <-fired:
+			seen[category] = true
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timeouts should have fired")
+		}
+	}
+	if !seen["bootstrap"] || !seen["query"] {
+		t.Fatalf("Expected timeouts of both categories to fire but got %v", seen)
+	}
+}
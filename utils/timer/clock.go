@@ -4,29 +4,74 @@
 package timer
 
 import (
+	"sync"
 	"time"
 )
 
-// Clock acts as a thin wrapper around global time that allows for easy testing
+// Clock acts as a thin wrapper around global time that allows for easy testing.
+// It's safe to set the time while it's being read concurrently.
 type Clock struct {
+	lock  sync.RWMutex
 	faked bool
 	time  time.Time
+
+	// timers started on this clock with AfterFunc. Created lazily, so that
+	// the zero value of a Clock remains usable.
+	timers *clockTimers
+}
+
+// Set the time on the clock. Timers started on this clock whose deadlines have
+// passed at [time] are fired before Set returns.
+func (c *Clock) Set(time time.Time) {
+	c.lock.Lock()
+	c.faked = true
+	c.time = time
+	timers := c.timers
+	c.lock.Unlock()
+
+	if timers != nil {
+		for _, t := range timers.list() {
+			t.fire()
+		}
+	}
 }
 
-// Set the time on the clock
-func (c *Clock) Set(time time.Time) { c.faked = true; c.time = time }
+// getTimers returns the timers started on this clock, creating the set if
+// needed
+func (c *Clock) getTimers() *clockTimers {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.timers == nil {
+		c.timers = &clockTimers{timers: make(map[*ManagedTimer]struct{})}
+	}
+	return c.timers
+}
 
 // Sync this clock with global time
-func (c *Clock) Sync() { c.faked = false }
+func (c *Clock) Sync() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.faked = false
+}
 
 // Time returns the time on this clock
 func (c *Clock) Time() time.Time {
-	if c.faked {
-		return c.time
+	if fakeTime, faked := c.fakeTime(); faked {
+		return fakeTime
 	}
 	return time.Now()
 }
 
+// fakeTime returns the time the clock was set to, and whether it was set
+func (c *Clock) fakeTime() (time.Time, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.time, c.faked
+}
+
 // Unix returns the unix time on this clock.
 func (c *Clock) Unix() uint64 {
 	unix := c.Time().Unix()
@@ -35,3 +80,75 @@ func (c *Clock) Unix() uint64 {
 	}
 	return uint64(unix)
 }
+
+// MonotonicClock is a Clock whose time is measured with the monotonic clock
+// from the moment it was started, rather than read from the wall clock. The
+// time it reports moves forward at a steady rate even if the wall clock is
+// stepped, so deadlines computed from it are unaffected by such steps.
+// WallTime should be used where the actual wall clock time is needed, such as
+// for timestamps that are shared with peers.
+//
+// Until Start is called, the wall clock is used. Start isn't safe to call
+// concurrently with reading the time.
+type MonotonicClock struct {
+	Clock
+
+	started bool
+	// wall clock time when the clock was started
+	start time.Time
+	// time since the clock was started, measured with the monotonic clock
+	elapsed func() time.Duration
+	wall    func() time.Time
+}
+
+// NewMonotonicClock returns a started monotonic clock
+func NewMonotonicClock() *MonotonicClock {
+	c := &MonotonicClock{}
+	c.Start()
+	return c
+}
+
+// Start measuring time from now
+func (c *MonotonicClock) Start() {
+	start := time.Now()
+	c.started = true
+	c.start = start
+	c.elapsed = func() time.Duration { return time.Since(start) }
+	c.wall = time.Now
+}
+
+// Time returns the time on this clock
+func (c *MonotonicClock) Time() time.Time {
+	fakeTime, faked := c.fakeTime()
+	switch {
+	case faked:
+		return fakeTime
+	case !c.started:
+		return time.Now()
+	default:
+		return c.start.Add(c.elapsed())
+	}
+}
+
+// Unix returns the unix time on this clock
+func (c *MonotonicClock) Unix() uint64 {
+	unix := c.Time().Unix()
+	if unix < 0 {
+		unix = 0
+	}
+	return uint64(unix)
+}
+
+// WallTime returns the time on the wall clock, or the fake time if one was
+// set
+func (c *MonotonicClock) WallTime() time.Time {
+	fakeTime, faked := c.fakeTime()
+	switch {
+	case faked:
+		return fakeTime
+	case !c.started:
+		return time.Now()
+	default:
+		return c.wall()
+	}
+}
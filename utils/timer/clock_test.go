@@ -17,7 +17,7 @@ func TestClockSet(t *testing.T) {
 }
 
 func TestClockSync(t *testing.T) {
-	clock := Clock{true, time.Unix(0, 0)}
+	clock := Clock{faked: true, time: time.Unix(0, 0)}
 	clock.Sync()
 	if clock.faked == true {
 		t.Error("Clock was synced, but .faked flag was set")
@@ -28,10 +28,56 @@ func TestClockSync(t *testing.T) {
 }
 
 func TestClockUnix(t *testing.T) {
-	clock := Clock{true, time.Unix(-14159040, 0)}
+	clock := Clock{faked: true, time: time.Unix(-14159040, 0)}
 	actual := clock.Unix()
 	if actual != 0 {
 		// We are Unix of 1970s, Moon landings are irrelevant
 		t.Errorf("Expected time prior to Unix epoch to be clamped to 0, got %d", actual)
 	}
 }
+
+func TestMonotonicClockIgnoresWallClockSteps(t *testing.T) {
+	start := time.Unix(1000000, 0)
+	elapsed := time.Duration(0)
+	wall := start
+
+	clock := MonotonicClock{}
+	clock.Start()
+	clock.start = start
+	clock.elapsed = func() time.Duration { return elapsed }
+	clock.wall = func() time.Time { return wall }
+
+	// Step the wall clock back an hour
+	wall = wall.Add(-time.Hour)
+	if !clock.Time().Equal(start) {
+		t.Fatalf("Expected time %s but got %s", start, clock.Time())
+	}
+	if !clock.WallTime().Equal(wall) {
+		t.Fatalf("Expected wall time %s but got %s", wall, clock.WallTime())
+	}
+
+	elapsed = time.Second
+	if expected := start.Add(time.Second); !clock.Time().Equal(expected) {
+		t.Fatalf("Expected time %s but got %s", expected, clock.Time())
+	}
+
+	// Step the wall clock forward two hours
+	wall = wall.Add(2 * time.Hour)
+	if expected := start.Add(time.Second); !clock.Time().Equal(expected) {
+		t.Fatalf("Expected time %s but got %s", expected, clock.Time())
+	}
+	if expected := uint64(start.Unix() + 1); clock.Unix() != expected {
+		t.Fatalf("Expected unix time %d but got %d", expected, clock.Unix())
+	}
+}
+
+func TestMonotonicClockSet(t *testing.T) {
+	clock := NewMonotonicClock()
+	clock.Set(time.Unix(1000000, 0))
+	if !clock.Time().Equal(time.Unix(1000000, 0)) {
+		t.Fatal("Fake time was set, but not returned")
+	}
+	if !clock.WallTime().Equal(time.Unix(1000000, 0)) {
+		t.Fatal("Fake time was set, but not returned as the wall time")
+	}
+}
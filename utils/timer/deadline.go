@@ -0,0 +1,79 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// Deadline calls a handler exactly once after a deadline. Before the handler
+// is called, the deadline may be pushed back any number of times. Once the
+// handler has been called, or the deadline has been canceled, the deadline can
+// no longer be modified.
+type Deadline struct {
+	handler func()
+	timer   *Timer
+
+	lock     sync.Mutex
+	deadline time.Time
+	finished bool
+}
+
+// NewDeadline creates a new deadline that will call [handler] in [duration]
+func NewDeadline(handler func(), duration time.Duration) *Deadline {
+	d := &Deadline{
+		handler:  handler,
+		deadline: time.Now().Add(duration),
+	}
+	d.timer = NewTimer(d.fire)
+	go d.timer.Dispatch()
+	d.timer.SetTimeoutIn(duration)
+	return d
+}
+
+// Extend pushes the deadline back by [duration]. If the handler has already
+// been called, or the deadline was canceled, this is a no-op.
+func (d *Deadline) Extend(duration time.Duration) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if d.finished {
+		return
+	}
+	d.deadline = d.deadline.Add(duration)
+	d.timer.SetTimeoutIn(time.Until(d.deadline))
+}
+
+// Cancel the deadline so that the handler is never called. If the handler has
+// already been called, this is a no-op.
+func (d *Deadline) Cancel() {
+	d.lock.Lock()
+	if d.finished {
+		d.lock.Unlock()
+		return
+	}
+	d.finished = true
+	d.lock.Unlock()
+
+	// The lock must be released before stopping the timer, as the dispatch
+	// thread may be waiting on it in [fire]
+	d.timer.Stop()
+}
+
+func (d *Deadline) fire() {
+	d.lock.Lock()
+	// The deadline may have been extended after the timer went off
+	if d.finished || time.Now().Before(d.deadline) {
+		d.lock.Unlock()
+		return
+	}
+	d.finished = true
+	d.lock.Unlock()
+
+	// Stop must not be called from the dispatch thread, as it waits for the
+	// dispatch thread to exit
+	go d.timer.Stop()
+	d.handler()
+}
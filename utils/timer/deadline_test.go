@@ -0,0 +1,56 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadlineExtend(t *testing.T) {
+	start := time.Now()
+	fired := make(chan time.Time, 2)
+	deadline := NewDeadline(func() { fired <- time.Now() }, 20*time.Millisecond)
+	deadline.Extend(20 * time.Millisecond)
+	deadline.Extend(20 * time.Millisecond)
+
+	firedAt := <-fired
+	if elapsed := firedAt.Sub(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("Deadline fired after %s but should have been extended to at least %s", elapsed, 60*time.Millisecond)
+	}
+
+	// Once the dispatch thread has exited, the handler can't be called again
+	deadline.timer.wg.Wait()
+	if len(fired) != 0 {
+		t.Fatalf("Deadline fired more than once")
+	}
+}
+
+func TestDeadlineCancel(t *testing.T) {
+	fired := make(chan struct{}, 1)
+	deadline := NewDeadline(func() { fired <- struct{}{} }, 10*time.Millisecond)
+	deadline.Cancel()
+	deadline.Extend(time.Millisecond)
+
+	// Cancel waits for the dispatch thread to exit, so the handler can't be
+	// called after it returns
+	if len(fired) != 0 {
+		t.Fatalf("Deadline should have been canceled before being called")
+	}
+}
+
+func TestDeadlineFireThenExtend(t *testing.T) {
+	fired := make(chan struct{}, 2)
+	deadline := NewDeadline(func() { fired <- struct{}{} }, time.Millisecond)
+	<-fired
+
+	deadline.Extend(time.Millisecond)
+	deadline.Cancel()
+
+	// Once the dispatch thread has exited, the handler can't be called again
+	deadline.timer.wg.Wait()
+	if calls := len(fired) + 1; calls != 1 {
+		t.Fatalf("Deadline should have fired exactly once but fired %d times", calls)
+	}
+}
@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// EWMA is an exponentially weighted moving average of durations, such as
+// observed round trip times. Each new sample moves the average towards it by
+// a fixed fraction of the difference, so recent samples count the most while
+// a single outlier can only move the average so far. It is safe for concurrent
+// use.
+type EWMA struct {
+	lock sync.Mutex
+
+	// weight given to each new sample, in (0, 1]
+	alpha float64
+
+	value    float64
+	observed bool
+}
+
+// NewEWMA returns an average that weights each new sample by [alpha], which
+// must be in (0, 1]. Larger values of [alpha] make the average react to
+// changes faster. The average is 0 until the first sample is observed, which
+// then becomes the average.
+func NewEWMA(alpha float64) *EWMA { return &EWMA{alpha: alpha} }
+
+// Observe adds [sample] to the average
+func (e *EWMA) Observe(sample time.Duration) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if !e.observed {
+		e.value = float64(sample)
+		e.observed = true
+		return
+	}
+	e.value += e.alpha * (float64(sample) - e.value)
+}
+
+// Value returns the current average
+func (e *EWMA) Value() time.Duration {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return time.Duration(e.value)
+}
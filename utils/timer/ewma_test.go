@@ -0,0 +1,66 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEWMAFirstSample(t *testing.T) {
+	e := NewEWMA(.1)
+	if value := e.Value(); value != 0 {
+		t.Fatalf("Expected an empty average to be 0 but was %s", value)
+	}
+
+	e.Observe(time.Second)
+	if value := e.Value(); value != time.Second {
+		t.Fatalf("Expected the first sample to become the average but was %s", value)
+	}
+}
+
+func TestEWMAStepChange(t *testing.T) {
+	alpha := .25
+	e := NewEWMA(alpha)
+
+	before := 100 * time.Millisecond
+	after := 200 * time.Millisecond
+	for i := 0; i < 10; i++ {
+		e.Observe(before)
+	}
+	if value := e.Value(); value != before {
+		t.Fatalf("Expected a constant average of %s but was %s", before, value)
+	}
+
+	// After [n] samples at the new value, the remaining gap should have
+	// shrunk by a factor of (1-alpha)^n
+	for n := 1; n <= 20; n++ {
+		e.Observe(after)
+
+		gap := float64(after-before) * math.Pow(1-alpha, float64(n))
+		expected := after - time.Duration(gap)
+		if value := e.Value(); value < expected-1 || value > expected+1 {
+			t.Fatalf("Expected %s after %d samples but was %s", expected, n, value)
+		}
+	}
+
+	// The average should never overshoot the new value
+	for i := 0; i < 100; i++ {
+		e.Observe(after)
+	}
+	if value := e.Value(); value > after || after-value > time.Microsecond {
+		t.Fatalf("Expected the average to converge to %s but was %s", after, value)
+	}
+}
+
+func TestEWMANoSmoothing(t *testing.T) {
+	e := NewEWMA(1)
+	for _, sample := range []time.Duration{time.Second, time.Millisecond, time.Minute} {
+		e.Observe(sample)
+		if value := e.Value(); value != sample {
+			t.Fatalf("Expected the average to be the last sample %s but was %s", sample, value)
+		}
+	}
+}
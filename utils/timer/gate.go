@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"time"
+)
+
+// Gate allows at most [limit] passes per [interval]. Intervals start at the
+// first pass made after the previous interval ended. A Gate isn't safe for
+// concurrent use.
+type Gate struct {
+	limit    int
+	interval time.Duration
+
+	start  time.Time
+	passed int
+}
+
+// NewGate returns a gate that allows [limit] passes per [interval]. If [limit]
+// isn't positive, every pass is allowed.
+func NewGate(limit int, interval time.Duration) *Gate {
+	return &Gate{
+		limit:    limit,
+		interval: interval,
+	}
+}
+
+// Pass returns true and counts a pass if the gate is open at time [now].
+// Otherwise, returns false.
+func (g *Gate) Pass(now time.Time) bool {
+	if g.limit <= 0 {
+		return true
+	}
+	if g.passed == 0 || now.Sub(g.start) >= g.interval {
+		g.start = now
+		g.passed = 0
+	}
+	if g.passed >= g.limit {
+		return false
+	}
+	g.passed++
+	return true
+}
+
+// Open returns true if a pass at time [now] would be allowed. No pass is
+// counted.
+func (g *Gate) Open(now time.Time) bool {
+	return g.limit <= 0 || g.passed < g.limit || now.Sub(g.start) >= g.interval
+}
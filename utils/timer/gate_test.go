@@ -0,0 +1,34 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGate(t *testing.T) {
+	g := NewGate(2, time.Second)
+	now := time.Now()
+
+	if !g.Pass(now) || !g.Pass(now.Add(100*time.Millisecond)) {
+		t.Fatalf("Gate should allow passes up to the limit")
+	}
+	if g.Pass(now.Add(999 * time.Millisecond)) {
+		t.Fatalf("Gate should be closed once the limit is reached")
+	}
+	if !g.Pass(now.Add(time.Second)) {
+		t.Fatalf("Gate should reopen once the interval has passed")
+	}
+}
+
+func TestGateUnlimited(t *testing.T) {
+	g := NewGate(0, time.Second)
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		if !g.Pass(now) {
+			t.Fatalf("Gate without a limit should always be open")
+		}
+	}
+}
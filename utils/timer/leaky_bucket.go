@@ -0,0 +1,156 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// OverflowPolicy determines what a LeakyBucket does with items that are added
+// while it is full
+type OverflowPolicy int
+
+const (
+	// DropOnOverflow drops items that are added while the bucket is full
+	DropOnOverflow OverflowPolicy = iota
+	// BlockOnOverflow waits for an item to be released before adding another
+	// item to a full bucket
+	BlockOnOverflow
+)
+
+// LeakyBucket smooths bursts of items into a steady outflow. Items are queued
+// as they are added and released to a consumer, in the order they were added,
+// at most once per interval. Time is measured with a Clock, and items are only
+// released when Leak is called, which Dispatch does whenever the next item is
+// due. It is safe for concurrent use.
+type LeakyBucket struct {
+	lock  sync.Mutex
+	space *sync.Cond // signalled when items are released or the bucket stops
+	clock *Clock
+
+	// time between releases
+	interval time.Duration
+	// maximum number of items the bucket can hold
+	capacity int
+	policy   OverflowPolicy
+	consumer func(interface{})
+
+	queue []interface{}
+	// time the next item may be released
+	next    time.Time
+	stopped bool
+
+	// held while releasing items, so they are consumed in order
+	leakLock sync.Mutex
+	timer    *Timer
+}
+
+// NewLeakyBucket returns an empty bucket holding at most [capacity] items,
+// which releases items to [consumer] at [rate] items per second. Items added
+// while the bucket is full are handled according to [policy]. If [clock] is
+// nil, the bucket uses the system time.
+func NewLeakyBucket(
+	clock *Clock,
+	rate float64,
+	capacity int,
+	policy OverflowPolicy,
+	consumer func(interface{}),
+) *LeakyBucket {
+	if clock == nil {
+		clock = &Clock{}
+	}
+	b := &LeakyBucket{
+		clock:    clock,
+		interval: time.Duration(float64(time.Second) / rate),
+		capacity: capacity,
+		policy:   policy,
+		consumer: consumer,
+	}
+	b.space = sync.NewCond(&b.lock)
+	b.timer = NewTimer(b.Leak)
+	return b
+}
+
+// Add [item] to the bucket. Returns false if the item was dropped because the
+// bucket is full, or because the bucket was stopped.
+func (b *LeakyBucket) Add(item interface{}) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for !b.stopped && len(b.queue) >= b.capacity {
+		if b.policy != BlockOnOverflow {
+			return false
+		}
+		b.space.Wait()
+	}
+	if b.stopped {
+		return false
+	}
+
+	now := b.clock.Time()
+	if len(b.queue) == 0 {
+		// Releases that weren't needed while the bucket was empty can't be
+		// saved up for a later burst
+		if b.next.Before(now) {
+			b.next = now
+		}
+		b.timer.SetTimeoutIn(b.next.Sub(now))
+	}
+	b.queue = append(b.queue, item)
+	return true
+}
+
+// Len returns the number of items waiting to be released
+func (b *LeakyBucket) Len() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.queue)
+}
+
+// Leak releases the items that are due to the consumer. If items were due
+// while Leak wasn't called, they are all released now.
+func (b *LeakyBucket) Leak() {
+	b.leakLock.Lock()
+	defer b.leakLock.Unlock()
+
+	b.lock.Lock()
+	now := b.clock.Time()
+	released := 0
+	for released < len(b.queue) && !now.Before(b.next) {
+		released++
+		b.next = b.next.Add(b.interval)
+	}
+	items := b.queue[:released]
+	b.queue = b.queue[released:]
+	if len(b.queue) > 0 {
+		b.timer.SetTimeoutIn(b.next.Sub(now))
+	}
+	if released > 0 {
+		b.space.Broadcast()
+	}
+	b.lock.Unlock()
+
+	// Don't execute a callback with a lock held
+	for _, item := range items {
+		b.consumer(item)
+	}
+}
+
+// Dispatch releases items as they become due until Stop is called
+func (b *LeakyBucket) Dispatch() { b.timer.Dispatch() }
+
+// Stop releasing items. Items that are still in the bucket are dropped, and
+// any blocked or future calls to Add return false. Dispatch must have been
+// called.
+func (b *LeakyBucket) Stop() {
+	b.lock.Lock()
+	b.stopped = true
+	b.queue = nil
+	b.space.Broadcast()
+	b.lock.Unlock()
+
+	b.timer.Stop()
+}
@@ -0,0 +1,177 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// released records the items a LeakyBucket released
+type released struct {
+	lock  sync.Mutex
+	items []interface{}
+}
+
+func (r *released) consume(item interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.items = append(r.items, item)
+}
+
+func (r *released) len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return len(r.items)
+}
+
+func TestLeakyBucketSteadyRate(t *testing.T) {
+	clock := &Clock{}
+	now := time.Now()
+	clock.Set(now)
+
+	r := &released{}
+	b := NewLeakyBucket(clock, 2, 10, DropOnOverflow, r.consume)
+
+	// A burst of items
+	for i := 0; i < 5; i++ {
+		if !b.Add(i) {
+			t.Fatalf("Should have been able to add item %d", i)
+		}
+	}
+
+	// The first item is released immediately
+	b.Leak()
+	if n := r.len(); n != 1 {
+		t.Fatalf("Expected 1 item to be released but %d were", n)
+	}
+
+	// 2 items per second
+	now = now.Add(250 * time.Millisecond)
+	clock.Set(now)
+	b.Leak()
+	if n := r.len(); n != 1 {
+		t.Fatalf("Expected 1 item to be released but %d were", n)
+	}
+	now = now.Add(250 * time.Millisecond)
+	clock.Set(now)
+	b.Leak()
+	if n := r.len(); n != 2 {
+		t.Fatalf("Expected 2 items to be released but %d were", n)
+	}
+
+	// Items that were due while Leak wasn't called are released together
+	now = now.Add(time.Second)
+	clock.Set(now)
+	b.Leak()
+	if n := r.len(); n != 4 {
+		t.Fatalf("Expected 4 items to be released but %d were", n)
+	}
+	if n := b.Len(); n != 1 {
+		t.Fatalf("Expected 1 item to be waiting but %d were", n)
+	}
+
+	// Once the bucket is empty, idle time shouldn't allow a burst
+	now = now.Add(time.Hour)
+	clock.Set(now)
+	b.Leak()
+	for i := 5; i < 8; i++ {
+		if !b.Add(i) {
+			t.Fatalf("Should have been able to add item %d", i)
+		}
+	}
+	b.Leak()
+	if n := r.len(); n != 6 {
+		t.Fatalf("Expected 6 items to be released but %d were", n)
+	}
+
+	for i, item := range r.items {
+		if item != i {
+			t.Fatalf("Expected item %d to be released in order but got %v", i, item)
+		}
+	}
+}
+
+func TestLeakyBucketDropOnOverflow(t *testing.T) {
+	clock := &Clock{}
+	now := time.Now()
+	clock.Set(now)
+
+	r := &released{}
+	b := NewLeakyBucket(clock, 1, 2, DropOnOverflow, r.consume)
+
+	if !b.Add(0) || !b.Add(1) {
+		t.Fatalf("Should have been able to fill the bucket")
+	}
+	if b.Add(2) {
+		t.Fatalf("Should have dropped an item added to a full bucket")
+	}
+
+	// Releasing an item makes space
+	b.Leak()
+	if !b.Add(3) {
+		t.Fatalf("Should have been able to add an item after one was released")
+	}
+
+	now = now.Add(2 * time.Second)
+	clock.Set(now)
+	b.Leak()
+	if n := r.len(); n != 3 {
+		t.Fatalf("Expected 3 items to be released but %d were", n)
+	}
+	for i, expected := range []interface{}{0, 1, 3} {
+		if r.items[i] != expected {
+			t.Fatalf("Expected %v to be released but got %v", expected, r.items[i])
+		}
+	}
+}
+
+func TestLeakyBucketBlockOnOverflow(t *testing.T) {
+	clock := &Clock{}
+	now := time.Now()
+	clock.Set(now)
+
+	r := &released{}
+	b := NewLeakyBucket(clock, 1, 1, BlockOnOverflow, r.consume)
+	go b.Dispatch()
+
+	if !b.Add(0) {
+		t.Fatalf("Should have been able to fill the bucket")
+	}
+
+	// Release the first item, then fill the bucket again
+	b.Leak()
+	if !b.Add(1) {
+		t.Fatalf("Should have been able to fill the bucket")
+	}
+
+	added := make(chan bool)
+	go func() { added <- b.Add(2) }()
+	select {
+	case <-added:
+		t.Fatalf("Add should have blocked while the bucket was full")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Releasing an item unblocks the add
+	now = now.Add(time.Second)
+	clock.Set(now)
+	b.Leak()
+	if !<-added {
+		t.Fatalf("Add should have succeeded once space was made")
+	}
+
+	// Stopping the bucket unblocks adds
+	go func() { added <- b.Add(3) }()
+	b.Stop()
+	if <-added {
+		t.Fatalf("Add should have failed once the bucket was stopped")
+	}
+	if n := r.len(); n != 2 {
+		t.Fatalf("Expected 2 items to be released but %d were", n)
+	}
+}
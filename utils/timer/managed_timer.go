@@ -0,0 +1,136 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// ManagedTimer calls a function once its deadline, measured on its clock, has
+// passed. Like a time.Timer created by time.AfterFunc, it can be stopped and
+// reset before the function is called. Once the function has been called the
+// timer is finished, and resetting it is a no-op.
+type ManagedTimer struct {
+	f func()
+
+	lock     sync.Mutex
+	clock    *Clock
+	timers   *clockTimers
+	timer    *time.Timer
+	deadline time.Time
+	// true if the function is scheduled to be called
+	active bool
+	// true if the function has been called
+	fired bool
+}
+
+// AfterFunc calls [f] once [d] has elapsed on [clock]. If the clock follows
+// the wall clock, [f] is called in its own goroutine, like time.AfterFunc. If
+// the clock is set past the deadline, [f] is called by Set before it returns,
+// so that tests can fire the timer deterministically. The returned timer can
+// be used to cancel or delay the call.
+func AfterFunc(clock *Clock, d time.Duration, f func()) *ManagedTimer {
+	t := &ManagedTimer{
+		f:        f,
+		clock:    clock,
+		timers:   clock.getTimers(),
+		deadline: clock.Time().Add(d),
+		active:   true,
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.timers.add(t)
+	t.timer = time.AfterFunc(d, t.fire)
+	return t
+}
+
+// Stop prevents the function from being called. Returns true if the call was
+// stopped, and false if the function was already called or the timer was
+// already stopped.
+func (t *ManagedTimer) Stop() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	wasActive := t.active
+	t.active = false
+	t.timer.Stop()
+	t.timers.remove(t)
+	return wasActive
+}
+
+// Reset the timer to call the function once [d] has elapsed from now. Returns
+// true if the timer was active. If the function was already called, the timer
+// isn't restarted.
+func (t *ManagedTimer) Reset(d time.Duration) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.fired {
+		return false
+	}
+
+	wasActive := t.active
+	t.active = true
+	t.deadline = t.clock.Time().Add(d)
+	t.timers.add(t)
+	t.timer.Stop()
+	t.timer.Reset(d)
+	return wasActive
+}
+
+// fire calls the function if its deadline has passed
+func (t *ManagedTimer) fire() {
+	t.lock.Lock()
+	if !t.active {
+		t.lock.Unlock()
+		return
+	}
+	// The timer may go off before the deadline has passed on the clock
+	if remaining := t.deadline.Sub(t.clock.Time()); remaining > 0 {
+		t.timer.Reset(remaining)
+		t.lock.Unlock()
+		return
+	}
+	t.active = false
+	t.fired = true
+	t.timer.Stop()
+	t.timers.remove(t)
+	t.lock.Unlock()
+
+	t.f()
+}
+
+// clockTimers is the set of active timers started on a clock
+type clockTimers struct {
+	lock   sync.Mutex
+	timers map[*ManagedTimer]struct{}
+}
+
+func (c *clockTimers) add(t *ManagedTimer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.timers[t] = struct{}{}
+}
+
+func (c *clockTimers) remove(t *ManagedTimer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.timers, t)
+}
+
+func (c *clockTimers) list() []*ManagedTimer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	timers := make([]*ManagedTimer, 0, len(c.timers))
+	for t := range c.timers {
+		timers = append(timers, t)
+	}
+	return timers
+}
@@ -0,0 +1,130 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManagedTimerFires(t *testing.T) {
+	fired := make(chan struct{})
+	timer := AfterFunc(&Clock{}, time.Millisecond, func() { close(fired) })
+
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Fatal("Should have called the function")
+	}
+
+	if timer.Stop() {
+		t.Fatal("Stop should return false once the function was called")
+	}
+}
+
+func TestManagedTimerResetBeforeFire(t *testing.T) {
+	start := time.Unix(1000000, 0)
+	clock := Clock{}
+	clock.Set(start)
+
+	calls := 0
+	timer := AfterFunc(&clock, time.Hour, func() { calls++ })
+	defer timer.Stop()
+
+	if !timer.Reset(2 * time.Hour) {
+		t.Fatal("Reset should return true while the timer is active")
+	}
+
+	clock.Set(start.Add(time.Hour))
+	if calls != 0 {
+		t.Fatal("Shouldn't have called the function before the new deadline")
+	}
+
+	clock.Set(start.Add(2 * time.Hour))
+	if calls != 1 {
+		t.Fatalf("Should have called the function once, but called it %d times", calls)
+	}
+}
+
+func TestManagedTimerStopBeforeFire(t *testing.T) {
+	start := time.Unix(1000000, 0)
+	clock := Clock{}
+	clock.Set(start)
+
+	calls := 0
+	timer := AfterFunc(&clock, time.Hour, func() { calls++ })
+
+	if !timer.Stop() {
+		t.Fatal("Stop should return true while the timer is active")
+	}
+	if timer.Stop() {
+		t.Fatal("Stop should return false once the timer was stopped")
+	}
+
+	clock.Set(start.Add(time.Hour))
+	if calls != 0 {
+		t.Fatal("Shouldn't have called the function after the timer was stopped")
+	}
+
+	// A stopped timer can be reused
+	if timer.Reset(time.Hour) {
+		t.Fatal("Reset should return false once the timer was stopped")
+	}
+	defer timer.Stop()
+
+	clock.Set(start.Add(2 * time.Hour))
+	if calls != 1 {
+		t.Fatalf("Should have called the function once, but called it %d times", calls)
+	}
+}
+
+func TestManagedTimerResetAfterFire(t *testing.T) {
+	start := time.Unix(1000000, 0)
+	clock := Clock{}
+	clock.Set(start)
+
+	calls := 0
+	timer := AfterFunc(&clock, time.Hour, func() { calls++ })
+	defer timer.Stop()
+
+	clock.Set(start.Add(time.Hour))
+	if calls != 1 {
+		t.Fatalf("Should have called the function once, but called it %d times", calls)
+	}
+
+	if timer.Reset(time.Hour) {
+		t.Fatal("Reset should return false once the function was called")
+	}
+
+	clock.Set(start.Add(3 * time.Hour))
+	if calls != 1 {
+		t.Fatal("Resetting the timer after it fired should be a no-op")
+	}
+}
+
+func TestManagedTimerFiresOnClock(t *testing.T) {
+	start := time.Unix(1000000, 0)
+	clock := Clock{}
+	clock.Set(start)
+
+	calls := 0
+	timer := AfterFunc(&clock, time.Hour, func() { calls++ })
+	defer timer.Stop()
+
+	clock.Set(start.Add(time.Hour - time.Nanosecond))
+	if calls != 0 {
+		t.Fatal("Shouldn't have called the function before the deadline")
+	}
+
+	// Advancing the clock fires the timer without waiting on the wall clock
+	clock.Set(start.Add(time.Hour))
+	if calls != 1 {
+		t.Fatalf("Should have called the function once, but called it %d times", calls)
+	}
+
+	clock.Set(start.Add(2 * time.Hour))
+	if calls != 1 {
+		t.Fatalf("Should have called the function once, but called it %d times", calls)
+	}
+}
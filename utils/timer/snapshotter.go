@@ -0,0 +1,59 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/utils"
+)
+
+// Sample is a value collected by a Snapshotter
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// Snapshotter periodically collects a value and retains the most recent
+// samples. This can be used to roll up a frequently updated value into samples
+// that are cheap to export.
+type Snapshotter struct {
+	group   *TickerGroup
+	collect func() float64
+	ticker  *Ticker
+	samples *utils.RingBuffer
+}
+
+// NewSnapshotter registers [collect] to be called every [frequency] by
+// [group], retaining the last [size] values it returned. If [size] isn't
+// positive, no samples are retained.
+func NewSnapshotter(group *TickerGroup, collect func() float64, frequency time.Duration, size int) *Snapshotter {
+	s := &Snapshotter{
+		group:   group,
+		collect: collect,
+		samples: utils.NewRingBuffer(size),
+	}
+	s.ticker = group.Register(s.snapshot, frequency)
+	return s
+}
+
+// Samples returns the retained samples, oldest first
+func (s *Snapshotter) Samples() []Sample {
+	elements := s.samples.List()
+	samples := make([]Sample, len(elements))
+	for i, element := range elements {
+		samples[i] = element.(Sample)
+	}
+	return samples
+}
+
+// Stop collecting samples. The retained samples can still be read.
+func (s *Snapshotter) Stop() { s.ticker.Cancel() }
+
+func (s *Snapshotter) snapshot() {
+	s.samples.Append(Sample{
+		Time:  s.group.clock.Time(),
+		Value: s.collect(),
+	})
+}
@@ -0,0 +1,74 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotter(t *testing.T) {
+	g := NewTickerGroup()
+	start := time.Unix(1000000, 0)
+	g.clock.Set(start)
+
+	value := 0.
+	s := NewSnapshotter(g, func() float64 { return value }, time.Second, 4)
+
+	if samples := s.Samples(); len(samples) != 0 {
+		t.Fatalf("Should have had no samples but had %d", len(samples))
+	}
+
+	for i := 1; i <= 10; i++ {
+		value = float64(i)
+		g.clock.Set(start.Add(time.Duration(i) * time.Second))
+		g.fire()
+
+		expectedLen := i
+		if expectedLen > 4 {
+			expectedLen = 4
+		}
+		if samples := s.Samples(); len(samples) != expectedLen {
+			t.Fatalf("Should have had %d samples but had %d", expectedLen, len(samples))
+		}
+	}
+
+	samples := s.Samples()
+	for i, sample := range samples {
+		expectedValue := float64(7 + i)
+		expectedTime := start.Add(time.Duration(7+i) * time.Second)
+		if sample.Value != expectedValue {
+			t.Fatalf("Sample %d should have had value %f but had %f", i, expectedValue, sample.Value)
+		}
+		if !sample.Time.Equal(expectedTime) {
+			t.Fatalf("Sample %d should have been taken at %s but was taken at %s", i, expectedTime, sample.Time)
+		}
+	}
+
+	s.Stop()
+	g.clock.Set(start.Add(11 * time.Second))
+	g.fire()
+	if samples := s.Samples(); samples[len(samples)-1].Value != 10 {
+		t.Fatalf("Shouldn't have collected a sample after being stopped")
+	}
+}
+
+func TestSnapshotterNoSamples(t *testing.T) {
+	g := NewTickerGroup()
+	start := time.Unix(1000000, 0)
+	g.clock.Set(start)
+
+	collected := 0
+	s := NewSnapshotter(g, func() float64 { collected++; return 0 }, time.Second, 0)
+
+	g.clock.Set(start.Add(time.Second))
+	g.fire()
+
+	if collected != 1 {
+		t.Fatalf("Should have collected once but collected %d times", collected)
+	}
+	if samples := s.Samples(); len(samples) != 0 {
+		t.Fatalf("Should have had no samples but had %d", len(samples))
+	}
+}
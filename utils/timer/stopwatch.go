@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"time"
+)
+
+// Stopwatch measures how long the phases of a task take. Each call to Lap
+// attributes the time since the previous lap, or since the stopwatch was
+// started, to the named phase. A Stopwatch isn't safe for concurrent use.
+type Stopwatch struct {
+	clock *Clock
+
+	running bool
+	start   time.Time
+	lastLap time.Time
+	stop    time.Time
+	laps    map[string]time.Duration
+}
+
+// NewStopwatch returns a stopwatch that reads the time from [clock]. If
+// [clock] is nil, the stopwatch uses the system time.
+func NewStopwatch(clock *Clock) *Stopwatch {
+	if clock == nil {
+		clock = &Clock{}
+	}
+	return &Stopwatch{
+		clock: clock,
+		laps:  make(map[string]time.Duration),
+	}
+}
+
+// Start the stopwatch, discarding any previously recorded laps
+func (s *Stopwatch) Start() {
+	now := s.clock.Time()
+	s.running = true
+	s.start = now
+	s.lastLap = now
+	s.laps = make(map[string]time.Duration)
+}
+
+// Lap attributes the time since the last lap to [name]. If [name] has already
+// been used, the time is added to its previous total. Does nothing if the
+// stopwatch isn't running.
+func (s *Stopwatch) Lap(name string) {
+	if !s.running {
+		return
+	}
+	now := s.clock.Time()
+	s.laps[name] += now.Sub(s.lastLap)
+	s.lastLap = now
+}
+
+// Stop the stopwatch and return the total time it ran for. Time since the last
+// lap isn't attributed to any lap.
+func (s *Stopwatch) Stop() time.Duration {
+	if s.running {
+		s.running = false
+		s.stop = s.clock.Time()
+	}
+	return s.stop.Sub(s.start)
+}
+
+// Results returns the time attributed to each lap
+func (s *Stopwatch) Results() map[string]time.Duration {
+	results := make(map[string]time.Duration, len(s.laps))
+	for name, duration := range s.laps {
+		results[name] = duration
+	}
+	return results
+}
@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopwatchLaps(t *testing.T) {
+	clock := &Clock{}
+	now := time.Now()
+	clock.Set(now)
+
+	s := NewStopwatch(clock)
+	s.Start()
+
+	now = now.Add(2 * time.Second)
+	clock.Set(now)
+	s.Lap("verify")
+
+	now = now.Add(3 * time.Second)
+	clock.Set(now)
+	s.Lap("accept")
+
+	now = now.Add(time.Second)
+	clock.Set(now)
+	s.Lap("verify")
+
+	now = now.Add(5 * time.Second)
+	clock.Set(now)
+	if total := s.Stop(); total != 11*time.Second {
+		t.Fatalf("Expected total of %s but got %s", 11*time.Second, total)
+	}
+
+	// Laps after stopping shouldn't be recorded
+	clock.Set(now.Add(time.Second))
+	s.Lap("accept")
+
+	results := s.Results()
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 laps but got %d", len(results))
+	}
+	if results["verify"] != 3*time.Second {
+		t.Fatalf("Expected verify to take %s but took %s", 3*time.Second, results["verify"])
+	}
+	if results["accept"] != 3*time.Second {
+		t.Fatalf("Expected accept to take %s but took %s", 3*time.Second, results["accept"])
+	}
+}
@@ -0,0 +1,139 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// TickerGroup calls many handlers periodically using a single timer and
+// dispatch thread. This is cheaper than running a Repeater per handler when
+// there are many handlers.
+type TickerGroup struct {
+	clock Clock
+	timer *Timer
+
+	lock    sync.Mutex
+	tickers tickerHeap
+}
+
+// Ticker is a handle to a handler registered in a TickerGroup
+type Ticker struct {
+	group     *TickerGroup
+	handler   func()
+	frequency time.Duration
+	next      time.Time
+	index     int
+}
+
+// NewTickerGroup creates a new ticker group. Dispatch must be called for the
+// handlers to be executed.
+func NewTickerGroup() *TickerGroup {
+	g := &TickerGroup{}
+	g.timer = NewTimer(g.fire)
+	return g
+}
+
+// Dispatch calls the registered handlers until Stop is called
+func (g *TickerGroup) Dispatch() { g.timer.Dispatch() }
+
+// Stop calling the registered handlers
+func (g *TickerGroup) Stop() { g.timer.Stop() }
+
+// Register [handler] to be called every [frequency]. The returned ticker can
+// be used to unregister the handler.
+func (g *TickerGroup) Register(handler func(), frequency time.Duration) *Ticker {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	ticker := &Ticker{
+		group:     g,
+		handler:   handler,
+		frequency: frequency,
+		next:      g.clock.Time().Add(frequency),
+	}
+	heap.Push(&g.tickers, ticker)
+	g.registerTimeout()
+	return ticker
+}
+
+// Cancel the ticker so that its handler isn't called again. If the handler is
+// currently executing, it will run to completion.
+func (t *Ticker) Cancel() {
+	g := t.group
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if t.index < 0 {
+		return
+	}
+	heap.Remove(&g.tickers, t.index)
+	g.registerTimeout()
+}
+
+// fire calls the handlers of every ticker that is due and reschedules them
+func (g *TickerGroup) fire() {
+	g.lock.Lock()
+	now := g.clock.Time()
+	handlers := []func(){}
+	for len(g.tickers) > 0 {
+		ticker := g.tickers[0]
+		if ticker.next.After(now) {
+			break
+		}
+		handlers = append(handlers, ticker.handler)
+
+		// If the handler has fallen behind, skip the missed ticks rather
+		// than calling the handler repeatedly to catch up
+		ticker.next = ticker.next.Add(ticker.frequency)
+		if !ticker.next.After(now) {
+			ticker.next = now.Add(ticker.frequency)
+		}
+		heap.Fix(&g.tickers, 0)
+	}
+	g.registerTimeout()
+	g.lock.Unlock()
+
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
+// registerTimeout sets the timer to go off when the next ticker is due.
+// Assumes the lock is held.
+func (g *TickerGroup) registerTimeout() {
+	if len(g.tickers) == 0 {
+		g.timer.Cancel()
+		return
+	}
+	g.timer.SetTimeoutIn(g.tickers[0].next.Sub(g.clock.Time()))
+}
+
+// tickerHeap is a min-heap of tickers ordered by the next time they are due
+type tickerHeap []*Ticker
+
+func (h tickerHeap) Len() int           { return len(h) }
+func (h tickerHeap) Less(i, j int) bool { return h[i].next.Before(h[j].next) }
+func (h tickerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *tickerHeap) Push(x interface{}) {
+	ticker := x.(*Ticker)
+	ticker.index = len(*h)
+	*h = append(*h, ticker)
+}
+func (h *tickerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	ticker := old[n-1]
+	old[n-1] = nil
+	ticker.index = -1
+	*h = old[:n-1]
+	return ticker
+}
@@ -0,0 +1,91 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTickerGroupFakeClock(t *testing.T) {
+	g := NewTickerGroup()
+	start := time.Unix(1000000, 0)
+	g.clock.Set(start)
+
+	counts := make([]int, 3)
+	frequencies := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		5 * time.Second,
+	}
+	tickers := make([]*Ticker, len(frequencies))
+	for i, frequency := range frequencies {
+		i := i
+		tickers[i] = g.Register(func() { counts[i]++ }, frequency)
+	}
+
+	for i := 1; i <= 10; i++ {
+		g.clock.Set(start.Add(time.Duration(i) * time.Second))
+		g.fire()
+	}
+
+	expected := []int{10, 5, 2}
+	for i, count := range counts {
+		if count != expected[i] {
+			t.Fatalf("Handler %d was called %d times but should have been called %d times", i, count, expected[i])
+		}
+	}
+
+	tickers[0].Cancel()
+	tickers[0].Cancel()
+	for i := 11; i <= 20; i++ {
+		g.clock.Set(start.Add(time.Duration(i) * time.Second))
+		g.fire()
+	}
+
+	expected = []int{10, 10, 4}
+	for i, count := range counts {
+		if count != expected[i] {
+			t.Fatalf("Handler %d was called %d times but should have been called %d times", i, count, expected[i])
+		}
+	}
+}
+
+func TestTickerGroupSkipsMissedTicks(t *testing.T) {
+	g := NewTickerGroup()
+	start := time.Unix(1000000, 0)
+	g.clock.Set(start)
+
+	count := 0
+	g.Register(func() { count++ }, time.Second)
+
+	g.clock.Set(start.Add(10 * time.Second))
+	g.fire()
+	g.fire()
+	if count != 1 {
+		t.Fatalf("Handler was called %d times but should have been called once", count)
+	}
+}
+
+func TestTickerGroupDispatch(t *testing.T) {
+	g := NewTickerGroup()
+	go g.Dispatch()
+	defer g.Stop()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+
+	calls := 0
+	ticker := g.Register(func() {
+		calls++
+		if calls <= 2 {
+			wg.Done()
+		}
+	}, time.Millisecond)
+	g.Register(func() {}, time.Hour)
+
+	wg.Wait()
+	ticker.Cancel()
+}
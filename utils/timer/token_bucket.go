@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket rate limits an action by requiring tokens to perform it. Tokens
+// are refilled at a constant rate, up to a maximum capacity, so short bursts
+// are allowed while the long term rate is bounded. It is safe for concurrent
+// use.
+type TokenBucket struct {
+	lock  sync.Mutex
+	clock *Clock
+
+	// tokens refilled per second
+	rate float64
+	// maximum number of tokens the bucket can hold
+	capacity float64
+
+	tokens     float64
+	lastRefill time.Time
+}
+
+// NewTokenBucket returns a full bucket holding at most [capacity] tokens,
+// which refills at [rate] tokens per second. If [clock] is nil, the bucket
+// uses the system time.
+func NewTokenBucket(clock *Clock, rate float64, capacity int) *TokenBucket {
+	if clock == nil {
+		clock = &Clock{}
+	}
+	return &TokenBucket{
+		clock:      clock,
+		rate:       rate,
+		capacity:   float64(capacity),
+		tokens:     float64(capacity),
+		lastRefill: clock.Time(),
+	}
+}
+
+// Take attempts to take a single token from the bucket
+func (b *TokenBucket) Take() bool { return b.TakeN(1) }
+
+// TakeN attempts to take [n] tokens from the bucket. If there aren't enough
+// tokens, none are taken and false is returned.
+func (b *TokenBucket) TakeN(n int) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refill()
+	if float64(n) > b.tokens {
+		return false
+	}
+	b.tokens -= float64(n)
+	return true
+}
+
+// Available returns the number of whole tokens currently in the bucket
+func (b *TokenBucket) Available() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refill()
+	return int(b.tokens)
+}
+
+// refill adds the tokens accumulated since the last refill. Assumes the lock
+// is held.
+func (b *TokenBucket) refill() {
+	now := b.clock.Time()
+	if elapsed := now.Sub(b.lastRefill); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+	}
+	// If the clock went backwards, wait for it to catch up rather than
+	// refilling the same interval twice
+	if now.After(b.lastRefill) {
+		b.lastRefill = now
+	}
+}
@@ -0,0 +1,84 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketRefill(t *testing.T) {
+	clock := &Clock{}
+	now := time.Now()
+	clock.Set(now)
+
+	b := NewTokenBucket(clock, 2, 10)
+	if available := b.Available(); available != 10 {
+		t.Fatalf("Expected a new bucket to be full but had %d tokens", available)
+	}
+	if !b.TakeN(10) {
+		t.Fatalf("Should have been able to take every token")
+	}
+	if available := b.Available(); available != 0 {
+		t.Fatalf("Expected 0 tokens but had %d", available)
+	}
+
+	// 2 tokens per second
+	now = now.Add(1500 * time.Millisecond)
+	clock.Set(now)
+	if available := b.Available(); available != 3 {
+		t.Fatalf("Expected 3 tokens but had %d", available)
+	}
+
+	// Partial tokens shouldn't be lost
+	now = now.Add(250 * time.Millisecond)
+	clock.Set(now)
+	if available := b.Available(); available != 3 {
+		t.Fatalf("Expected 3 tokens but had %d", available)
+	}
+	now = now.Add(250 * time.Millisecond)
+	clock.Set(now)
+	if available := b.Available(); available != 4 {
+		t.Fatalf("Expected 4 tokens but had %d", available)
+	}
+
+	// The bucket shouldn't fill past its capacity
+	now = now.Add(time.Hour)
+	clock.Set(now)
+	if available := b.Available(); available != 10 {
+		t.Fatalf("Expected 10 tokens but had %d", available)
+	}
+}
+
+func TestTokenBucketOverBudget(t *testing.T) {
+	clock := &Clock{}
+	now := time.Now()
+	clock.Set(now)
+
+	b := NewTokenBucket(clock, 1, 5)
+	if b.TakeN(6) {
+		t.Fatalf("Shouldn't have been able to take more tokens than the capacity")
+	}
+	if !b.TakeN(3) {
+		t.Fatalf("Should have been able to take 3 tokens")
+	}
+	if b.TakeN(3) {
+		t.Fatalf("Shouldn't have been able to take 3 tokens when only 2 remain")
+	}
+	if available := b.Available(); available != 2 {
+		t.Fatalf("A failed take shouldn't consume tokens, but only %d remain", available)
+	}
+	if !b.Take() || !b.Take() {
+		t.Fatalf("Should have been able to take the remaining tokens")
+	}
+	if b.Take() {
+		t.Fatalf("Shouldn't have been able to take from an empty bucket")
+	}
+
+	now = now.Add(time.Second)
+	clock.Set(now)
+	if !b.Take() {
+		t.Fatalf("Should have been able to take a refilled token")
+	}
+}
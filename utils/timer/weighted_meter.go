@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// WeightedMeter sums the weights of the events that occurred within a sliding
+// window, such as the number of bytes sent in the last ten seconds. Events are
+// grouped into buckets of window/numBuckets, so events expire one bucket at a
+// time. It is safe for concurrent use.
+type WeightedMeter struct {
+	lock  sync.Mutex
+	clock *Clock
+
+	bucketDuration time.Duration
+	buckets        []uint64
+	// index, since the unix epoch, of the bucket events are currently added to
+	current int64
+	total   uint64
+}
+
+// NewWeightedMeter returns a meter over the last [window], split into
+// [numBuckets] buckets. [window] must be at least [numBuckets] nanoseconds.
+func NewWeightedMeter(clock *Clock, window time.Duration, numBuckets int) *WeightedMeter {
+	return &WeightedMeter{
+		clock:          clock,
+		bucketDuration: window / time.Duration(numBuckets),
+		buckets:        make([]uint64, numBuckets),
+	}
+}
+
+// Add an event of [weight] to the meter
+func (m *WeightedMeter) Add(weight uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.advance()
+	m.buckets[m.current%int64(len(m.buckets))] += weight
+	m.total += weight
+}
+
+// Weight returns the total weight of the events within the window
+func (m *WeightedMeter) Weight() uint64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.advance()
+	return m.total
+}
+
+// advance expires the buckets that have left the window. Assumes the lock is
+// held.
+func (m *WeightedMeter) advance() {
+	now := m.clock.Time().UnixNano() / int64(m.bucketDuration)
+	if now <= m.current {
+		return
+	}
+
+	numBuckets := int64(len(m.buckets))
+	if now-m.current >= numBuckets {
+		for i := range m.buckets {
+			m.buckets[i] = 0
+		}
+		m.total = 0
+	} else {
+		for i := m.current + 1; i <= now; i++ {
+			index := i % numBuckets
+			m.total -= m.buckets[index]
+			m.buckets[index] = 0
+		}
+	}
+	m.current = now
+}
@@ -0,0 +1,45 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeightedMeter(t *testing.T) {
+	clock := &Clock{}
+	now := time.Unix(1000000, 0)
+	clock.Set(now)
+
+	m := NewWeightedMeter(clock, 10*time.Second, 10)
+	if weight := m.Weight(); weight != 0 {
+		t.Fatalf("New meter should be empty but has weight %d", weight)
+	}
+
+	m.Add(100)
+	clock.Set(now.Add(5 * time.Second))
+	m.Add(50)
+	if weight := m.Weight(); weight != 150 {
+		t.Fatalf("Expected weight 150 but had %d", weight)
+	}
+
+	// The first event leaves the window
+	clock.Set(now.Add(10 * time.Second))
+	if weight := m.Weight(); weight != 50 {
+		t.Fatalf("Expected weight 50 but had %d", weight)
+	}
+
+	clock.Set(now.Add(15 * time.Second))
+	if weight := m.Weight(); weight != 0 {
+		t.Fatalf("Expected weight 0 but had %d", weight)
+	}
+
+	// Skipping more than the whole window clears the meter
+	m.Add(25)
+	clock.Set(now.Add(time.Hour))
+	if weight := m.Weight(); weight != 0 {
+		t.Fatalf("Expected weight 0 but had %d", weight)
+	}
+}
@@ -20,3 +20,15 @@ func (errs *Errs) Add(errors ...error) {
 		}
 	}
 }
+
+// AddFunc calls [fs] in order until one of them returns an error, which is
+// then recorded. Unlike Add, once an error has been recorded the remaining
+// functions, and all functions passed to later calls, are never called.
+func (errs *Errs) AddFunc(fs ...func() error) {
+	for _, f := range fs {
+		if errs.Err != nil {
+			return
+		}
+		errs.Err = f()
+	}
+}
@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package wrappers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrsAdd(t *testing.T) {
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+
+	errs := Errs{}
+	if errs.Errored() {
+		t.Fatalf("Shouldn't have errored yet")
+	}
+
+	errs.Add(nil, err1, err2)
+	if !errs.Errored() {
+		t.Fatalf("Should have errored")
+	}
+	if errs.Err != err1 {
+		t.Fatalf("Expected %s but got %s", err1, errs.Err)
+	}
+
+	errs.Add(err2)
+	if errs.Err != err1 {
+		t.Fatalf("Expected the first error to be kept but got %s", errs.Err)
+	}
+}
+
+func TestErrsAddFunc(t *testing.T) {
+	err1 := errors.New("err1")
+
+	calls := 0
+	succeed := func() error { calls++; return nil }
+	fail := func() error { calls++; return err1 }
+
+	errs := Errs{}
+	errs.AddFunc(succeed, succeed)
+	if errs.Errored() {
+		t.Fatalf("Shouldn't have errored yet")
+	}
+
+	errs.AddFunc(fail, succeed)
+	if errs.Err != err1 {
+		t.Fatalf("Expected %s but got %v", err1, errs.Err)
+	}
+	errs.AddFunc(succeed)
+	if calls != 3 {
+		t.Fatalf("Expected 3 calls but got %d", calls)
+	}
+
+	// Errors added through Add also stop later functions from being called
+	errs = Errs{}
+	errs.Add(err1)
+	errs.AddFunc(succeed)
+	if calls != 3 {
+		t.Fatalf("Expected 3 calls but got %d", calls)
+	}
+}
@@ -46,6 +46,17 @@ type Packer struct {
 	Bytes []byte
 	// The offset that is being written to in the byte array
 	Offset int
+	// The byte order that integers, including length prefixes, are packed in.
+	// Defaults to big-endian.
+	ByteOrder binary.ByteOrder
+}
+
+// byteOrder returns the byte order integers are packed in
+func (p *Packer) byteOrder() binary.ByteOrder {
+	if p.ByteOrder == nil {
+		return binary.BigEndian
+	}
+	return p.ByteOrder
 }
 
 // CheckSpace requires that there is at least [bytes] of write space left in the
@@ -104,6 +115,42 @@ func (p *Packer) UnpackByte() byte {
 	return val
 }
 
+// Peek returns the next byte in the byte array without consuming it. Unlike
+// the unpack methods, an error is returned rather than added to the packer, so
+// a dispatcher can inspect the array without affecting later unpacking.
+func (p *Packer) Peek() (byte, error) {
+	switch {
+	case p.Errored():
+		return 0, p.Err
+	case p.Offset < 0:
+		return 0, errNegativeOffset
+	case p.Offset >= len(p.Bytes):
+		return 0, errBadLength
+	default:
+		return p.Bytes[p.Offset], nil
+	}
+}
+
+// PeekByte returns the next byte in the byte array without consuming it
+func (p *Packer) PeekByte() byte {
+	p.CheckSpace(ByteLen)
+	if p.Errored() {
+		return 0
+	}
+
+	return p.Bytes[p.Offset]
+}
+
+// Skip consumes the next [n] bytes of the byte array without unpacking them
+func (p *Packer) Skip(n int) {
+	p.CheckSpace(n)
+	if p.Errored() {
+		return
+	}
+
+	p.Offset += n
+}
+
 // PackShort append a short to the byte array
 func (p *Packer) PackShort(val uint16) {
 	p.Expand(ShortLen)
@@ -111,7 +158,7 @@ func (p *Packer) PackShort(val uint16) {
 		return
 	}
 
-	binary.BigEndian.PutUint16(p.Bytes[p.Offset:], val)
+	p.byteOrder().PutUint16(p.Bytes[p.Offset:], val)
 	p.Offset += ShortLen
 }
 
@@ -122,7 +169,7 @@ func (p *Packer) UnpackShort() uint16 {
 		return 0
 	}
 
-	val := binary.BigEndian.Uint16(p.Bytes[p.Offset:])
+	val := p.byteOrder().Uint16(p.Bytes[p.Offset:])
 	p.Offset += ShortLen
 	return val
 }
@@ -134,7 +181,7 @@ func (p *Packer) PackInt(val uint32) {
 		return
 	}
 
-	binary.BigEndian.PutUint32(p.Bytes[p.Offset:], val)
+	p.byteOrder().PutUint32(p.Bytes[p.Offset:], val)
 	p.Offset += IntLen
 }
 
@@ -145,7 +192,7 @@ func (p *Packer) UnpackInt() uint32 {
 		return 0
 	}
 
-	val := binary.BigEndian.Uint32(p.Bytes[p.Offset:])
+	val := p.byteOrder().Uint32(p.Bytes[p.Offset:])
 	p.Offset += IntLen
 	return val
 }
@@ -157,7 +204,7 @@ func (p *Packer) PackLong(val uint64) {
 		return
 	}
 
-	binary.BigEndian.PutUint64(p.Bytes[p.Offset:], val)
+	p.byteOrder().PutUint64(p.Bytes[p.Offset:], val)
 	p.Offset += LongLen
 }
 
@@ -168,7 +215,7 @@ func (p *Packer) UnpackLong() uint64 {
 		return 0
 	}
 
-	val := binary.BigEndian.Uint64(p.Bytes[p.Offset:])
+	val := p.byteOrder().Uint64(p.Bytes[p.Offset:])
 	p.Offset += LongLen
 	return val
 }
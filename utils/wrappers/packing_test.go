@@ -5,6 +5,7 @@ package wrappers
 
 import (
 	"bytes"
+	"encoding/binary"
 	"reflect"
 	"testing"
 )
@@ -566,3 +567,171 @@ func TestPacker2DByteSlice(t *testing.T) {
 		t.Fatal("should match")
 	}
 }
+
+func TestPackerByteOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		byteOrder binary.ByteOrder
+		expected  []byte
+	}{
+		{
+			name:      "default",
+			byteOrder: nil,
+			expected: []byte{
+				0x01, 0x02,
+				0x01, 0x02, 0x03, 0x04,
+				0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+				0x00, 0x00, 0x00, 0x02, 0xaa, 0xbb,
+			},
+		},
+		{
+			name:      "big endian",
+			byteOrder: binary.BigEndian,
+			expected: []byte{
+				0x01, 0x02,
+				0x01, 0x02, 0x03, 0x04,
+				0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+				0x00, 0x00, 0x00, 0x02, 0xaa, 0xbb,
+			},
+		},
+		{
+			name:      "little endian",
+			byteOrder: binary.LittleEndian,
+			expected: []byte{
+				0x02, 0x01,
+				0x04, 0x03, 0x02, 0x01,
+				0x08, 0x07, 0x06, 0x05, 0x04, 0x03, 0x02, 0x01,
+				0x02, 0x00, 0x00, 0x00, 0xaa, 0xbb,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := Packer{MaxSize: 1024, ByteOrder: test.byteOrder}
+			p.PackShort(0x0102)
+			p.PackInt(0x01020304)
+			p.PackLong(0x0102030405060708)
+			p.PackBytes([]byte{0xaa, 0xbb})
+			if p.Errored() {
+				t.Fatal(p.Err)
+			}
+			if !bytes.Equal(p.Bytes, test.expected) {
+				t.Fatalf("Packer wrote:\n%v\nExpected:\n%v", p.Bytes, test.expected)
+			}
+
+			p = Packer{Bytes: test.expected, ByteOrder: test.byteOrder}
+			if short := p.UnpackShort(); short != 0x0102 {
+				t.Fatalf("Packer.UnpackShort returned %x but expected %x", short, 0x0102)
+			}
+			if i := p.UnpackInt(); i != 0x01020304 {
+				t.Fatalf("Packer.UnpackInt returned %x but expected %x", i, 0x01020304)
+			}
+			if long := p.UnpackLong(); long != 0x0102030405060708 {
+				t.Fatalf("Packer.UnpackLong returned %x but expected %x", long, uint64(0x0102030405060708))
+			}
+			if b := p.UnpackBytes(); !bytes.Equal(b, []byte{0xaa, 0xbb}) {
+				t.Fatalf("Packer.UnpackBytes returned %v but expected %v", b, []byte{0xaa, 0xbb})
+			}
+			if p.Errored() {
+				t.Fatal(p.Err)
+			}
+		})
+	}
+}
+
+func TestPackerMismatchedByteOrder(t *testing.T) {
+	p := Packer{MaxSize: 4, ByteOrder: binary.LittleEndian}
+	p.PackInt(0x01020304)
+
+	p = Packer{Bytes: p.Bytes}
+	if i := p.UnpackInt(); i != 0x04030201 {
+		t.Fatalf("Packer.UnpackInt returned %x but expected %x", i, 0x04030201)
+	}
+}
+
+func TestPackerPeek(t *testing.T) {
+	p := Packer{MaxSize: 7}
+	p.PackByte(0x01)
+	p.PackShort(0x0203)
+	p.PackInt(0x04050607)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+
+	p = Packer{Bytes: p.Bytes}
+	op, err := p.Peek()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op != 0x01 {
+		t.Fatalf("Peek returned %x, expected %x", op, 0x01)
+	}
+	if op := p.PeekByte(); op != 0x01 {
+		t.Fatalf("PeekByte returned %x, expected %x", op, 0x01)
+	}
+	if p.Offset != 0 {
+		t.Fatalf("Peeking consumed %d bytes", p.Offset)
+	}
+
+	// Peeking shouldn't change what's unpacked
+	if op := p.UnpackByte(); op != 0x01 {
+		t.Fatalf("UnpackByte returned %x after peeking, expected %x", op, 0x01)
+	}
+
+	// Skip past the short to the int
+	p.Skip(ShortLen)
+	if next := p.PeekByte(); next != 0x04 {
+		t.Fatalf("PeekByte returned %x after skipping, expected %x", next, 0x04)
+	}
+	if val := p.UnpackInt(); val != 0x04050607 {
+		t.Fatalf("UnpackInt returned %x after skipping, expected %x", val, 0x04050607)
+	}
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+}
+
+func TestPackerPeekEndOfBuffer(t *testing.T) {
+	p := Packer{Bytes: []byte{0x01}, Offset: 1}
+	if _, err := p.Peek(); err == nil {
+		t.Fatal("Peek should have errored at the end of the buffer")
+	}
+	if p.Errored() {
+		t.Fatal("Peek shouldn't add errors to the packer")
+	}
+
+	if val := p.PeekByte(); val != ByteSentinal {
+		t.Fatalf("PeekByte returned %x, expected sentinal value %x", val, ByteSentinal)
+	}
+	if !p.Errored() {
+		t.Fatal("PeekByte should have errored at the end of the buffer")
+	}
+	if _, err := p.Peek(); err == nil {
+		t.Fatal("Peek should return the packer's error")
+	}
+}
+
+func TestPackerSkip(t *testing.T) {
+	p := Packer{Bytes: []byte{0x01, 0x02}}
+	p.Skip(2)
+	if p.Errored() {
+		t.Fatal(p.Err)
+	}
+	if p.Offset != 2 {
+		t.Fatalf("Skip moved the offset to %d, expected %d", p.Offset, 2)
+	}
+
+	p.Skip(1)
+	if !p.Errored() {
+		t.Fatal("Skip should have errored past the end of the buffer")
+	}
+	if p.Offset != 2 {
+		t.Fatalf("A failed skip moved the offset to %d", p.Offset)
+	}
+
+	p = Packer{Bytes: []byte{0x01}}
+	p.Skip(-1)
+	if !p.Errored() {
+		t.Fatal("Skip should have errored with a negative length")
+	}
+}
@@ -30,11 +30,12 @@ func (t *BaseTx) SyntacticVerify(
 	txFeeAssetID ids.ID,
 	txFee uint64,
 	_ int,
+	maxMemoSize int,
 ) error {
 	if t == nil {
 		return errNilTx
 	}
-	if err := t.MetadataVerify(ctx); err != nil {
+	if err := t.MetadataVerify(ctx, maxMemoSize); err != nil {
 		return err
 	}
 
@@ -222,7 +222,7 @@ func TestBaseTxSyntacticVerify(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err != nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -266,17 +266,75 @@ func TestBaseTxSyntacticVerifyMemoTooLarge(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatal("should have failed because memo is too large")
 	}
 }
 
+func TestBaseTxSyntacticVerifyMemoSize(t *testing.T) {
+	ctx := NewContext(t)
+	c := setupCodec()
+
+	// The cap may be larger than the default
+	maxMemoSize := 4 * avax.MaxMemoSize
+	tests := []struct {
+		memoSize    int
+		shouldError bool
+	}{
+		{memoSize: 0, shouldError: false},
+		{memoSize: maxMemoSize - 1, shouldError: false},
+		{memoSize: maxMemoSize, shouldError: false},
+		{memoSize: maxMemoSize + 1, shouldError: true},
+	}
+	for _, test := range tests {
+		tx := &BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    networkID,
+			BlockchainID: chainID,
+			Outs: []*avax.TransferableOutput{{
+				Asset: avax.Asset{ID: asset},
+				Out: &secp256k1fx.TransferOutput{
+					Amt: 12345,
+					OutputOwners: secp256k1fx.OutputOwners{
+						Threshold: 1,
+						Addrs:     []ids.ShortID{keys[0].PublicKey().Address()},
+					},
+				},
+			}},
+			Ins: []*avax.TransferableInput{{
+				UTXOID: avax.UTXOID{
+					TxID:        ids.NewID([32]byte{0xff}),
+					OutputIndex: 0,
+				},
+				Asset: avax.Asset{ID: asset},
+				In: &secp256k1fx.TransferInput{
+					Amt: 54321,
+					Input: secp256k1fx.Input{
+						SigIndices: []uint32{2},
+					},
+				},
+			}},
+		}}
+		if test.memoSize > 0 {
+			tx.Memo = make([]byte, test.memoSize)
+		}
+		tx.Initialize(nil, nil)
+
+		err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, maxMemoSize)
+		switch {
+		case test.shouldError && err == nil:
+			t.Fatalf("should have failed because a %d byte memo is too large", test.memoSize)
+		case !test.shouldError && err != nil:
+			t.Fatalf("a %d byte memo should have been allowed but failed with: %s", test.memoSize, err)
+		}
+	}
+}
+
 func TestBaseTxSyntacticVerifyNil(t *testing.T) {
 	ctx := NewContext(t)
 	c := setupCodec()
 
 	tx := (*BaseTx)(nil)
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Nil BaseTx should have errored")
 	}
 }
@@ -319,7 +377,7 @@ func TestBaseTxSyntacticVerifyWrongNetworkID(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Wrong networkID should have errored")
 	}
 }
@@ -362,7 +420,7 @@ func TestBaseTxSyntacticVerifyWrongChainID(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Wrong chain ID should have errored")
 	}
 }
@@ -396,7 +454,7 @@ func TestBaseTxSyntacticVerifyInvalidOutput(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Invalid output should have errored")
 	}
 }
@@ -453,7 +511,7 @@ func TestBaseTxSyntacticVerifyUnsortedOutputs(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Unsorted outputs should have errored")
 	}
 }
@@ -479,7 +537,7 @@ func TestBaseTxSyntacticVerifyInvalidInput(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Invalid input should have errored")
 	}
 }
@@ -542,7 +600,7 @@ func TestBaseTxSyntacticVerifyInputOverflow(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Input overflow should have errored")
 	}
 }
@@ -597,7 +655,7 @@ func TestBaseTxSyntacticVerifyOutputOverflow(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Output overflow should have errored")
 	}
 }
@@ -640,7 +698,7 @@ func TestBaseTxSyntacticVerifyInsufficientFunds(t *testing.T) {
 	}}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Insufficient funds should have errored")
 	}
 }
@@ -682,7 +740,7 @@ func TestBaseTxSyntacticVerifyUninitialized(t *testing.T) {
 		}},
 	}}
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Uninitialized tx should have errored")
 	}
 }
@@ -79,6 +79,7 @@ func (t *CreateAssetTx) SyntacticVerify(
 	txFeeAssetID ids.ID,
 	txFee uint64,
 	numFxs int,
+	maxMemoSize int,
 ) error {
 	switch {
 	case t == nil:
@@ -110,7 +111,7 @@ func (t *CreateAssetTx) SyntacticVerify(
 		}
 	}
 
-	if err := t.BaseTx.SyntacticVerify(ctx, c, txFeeAssetID, txFee, numFxs); err != nil {
+	if err := t.BaseTx.SyntacticVerify(ctx, c, txFeeAssetID, txFee, numFxs, maxMemoSize); err != nil {
 		return err
 	}
 
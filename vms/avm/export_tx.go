@@ -37,6 +37,7 @@ func (t *ExportTx) SyntacticVerify(
 	txFeeAssetID ids.ID,
 	txFee uint64,
 	_ int,
+	maxMemoSize int,
 ) error {
 	switch {
 	case t == nil:
@@ -47,7 +48,7 @@ func (t *ExportTx) SyntacticVerify(
 		return errNoExportOutputs
 	}
 
-	if err := t.MetadataVerify(ctx); err != nil {
+	if err := t.MetadataVerify(ctx, maxMemoSize); err != nil {
 		return err
 	}
 
@@ -90,21 +91,27 @@ func (t *ExportTx) SemanticVerify(vm *VM, tx UnsignedTx, creds []verify.Verifiab
 	return t.BaseTx.SemanticVerify(vm, tx, creds)
 }
 
+// exportedUTXO returns the UTXO produced by the [i]th exported output of the
+// transaction with ID [txID]
+func (t *ExportTx) exportedUTXO(txID ids.ID, i int) *avax.UTXO {
+	out := t.ExportedOuts[i]
+	return &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        txID,
+			OutputIndex: uint32(len(t.Outs) + i),
+		},
+		Asset: avax.Asset{ID: out.AssetID()},
+		Out:   out.Out,
+	}
+}
+
 // ExecuteWithSideEffects writes the batch with any additional side effects
 func (t *ExportTx) ExecuteWithSideEffects(vm *VM, batch database.Batch) error {
 	txID := t.ID()
 
 	elems := make([]*atomic.Element, len(t.ExportedOuts))
-	for i, out := range t.ExportedOuts {
-		utxo := &avax.UTXO{
-			UTXOID: avax.UTXOID{
-				TxID:        txID,
-				OutputIndex: uint32(len(t.Outs) + i),
-			},
-			Asset: avax.Asset{ID: out.AssetID()},
-			Out:   out.Out,
-		}
-
+	for i := range t.ExportedOuts {
+		utxo := t.exportedUTXO(txID, i)
 		utxoBytes, err := vm.codec.Marshal(utxo)
 		if err != nil {
 			return err
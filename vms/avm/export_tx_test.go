@@ -62,7 +62,7 @@ func TestExportTxSyntacticVerify(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err != nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -73,7 +73,7 @@ func TestExportTxSyntacticVerifyNil(t *testing.T) {
 
 	tx := (*ExportTx)(nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to a nil ExportTx")
 	}
 }
@@ -119,7 +119,7 @@ func TestExportTxSyntacticVerifyWrongNetworkID(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to a wrong network ID")
 	}
 }
@@ -170,7 +170,7 @@ func TestExportTxSyntacticVerifyWrongBlockchainID(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to wrong blockchain ID")
 	}
 }
@@ -217,7 +217,7 @@ func TestExportTxSyntacticVerifyInvalidMemo(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to memo field being too long")
 	}
 }
@@ -273,7 +273,7 @@ func TestExportTxSyntacticVerifyInvalidBaseOutput(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to an invalid base output")
 	}
 }
@@ -341,7 +341,7 @@ func TestExportTxSyntacticVerifyUnsortedBaseOutputs(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to unsorted base outputs")
 	}
 }
@@ -387,7 +387,7 @@ func TestExportTxSyntacticVerifyInvalidOutput(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to invalid output")
 	}
 }
@@ -445,7 +445,7 @@ func TestExportTxSyntacticVerifyUnsortedOutputs(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to unsorted outputs")
 	}
 }
@@ -511,7 +511,7 @@ func TestExportTxSyntacticVerifyInvalidInput(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to invalid input")
 	}
 }
@@ -577,7 +577,7 @@ func TestExportTxSyntacticVerifyUnsortedInputs(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to unsorted inputs")
 	}
 }
@@ -623,7 +623,7 @@ func TestExportTxSyntacticVerifyInvalidFlowCheck(t *testing.T) {
 	}
 	tx.Initialize(nil, nil)
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 0, avax.MaxMemoSize); err == nil {
 		t.Fatalf("should have errored due to an invalid flow check")
 	}
 }
@@ -0,0 +1,118 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/codec"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/vms/components/avax"
+)
+
+var (
+	errNilExportedUTXO   = errors.New("nil exported utxo")
+	errNotExportTx       = errors.New("transaction is not an export transaction")
+	errNotExportedOutput = errors.New("output wasn't exported")
+	errWrongExportTx     = errors.New("export transaction didn't produce the utxo")
+	errWrongExportedUTXO = errors.New("utxo doesn't match the exported output")
+)
+
+// ExportedUTXO is a UTXO exported by a transaction, along with that
+// transaction.
+//
+// The UTXO's TxID is the hash of [Tx], so hashing [Tx] and recomputing the UTXO
+// from its exported outputs shows that the UTXO was produced by the
+// transaction with that ID. It does not show that the transaction was
+// accepted. The source chain's status for the transaction must be checked
+// separately.
+type ExportedUTXO struct {
+	// Byte representation of the exported UTXO
+	UTXO []byte `serialize:"true" json:"utxo"`
+	// Byte representation of the signed export transaction
+	Tx []byte `serialize:"true" json:"tx"`
+}
+
+// GetExportedUTXO returns the UTXO with ID [utxoID], which must have been
+// exported by a transaction accepted by this chain, along with that
+// transaction
+func (vm *VM) GetExportedUTXO(utxoID avax.UTXOID) (*ExportedUTXO, error) {
+	status, err := vm.state.Status(utxoID.TxID)
+	if err != nil {
+		return nil, err
+	}
+	if status != choices.Accepted {
+		return nil, fmt.Errorf("transaction %s has status %s", utxoID.TxID, status)
+	}
+
+	tx, err := vm.state.Tx(utxoID.TxID)
+	if err != nil {
+		return nil, err
+	}
+	exportTx, ok := tx.UnsignedTx.(*ExportTx)
+	if !ok {
+		return nil, errNotExportTx
+	}
+	index := int(utxoID.OutputIndex) - len(exportTx.Outs)
+	if index < 0 || index >= len(exportTx.ExportedOuts) {
+		return nil, errNotExportedOutput
+	}
+
+	utxoBytes, err := vm.codec.Marshal(exportTx.exportedUTXO(utxoID.TxID, index))
+	if err != nil {
+		return nil, err
+	}
+	return &ExportedUTXO{
+		UTXO: utxoBytes,
+		Tx:   tx.Bytes(),
+	}, nil
+}
+
+// VerifyExportedUTXO verifies that the UTXO of [exported] was exported to the
+// chain [destinationChainID] by the transaction of [exported]. It doesn't
+// verify that the transaction was accepted. [c] must be able to parse the
+// source chain's transactions. Returns the exported UTXO.
+func VerifyExportedUTXO(c codec.Codec, exported *ExportedUTXO, destinationChainID ids.ID) (*avax.UTXO, error) {
+	if exported == nil {
+		return nil, errNilExportedUTXO
+	}
+
+	utxo := &avax.UTXO{}
+	if err := c.Unmarshal(exported.UTXO, utxo); err != nil {
+		return nil, fmt.Errorf("couldn't parse exported utxo: %w", err)
+	}
+	tx := &Tx{}
+	if err := c.Unmarshal(exported.Tx, tx); err != nil {
+		return nil, fmt.Errorf("couldn't parse export transaction: %w", err)
+	}
+
+	txID := ids.NewID(hashing.ComputeHash256Array(exported.Tx))
+	if !utxo.TxID.Equals(txID) {
+		return nil, errWrongExportTx
+	}
+	exportTx, ok := tx.UnsignedTx.(*ExportTx)
+	if !ok {
+		return nil, errNotExportTx
+	}
+	if !exportTx.DestinationChain.Equals(destinationChainID) {
+		return nil, errWrongBlockchainID
+	}
+	index := int(utxo.OutputIndex) - len(exportTx.Outs)
+	if index < 0 || index >= len(exportTx.ExportedOuts) {
+		return nil, errNotExportedOutput
+	}
+
+	expectedBytes, err := c.Marshal(exportTx.exportedUTXO(txID, index))
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(expectedBytes, exported.UTXO) {
+		return nil, errWrongExportedUTXO
+	}
+	return utxo, nil
+}
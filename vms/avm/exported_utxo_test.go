@@ -0,0 +1,193 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package avm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/chains/atomic"
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils/crypto"
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/components/avax"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// setupAcceptedExportTx returns a VM with an accepted export transaction that
+// exports 50000 AVAX to the platform chain
+func setupAcceptedExportTx(t *testing.T) (*VM, ids.ID) {
+	genesisBytes := BuildGenesisTest(t)
+
+	issuer := make(chan common.Message, 1)
+	baseDB := memdb.New()
+
+	m := &atomic.Memory{}
+	m.Initialize(logging.NoLog{}, prefixdb.New([]byte{0}, baseDB))
+
+	ctx := NewContext(t)
+	ctx.SharedMemory = m.NewSharedMemory(chainID)
+
+	genesisTx := GetFirstTxFromGenesisTest(genesisBytes, t)
+
+	avaxID := genesisTx.ID()
+
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	vm := &VM{}
+	if err := vm.Initialize(
+		ctx,
+		prefixdb.New([]byte{1}, baseDB),
+		genesisBytes,
+		issuer,
+		[]*common.Fx{{
+			ID: ids.Empty,
+			Fx: &secp256k1fx.Fx{},
+		}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	vm.batchTimeout = 0
+
+	if err := vm.Bootstrapping(); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Bootstrapped(); err != nil {
+		t.Fatal(err)
+	}
+
+	key := keys[0]
+
+	tx := &Tx{UnsignedTx: &ExportTx{
+		BaseTx: BaseTx{BaseTx: avax.BaseTx{
+			NetworkID:    networkID,
+			BlockchainID: chainID,
+			Ins: []*avax.TransferableInput{{
+				UTXOID: avax.UTXOID{
+					TxID:        avaxID,
+					OutputIndex: 1,
+				},
+				Asset: avax.Asset{ID: avaxID},
+				In: &secp256k1fx.TransferInput{
+					Amt:   50000,
+					Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+				},
+			}},
+		}},
+		DestinationChain: platformChainID,
+		ExportedOuts: []*avax.TransferableOutput{{
+			Asset: avax.Asset{ID: avaxID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 50000,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{key.PublicKey().Address()},
+				},
+			},
+		}},
+	}}
+	if err := tx.SignSECP256K1Fx(vm.codec, [][]*crypto.PrivateKeySECP256K1R{{key}}); err != nil {
+		t.Fatal(err)
+	}
+
+	parsedTx, err := vm.ParseTx(tx.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsedTx.Verify(); err != nil {
+		t.Fatal(err)
+	} else if err := parsedTx.Accept(); err != nil {
+		t.Fatal(err)
+	}
+	return vm, parsedTx.ID()
+}
+
+func TestExportedUTXO(t *testing.T) {
+	vm, txID := setupAcceptedExportTx(t)
+	defer func() {
+		vm.ctx.Lock.Lock()
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	exported, err := vm.GetExportedUTXO(avax.UTXOID{TxID: txID, OutputIndex: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utxo, err := VerifyExportedUTXO(vm.codec, exported, platformChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utxo.TxID.Equals(txID) || utxo.OutputIndex != 0 {
+		t.Fatalf("Verified the wrong utxo")
+	}
+	out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		t.Fatalf("Wrong output type")
+	}
+	if out.Amount() != 50000 {
+		t.Fatalf("Wrong amount. Expected %d ; Returned %d", 50000, out.Amount())
+	}
+
+	if _, err := VerifyExportedUTXO(vm.codec, exported, chainID); err == nil {
+		t.Fatalf("Should have errored due to the wrong destination chain")
+	}
+
+	if _, err := vm.GetExportedUTXO(avax.UTXOID{TxID: txID, OutputIndex: 1}); err == nil {
+		t.Fatalf("Should have errored due to an output that wasn't exported")
+	}
+	if _, err := vm.GetExportedUTXO(avax.UTXOID{TxID: ids.GenerateTestID()}); err == nil {
+		t.Fatalf("Should have errored due to an unknown transaction")
+	}
+}
+
+func TestExportedUTXOTampered(t *testing.T) {
+	vm, txID := setupAcceptedExportTx(t)
+	defer func() {
+		vm.ctx.Lock.Lock()
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	exported, err := vm.GetExportedUTXO(avax.UTXOID{TxID: txID, OutputIndex: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Claim a larger amount than was exported
+	utxo := &avax.UTXO{}
+	if err := vm.codec.Unmarshal(exported.UTXO, utxo); err != nil {
+		t.Fatal(err)
+	}
+	utxo.Out.(*secp256k1fx.TransferOutput).Amt++
+	tamperedUTXO, err := vm.codec.Marshal(utxo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyExportedUTXO(vm.codec, &ExportedUTXO{
+		UTXO: tamperedUTXO,
+		Tx:   exported.Tx,
+	}, platformChainID); err == nil {
+		t.Fatalf("Should have errored due to a tampered utxo")
+	}
+
+	// Modify the transaction, which changes its ID
+	tamperedTx := make([]byte, len(exported.Tx))
+	copy(tamperedTx, exported.Tx)
+	tamperedTx[len(tamperedTx)-1]++
+	if _, err := VerifyExportedUTXO(vm.codec, &ExportedUTXO{
+		UTXO: exported.UTXO,
+		Tx:   tamperedTx,
+	}, platformChainID); err == nil {
+		t.Fatalf("Should have errored due to a tampered transaction")
+	}
+
+	if _, err := VerifyExportedUTXO(vm.codec, nil, platformChainID); err == nil {
+		t.Fatalf("Should have errored due to a nil exported utxo")
+	}
+}
@@ -67,6 +67,7 @@ func (t *ImportTx) SyntacticVerify(
 	txFeeAssetID ids.ID,
 	txFee uint64,
 	numFxs int,
+	maxMemoSize int,
 ) error {
 	switch {
 	case t == nil:
@@ -77,7 +78,7 @@ func (t *ImportTx) SyntacticVerify(
 		return errNoImportInputs
 	}
 
-	if err := t.MetadataVerify(ctx); err != nil {
+	if err := t.MetadataVerify(ctx, maxMemoSize); err != nil {
 		return err
 	}
 
@@ -90,6 +90,7 @@ func (t *OperationTx) SyntacticVerify(
 	txFeeAssetID ids.ID,
 	txFee uint64,
 	numFxs int,
+	maxMemoSize int,
 ) error {
 	switch {
 	case t == nil:
@@ -98,7 +99,7 @@ func (t *OperationTx) SyntacticVerify(
 		return errNoOperations
 	}
 
-	if err := t.BaseTx.SyntacticVerify(ctx, c, txFeeAssetID, txFee, numFxs); err != nil {
+	if err := t.BaseTx.SyntacticVerify(ctx, c, txFeeAssetID, txFee, numFxs, maxMemoSize); err != nil {
 		return err
 	}
 
@@ -5,8 +5,10 @@ package avm
 
 import (
 	"github.com/ava-labs/gecko/cache"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/codec"
 	"github.com/ava-labs/gecko/vms/components/avax"
 )
 
@@ -30,6 +32,22 @@ type prefixedState struct {
 	uniqueTx           cache.Deduplicator
 }
 
+func newPrefixedState(db database.Database, codec codec.Codec) *prefixedState {
+	return &prefixedState{
+		state: &state{State: avax.State{
+			Cache: &cache.LRU{Size: stateCacheSize},
+			DB:    db,
+			Codec: codec,
+		}},
+
+		tx:       &cache.LRU{Size: idCacheSize},
+		utxo:     &cache.LRU{Size: idCacheSize},
+		txStatus: &cache.LRU{Size: idCacheSize},
+
+		uniqueTx: &cache.EvictableLRU{Size: txCacheSize},
+	}
+}
+
 // UniqueTx de-duplicates the transaction.
 func (s *prefixedState) UniqueTx(tx *UniqueTx) *UniqueTx {
 	return s.uniqueTx.Deduplicate(tx).(*UniqueTx)
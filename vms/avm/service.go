@@ -42,8 +42,21 @@ var (
 	errInvalidUTXO            = errors.New("invalid utxo")
 	errNilTxID                = errors.New("nil transaction ID")
 	errNoAddresses            = errors.New("no addresses provided")
+	errUnknownTxType          = errors.New("unknown transaction type")
+	errNoInputs               = errors.New("transaction must have an input to pay the fee")
+	errStartIndexAndPageToken = errors.New("start index and page token can't both be provided")
+	errAtomicPageToken        = errors.New("page tokens aren't supported for UTXOs on other chains")
 )
 
+// Transaction types that fees can be estimated for
+var estimableTxTypes = map[string]bool{
+	"base":        true,
+	"createAsset": true,
+	"operation":   true,
+	"import":      true,
+	"export":      true,
+}
+
 // Service defines the base service for the asset vm
 type Service struct{ vm *VM }
 
@@ -70,6 +83,30 @@ func (service *Service) IssueTx(r *http.Request, args *FormattedTx, reply *api.J
 	return nil
 }
 
+// DecodeTxReply defines the DecodeTx replies returned from the API
+type DecodeTxReply struct {
+	TxID     ids.ID   `json:"txID"`
+	Tx       *Tx      `json:"tx"`
+	AssetIDs []ids.ID `json:"assetIDs"`
+}
+
+// DecodeTx parses a transaction without issuing it, so that the caller can
+// check that the bytes describe the transaction they intended. The transaction
+// isn't verified.
+func (service *Service) DecodeTx(_ *http.Request, args *FormattedTx, reply *DecodeTxReply) error {
+	service.vm.ctx.Log.Info("AVM: DecodeTx called with %s", args.Tx)
+
+	tx, err := service.vm.decodeTx(args.Tx.Bytes)
+	if err != nil {
+		return fmt.Errorf("couldn't decode tx: %w", err)
+	}
+
+	reply.TxID = tx.ID()
+	reply.Tx = tx
+	reply.AssetIDs = tx.AssetIDs().List()
+	return nil
+}
+
 // GetTxStatusReply defines the GetTxStatus replies returned from the API
 type GetTxStatusReply struct {
 	Status choices.Status `json:"status"`
@@ -112,6 +149,47 @@ func (service *Service) GetTx(r *http.Request, args *api.JsonTxID, reply *Format
 	return nil
 }
 
+// EstimateFeeArgs describe the shape of a transaction to estimate the fee of
+type EstimateFeeArgs struct {
+	// One of "base", "createAsset", "operation", "import" or "export"
+	TxType string `json:"txType"`
+	// Number of inputs, including imported inputs
+	InputCount json.Uint32 `json:"inputCount"`
+	// Number of outputs, including exported outputs
+	OutputCount json.Uint32 `json:"outputCount"`
+	// Length of the memo, in bytes
+	MemoLen json.Uint32 `json:"memoLen"`
+}
+
+// EstimateFeeReply defines the EstimateFee replies returned from the API
+type EstimateFeeReply struct {
+	FormattedAssetID
+	Fee json.Uint64 `json:"fee"`
+}
+
+// EstimateFee returns the fee that a transaction of the given shape must burn
+// for IssueTx to accept it, and the asset the fee is paid in
+func (service *Service) EstimateFee(_ *http.Request, args *EstimateFeeArgs, reply *EstimateFeeReply) error {
+	service.vm.ctx.Log.Info("AVM: EstimateFee called with type %s, %d inputs, %d outputs and a %d byte memo",
+		args.TxType, args.InputCount, args.OutputCount, args.MemoLen)
+
+	switch {
+	case !estimableTxTypes[args.TxType]:
+		return fmt.Errorf("%w: %q", errUnknownTxType, args.TxType)
+	case int(args.MemoLen) > avax.MaxMemoSize:
+		return fmt.Errorf("memo length, %d, exceeds maximum memo length, %d",
+			args.MemoLen, avax.MaxMemoSize)
+	case args.InputCount == 0 && service.vm.txFee > 0:
+		return errNoInputs
+	}
+
+	// The fee is currently the same for every transaction, regardless of its
+	// type or size. This must match the fee passed to SyntacticVerify.
+	reply.AssetID = service.vm.ctx.AVAXAssetID
+	reply.Fee = json.Uint64(service.vm.txFee)
+	return nil
+}
+
 // Index is an address and an associated UTXO.
 // Marks a starting or stopping point when fetching UTXOs. Used for pagination.
 type Index struct {
@@ -127,12 +205,20 @@ type Index struct {
 // UTXOs fetched are from addresses equal to or greater than [StartIndex.Address]
 // For address [StartIndex.Address], only UTXOs with IDs greater than [StartIndex.Utxo] will be returned.
 // If [StartIndex] is omitted, gets all UTXOs.
+// Pages fetched with [StartIndex] are each read from the current state, so UTXOs
+// may appear or disappear between pages. For UTXOs on this chain, pages can
+// instead be fetched with [PageToken], set to the [NextPageToken] of the
+// previous page. All pages fetched that way are read from the same snapshot.
+// [StartIndex] and [PageToken] can't both be given.
+// If [AssetID] is given, only UTXOs of that asset are returned.
 // If GetUTXOs is called multiple times, with our without [StartIndex], it is not guaranteed
 // that returned UTXOs are unique. That is, the same UTXO may appear in the response of multiple calls.
 type GetUTXOsArgs struct {
 	Addresses  []string    `json:"addresses"`
 	Limit      json.Uint32 `json:"limit"`
 	StartIndex Index       `json:"startIndex"`
+	PageToken  string      `json:"pageToken"`
+	AssetID    string      `json:"assetID"`
 }
 
 // GetUTXOsReply defines the GetUTXOs replies returned from the API
@@ -143,6 +229,11 @@ type GetUTXOsReply struct {
 	// Used for pagination. To get the rest of the UTXOs, call GetUTXOs
 	// again and set [StartIndex] to this value.
 	EndIndex Index `json:"endIndex"`
+	// Set if the UTXOs on this chain were fetched without [StartIndex] and
+	// there may be more. To get the next page, call GetUTXOs again and set
+	// [PageToken] to this value. A page may hold fewer than [Limit] UTXOs even
+	// if there are more.
+	NextPageToken string `json:"nextPageToken"`
 }
 
 // GetUTXOs gets all utxos for passed in addresses
@@ -176,7 +267,8 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 
 	startAddr := ids.ShortEmpty
 	startUTXO := ids.Empty
-	if args.StartIndex.Address != "" || args.StartIndex.Utxo != "" {
+	hasStartIndex := args.StartIndex.Address != "" || args.StartIndex.Utxo != ""
+	if hasStartIndex {
 		addrChainID, addr, err := service.vm.ParseAddress(args.StartIndex.Address)
 		if err != nil {
 			return fmt.Errorf("couldn't parse start index address: %w", err)
@@ -194,26 +286,64 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 		startUTXO = utxo
 	}
 
+	assetID := ids.ID{}
+	if args.AssetID != "" {
+		id, err := service.vm.Lookup(args.AssetID)
+		if err != nil {
+			id, err = ids.FromString(args.AssetID)
+			if err != nil {
+				return fmt.Errorf("problem parsing assetID '%s': %w", args.AssetID, err)
+			}
+		}
+		assetID = id
+	}
+
+	pageToken := ids.ID{}
+	if args.PageToken != "" {
+		if hasStartIndex {
+			return errStartIndexAndPageToken
+		}
+		if !chainID.Equals(service.vm.ctx.ChainID) {
+			return errAtomicPageToken
+		}
+		token, err := ids.FromString(args.PageToken)
+		if err != nil {
+			return fmt.Errorf("problem parsing page token: %w", err)
+		}
+		pageToken = token
+	}
+
 	var (
 		utxos     []*avax.UTXO
 		endAddr   ids.ShortID
 		endUTXOID ids.ID
+		nextToken ids.ID
 		err       error
 	)
-	if chainID.Equals(service.vm.ctx.ChainID) {
-		utxos, endAddr, endUTXOID, err = service.vm.GetUTXOs(
+	switch {
+	case !chainID.Equals(service.vm.ctx.ChainID):
+		utxos, endAddr, endUTXOID, err = service.vm.GetAtomicUTXOs(
+			chainID,
 			addrSet,
 			startAddr,
 			startUTXO,
 			int(args.Limit),
+			assetID,
 		)
-	} else {
-		utxos, endAddr, endUTXOID, err = service.vm.GetAtomicUTXOs(
-			chainID,
+	case hasStartIndex:
+		utxos, endAddr, endUTXOID, err = service.vm.GetUTXOs(
 			addrSet,
 			startAddr,
 			startUTXO,
 			int(args.Limit),
+			assetID,
+		)
+	default:
+		utxos, endAddr, endUTXOID, nextToken, err = service.vm.GetUTXOsPage(
+			addrSet,
+			pageToken,
+			int(args.Limit),
+			assetID,
 		)
 	}
 	if err != nil {
@@ -236,6 +366,9 @@ func (service *Service) GetUTXOs(r *http.Request, args *GetUTXOsArgs, reply *Get
 
 	reply.EndIndex.Address = endAddress
 	reply.EndIndex.Utxo = endUTXOID.String()
+	if !nextToken.IsZero() {
+		reply.NextPageToken = nextToken.String()
+	}
 	return nil
 }
 
@@ -316,16 +449,13 @@ func (service *Service) GetBalance(r *http.Request, args *GetBalanceArgs, reply
 	addrSet := ids.ShortSet{}
 	addrSet.Add(addr)
 
-	utxos, _, _, err := service.vm.GetUTXOs(addrSet, ids.ShortEmpty, ids.Empty, -1)
+	utxos, _, _, err := service.vm.GetUTXOs(addrSet, ids.ShortEmpty, ids.Empty, -1, assetID)
 	if err != nil {
 		return fmt.Errorf("problem retrieving UTXOs: %w", err)
 	}
 
 	reply.UTXOIDs = make([]avax.UTXOID, 0, len(utxos))
 	for _, utxo := range utxos {
-		if !utxo.AssetID().Equals(assetID) {
-			continue
-		}
 		transferable, ok := utxo.Out.(avax.TransferableOut)
 		if !ok {
 			continue
@@ -367,7 +497,7 @@ func (service *Service) GetAllBalances(r *http.Request, args *api.JsonAddress, r
 	addrSet := ids.ShortSet{}
 	addrSet.Add(address)
 
-	utxos, _, _, err := service.vm.GetUTXOs(addrSet, ids.ShortEmpty, ids.Empty, -1)
+	utxos, _, _, err := service.vm.GetUTXOs(addrSet, ids.ShortEmpty, ids.Empty, -1, ids.ID{})
 	if err != nil {
 		return fmt.Errorf("couldn't get address's UTXOs: %s", err)
 	}
@@ -1315,7 +1445,7 @@ func (service *Service) ImportAVAX(_ *http.Request, args *ImportAVAXArgs, reply
 		return err
 	}
 
-	atomicUtxos, _, _, err := service.vm.GetAtomicUTXOs(chainID, kc.Addrs, ids.ShortEmpty, ids.Empty, -1)
+	atomicUtxos, _, _, err := service.vm.GetAtomicUTXOs(chainID, kc.Addrs, ids.ShortEmpty, ids.Empty, -1, ids.ID{})
 	if err != nil {
 		return fmt.Errorf("problem retrieving user's atomic UTXOs: %w", err)
 	}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -81,6 +82,44 @@ func TestServiceIssueTx(t *testing.T) {
 	}
 }
 
+func TestServiceDecodeTx(t *testing.T) {
+	genesisBytes, vm, s, _ := setup(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	tx := NewTx(t, genesisBytes, vm)
+	reply := DecodeTxReply{}
+	if err := s.DecodeTx(nil, &FormattedTx{Tx: formatting.CB58{Bytes: tx.Bytes()}}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.TxID.Equals(tx.ID()) {
+		t.Fatalf("Expected %q, got %q", tx.ID(), reply.TxID)
+	}
+	if !bytes.Equal(reply.Tx.Bytes(), tx.Bytes()) {
+		t.Fatalf("Decoded tx has the wrong bytes")
+	}
+	if len(reply.Tx.InputUTXOs()) != len(tx.InputUTXOs()) {
+		t.Fatalf("Expected %d inputs, got %d", len(tx.InputUTXOs()), len(reply.Tx.InputUTXOs()))
+	}
+	assetIDs := ids.Set{}
+	assetIDs.Add(reply.AssetIDs...)
+	if !assetIDs.Equals(tx.AssetIDs()) {
+		t.Fatalf("Expected asset IDs %s, got %s", tx.AssetIDs(), assetIDs)
+	}
+
+	// Decoding shouldn't issue or store the tx
+	if status := (&UniqueTx{vm: vm, txID: tx.ID()}).Status(); status != choices.Unknown {
+		t.Fatalf("Expected status %s, got %s", choices.Unknown, status)
+	}
+
+	truncated := tx.Bytes()[:len(tx.Bytes())/2]
+	if err := s.DecodeTx(nil, &FormattedTx{Tx: formatting.CB58{Bytes: truncated}}, &DecodeTxReply{}); err == nil {
+		t.Fatal("Expected truncated tx to return an error")
+	}
+}
+
 func TestServiceGetTxStatus(t *testing.T) {
 	genesisBytes, vm, s, _ := setup(t)
 	defer func() {
@@ -427,6 +466,46 @@ func TestServiceGetUTXOs(t *testing.T) {
 				},
 			},
 		},
+		{
+			label: "filter X-chain UTXOs by asset",
+			count: numUTXOs,
+			args: &GetUTXOsArgs{
+				Addresses: []string{
+					xAddr,
+				},
+				AssetID: vm.ctx.AVAXAssetID.String(),
+			},
+		},
+		{
+			label: "filter X-chain UTXOs by another asset",
+			count: 0,
+			args: &GetUTXOsArgs{
+				Addresses: []string{
+					xAddr,
+				},
+				AssetID: ids.GenerateTestID().String(),
+			},
+		},
+		{
+			label: "filter P-chain UTXOs by another asset",
+			count: 0,
+			args: &GetUTXOsArgs{
+				Addresses: []string{
+					pAddr,
+				},
+				AssetID: ids.GenerateTestID().String(),
+			},
+		},
+		{
+			label:     "invalid asset ID",
+			shouldErr: true,
+			args: &GetUTXOsArgs{
+				Addresses: []string{
+					xAddr,
+				},
+				AssetID: "foo",
+			},
+		},
 		{
 			label:     "get UTXOs from multiple chains",
 			shouldErr: true,
@@ -458,6 +537,275 @@ func TestServiceGetUTXOs(t *testing.T) {
 	}
 }
 
+func TestServiceGetUTXOsPagination(t *testing.T) {
+	_, vm, s, _ := setup(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	rawAddr := ids.GenerateTestShortID()
+	otherAssetID := ids.GenerateTestID()
+
+	numUTXOs := 250
+	numOtherUTXOs := 0
+	for i := 0; i < numUTXOs; i++ {
+		assetID := vm.ctx.AVAXAssetID
+		if i%5 < 2 {
+			assetID = otherAssetID
+			numOtherUTXOs++
+		}
+		if err := vm.state.FundUTXO(&avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID: ids.GenerateTestID(),
+			},
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{rawAddr},
+				},
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	xAddr, err := vm.FormatLocalAddress(rawAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// getAll pages through the UTXOs of [xAddr], 30 at a time
+	getAll := func(assetID string) []*avax.UTXO {
+		utxos := []*avax.UTXO(nil)
+		args := &GetUTXOsArgs{
+			Addresses: []string{xAddr},
+			Limit:     30,
+			AssetID:   assetID,
+		}
+		for {
+			reply := &GetUTXOsReply{}
+			if err := s.GetUTXOs(nil, args, reply); err != nil {
+				t.Fatal(err)
+			}
+			for _, utxoBytes := range reply.UTXOs {
+				utxo := &avax.UTXO{}
+				if err := vm.codec.Unmarshal(utxoBytes.Bytes, utxo); err != nil {
+					t.Fatal(err)
+				}
+				utxos = append(utxos, utxo)
+			}
+			if reply.NextPageToken == "" {
+				return utxos
+			}
+			args.PageToken = reply.NextPageToken
+		}
+	}
+
+	utxos := getAll("")
+	if len(utxos) != numUTXOs {
+		t.Fatalf("Expected %d utxos, got %d", numUTXOs, len(utxos))
+	}
+	seen := ids.Set{}
+	for _, utxo := range utxos {
+		seen.Add(utxo.InputID())
+	}
+	if seen.Len() != numUTXOs {
+		t.Fatalf("Expected %d unique utxos, got %d", numUTXOs, seen.Len())
+	}
+
+	otherUTXOs := getAll(otherAssetID.String())
+	if len(otherUTXOs) != numOtherUTXOs {
+		t.Fatalf("Expected %d utxos, got %d", numOtherUTXOs, len(otherUTXOs))
+	}
+	seen.Clear()
+	for _, utxo := range otherUTXOs {
+		if !utxo.AssetID().Equals(otherAssetID) {
+			t.Fatalf("Expected only UTXOs of asset %s, got one of asset %s", otherAssetID, utxo.AssetID())
+		}
+		seen.Add(utxo.InputID())
+	}
+	if seen.Len() != numOtherUTXOs {
+		t.Fatalf("Expected %d unique utxos, got %d", numOtherUTXOs, seen.Len())
+	}
+}
+
+func TestServiceGetUTXOsPagesAreStable(t *testing.T) {
+	_, vm, s, _ := setup(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	rawAddr := ids.GenerateTestShortID()
+	fund := func() {
+		if err := vm.state.FundUTXO(&avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID: ids.GenerateTestID(),
+			},
+			Asset: avax.Asset{ID: vm.ctx.AVAXAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{rawAddr},
+				},
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	numUTXOs := 100
+	for i := 0; i < numUTXOs; i++ {
+		fund()
+	}
+	if err := vm.db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	xAddr, err := vm.FormatLocalAddress(rawAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := &GetUTXOsArgs{
+		Addresses: []string{xAddr},
+		Limit:     30,
+	}
+	numFetched := 0
+	for {
+		reply := &GetUTXOsReply{}
+		if err := s.GetUTXOs(nil, args, reply); err != nil {
+			t.Fatal(err)
+		}
+		numFetched += len(reply.UTXOs)
+		if reply.NextPageToken == "" {
+			break
+		}
+		args.PageToken = reply.NextPageToken
+
+		// UTXOs funded after the first page shouldn't show up in later pages
+		fund()
+		if err := vm.db.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if numFetched != numUTXOs {
+		t.Fatalf("Expected %d utxos, got %d", numUTXOs, numFetched)
+	}
+
+	// Tokens can only be used once
+	if err := s.GetUTXOs(nil, args, &GetUTXOsReply{}); err == nil {
+		t.Fatalf("Should have errored on a used page token")
+	}
+}
+
+func TestServiceGetUTXOsScanCap(t *testing.T) {
+	_, vm, s, _ := setup(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	rawAddr := ids.GenerateTestShortID()
+	otherAssetID := ids.GenerateTestID()
+
+	// The only UTXO of the requested asset is preceded by more UTXOs of other
+	// assets than a single call reads
+	for i := 0; i <= maxUTXOsToScan; i++ {
+		assetID := otherAssetID
+		if i == maxUTXOsToScan {
+			assetID = vm.ctx.AVAXAssetID
+		}
+		txID := [32]byte{}
+		txID[0] = byte(i >> 8)
+		txID[1] = byte(i)
+		if err := vm.state.FundUTXO(&avax.UTXO{
+			UTXOID: avax.UTXOID{
+				TxID: ids.NewID(txID),
+			},
+			Asset: avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt: 1,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{rawAddr},
+				},
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	xAddr, err := vm.FormatLocalAddress(rawAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := &GetUTXOsArgs{
+		Addresses: []string{xAddr},
+		AssetID:   vm.ctx.AVAXAssetID.String(),
+	}
+	numFetched := 0
+	numCalls := 0
+	for {
+		reply := &GetUTXOsReply{}
+		if err := s.GetUTXOs(nil, args, reply); err != nil {
+			t.Fatal(err)
+		}
+		numCalls++
+		numFetched += len(reply.UTXOs)
+		if reply.NextPageToken == "" {
+			break
+		}
+		args.PageToken = reply.NextPageToken
+	}
+	if numFetched != 1 {
+		t.Fatalf("Expected 1 utxo, got %d", numFetched)
+	}
+	if numCalls < 2 {
+		t.Fatalf("A single call shouldn't have read more than %d UTXOs", maxUTXOsToScan)
+	}
+}
+
+func TestServiceGetUTXOsPageTokenExpires(t *testing.T) {
+	_, vm, s, _ := setup(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	xAddr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := &GetUTXOsArgs{
+		Addresses: []string{xAddr},
+		Limit:     1,
+	}
+	reply := &GetUTXOsReply{}
+	if err := s.GetUTXOs(nil, args, reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.NextPageToken == "" {
+		t.Fatalf("Expected a page token")
+	}
+
+	vm.clock.Set(vm.clock.Time().Add(utxoCursorTimeout + time.Second))
+
+	args.PageToken = reply.NextPageToken
+	if err := s.GetUTXOs(nil, args, &GetUTXOsReply{}); err == nil {
+		t.Fatalf("Should have errored on an expired page token")
+	}
+	if len(vm.utxoCursors) != 0 {
+		t.Fatalf("Expired snapshot should have been released")
+	}
+}
+
 func TestGetAssetDescription(t *testing.T) {
 	genesisBytes, vm, s, _ := setup(t)
 	defer func() {
@@ -979,3 +1327,101 @@ func TestImportAVAX(t *testing.T) {
 		t.Fatalf("Failed to import AVAX due to %s", err)
 	}
 }
+
+func TestEstimateFee(t *testing.T) {
+	_, vm, s, _ := setupWithKeys(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	vm.txFee = 7
+	avaxID := vm.ctx.AVAXAssetID
+
+	addrStr, err := vm.FormatLocalAddress(keys[0].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sendReply := &api.JsonTxID{}
+	vm.timer.Cancel()
+	err = s.Send(nil, &SendArgs{
+		UserPass: api.UserPass{
+			Username: username,
+			Password: password,
+		},
+		Amount:  500,
+		AssetID: avaxID.String(),
+		To:      addrStr,
+	}, sendReply)
+	if err != nil {
+		t.Fatalf("Failed to send transaction: %s", err)
+	}
+	if len(vm.txs) != 1 {
+		t.Fatalf("Expected to find 1 pending tx after send, but found %d", len(vm.txs))
+	}
+	tx := vm.txs[0].(*UniqueTx).Tx
+	baseTx := tx.UnsignedTx.(*BaseTx)
+
+	reply := &EstimateFeeReply{}
+	err = s.EstimateFee(nil, &EstimateFeeArgs{
+		TxType:      "base",
+		InputCount:  json.Uint32(len(baseTx.Ins)),
+		OutputCount: json.Uint32(len(baseTx.Outs)),
+		MemoLen:     json.Uint32(len(baseTx.Memo)),
+	}, reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, avaxID, reply.AssetID)
+
+	// The estimate should be exactly what the issued tx burned
+	burned := uint64(0)
+	for _, in := range baseTx.Ins {
+		if in.AssetID().Equals(avaxID) {
+			burned += in.Input().Amount()
+		}
+	}
+	for _, out := range baseTx.Outs {
+		if out.AssetID().Equals(avaxID) {
+			burned -= out.Output().Amount()
+		}
+	}
+	assert.Equal(t, burned, uint64(reply.Fee))
+
+	// And any higher fee would have caused the tx to be rejected
+	err = tx.SyntacticVerify(vm.ctx, vm.codec, avaxID, uint64(reply.Fee)+1, len(vm.fxs), avax.MaxMemoSize)
+	assert.Error(t, err)
+}
+
+func TestEstimateFeeInvalidShape(t *testing.T) {
+	_, vm, s, _ := setup(t)
+	defer func() {
+		vm.Shutdown()
+		vm.ctx.Lock.Unlock()
+	}()
+
+	vm.txFee = 7
+
+	tests := map[string]*EstimateFeeArgs{
+		"unknown type": {
+			TxType:      "unknown",
+			InputCount:  1,
+			OutputCount: 1,
+		},
+		"memo too long": {
+			TxType:      "base",
+			InputCount:  1,
+			OutputCount: 1,
+			MemoLen:     avax.MaxMemoSize + 1,
+		},
+		"no inputs": {
+			TxType:      "export",
+			OutputCount: 1,
+		},
+	}
+	for name, args := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, s.EstimateFee(nil, args, &EstimateFeeReply{}))
+		})
+	}
+}
@@ -37,7 +37,7 @@ type UnsignedTx interface {
 	InputUTXOs() []*avax.UTXOID
 	UTXOs() []*avax.UTXO
 
-	SyntacticVerify(ctx *snow.Context, c codec.Codec, txFeeAssetID ids.ID, txFee uint64, numFxs int) error
+	SyntacticVerify(ctx *snow.Context, c codec.Codec, txFeeAssetID ids.ID, txFee uint64, numFxs int, maxMemoSize int) error
 	SemanticVerify(vm *VM, tx UnsignedTx, creds []verify.Verifiable) error
 	ExecuteWithSideEffects(vm *VM, batch database.Batch) error
 }
@@ -64,13 +64,14 @@ func (t *Tx) SyntacticVerify(
 	txFeeAssetID ids.ID,
 	txFee uint64,
 	numFxs int,
+	maxMemoSize int,
 ) error {
 	switch {
 	case t == nil || t.UnsignedTx == nil:
 		return errNilTx
 	}
 
-	if err := t.UnsignedTx.SyntacticVerify(ctx, c, txFeeAssetID, txFee, numFxs); err != nil {
+	if err := t.UnsignedTx.SyntacticVerify(ctx, c, txFeeAssetID, txFee, numFxs, maxMemoSize); err != nil {
 		return err
 	}
 
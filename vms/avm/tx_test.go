@@ -19,7 +19,7 @@ func TestTxNil(t *testing.T) {
 	ctx := NewContext(t)
 	c := codec.NewDefault()
 	tx := (*Tx)(nil)
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Should have errored due to nil tx")
 	}
 	if err := tx.SemanticVerify(nil, nil); err == nil {
@@ -46,7 +46,7 @@ func TestTxEmpty(t *testing.T) {
 	ctx := NewContext(t)
 	c := setupCodec()
 	tx := &Tx{}
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Should have errored due to nil tx")
 	}
 }
@@ -82,7 +82,7 @@ func TestTxInvalidCredential(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Tx should have failed due to an invalid credential")
 	}
 }
@@ -138,7 +138,7 @@ func TestTxInvalidUnsignedTx(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Tx should have failed due to an invalid unsigned tx")
 	}
 }
@@ -185,7 +185,7 @@ func TestTxInvalidNumberOfCredentials(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1); err == nil {
+	if err := tx.SyntacticVerify(ctx, c, ids.Empty, 0, 1, avax.MaxMemoSize); err == nil {
 		t.Fatalf("Tx should have failed due to an invalid unsigned tx")
 	}
 }
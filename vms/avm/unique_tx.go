@@ -144,6 +144,11 @@ func (tx *UniqueTx) Accept() error {
 	}
 
 	txID := tx.ID()
+	if err := tx.vm.replay.Accept(txID); err != nil {
+		tx.vm.ctx.Log.Error("Failed to accept tx %s due to %s", txID, err)
+		return err
+	}
+
 	commitBatch, err := tx.vm.db.CommitBatch()
 	if err != nil {
 		tx.vm.ctx.Log.Error("Failed to calculate CommitBatch for %s due to %s", txID, err)
@@ -296,7 +301,7 @@ func (tx *UniqueTx) SyntacticVerify() error {
 	}
 
 	tx.verifiedTx = true
-	tx.validity = tx.Tx.SyntacticVerify(tx.vm.ctx, tx.vm.codec, tx.vm.ctx.AVAXAssetID, tx.vm.txFee, len(tx.vm.fxs))
+	tx.validity = tx.Tx.SyntacticVerify(tx.vm.ctx, tx.vm.codec, tx.vm.ctx.AVAXAssetID, tx.vm.txFee, len(tx.vm.fxs), avax.MaxMemoSize)
 	return tx.validity
 }
 
@@ -310,6 +315,9 @@ func (tx *UniqueTx) SemanticVerify() error {
 		return tx.validity
 	}
 
+	if err := tx.vm.replay.Check(tx.ID()); err != nil {
+		return err
+	}
 	if err := tx.Tx.SemanticVerify(tx.vm, tx.UnsignedTx); err != nil {
 		return err
 	}
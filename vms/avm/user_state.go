@@ -34,7 +34,7 @@ func (s *userState) Addresses(db database.Database) ([]ids.ShortID, error) {
 }
 
 func (s *userState) SetKey(db database.Database, sk *crypto.PrivateKeySECP256K1R) error {
-	return db.Put(sk.PublicKey().Address().Bytes(), sk.Bytes())
+	return db.Put(sk.PublicKey().Address().Bytes(), sk.VersionedBytes())
 }
 
 func (s *userState) Key(db database.Database, address ids.ShortID) (*crypto.PrivateKeySECP256K1R, error) {
@@ -5,6 +5,7 @@ package avm
 
 import (
 	"bytes"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"reflect"
@@ -12,8 +13,9 @@ import (
 
 	"github.com/gorilla/rpc/v2"
 
-	"github.com/ava-labs/gecko/cache"
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	"github.com/ava-labs/gecko/database/snapshotdb"
 	"github.com/ava-labs/gecko/database/versiondb"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -28,6 +30,7 @@ import (
 	"github.com/ava-labs/gecko/utils/timer"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/avax"
+	"github.com/ava-labs/gecko/vms/components/replay"
 	"github.com/ava-labs/gecko/vms/components/verify"
 	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
@@ -43,8 +46,23 @@ const (
 	idCacheSize     = 30000
 	txCacheSize     = 30000
 	maxUTXOsToFetch = 1024
+
+	// Max number of UTXOs read in one call to GetUTXOs, including UTXOs that
+	// are skipped because they're of another asset
+	maxUTXOsToScan = 4 * maxUTXOsToFetch
+
+	// Max number of paginated GetUTXOs listings whose snapshots are retained
+	maxUTXOCursors = 64
+
+	// How long a GetUTXOs continuation token stays valid after it's returned
+	utxoCursorTimeout = time.Minute
+
+	// number of accepted txs whose IDs are remembered to prevent replays
+	replayGuardSize = 1 << 14
 )
 
+var replayPrefix = []byte("replay")
+
 var (
 	errIncompatibleFx            = errors.New("incompatible feature extension")
 	errUnknownFx                 = errors.New("unknown feature extension")
@@ -53,6 +71,7 @@ var (
 	errWrongBlockchainID         = errors.New("wrong blockchain ID")
 	errBootstrapping             = errors.New("chain is currently bootstrapping")
 	errInsufficientFunds         = errors.New("insufficient funds")
+	errUnknownPageToken          = errors.New("unknown or expired page token")
 )
 
 // VM implements the avalanche.DAGVM interface
@@ -73,6 +92,13 @@ type VM struct {
 	// State management
 	state *prefixedState
 
+	// Rejects txs that were recently accepted
+	replay *replay.Guard
+
+	// Snapshots retained between the pages of paginated GetUTXOs listings,
+	// keyed by the continuation token of the next page
+	utxoCursors map[[32]byte]*utxoCursor
+
 	// Set to true once this VM is marked as `Bootstrapped` by the engine
 	bootstrapped bool
 
@@ -122,10 +148,11 @@ func (vm *VM) Initialize(
 	vm.toEngine = toEngine
 	vm.baseDB = db
 	vm.db = versiondb.New(db)
+	vm.utxoCursors = make(map[[32]byte]*utxoCursor)
 	vm.typeToFxIndex = map[reflect.Type]int{}
 	vm.Aliaser.Initialize()
 
-	vm.pubsub = cjson.NewPubSubServer(ctx)
+	vm.pubsub = cjson.NewPubSubServer(ctx.Log)
 	c := codec.NewDefault()
 
 	errs := wrappers.Errs{}
@@ -171,19 +198,13 @@ func (vm *VM) Initialize(
 
 	vm.codec = c
 
-	vm.state = &prefixedState{
-		state: &state{State: avax.State{
-			Cache: &cache.LRU{Size: stateCacheSize},
-			DB:    vm.db,
-			Codec: vm.codec,
-		}},
-
-		tx:       &cache.LRU{Size: idCacheSize},
-		utxo:     &cache.LRU{Size: idCacheSize},
-		txStatus: &cache.LRU{Size: idCacheSize},
+	vm.state = newPrefixedState(vm.db, vm.codec)
 
-		uniqueTx: &cache.EvictableLRU{Size: txCacheSize},
+	guard, err := replay.New(prefixdb.New(replayPrefix, vm.db), replayGuardSize)
+	if err != nil {
+		return err
 	}
+	vm.replay = guard
 
 	if err := vm.initAliases(genesisBytes); err != nil {
 		return err
@@ -246,6 +267,10 @@ func (vm *VM) Shutdown() error {
 	vm.timer.Stop()
 	vm.ctx.Lock.Lock()
 
+	for key, cursor := range vm.utxoCursors {
+		delete(vm.utxoCursors, key)
+		cursor.db.Close()
+	}
 	return vm.baseDB.Close()
 }
 
@@ -338,17 +363,20 @@ func (vm *VM) IssueTx(b []byte) (ids.ID, error) {
 // GetAtomicUTXOs returns imported/exports UTXOs such that at least one of the addresses in [addrs] is referenced.
 // Returns at most [limit] UTXOs.
 // If [limit] <= 0 or [limit] > maxUTXOsToFetch, it is set to [maxUTXOsToFetch].
+// If [assetID] is set, only UTXOs of that asset are returned. At most
+// [maxUTXOsToScan] UTXOs are read, so fewer than [limit] UTXOs may be returned
+// even if there are more.
 // Returns:
 // * The fetched of UTXOs
-// * true if all there are no more UTXOs in this range to fetch
-// * The address associated with the last UTXO fetched
-// * The ID of the last UTXO fetched
+// * The address associated with the last UTXO read
+// * The ID of the last UTXO read
 func (vm *VM) GetAtomicUTXOs(
 	chainID ids.ID,
 	addrs ids.ShortSet,
 	startAddr ids.ShortID,
 	startUTXOID ids.ID,
 	limit int,
+	assetID ids.ID,
 ) ([]*avax.UTXO, ids.ShortID, ids.ID, error) {
 	if limit <= 0 || limit > maxUTXOsToFetch {
 		limit = maxUTXOsToFetch
@@ -359,33 +387,55 @@ func (vm *VM) GetAtomicUTXOs(
 		addrsList[i] = addr.Bytes()
 	}
 
-	allUTXOBytes, lastAddr, lastUTXO, err := vm.ctx.SharedMemory.Indexed(
-		chainID,
-		addrsList,
-		startAddr.Bytes(),
-		startUTXOID.Bytes(),
-		limit,
-	)
-	if err != nil {
-		return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("error fetching atomic UTXOs: %w", err)
-	}
+	utxos := make([]*avax.UTXO, 0, limit)
+	lastAddrID := ids.ShortEmpty
+	lastUTXOID := ids.Empty
+	startAddrBytes := startAddr.Bytes()
+	startUTXOBytes := startUTXOID.Bytes()
+	for scanned := 0; len(utxos) < limit && scanned < maxUTXOsToScan; {
+		// When filtering by asset, UTXOs of other assets may need to be
+		// skipped, so keep fetching until [limit] UTXOs are found, there are
+		// no more UTXOs, or [maxUTXOsToScan] UTXOs have been read
+		toFetch := limit - len(utxos)
+		if remaining := maxUTXOsToScan - scanned; toFetch > remaining {
+			toFetch = remaining
+		}
+		allUTXOBytes, lastAddr, lastUTXO, err := vm.ctx.SharedMemory.Indexed(
+			chainID,
+			addrsList,
+			startAddrBytes,
+			startUTXOBytes,
+			toFetch,
+		)
+		if err != nil {
+			return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("error fetching atomic UTXOs: %w", err)
+		}
 
-	lastAddrID, err := ids.ToShortID(lastAddr)
-	if err != nil {
-		lastAddrID = ids.ShortEmpty
-	}
-	lastUTXOID, err := ids.ToID(lastUTXO)
-	if err != nil {
-		lastUTXOID = ids.Empty
-	}
+		for _, utxoBytes := range allUTXOBytes {
+			utxo := &avax.UTXO{}
+			if err := vm.codec.Unmarshal(utxoBytes, utxo); err != nil {
+				return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("error parsing UTXO: %w", err)
+			}
+			if assetID.IsZero() || utxo.AssetID().Equals(assetID) {
+				utxos = append(utxos, utxo)
+			}
+		}
 
-	utxos := make([]*avax.UTXO, len(allUTXOBytes))
-	for i, utxoBytes := range allUTXOBytes {
-		utxo := &avax.UTXO{}
-		if err := vm.codec.Unmarshal(utxoBytes, utxo); err != nil {
-			return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("error parsing UTXO: %w", err)
+		if len(allUTXOBytes) == 0 {
+			break
+		}
+		scanned += len(allUTXOBytes)
+		if lastAddrID, err = ids.ToShortID(lastAddr); err != nil {
+			lastAddrID = ids.ShortEmpty
 		}
-		utxos[i] = utxo
+		if lastUTXOID, err = ids.ToID(lastUTXO); err != nil {
+			lastUTXOID = ids.Empty
+		}
+		if len(allUTXOBytes) < toFetch {
+			break
+		}
+		startAddrBytes = lastAddr
+		startUTXOBytes = lastUTXO
 	}
 	return utxos, lastAddrID, lastUTXOID, nil
 }
@@ -395,24 +445,172 @@ func (vm *VM) GetAtomicUTXOs(
 // If [limit] <= 0 or [limit] > maxUTXOsToFetch, it is set to [maxUTXOsToFetch].
 // Only returns UTXOs associated with addresses >= [startAddr].
 // For address [startAddr], only returns UTXOs whose IDs are greater than [startUtxoID].
+// If [assetID] is set, only UTXOs of that asset are returned. At most
+// [maxUTXOsToScan] UTXOs are read, so fewer than [limit] UTXOs may be returned
+// even if there are more.
+// The UTXOs are read from a snapshot of the database, so they're consistent
+// with each other even if the state changes while they're being read. Each call
+// takes a new snapshot, so UTXOs fetched by different calls may not be; see
+// GetUTXOsPage for a listing that's consistent across calls.
 // Returns:
 // * The fetched of UTXOs
-// * The address associated with the last UTXO fetched
-// * The ID of the last UTXO fetched
+// * The address associated with the last UTXO read
+// * The ID of the last UTXO read
 func (vm *VM) GetUTXOs(
 	addrs ids.ShortSet,
 	startAddr ids.ShortID,
 	startUTXOID ids.ID,
 	limit int,
+	assetID ids.ID,
 ) ([]*avax.UTXO, ids.ShortID, ids.ID, error) {
+	snapshot, err := vm.db.NewSnapshot()
+	if err != nil {
+		return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't snapshot the database: %w", err)
+	}
+	db := snapshotdb.New(snapshot)
+	defer db.Close()
+
+	utxos, lastAddr, lastUTXOID, _, err := getUTXOs(
+		newPrefixedState(db, vm.codec),
+		addrs,
+		startAddr,
+		startUTXOID,
+		limit,
+		assetID,
+	)
+	return utxos, lastAddr, lastUTXOID, err
+}
+
+// utxoCursor is a snapshot retained between the pages of a paginated GetUTXOs
+// listing, along with where the next page starts.
+type utxoCursor struct {
+	db       *snapshotdb.Database
+	state    *prefixedState
+	addr     ids.ShortID
+	utxoID   ids.ID
+	lastUsed time.Time
+}
+
+// GetUTXOsPage returns a page of the UTXOs such that at least one of the
+// addresses in [addrs] is referenced. [limit] and [assetID] are as in GetUTXOs.
+// If [token] is the zero value, a new listing is started from a new snapshot of
+// the database. Otherwise, the page continues where the page that returned
+// [token] stopped, and is read from the same snapshot. Every token can be used
+// once, and expires after [utxoCursorTimeout].
+// Returns:
+// * The fetched of UTXOs
+// * The address associated with the last UTXO read
+// * The ID of the last UTXO read
+// * The token of the next page, or the zero value if there are no more UTXOs
+func (vm *VM) GetUTXOsPage(
+	addrs ids.ShortSet,
+	token ids.ID,
+	limit int,
+	assetID ids.ID,
+) ([]*avax.UTXO, ids.ShortID, ids.ID, ids.ID, error) {
+	cursor := (*utxoCursor)(nil)
+	if token.IsZero() {
+		snapshot, err := vm.db.NewSnapshot()
+		if err != nil {
+			return nil, ids.ShortID{}, ids.ID{}, ids.ID{}, fmt.Errorf("couldn't snapshot the database: %w", err)
+		}
+		db := snapshotdb.New(snapshot)
+		cursor = &utxoCursor{
+			db:     db,
+			state:  newPrefixedState(db, vm.codec),
+			addr:   ids.ShortEmpty,
+			utxoID: ids.Empty,
+		}
+	} else {
+		key := token.Key()
+		c, ok := vm.utxoCursors[key]
+		if !ok {
+			return nil, ids.ShortID{}, ids.ID{}, ids.ID{}, errUnknownPageToken
+		}
+		delete(vm.utxoCursors, key)
+		if vm.clock.Time().Sub(c.lastUsed) > utxoCursorTimeout {
+			c.db.Close()
+			return nil, ids.ShortID{}, ids.ID{}, ids.ID{}, errUnknownPageToken
+		}
+		cursor = c
+	}
+
+	utxos, lastAddr, lastUTXOID, done, err := getUTXOs(
+		cursor.state,
+		addrs,
+		cursor.addr,
+		cursor.utxoID,
+		limit,
+		assetID,
+	)
+	if err != nil || done {
+		cursor.db.Close()
+		return utxos, lastAddr, lastUTXOID, ids.ID{}, err
+	}
+
+	cursor.addr = lastAddr
+	cursor.utxoID = lastUTXOID
+	nextToken, err := vm.retainUTXOCursor(cursor)
+	if err != nil {
+		cursor.db.Close()
+		return nil, ids.ShortID{}, ids.ID{}, ids.ID{}, err
+	}
+	return utxos, lastAddr, lastUTXOID, nextToken, nil
+}
+
+// retainUTXOCursor stores [cursor] until the returned token is used or
+// expires. If [maxUTXOCursors] cursors are already retained, the least
+// recently used one is released.
+func (vm *VM) retainUTXOCursor(cursor *utxoCursor) (ids.ID, error) {
+	now := vm.clock.Time()
+
+	oldestKey := [32]byte{}
+	oldest := (*utxoCursor)(nil)
+	for key, c := range vm.utxoCursors {
+		if now.Sub(c.lastUsed) > utxoCursorTimeout {
+			delete(vm.utxoCursors, key)
+			c.db.Close()
+			continue
+		}
+		if oldest == nil || c.lastUsed.Before(oldest.lastUsed) {
+			oldestKey = key
+			oldest = c
+		}
+	}
+	if oldest != nil && len(vm.utxoCursors) >= maxUTXOCursors {
+		delete(vm.utxoCursors, oldestKey)
+		oldest.db.Close()
+	}
+
+	key := [32]byte{}
+	if _, err := rand.Read(key[:]); err != nil {
+		return ids.ID{}, fmt.Errorf("couldn't generate page token: %w", err)
+	}
+	cursor.lastUsed = now
+	vm.utxoCursors[key] = cursor
+	return ids.NewID(key), nil
+}
+
+// getUTXOs reads the UTXOs for GetUTXOs and GetUTXOsPage from [state]. The
+// returned bool is true if every UTXO after [startAddr] and [startUTXOID] was
+// read.
+func getUTXOs(
+	state *prefixedState,
+	addrs ids.ShortSet,
+	startAddr ids.ShortID,
+	startUTXOID ids.ID,
+	limit int,
+	assetID ids.ID,
+) ([]*avax.UTXO, ids.ShortID, ids.ID, bool, error) {
 	if limit <= 0 || limit > maxUTXOsToFetch {
 		limit = maxUTXOsToFetch
 	}
 
 	seen := ids.Set{} // IDs of UTXOs already in the list
 	utxos := make([]*avax.UTXO, 0, limit)
-	lastAddr := ids.ShortEmpty
-	lastIndex := ids.Empty
+	scanned := 0 // Number of UTXOs read, including skipped ones
+	lastAddr := startAddr
+	lastIndex := startUTXOID
 	addrsList := addrs.List()
 	ids.SortShortIDs(addrsList)
 	for _, addr := range addrsList {
@@ -422,29 +620,45 @@ func (vm *VM) GetUTXOs(
 		} else if comp == 0 {
 			start = startUTXOID
 		}
-		utxoIDs, err := vm.state.Funds(addr.Bytes(), start, limit) // Get UTXOs associated with [addr]
-		if err != nil {
-			return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXOs for address %s", addr)
-		}
-		for _, utxoID := range utxoIDs {
-			if seen.Contains(utxoID) { // Already have this UTXO in the list
-				continue
+
+		// UTXOs that were already seen or are of other assets are skipped, so
+		// keep fetching until [limit] UTXOs are found or [addr] has no more
+		for {
+			if len(utxos) >= limit || scanned >= maxUTXOsToScan {
+				return utxos, lastAddr, lastIndex, false, nil
+			}
+			toFetch := limit - len(utxos)
+			if remaining := maxUTXOsToScan - scanned; toFetch > remaining {
+				toFetch = remaining
 			}
-			utxo, err := vm.state.UTXO(utxoID)
+			utxoIDs, err := state.Funds(addr.Bytes(), start, toFetch) // Get UTXOs associated with [addr]
 			if err != nil {
-				return nil, ids.ShortID{}, ids.ID{}, fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
+				return nil, ids.ShortID{}, ids.ID{}, false, fmt.Errorf("couldn't get UTXOs for address %s", addr)
+			}
+			scanned += len(utxoIDs)
+			for _, utxoID := range utxoIDs {
+				start = utxoID
+				lastAddr = addr
+				lastIndex = utxoID
+				if seen.Contains(utxoID) { // Already have this UTXO in the list
+					continue
+				}
+				utxo, err := state.UTXO(utxoID)
+				if err != nil {
+					return nil, ids.ShortID{}, ids.ID{}, false, fmt.Errorf("couldn't get UTXO %s: %w", utxoID, err)
+				}
+				if !assetID.IsZero() && !utxo.AssetID().Equals(assetID) {
+					continue
+				}
+				utxos = append(utxos, utxo)
+				seen.Add(utxoID)
 			}
-			utxos = append(utxos, utxo)
-			seen.Add(utxoID)
-			lastAddr = addr
-			lastIndex = utxoID
-			limit--
-			if limit <= 0 {
-				break // Found [limit] utxos; stop.
+			if len(utxoIDs) < toFetch {
+				break // There are no more UTXOs for [addr]
 			}
 		}
 	}
-	return utxos, lastAddr, lastIndex, nil
+	return utxos, lastAddr, lastIndex, true, nil
 }
 
 /*
@@ -550,10 +764,10 @@ func (vm *VM) initState(genesisBytes []byte) error {
 	return vm.state.SetDBInitialized(choices.Processing)
 }
 
-func (vm *VM) parseTx(bytes []byte) (*UniqueTx, error) {
+// decodeTx unmarshals [bytes] into a tx, without verifying or storing it
+func (vm *VM) decodeTx(bytes []byte) (*Tx, error) {
 	rawTx := &Tx{}
-	err := vm.codec.Unmarshal(bytes, rawTx)
-	if err != nil {
+	if err := vm.codec.Unmarshal(bytes, rawTx); err != nil {
 		return nil, err
 	}
 	unsignedBytes, err := vm.codec.Marshal(&rawTx.UnsignedTx)
@@ -561,6 +775,14 @@ func (vm *VM) parseTx(bytes []byte) (*UniqueTx, error) {
 		return nil, err
 	}
 	rawTx.Initialize(unsignedBytes, bytes)
+	return rawTx, nil
+}
+
+func (vm *VM) parseTx(bytes []byte) (*UniqueTx, error) {
+	rawTx, err := vm.decodeTx(bytes)
+	if err != nil {
+		return nil, err
+	}
 
 	tx := &UniqueTx{
 		TxState: &TxState{
@@ -733,7 +955,7 @@ func (vm *VM) LoadUser(
 	for _, addr := range addresses {
 		addrs.Add(addr)
 	}
-	utxos, _, _, err := vm.GetUTXOs(addrs, ids.ShortEmpty, ids.Empty, -1)
+	utxos, _, _, err := vm.GetUTXOs(addrs, ids.ShortEmpty, ids.Empty, -1, ids.ID{})
 	if err != nil {
 		return nil, nil, fmt.Errorf("problem retrieving user's UTXOs: %w", err)
 	}
@@ -23,6 +23,7 @@ import (
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/units"
 	"github.com/ava-labs/gecko/vms/components/avax"
+	"github.com/ava-labs/gecko/vms/components/replay"
 	"github.com/ava-labs/gecko/vms/components/verify"
 	"github.com/ava-labs/gecko/vms/nftfx"
 	"github.com/ava-labs/gecko/vms/propertyfx"
@@ -550,6 +551,34 @@ func TestIssueTx(t *testing.T) {
 	}
 }
 
+func TestIssueTxReplayed(t *testing.T) {
+	genesisBytes, _, vm, _ := GenesisVM(t)
+	ctx := vm.ctx
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	newTx := NewTx(t, genesisBytes, vm)
+
+	// Simulate a restart after the tx was recorded as accepted
+	if err := vm.replay.Accept(newTx.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.db.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	guard, err := replay.New(prefixdb.New(replayPrefix, vm.db), replayGuardSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.replay = guard
+
+	if _, err := vm.IssueTx(newTx.Bytes()); !errors.Is(err, replay.ErrReplayedTx) {
+		t.Fatalf("Expected %s but got %v", replay.ErrReplayedTx, err)
+	}
+}
+
 func TestGenesisGetUTXOs(t *testing.T) {
 	_, _, vm , _ := GenesisVM(t)
 	ctx := vm.ctx
@@ -562,7 +591,7 @@ func TestGenesisGetUTXOs(t *testing.T) {
 
 	addrs := ids.ShortSet{}
 	addrs.Add(addr)
-	utxos, _, _, err := vm.GetUTXOs(addrs, ids.ShortEmpty, ids.Empty, -1)
+	utxos, _, _, err := vm.GetUTXOs(addrs, ids.ShortEmpty, ids.Empty, -1, ids.ID{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -11,7 +11,8 @@ import (
 	"github.com/ava-labs/gecko/snow"
 )
 
-// MaxMemoSize is the maximum number of bytes in the memo field
+// MaxMemoSize is the maximum number of bytes in the memo field. As it decides
+// which txs are valid, every node on a network must use the same value.
 const MaxMemoSize = 256
 
 var (
@@ -28,7 +29,7 @@ type BaseTx struct {
 	BlockchainID ids.ID                `serialize:"true" json:"blockchainID"` // ID of the chain on which this transaction exists (prevents replay attacks)
 	Outs         []*TransferableOutput `serialize:"true" json:"outputs"`      // The outputs of this transaction
 	Ins          []*TransferableInput  `serialize:"true" json:"inputs"`       // The inputs to this transaction
-	Memo         []byte                `serialize:"true" json:"memo"`         // Memo field contains arbitrary bytes, up to maxMemoSize
+	Memo         []byte                `serialize:"true" json:"memo"`         // Memo field contains arbitrary bytes, up to the chain's maximum memo size
 }
 
 // InputUTXOs track which UTXOs this transaction is consuming.
@@ -72,8 +73,9 @@ func (t *BaseTx) UTXOs() []*UTXO {
 	return utxos
 }
 
-// MetadataVerify ensures that transaction metadata is valid
-func (t *BaseTx) MetadataVerify(ctx *snow.Context) error {
+// MetadataVerify ensures that transaction metadata is valid and that the memo
+// is at most [maxMemoSize] bytes
+func (t *BaseTx) MetadataVerify(ctx *snow.Context, maxMemoSize int) error {
 	switch {
 	case t == nil:
 		return errNilTx
@@ -81,9 +83,9 @@ func (t *BaseTx) MetadataVerify(ctx *snow.Context) error {
 		return errWrongNetworkID
 	case !t.BlockchainID.Equals(ctx.ChainID):
 		return errWrongChainID
-	case len(t.Memo) > MaxMemoSize:
+	case len(t.Memo) > maxMemoSize:
 		return fmt.Errorf("memo length, %d, exceeds maximum memo length, %d",
-			len(t.Memo), MaxMemoSize)
+			len(t.Memo), maxMemoSize)
 	default:
 		return t.Metadata.Verify()
 	}
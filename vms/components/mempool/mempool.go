@@ -0,0 +1,215 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mempool
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"math/bits"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+var (
+	errInvalidSize   = errors.New("mempool size must be positive")
+	errDuplicateTx   = errors.New("tx is already in the mempool")
+	errConflictingTx = errors.New("tx conflicts with accepted state")
+	errMempoolFull   = errors.New("mempool is full and tx fee is too low to evict another tx")
+)
+
+// Tx is a transaction that can be stored in the mempool
+type Tx interface {
+	ID() ids.ID
+	Bytes() []byte
+	// Fee returns the fee this tx pays. Txs are ordered by their fee divided
+	// by the length of their byte representation.
+	Fee() uint64
+}
+
+// ConflictFunc returns true if [tx] can no longer be issued, for example
+// because one of its inputs has been consumed
+type ConflictFunc func(tx Tx) bool
+
+// Mempool stores pending transactions and orders them by fee-per-byte for
+// block building. When the mempool is full, adding a tx evicts the tx with
+// the lowest fee-per-byte, if the new tx pays more.
+type Mempool struct {
+	maxSize   int
+	conflicts ConflictFunc
+
+	txs     map[[32]byte]*txEntry
+	ordered txHeap
+}
+
+// New returns a new mempool that holds at most [maxSize] txs. If [conflicts]
+// is non-nil, it is used to reject and drop txs that can no longer be issued.
+func New(maxSize int, conflicts ConflictFunc) (*Mempool, error) {
+	if maxSize <= 0 {
+		return nil, errInvalidSize
+	}
+	return &Mempool{
+		maxSize:   maxSize,
+		conflicts: conflicts,
+		txs:       make(map[[32]byte]*txEntry),
+	}, nil
+}
+
+// Add [tx] to the mempool. If the mempool is full, the tx with the lowest
+// fee-per-byte is evicted and returned.
+func (m *Mempool) Add(tx Tx) (Tx, error) {
+	txKey := tx.ID().Key()
+	if _, exists := m.txs[txKey]; exists {
+		return nil, errDuplicateTx
+	}
+	if m.conflicts != nil && m.conflicts(tx) {
+		return nil, errConflictingTx
+	}
+
+	entry := &txEntry{tx: tx, size: uint64(len(tx.Bytes()))}
+
+	var evicted Tx
+	if len(m.txs) >= m.maxSize {
+		lowest := m.lowest()
+		if !lowest.less(entry) {
+			return nil, errMempoolFull
+		}
+		m.remove(lowest)
+		evicted = lowest.tx
+	}
+
+	m.txs[txKey] = entry
+	heap.Push(&m.ordered, entry)
+	return evicted, nil
+}
+
+// Has returns true if the tx with ID [txID] is in the mempool
+func (m *Mempool) Has(txID ids.ID) bool {
+	_, exists := m.txs[txID.Key()]
+	return exists
+}
+
+// Get returns the tx with ID [txID], or nil if it isn't in the mempool
+func (m *Mempool) Get(txID ids.ID) Tx {
+	if entry, exists := m.txs[txID.Key()]; exists {
+		return entry.tx
+	}
+	return nil
+}
+
+// Remove the tx with ID [txID] from the mempool. Returns true if the tx was
+// in the mempool.
+func (m *Mempool) Remove(txID ids.ID) bool {
+	entry, exists := m.txs[txID.Key()]
+	if exists {
+		m.remove(entry)
+	}
+	return exists
+}
+
+// Len returns the number of txs in the mempool
+func (m *Mempool) Len() int { return len(m.txs) }
+
+// Peek returns the tx with the highest fee-per-byte without removing it, or
+// nil if the mempool is empty
+func (m *Mempool) Peek() Tx {
+	if len(m.ordered) == 0 {
+		return nil
+	}
+	return m.ordered[0].tx
+}
+
+// Pop removes and returns the tx with the highest fee-per-byte, or nil if the
+// mempool is empty
+func (m *Mempool) Pop() Tx {
+	if len(m.ordered) == 0 {
+		return nil
+	}
+	entry := m.ordered[0]
+	m.remove(entry)
+	return entry.tx
+}
+
+// RemoveConflicts drops every tx that the conflict callback reports as no
+// longer issuable. This should be called after the VM's state changes, such as
+// when a block is accepted. Returns the txs that were dropped.
+func (m *Mempool) RemoveConflicts() []Tx {
+	if m.conflicts == nil {
+		return nil
+	}
+	dropped := []Tx(nil)
+	for _, entry := range m.txs {
+		if m.conflicts(entry.tx) {
+			dropped = append(dropped, entry.tx)
+		}
+	}
+	for _, tx := range dropped {
+		m.Remove(tx.ID())
+	}
+	return dropped
+}
+
+// lowest returns the entry with the lowest fee-per-byte. Assumes the mempool
+// isn't empty.
+func (m *Mempool) lowest() *txEntry {
+	// The heap only orders the highest entry, so the leaves must be searched
+	lowest := m.ordered[len(m.ordered)-1]
+	for _, entry := range m.ordered[len(m.ordered)/2:] {
+		if entry.less(lowest) {
+			lowest = entry
+		}
+	}
+	return lowest
+}
+
+func (m *Mempool) remove(entry *txEntry) {
+	delete(m.txs, entry.tx.ID().Key())
+	heap.Remove(&m.ordered, entry.index)
+}
+
+type txEntry struct {
+	tx    Tx
+	size  uint64
+	index int
+}
+
+// less returns true if [e] pays a lower fee-per-byte than [o]. Ties are broken
+// by tx ID so that the ordering is deterministic.
+func (e *txEntry) less(o *txEntry) bool {
+	// Compare e.fee/e.size with o.fee/o.size without division or overflow
+	eHi, eLo := bits.Mul64(e.tx.Fee(), o.size)
+	oHi, oLo := bits.Mul64(o.tx.Fee(), e.size)
+	switch {
+	case eHi != oHi:
+		return eHi < oHi
+	case eLo != oLo:
+		return eLo < oLo
+	default:
+		return bytes.Compare(e.tx.ID().Bytes(), o.tx.ID().Bytes()) == 1
+	}
+}
+
+// txHeap is a max-heap of txs ordered by fee-per-byte
+type txHeap []*txEntry
+
+func (h txHeap) Len() int           { return len(h) }
+func (h txHeap) Less(i, j int) bool { return h[j].less(h[i]) }
+func (h txHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *txHeap) Push(x interface{}) {
+	entry := x.(*txEntry)
+	entry.index = len(*h)
+	*h = append(*h, entry)
+}
+func (h *txHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
+}
@@ -0,0 +1,178 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package mempool
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+type testTx struct {
+	id    ids.ID
+	bytes []byte
+	fee   uint64
+}
+
+func (tx *testTx) ID() ids.ID    { return tx.id }
+func (tx *testTx) Bytes() []byte { return tx.bytes }
+func (tx *testTx) Fee() uint64   { return tx.fee }
+
+func newTestTx(size int, fee uint64) *testTx {
+	return &testTx{
+		id:    ids.GenerateTestID(),
+		bytes: make([]byte, size),
+		fee:   fee,
+	}
+}
+
+func TestMempoolOrdering(t *testing.T) {
+	m, err := New(10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	low := newTestTx(100, 100)      // 1 per byte
+	high := newTestTx(10, 50)       // 5 per byte
+	medium := newTestTx(1000, 2000) // 2 per byte
+	for _, tx := range []Tx{low, high, medium} {
+		if _, err := m.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := m.Add(low); err == nil {
+		t.Fatalf("Should have errored due to a duplicate tx")
+	}
+	if m.Len() != 3 {
+		t.Fatalf("Mempool should contain 3 txs but contains %d", m.Len())
+	}
+	if tx := m.Peek(); tx != high {
+		t.Fatalf("Peeked the wrong tx")
+	}
+
+	for i, expected := range []Tx{high, medium, low} {
+		if tx := m.Pop(); tx != expected {
+			t.Fatalf("Popped the wrong tx at index %d", i)
+		}
+	}
+	if tx := m.Pop(); tx != nil {
+		t.Fatalf("Popped a tx from an empty mempool")
+	}
+}
+
+func TestMempoolEviction(t *testing.T) {
+	m, err := New(2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	low := newTestTx(100, 100)
+	medium := newTestTx(100, 200)
+	high := newTestTx(100, 300)
+	lowest := newTestTx(100, 50)
+
+	for _, tx := range []Tx{low, medium} {
+		if _, err := m.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	evicted, err := m.Add(high)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evicted != low {
+		t.Fatalf("Should have evicted the tx with the lowest fee-per-byte")
+	}
+	if m.Has(low.ID()) {
+		t.Fatalf("Evicted tx is still in the mempool")
+	}
+
+	if _, err := m.Add(lowest); err == nil {
+		t.Fatalf("Should have errored because the tx pays too little to evict another tx")
+	}
+	if m.Has(lowest.ID()) {
+		t.Fatalf("Rejected tx was added to the mempool")
+	}
+	if m.Len() != 2 {
+		t.Fatalf("Mempool should contain 2 txs but contains %d", m.Len())
+	}
+	if tx := m.Pop(); tx != high {
+		t.Fatalf("Popped the wrong tx")
+	}
+	if tx := m.Pop(); tx != medium {
+		t.Fatalf("Popped the wrong tx")
+	}
+}
+
+func TestMempoolConflicts(t *testing.T) {
+	consumed := ids.Set{}
+	m, err := New(10, func(tx Tx) bool { return consumed.Contains(tx.ID()) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx0 := newTestTx(100, 100)
+	tx1 := newTestTx(100, 200)
+	tx2 := newTestTx(100, 300)
+	for _, tx := range []Tx{tx0, tx1, tx2} {
+		if _, err := m.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	consumed.Add(tx0.ID(), tx2.ID())
+	dropped := m.RemoveConflicts()
+	if len(dropped) != 2 {
+		t.Fatalf("Should have dropped 2 txs but dropped %d", len(dropped))
+	}
+	if m.Has(tx0.ID()) || m.Has(tx2.ID()) {
+		t.Fatalf("Conflicting txs are still in the mempool")
+	}
+	if !m.Has(tx1.ID()) {
+		t.Fatalf("Non-conflicting tx was dropped")
+	}
+	if tx := m.Peek(); tx != tx1 {
+		t.Fatalf("Peeked the wrong tx")
+	}
+
+	if _, err := m.Add(tx0); err == nil {
+		t.Fatalf("Should have errored due to a conflicting tx")
+	}
+}
+
+func TestMempoolRemove(t *testing.T) {
+	m, err := New(10, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx0 := newTestTx(100, 100)
+	tx1 := newTestTx(100, 200)
+	for _, tx := range []Tx{tx0, tx1} {
+		if _, err := m.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !m.Remove(tx1.ID()) {
+		t.Fatalf("Should have removed the tx")
+	}
+	if m.Remove(tx1.ID()) {
+		t.Fatalf("Shouldn't have removed the tx twice")
+	}
+	if tx := m.Get(tx0.ID()); tx != tx0 {
+		t.Fatalf("Returned the wrong tx")
+	}
+	if tx := m.Pop(); tx != tx0 {
+		t.Fatalf("Popped the wrong tx")
+	}
+}
+
+func TestMempoolInvalidSize(t *testing.T) {
+	if _, err := New(0, nil); err == nil {
+		t.Fatalf("Should have errored due to an invalid size")
+	}
+}
@@ -0,0 +1,137 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+var (
+	errInvalidSize = errors.New("replay guard size must be positive")
+
+	// ErrReplayedTx is returned when a tx that was already accepted is
+	// checked or accepted again
+	ErrReplayedTx = errors.New("tx was already accepted")
+)
+
+// Guard remembers the IDs of the most recently accepted txs, so that a VM
+// doesn't process a tx twice, for example when it restarts part way through
+// accepting a block. Accepted IDs are persisted so that they survive
+// restarts. A Guard isn't safe for concurrent use.
+//
+// VMs should consult Check before executing a tx, and call Accept once the tx
+// is accepted. If the guard's database is the VM's versioned database, the
+// accepted IDs are committed atomically with the rest of the VM's state.
+type Guard struct {
+	db      database.Database
+	maxSize int
+
+	// Key: ID of an accepted tx
+	// Value: the order in which the tx was accepted
+	accepted map[[32]byte]uint64
+	// index that the next accepted tx will be stored at
+	next uint64
+}
+
+// New returns a guard that remembers the last [maxSize] txs accepted. [db]
+// should only be used by the guard. IDs previously persisted to [db] are
+// reloaded.
+func New(db database.Database, maxSize int) (*Guard, error) {
+	if maxSize <= 0 {
+		return nil, errInvalidSize
+	}
+	g := &Guard{
+		db:       db,
+		maxSize:  maxSize,
+		accepted: make(map[[32]byte]uint64),
+	}
+	return g, g.load()
+}
+
+// Check returns ErrReplayedTx if [txID] is one of the recently accepted txs
+func (g *Guard) Check(txID ids.ID) error {
+	if _, accepted := g.accepted[txID.Key()]; accepted {
+		return fmt.Errorf("%w: %s", ErrReplayedTx, txID)
+	}
+	return nil
+}
+
+// Accept records that [txID] was accepted, forgetting the oldest accepted tx
+// if more than the maximum number of txs would be remembered. Returns
+// ErrReplayedTx if [txID] was already accepted.
+func (g *Guard) Accept(txID ids.ID) error {
+	if err := g.Check(txID); err != nil {
+		return err
+	}
+
+	index := g.next
+	if err := g.db.Put(indexKey(index), txID.Bytes()); err != nil {
+		return err
+	}
+	g.accepted[txID.Key()] = index
+	g.next++
+
+	if len(g.accepted) <= g.maxSize {
+		return nil
+	}
+	return g.evict(index - uint64(g.maxSize))
+}
+
+// Len returns the number of accepted txs that are remembered
+func (g *Guard) Len() int { return len(g.accepted) }
+
+// evict forgets the tx that was accepted at [index]
+func (g *Guard) evict(index uint64) error {
+	key := indexKey(index)
+	txIDBytes, err := g.db.Get(key)
+	if err != nil {
+		return err
+	}
+	txID, err := ids.ToID(txIDBytes)
+	if err != nil {
+		return err
+	}
+	delete(g.accepted, txID.Key())
+	return g.db.Delete(key)
+}
+
+// load reads the persisted IDs. Indices are stored big-endian, so the
+// iterator returns them in the order the txs were accepted.
+func (g *Guard) load() error {
+	it := g.db.NewIterator()
+	defer it.Release()
+
+	for it.Next() {
+		index := binary.BigEndian.Uint64(it.Key())
+		txID, err := ids.ToID(it.Value())
+		if err != nil {
+			return err
+		}
+		g.accepted[txID.Key()] = index
+		g.next = index + 1
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	// The maximum size may have been lowered since the IDs were persisted
+	for len(g.accepted) > g.maxSize {
+		if err := g.evict(g.next - uint64(len(g.accepted))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indexKey(index uint64) []byte {
+	key := make([]byte, wrappers.LongLen)
+	binary.BigEndian.PutUint64(key, index)
+	return key
+}
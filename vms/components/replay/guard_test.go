@@ -0,0 +1,117 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package replay
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/gecko/database/memdb"
+	"github.com/ava-labs/gecko/ids"
+)
+
+func TestGuardRejectsReplay(t *testing.T) {
+	db := memdb.New()
+	g, err := New(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// process executes a tx the way a VM would, consulting the guard first
+	executed := 0
+	process := func(g *Guard, txID ids.ID) error {
+		if err := g.Check(txID); err != nil {
+			return err
+		}
+		executed++
+		return g.Accept(txID)
+	}
+
+	txID := ids.GenerateTestID()
+	if err := process(g, txID); err != nil {
+		t.Fatal(err)
+	}
+	if err := process(g, txID); !errors.Is(err, ErrReplayedTx) {
+		t.Fatalf("Expected %s but got %v", ErrReplayedTx, err)
+	}
+
+	// The accepted ID should be reloaded after a restart
+	g, err = New(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := process(g, txID); !errors.Is(err, ErrReplayedTx) {
+		t.Fatalf("Expected %s after restarting but got %v", ErrReplayedTx, err)
+	}
+	if executed != 1 {
+		t.Fatalf("Tx should have been executed once but was executed %d times", executed)
+	}
+
+	if err := process(g, ids.GenerateTestID()); err != nil {
+		t.Fatal(err)
+	}
+	if executed != 2 {
+		t.Fatalf("New tx should have been executed")
+	}
+}
+
+func TestGuardEviction(t *testing.T) {
+	db := memdb.New()
+	g, err := New(db, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txIDs := []ids.ID(nil)
+	for i := 0; i < 5; i++ {
+		txID := ids.GenerateTestID()
+		if err := g.Accept(txID); err != nil {
+			t.Fatal(err)
+		}
+		txIDs = append(txIDs, txID)
+	}
+
+	if g.Len() != 3 {
+		t.Fatalf("Guard should remember 3 txs but remembers %d", g.Len())
+	}
+	for i, txID := range txIDs {
+		if err := g.Check(txID); (err == nil) != (i < 2) {
+			t.Fatalf("Wrong result for tx %d: %v", i, err)
+		}
+	}
+	if count, err := db.Count(nil); err != nil {
+		t.Fatal(err)
+	} else if count != 3 {
+		t.Fatalf("Evicted txs should have been deleted, but %d remain", count)
+	}
+
+	// Restarting with a smaller size should forget the oldest txs
+	g, err = New(db, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, txID := range txIDs {
+		if err := g.Check(txID); (err == nil) != (i < 3) {
+			t.Fatalf("Wrong result for tx %d after restarting: %v", i, err)
+		}
+	}
+
+	// New txs should be stored after the reloaded ones
+	txID := ids.GenerateTestID()
+	if err := g.Accept(txID); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Check(txIDs[3]); err != nil {
+		t.Fatalf("Oldest tx should have been evicted but got %v", err)
+	}
+	if err := g.Check(txIDs[4]); err == nil {
+		t.Fatalf("Tx %s should still be remembered", txIDs[4])
+	}
+}
+
+func TestGuardInvalidSize(t *testing.T) {
+	if _, err := New(memdb.New(), 0); err == nil {
+		t.Fatalf("Should have errored due to an invalid size")
+	}
+}
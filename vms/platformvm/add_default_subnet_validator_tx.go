@@ -30,6 +30,10 @@ var (
 	errStakeTooLong   = errors.New("staking period is too long")
 	errTooManyShares  = fmt.Errorf("a staker can only require at most %d shares from delegators", NumberOfShares)
 
+	errStartTimeTooEarly = errors.New("validator's start time isn't after the current chain timestamp")
+	errValidatorExists   = errors.New("node is already a Default Subnet validator")
+	errValidatorPending  = errors.New("node is already a pending Default Subnet validator")
+
 	_ UnsignedProposalTx = &UnsignedAddDefaultSubnetValidatorTx{}
 	_ TimedTx            = &UnsignedAddDefaultSubnetValidatorTx{}
 )
@@ -98,10 +102,9 @@ func (tx *UnsignedAddDefaultSubnetValidatorTx) Verify(
 		return errOutputsNotSorted
 	case totalStakeWeight != tx.Validator.Wght:
 		return errInvalidAmount
-	case tx.Validator.Wght < minStake: // Ensure validator is staking at least the minimum amount
-		return errWeightTooSmall
-	case tx.Shares > NumberOfShares: // Ensure delegators shares are in the allowed amount
-		return errTooManyShares
+	}
+	if problems := validatorTermsProblems(&tx.Validator, tx.Shares, minStake); len(problems) > 0 {
+		return problems[0]
 	}
 
 	// cache that this is valid
@@ -126,38 +129,14 @@ func (tx *UnsignedAddDefaultSubnetValidatorTx) SemanticVerify(
 		return nil, nil, nil, nil, permError{err}
 	}
 
-	// Ensure the proposed validator starts after the current time
-	if currentTime, err := vm.getTimestamp(db); err != nil {
-		return nil, nil, nil, nil, tempError{err}
-	} else if startTime := tx.StartTime(); !currentTime.Before(startTime) {
-		return nil, nil, nil, nil, permError{fmt.Errorf("validator's start time (%s) at or after current timestamp (%s)",
-			currentTime,
-			startTime)}
-	}
-
-	// Ensure the proposed validator is not already a validator of the specified subnet
-	currentValidators, err := vm.getCurrentValidators(db, constants.DefaultSubnetID)
-	if err != nil {
-		return nil, nil, nil, nil, tempError{err}
-	}
-	for _, currentVdr := range vm.getValidators(currentValidators) {
-		if currentVdr.ID().Equals(tx.Validator.NodeID) {
-			return nil, nil, nil, nil, permError{fmt.Errorf("validator %s already is already a Default Subnet validator",
-				tx.Validator.NodeID)}
-		}
+	if problems := vm.validatorScheduleProblems(db, &tx.Validator); len(problems) > 0 {
+		return nil, nil, nil, nil, problems[0]
 	}
 
-	// Ensure the proposed validator is not already slated to validate for the specified subnet
 	pendingValidators, err := vm.getPendingValidators(db, constants.DefaultSubnetID)
 	if err != nil {
 		return nil, nil, nil, nil, tempError{err}
 	}
-	for _, pendingVdr := range vm.getValidators(pendingValidators) {
-		if pendingVdr.ID().Equals(tx.Validator.NodeID) {
-			return nil, nil, nil, nil, tempError{fmt.Errorf("validator %s is already a pending Default Subnet validator",
-				tx.Validator.NodeID)}
-		}
-	}
 
 	outs := make([]*avax.TransferableOutput, len(tx.Outs)+len(tx.Stake))
 	copy(outs, tx.Outs)
@@ -201,6 +180,78 @@ func (tx *UnsignedAddDefaultSubnetValidatorTx) SemanticVerify(
 	return onCommitDB, onAbortDB, nil, nil, nil
 }
 
+// validatorTermsProblems returns the reasons, that don't depend on the chain's
+// state, that [vdr] couldn't be added to the Default Subnet with delegation fee
+// [shares]
+func validatorTermsProblems(vdr *Validator, shares uint32, minStake uint64) []error {
+	problems := []error(nil)
+	if err := vdr.Verify(); err != nil {
+		problems = append(problems, err)
+	}
+	if vdr.Wght < minStake { // Ensure validator is staking at least the minimum amount
+		problems = append(problems, errWeightTooSmall)
+	}
+	if shares > NumberOfShares { // Ensure delegators shares are in the allowed amount
+		problems = append(problems, errTooManyShares)
+	}
+	return problems
+}
+
+// validatorScheduleProblems returns the reasons that [vdr] couldn't be added to
+// the Default Subnet given the state in [db]
+func (vm *VM) validatorScheduleProblems(db database.Database, vdr *Validator) []TxError {
+	problems := []TxError(nil)
+
+	// Ensure the proposed validator starts after the current time
+	if currentTime, err := vm.getTimestamp(db); err != nil {
+		return append(problems, tempError{err})
+	} else if startTime := vdr.StartTime(); !currentTime.Before(startTime) {
+		problems = append(problems, permError{fmt.Errorf("%w: start time (%s) is at or before current timestamp (%s)",
+			errStartTimeTooEarly,
+			startTime,
+			currentTime)})
+	}
+
+	// Ensure the proposed validator is not already a validator of the specified subnet
+	currentValidators, err := vm.getCurrentValidators(db, constants.DefaultSubnetID)
+	if err != nil {
+		return append(problems, tempError{err})
+	}
+	for _, currentVdr := range vm.getValidators(currentValidators) {
+		if currentVdr.ID().Equals(vdr.NodeID) {
+			problems = append(problems, permError{fmt.Errorf("%w: %s", errValidatorExists, vdr.NodeID)})
+			break
+		}
+	}
+
+	// Ensure the proposed validator is not already slated to validate for the specified subnet
+	pendingValidators, err := vm.getPendingValidators(db, constants.DefaultSubnetID)
+	if err != nil {
+		return append(problems, tempError{err})
+	}
+	for _, pendingVdr := range vm.getValidators(pendingValidators) {
+		if pendingVdr.ID().Equals(vdr.NodeID) {
+			problems = append(problems, tempError{fmt.Errorf("%w: %s", errValidatorPending, vdr.NodeID)})
+			break
+		}
+	}
+	return problems
+}
+
+// ValidateAddValidator returns every reason that a transaction adding [vdr] to
+// the Default Subnet, with delegation fee [shares], would be rejected if it
+// were issued now. The checks are the ones made when such a transaction is
+// verified, except that the transaction's inputs aren't known, so whether
+// they cover the stake and fee isn't checked. If no problems are returned,
+// the validator may be added.
+func (vm *VM) ValidateAddValidator(vdr Validator, shares uint32) []error {
+	problems := validatorTermsProblems(&vdr, shares, vm.minStake)
+	for _, problem := range vm.validatorScheduleProblems(vm.DB, &vdr) {
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
 // InitiallyPrefersCommit returns true if the proposed validators start time is
 // after the current wall clock time,
 func (tx *UnsignedAddDefaultSubnetValidatorTx) InitiallyPrefersCommit(vm *VM) bool {
@@ -4,6 +4,7 @@
 package platformvm
 
 import (
+	"errors"
 	"math"
 	"testing"
 	"time"
@@ -352,3 +353,154 @@ func TestAddDefaultSubnetValidatorTxSemanticVerify(t *testing.T) {
 	}
 	vDB.Abort()
 }
+
+func TestValidateAddValidator(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	key, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodeID := key.PublicKey().Address()
+
+	startTime := defaultGenesisTime.Add(Delta).Add(time.Second)
+	validVdr := Validator{
+		NodeID: nodeID,
+		Start:  uint64(startTime.Unix()),
+		End:    uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		Wght:   vm.minStake,
+	}
+
+	// A pending validator
+	pendingKey, err := vm.factory.NewPrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingNodeID := pendingKey.PublicKey().Address()
+	pendingTx, err := vm.newAddDefaultSubnetValidatorTx(
+		vm.minStake,
+		uint64(startTime.Unix()),
+		uint64(startTime.Add(MinimumStakingDuration).Unix()),
+		pendingNodeID,
+		pendingNodeID,
+		NumberOfShares,
+		[]*crypto.PrivateKeySECP256K1R{keys[0]},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingValidators, err := vm.getPendingValidators(vm.DB, constants.DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingValidators.Add(pendingTx)
+	if err := vm.putPendingValidators(vm.DB, pendingValidators, constants.DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		description string
+		vdr         func() Validator
+		shares      uint32
+		problems    []error
+	}{
+		{
+			description: "valid",
+			vdr:         func() Validator { return validVdr },
+			shares:      NumberOfShares,
+		},
+		{
+			description: "insufficient stake",
+			vdr: func() Validator {
+				vdr := validVdr
+				vdr.Wght = vm.minStake - 1
+				return vdr
+			},
+			shares:   NumberOfShares,
+			problems: []error{errWeightTooSmall},
+		},
+		{
+			description: "too many shares",
+			vdr:         func() Validator { return validVdr },
+			shares:      NumberOfShares + 1,
+			problems:    []error{errTooManyShares},
+		},
+		{
+			description: "staking period too short",
+			vdr: func() Validator {
+				vdr := validVdr
+				vdr.End = vdr.Start + uint64(MinimumStakingDuration/time.Second) - 1
+				return vdr
+			},
+			shares:   NumberOfShares,
+			problems: []error{errStakeTooShort},
+		},
+		{
+			description: "staking period too long",
+			vdr: func() Validator {
+				vdr := validVdr
+				vdr.End = vdr.Start + uint64(MaximumStakingDuration/time.Second) + 1
+				return vdr
+			},
+			shares:   NumberOfShares,
+			problems: []error{errStakeTooLong},
+		},
+		{
+			description: "start time not after the current timestamp",
+			vdr: func() Validator {
+				vdr := validVdr
+				vdr.Start = uint64(defaultGenesisTime.Unix())
+				return vdr
+			},
+			shares:   NumberOfShares,
+			problems: []error{errStartTimeTooEarly},
+		},
+		{
+			description: "already a validator",
+			vdr: func() Validator {
+				vdr := validVdr
+				vdr.NodeID = keys[0].PublicKey().Address()
+				return vdr
+			},
+			shares:   NumberOfShares,
+			problems: []error{errValidatorExists},
+		},
+		{
+			description: "already a pending validator",
+			vdr: func() Validator {
+				vdr := validVdr
+				vdr.NodeID = pendingNodeID
+				return vdr
+			},
+			shares:   NumberOfShares,
+			problems: []error{errValidatorPending},
+		},
+		{
+			description: "several problems",
+			vdr: func() Validator {
+				vdr := validVdr
+				vdr.NodeID = keys[0].PublicKey().Address()
+				vdr.Wght = vm.minStake - 1
+				return vdr
+			},
+			shares:   NumberOfShares + 1,
+			problems: []error{errWeightTooSmall, errTooManyShares, errValidatorExists},
+		},
+	}
+	for _, test := range tests {
+		problems := vm.ValidateAddValidator(test.vdr(), test.shares)
+		if len(problems) != len(test.problems) {
+			t.Fatalf("%s: expected %d problems but got %v", test.description, len(test.problems), problems)
+		}
+		for i, problem := range problems {
+			if !errors.Is(problem, test.problems[i]) {
+				t.Fatalf("%s: expected problem %q but got %q", test.description, test.problems[i], problem)
+			}
+		}
+	}
+}
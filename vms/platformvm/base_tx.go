@@ -23,7 +23,7 @@ func (tx *BaseTx) Verify(ctx *snow.Context, c codec.Codec) error {
 	case tx.syntacticallyVerified: // already passed syntactic verification
 		return nil
 	}
-	if err := tx.MetadataVerify(ctx); err != nil {
+	if err := tx.MetadataVerify(ctx, avax.MaxMemoSize); err != nil {
 		return err
 	}
 	for _, out := range tx.Outs {
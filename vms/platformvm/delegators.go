@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/constants"
+
+	safemath "github.com/ava-labs/gecko/utils/math"
+)
+
+var (
+	errNoDelegation      = errors.New("only validators of the default subnet can be delegated to")
+	errValidatorNotFound = errors.New("couldn't find the validator in the current or pending validator sets")
+)
+
+// Delegation is stake that a delegator has delegated to a validator
+type Delegation struct {
+	TxID    ids.ID
+	Amount  uint64
+	EndTime time.Time
+}
+
+// Delegations describes the stake delegated to a validator
+type Delegations struct {
+	// Current and pending delegations to the validator
+	Delegations []Delegation
+	// Sum of the amounts of [Delegations]
+	TotalDelegated uint64
+	// How much more stake can be delegated to the validator. Delegation is
+	// only limited by the validator's total weight, including its own stake,
+	// fitting in a uint64.
+	RemainingCapacity uint64
+}
+
+// GetDelegators returns the current and pending delegations to the validator
+// [nodeID] of subnet [subnetID]. Only validators of the default subnet can be
+// delegated to.
+func (vm *VM) GetDelegators(subnetID ids.ID, nodeID ids.ShortID) (*Delegations, error) {
+	if !subnetID.Equals(constants.DefaultSubnetID) {
+		return nil, errNoDelegation
+	}
+
+	current, err := vm.getCurrentValidators(vm.DB, subnetID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get current validators: %w", err)
+	}
+	pending, err := vm.getPendingValidators(vm.DB, subnetID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get pending validators: %w", err)
+	}
+
+	validatorWeight := uint64(0)
+	delegations := &Delegations{}
+	for _, stakers := range []*EventHeap{current, pending} {
+		for _, tx := range stakers.Txs {
+			switch utx := tx.UnsignedTx.(type) {
+			case *UnsignedAddDefaultSubnetValidatorTx:
+				if utx.Validator.NodeID.Equals(nodeID) {
+					validatorWeight = utx.Validator.Weight()
+				}
+			case *UnsignedAddDefaultSubnetDelegatorTx:
+				if !utx.Validator.NodeID.Equals(nodeID) {
+					continue
+				}
+				totalDelegated, err := safemath.Add64(delegations.TotalDelegated, utx.Validator.Weight())
+				if err != nil {
+					return nil, err
+				}
+				delegations.TotalDelegated = totalDelegated
+				delegations.Delegations = append(delegations.Delegations, Delegation{
+					TxID:    tx.ID(),
+					Amount:  utx.Validator.Weight(),
+					EndTime: utx.EndTime(),
+				})
+			}
+		}
+	}
+	if validatorWeight == 0 {
+		return nil, errValidatorNotFound
+	}
+
+	totalWeight, err := safemath.Add64(validatorWeight, delegations.TotalDelegated)
+	if err != nil {
+		return nil, err
+	}
+	delegations.RemainingCapacity = math.MaxUint64 - totalWeight
+	return delegations, nil
+}
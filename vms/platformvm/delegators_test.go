@@ -0,0 +1,117 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/constants"
+	"github.com/ava-labs/gecko/utils/crypto"
+)
+
+func TestGetDelegators(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	nodeID := keys[0].PublicKey().Address()
+	startTime := uint64(defaultValidateStartTime.Add(time.Second).Unix())
+	endTime := uint64(defaultValidateStartTime.Add(time.Second + MinimumStakingDuration).Unix())
+
+	// One delegator is currently delegating and the other will start in the
+	// future
+	currentDelegator, err := vm.newAddDefaultSubnetDelegatorTx(
+		vm.minStake,                             // stake amount
+		startTime,                               // start time
+		endTime,                                 // end time
+		nodeID,                                  // node ID
+		nodeID,                                  // reward address
+		[]*crypto.PrivateKeySECP256K1R{keys[1]}, // key
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pendingDelegator, err := vm.newAddDefaultSubnetDelegatorTx(
+		2*vm.minStake,                           // stake amount
+		startTime,                               // start time
+		endTime+1,                               // end time
+		nodeID,                                  // node ID
+		nodeID,                                  // reward address
+		[]*crypto.PrivateKeySECP256K1R{keys[2]}, // key
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current, err := vm.getCurrentValidators(vm.DB, constants.DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current.Add(currentDelegator)
+	if err := vm.putCurrentValidators(vm.DB, current, constants.DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := vm.getPendingValidators(vm.DB, constants.DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending.Add(pendingDelegator)
+	if err := vm.putPendingValidators(vm.DB, pending, constants.DefaultSubnetID); err != nil {
+		t.Fatal(err)
+	}
+
+	validator, err := current.getDefaultSubnetStaker(nodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validatorWeight := validator.UnsignedTx.(*UnsignedAddDefaultSubnetValidatorTx).Validator.Weight()
+
+	delegations, err := vm.GetDelegators(constants.DefaultSubnetID, nodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delegations.Delegations) != 2 {
+		t.Fatalf("Expected 2 delegations but got %d", len(delegations.Delegations))
+	}
+	for i, tx := range []*Tx{currentDelegator, pendingDelegator} {
+		expected := tx.UnsignedTx.(*UnsignedAddDefaultSubnetDelegatorTx)
+		delegation := delegations.Delegations[i]
+		switch {
+		case !delegation.TxID.Equals(tx.ID()):
+			t.Fatalf("Expected delegation %s but got %s", tx.ID(), delegation.TxID)
+		case delegation.Amount != expected.Validator.Weight():
+			t.Fatalf("Expected %d delegated but got %d", expected.Validator.Weight(), delegation.Amount)
+		case !delegation.EndTime.Equal(expected.EndTime()):
+			t.Fatalf("Expected delegation to end at %s but ends at %s", expected.EndTime(), delegation.EndTime)
+		}
+	}
+	if expected := 3 * vm.minStake; delegations.TotalDelegated != expected {
+		t.Fatalf("Expected %d delegated in total but got %d", expected, delegations.TotalDelegated)
+	}
+	if expected := math.MaxUint64 - validatorWeight - 3*vm.minStake; delegations.RemainingCapacity != expected {
+		t.Fatalf("Expected a remaining capacity of %d but got %d", expected, delegations.RemainingCapacity)
+	}
+
+	// Other validators have no delegations
+	delegations, err = vm.GetDelegators(constants.DefaultSubnetID, keys[1].PublicKey().Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delegations.Delegations) != 0 || delegations.TotalDelegated != 0 {
+		t.Fatalf("Expected no delegations but got %d", len(delegations.Delegations))
+	}
+
+	if _, err := vm.GetDelegators(constants.DefaultSubnetID, ids.NewShortID([20]byte{1})); err == nil {
+		t.Fatalf("Should have errored because the validator doesn't exist")
+	}
+	if _, err := vm.GetDelegators(testSubnet1.ID(), nodeID); err == nil {
+		t.Fatalf("Should have errored because only the default subnet supports delegation")
+	}
+}
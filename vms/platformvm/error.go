@@ -14,6 +14,10 @@ type tempError struct{ error }
 
 func (tempError) Temporary() bool { return true }
 
+func (e tempError) Unwrap() error { return e.error }
+
 type permError struct{ error }
 
 func (permError) Temporary() bool { return false }
+
+func (e permError) Unwrap() error { return e.error }
@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reward
+
+import (
+	"math"
+	"time"
+)
+
+// Calculator calculates the reward for a staker
+type Calculator interface {
+	// Calculate returns the amount of tokens to reward a staker that staked
+	// [stakedAmount] tokens for [stakedDuration]
+	Calculate(stakedAmount uint64, stakedDuration time.Duration) uint64
+}
+
+// calculator rewards stakers by compounding their stake at a fixed rate per
+// year staked
+type calculator struct {
+	inflationRate      float64
+	maxStakingDuration time.Duration
+}
+
+// NewCalculator returns a calculator that grows the staked amount by a factor
+// of [inflationRate] per year staked. Durations longer than
+// [maxStakingDuration] are rewarded as if they were [maxStakingDuration].
+func NewCalculator(inflationRate float64, maxStakingDuration time.Duration) Calculator {
+	return &calculator{
+		inflationRate:      inflationRate,
+		maxStakingDuration: maxStakingDuration,
+	}
+}
+
+// Calculate implements the Calculator interface
+func (c *calculator) Calculate(stakedAmount uint64, stakedDuration time.Duration) uint64 {
+	// TODO: Can't use floats here. Need to figure out how to do some integer
+	// approximations
+
+	if stakedDuration > c.maxStakingDuration {
+		stakedDuration = c.maxStakingDuration
+	}
+	years := stakedDuration.Hours() / (365. * 24.)
+
+	// Total value of this transaction
+	value := float64(stakedAmount) * math.Pow(c.inflationRate, years)
+
+	// Amount of the reward
+	reward := value - float64(stakedAmount)
+	if reward <= 0 {
+		return 0
+	}
+
+	// The staked amount plus the reward must fit in a uint64
+	maxReward := math.MaxUint64 - stakedAmount
+	if reward >= float64(maxReward) {
+		return maxReward
+	}
+	return uint64(reward)
+}
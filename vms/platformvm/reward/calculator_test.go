@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package reward
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+const (
+	year               = 365 * 24 * time.Hour
+	minStakingDuration = 24 * time.Hour
+	maxStakingDuration = year
+)
+
+func TestCalculatorNoInflation(t *testing.T) {
+	c := NewCalculator(1, maxStakingDuration)
+	if reward := c.Calculate(1000000, maxStakingDuration); reward != 0 {
+		t.Fatalf("Reward should be 0 with no inflation but is %d", reward)
+	}
+}
+
+func TestCalculatorDurationBoundaries(t *testing.T) {
+	c := NewCalculator(1.1, maxStakingDuration)
+	amount := uint64(1000000)
+
+	tests := []struct {
+		duration time.Duration
+		expected uint64
+	}{
+		{0, 0},
+		{minStakingDuration, uint64(float64(amount) * (math.Pow(1.1, 1./365) - 1))},
+		{maxStakingDuration, 100000},
+		// Durations past the maximum are rewarded as the maximum
+		{2 * maxStakingDuration, 100000},
+	}
+	for _, test := range tests {
+		reward := c.Calculate(amount, test.duration)
+		// Allow for floating point rounding
+		if diff := int64(reward) - int64(test.expected); diff < -1 || diff > 1 {
+			t.Fatalf("Staking %d for %s should reward %d but rewarded %d", amount, test.duration, test.expected, reward)
+		}
+	}
+
+	if min, max := c.Calculate(amount, minStakingDuration), c.Calculate(amount, maxStakingDuration); min >= max {
+		t.Fatalf("Staking for the minimum duration rewarded %d, which isn't less than the %d rewarded for the maximum duration", min, max)
+	}
+}
+
+func TestCalculatorAmountBoundaries(t *testing.T) {
+	c := NewCalculator(2, maxStakingDuration)
+
+	if reward := c.Calculate(0, maxStakingDuration); reward != 0 {
+		t.Fatalf("Staking nothing should reward nothing but rewarded %d", reward)
+	}
+	if reward := c.Calculate(1, maxStakingDuration); reward != 1 {
+		t.Fatalf("Staking 1 with an inflation rate of 2 should reward 1 but rewarded %d", reward)
+	}
+
+	// The reward must never overflow the staked amount
+	for _, amount := range []uint64{math.MaxUint64 / 2, math.MaxUint64 - 1, math.MaxUint64} {
+		reward := c.Calculate(amount, maxStakingDuration)
+		if reward > math.MaxUint64-amount {
+			t.Fatalf("Staking %d rewarded %d, which overflows", amount, reward)
+		}
+	}
+}
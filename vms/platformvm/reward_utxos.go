@@ -0,0 +1,197 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/constants"
+	"github.com/ava-labs/gecko/vms/components/avax"
+	"github.com/ava-labs/gecko/vms/components/verify"
+
+	safemath "github.com/ava-labs/gecko/utils/math"
+)
+
+var errStakerNotFound = errors.New("couldn't find the staker in the current or pending validator sets")
+
+// RewardUTXO is a UTXO that rewards a staker
+type RewardUTXO struct {
+	UTXO *avax.UTXO
+	// Amount of AVAX the UTXO is worth
+	Amount uint64
+	// Addresses that own the UTXO
+	Owners []ids.ShortID
+}
+
+// RewardUTXOs describes the UTXOs that reward a staker
+type RewardUTXOs struct {
+	// Ended is true iff the staker's staking period has ended. If so, [UTXOs]
+	// are the UTXOs that were created to reward the staker, and are empty if
+	// the staker wasn't rewarded. Otherwise, [UTXOs] are the UTXOs that will
+	// be created if the staker is rewarded when its staking period ends.
+	Ended bool
+	UTXOs []RewardUTXO
+}
+
+// GetRewardUTXOs returns the UTXOs that reward the staker added to the default
+// subnet by the tx with ID [txID]
+func (vm *VM) GetRewardUTXOs(txID ids.ID) (*RewardUTXOs, error) {
+	utxos, err := vm.getRewardUTXOs(vm.DB, txID)
+	switch err {
+	case nil:
+		return newRewardUTXOs(true, utxos)
+	case database.ErrNotFound:
+	default:
+		return nil, fmt.Errorf("couldn't get reward UTXOs: %w", err)
+	}
+
+	// The staker hasn't been rewarded yet, so calculate what it will be
+	// rewarded
+	current, err := vm.getCurrentValidators(vm.DB, constants.DefaultSubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get current validators: %w", err)
+	}
+	pending, err := vm.getPendingValidators(vm.DB, constants.DefaultSubnetID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get pending validators: %w", err)
+	}
+	stakers := []*EventHeap{current, pending}
+
+	var stakerTx *Tx
+	for _, heap := range stakers {
+		for _, tx := range heap.Txs {
+			if tx.ID().Equals(txID) {
+				stakerTx = tx
+			}
+		}
+	}
+	if stakerTx == nil {
+		return nil, errStakerNotFound
+	}
+
+	var parentTx *UnsignedAddDefaultSubnetValidatorTx
+	if delegatorTx, ok := stakerTx.UnsignedTx.(*UnsignedAddDefaultSubnetDelegatorTx); ok {
+		for _, heap := range stakers {
+			if parent, err := heap.getDefaultSubnetStaker(delegatorTx.Validator.NodeID); err == nil {
+				parentTx = parent.UnsignedTx.(*UnsignedAddDefaultSubnetValidatorTx)
+				break
+			}
+		}
+		if parentTx == nil {
+			return nil, errValidatorNotFound
+		}
+	}
+
+	utxos, err = vm.rewardUTXOs(txID, stakerTx.UnsignedTx, parentTx)
+	if err != nil {
+		return nil, err
+	}
+	return newRewardUTXOs(false, utxos)
+}
+
+func newRewardUTXOs(ended bool, utxos []*avax.UTXO) (*RewardUTXOs, error) {
+	rewards := &RewardUTXOs{
+		Ended: ended,
+		UTXOs: make([]RewardUTXO, len(utxos)),
+	}
+	for i, utxo := range utxos {
+		rewards.UTXOs[i].UTXO = utxo
+		if out, ok := utxo.Out.(avax.Amounter); ok {
+			rewards.UTXOs[i].Amount = out.Amount()
+		}
+		if out, ok := utxo.Out.(avax.Addressable); ok {
+			for _, addrBytes := range out.Addresses() {
+				addr, err := ids.ToShortID(addrBytes)
+				if err != nil {
+					return nil, err
+				}
+				rewards.UTXOs[i].Owners = append(rewards.UTXOs[i].Owners, addr)
+			}
+		}
+	}
+	return rewards, nil
+}
+
+// rewardUTXOs returns the UTXOs that are created if the staker added by the tx
+// [stakerTx], with ID [txID], is rewarded when its staking period ends. If the
+// staker is a delegator, [parentTx] must be the validator it delegated to.
+func (vm *VM) rewardUTXOs(
+	txID ids.ID,
+	stakerTx UnsignedTx,
+	parentTx *UnsignedAddDefaultSubnetValidatorTx,
+) ([]*avax.UTXO, error) {
+	switch uVdrTx := stakerTx.(type) {
+	case *UnsignedAddDefaultSubnetValidatorTx:
+		reward := vm.rewards.Calculate(uVdrTx.Validator.Wght, uVdrTx.Validator.Duration())
+		if reward == 0 {
+			return nil, nil
+		}
+		utxo, err := vm.rewardUTXO(txID, uint32(len(uVdrTx.Outs)+len(uVdrTx.Stake)), reward, uVdrTx.RewardsOwner)
+		if err != nil {
+			return nil, err
+		}
+		return []*avax.UTXO{utxo}, nil
+	case *UnsignedAddDefaultSubnetDelegatorTx:
+		// If reward given, it will be this amount
+		reward := vm.rewards.Calculate(uVdrTx.Validator.Wght, uVdrTx.Validator.Duration())
+		// Calculate split of reward between delegator/delegatee
+		// The delegator gives stake to the validatee
+		delegatorShares := NumberOfShares - uint64(parentTx.Shares)    // parentTx.Shares <= NumberOfShares so no underflow
+		delegatorReward := delegatorShares * (reward / NumberOfShares) // delegatorShares <= NumberOfShares so no overflow
+		// Delay rounding as long as possible for small numbers
+		if optimisticReward, err := safemath.Mul64(delegatorShares, reward); err == nil {
+			delegatorReward = optimisticReward / NumberOfShares
+		}
+		delegateeReward := reward - delegatorReward // delegatorReward <= reward so no underflow
+
+		outputIndex := uint32(len(uVdrTx.Outs) + len(uVdrTx.Stake))
+		var utxos []*avax.UTXO
+
+		// Reward the delegator here
+		if delegatorReward > 0 {
+			utxo, err := vm.rewardUTXO(txID, outputIndex, delegatorReward, uVdrTx.RewardsOwner)
+			if err != nil {
+				return nil, err
+			}
+			utxos = append(utxos, utxo)
+			outputIndex++
+		}
+
+		// Reward the delegatee here
+		if delegateeReward > 0 {
+			utxo, err := vm.rewardUTXO(txID, outputIndex, delegateeReward, parentTx.RewardsOwner)
+			if err != nil {
+				return nil, err
+			}
+			utxos = append(utxos, utxo)
+		}
+		return utxos, nil
+	default:
+		return nil, errShouldBeDSValidator
+	}
+}
+
+// rewardUTXO returns the UTXO, produced by the tx with ID [txID], that gives
+// [amount] AVAX to [owner]
+func (vm *VM) rewardUTXO(txID ids.ID, outputIndex uint32, amount uint64, owner verify.Verifiable) (*avax.UTXO, error) {
+	outIntf, err := vm.fx.CreateOutput(amount, owner)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := outIntf.(verify.State)
+	if !ok {
+		return nil, errInvalidState
+	}
+	return &avax.UTXO{
+		UTXOID: avax.UTXOID{
+			TxID:        txID,
+			OutputIndex: outputIndex,
+		},
+		Asset: avax.Asset{ID: vm.Ctx.AVAXAssetID},
+		Out:   out,
+	}, nil
+}
@@ -0,0 +1,147 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/constants"
+	"github.com/ava-labs/gecko/vms/secp256k1fx"
+)
+
+// constantCalculator rewards every staker the same amount
+type constantCalculator uint64
+
+func (c constantCalculator) Calculate(uint64, time.Duration) uint64 { return uint64(c) }
+
+func TestGetRewardUTXOs(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	// The default stakers are too small to be rewarded by the real calculator
+	vm.rewards = constantCalculator(1000)
+
+	if _, err := vm.GetRewardUTXOs(ids.GenerateTestID()); err == nil {
+		t.Fatalf("should have errored because the staker doesn't exist")
+	}
+
+	currentValidators, err := vm.getCurrentValidators(vm.DB, constants.DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stakerTx := currentValidators.Peek().UnsignedTx.(*UnsignedAddDefaultSubnetValidatorTx)
+	expectedReward := vm.rewards.Calculate(stakerTx.Validator.Weight(), stakerTx.Validator.Duration())
+	expectedOwners := stakerTx.RewardsOwner.(*secp256k1fx.OutputOwners).Addrs
+
+	checkRewards := func(rewards *RewardUTXOs, ended bool) {
+		if rewards.Ended != ended {
+			t.Fatalf("expected ended to be %v", ended)
+		}
+		if len(rewards.UTXOs) != 1 {
+			t.Fatalf("expected 1 reward UTXO but got %d", len(rewards.UTXOs))
+		}
+		reward := rewards.UTXOs[0]
+		if !reward.UTXO.TxID.Equals(stakerTx.ID()) {
+			t.Fatalf("reward UTXO should have been produced by the staking tx")
+		}
+		if reward.Amount != expectedReward {
+			t.Fatalf("expected a reward of %d but got %d", expectedReward, reward.Amount)
+		}
+		if len(reward.Owners) != len(expectedOwners) {
+			t.Fatalf("expected %d owners but got %d", len(expectedOwners), len(reward.Owners))
+		}
+		for i, owner := range reward.Owners {
+			if !owner.Equals(expectedOwners[i]) {
+				t.Fatalf("expected owner %s but got %s", expectedOwners[i], owner)
+			}
+		}
+	}
+
+	// The staking period hasn't ended, so the reward should be calculated
+	rewards, err := vm.GetRewardUTXOs(stakerTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRewards(rewards, false)
+
+	// End the staking period and reward the validator
+	if err := vm.putTimestamp(vm.DB, stakerTx.EndTime()); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := vm.newRewardValidatorTx(stakerTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	onCommitDB, _, _, _, err := tx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vm.DB, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := onCommitDB.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	rewards, err = vm.GetRewardUTXOs(stakerTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRewards(rewards, true)
+
+	// The reward should still be returned after the UTXO is spent
+	if err := vm.removeUTXO(vm.DB, rewards.UTXOs[0].UTXO.InputID()); err != nil {
+		t.Fatal(err)
+	}
+	rewards, err = vm.GetRewardUTXOs(stakerTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRewards(rewards, true)
+}
+
+func TestGetRewardUTXOsAborted(t *testing.T) {
+	vm, _ := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	currentValidators, err := vm.getCurrentValidators(vm.DB, constants.DefaultSubnetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stakerTx := currentValidators.Peek().UnsignedTx.(*UnsignedAddDefaultSubnetValidatorTx)
+	vm.rewards = constantCalculator(1000)
+
+	if err := vm.putTimestamp(vm.DB, stakerTx.EndTime()); err != nil {
+		t.Fatal(err)
+	}
+	tx, err := vm.newRewardValidatorTx(stakerTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, onAbortDB, _, _, err := tx.UnsignedTx.(UnsignedProposalTx).SemanticVerify(vm, vm.DB, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := onAbortDB.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	rewards, err := vm.GetRewardUTXOs(stakerTx.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rewards.Ended {
+		t.Fatalf("staking period should have ended")
+	}
+	if len(rewards.UTXOs) != 0 {
+		t.Fatalf("an aborted staker shouldn't have been rewarded")
+	}
+}
@@ -12,9 +12,6 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/constants"
 	"github.com/ava-labs/gecko/vms/components/avax"
-	"github.com/ava-labs/gecko/vms/components/verify"
-
-	safemath "github.com/ava-labs/gecko/utils/math"
 )
 
 var (
@@ -111,6 +108,7 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 		return nil, nil, nil, nil, tempError{err}
 	}
 
+	var parentTx *UnsignedAddDefaultSubnetValidatorTx
 	switch uVdrTx := vdrTx.UnsignedTx.(type) {
 	case *UnsignedAddDefaultSubnetValidatorTx:
 		// Refund the stake here
@@ -131,35 +129,13 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 				return nil, nil, nil, nil, tempError{err}
 			}
 		}
-
-		// Provide the reward here
-		if reward := reward(uVdrTx.Validator.Duration(), uVdrTx.Validator.Wght, InflationRate); reward > 0 {
-			outIntf, err := vm.fx.CreateOutput(reward, uVdrTx.RewardsOwner)
-			if err != nil {
-				return nil, nil, nil, nil, permError{err}
-			}
-			out, ok := outIntf.(verify.State)
-			if !ok {
-				return nil, nil, nil, nil, permError{errInvalidState}
-			}
-			if err := vm.putUTXO(onCommitDB, &avax.UTXO{
-				UTXOID: avax.UTXOID{
-					TxID:        txID,
-					OutputIndex: uint32(len(uVdrTx.Outs) + len(uVdrTx.Stake)),
-				},
-				Asset: avax.Asset{ID: vm.Ctx.AVAXAssetID},
-				Out:   out,
-			}); err != nil {
-				return nil, nil, nil, nil, tempError{err}
-			}
-		}
 	case *UnsignedAddDefaultSubnetDelegatorTx:
 		// We're removing a delegator
-		parentTx, err := defaultSubnetVdrHeap.getDefaultSubnetStaker(uVdrTx.Validator.NodeID)
+		parent, err := defaultSubnetVdrHeap.getDefaultSubnetStaker(uVdrTx.Validator.NodeID)
 		if err != nil {
 			return nil, nil, nil, nil, permError{err}
 		}
-		unsignedParentTx := parentTx.UnsignedTx.(*UnsignedAddDefaultSubnetValidatorTx)
+		parentTx = parent.UnsignedTx.(*UnsignedAddDefaultSubnetValidatorTx)
 
 		// Refund the stake here
 		for i, out := range uVdrTx.Stake {
@@ -179,68 +155,28 @@ func (tx *UnsignedRewardValidatorTx) SemanticVerify(
 				return nil, nil, nil, nil, tempError{err}
 			}
 		}
+	default:
+		return nil, nil, nil, nil, permError{errShouldBeDSValidator}
+	}
 
-		// If reward given, it will be this amount
-		reward := reward(uVdrTx.Validator.Duration(), uVdrTx.Validator.Wght, InflationRate)
-		// Calculate split of reward between delegator/delegatee
-		// The delegator gives stake to the validatee
-		delegatorShares := NumberOfShares - uint64(unsignedParentTx.Shares) // parentTx.Shares <= NumberOfShares so no underflow
-		delegatorReward := delegatorShares * (reward / NumberOfShares)      // delegatorShares <= NumberOfShares so no overflow
-		// Delay rounding as long as possible for small numbers
-		if optimisticReward, err := safemath.Mul64(delegatorShares, reward); err == nil {
-			delegatorReward = optimisticReward / NumberOfShares
-		}
-		delegateeReward := reward - delegatorReward // delegatorReward <= reward so no underflow
-
-		offset := 0
-
-		// Reward the delegator here
-		if delegatorReward > 0 {
-			outIntf, err := vm.fx.CreateOutput(delegatorReward, uVdrTx.RewardsOwner)
-			if err != nil {
-				return nil, nil, nil, nil, permError{err}
-			}
-			out, ok := outIntf.(verify.State)
-			if !ok {
-				return nil, nil, nil, nil, permError{errInvalidState}
-			}
-			if err := vm.putUTXO(onCommitDB, &avax.UTXO{
-				UTXOID: avax.UTXOID{
-					TxID:        txID,
-					OutputIndex: uint32(len(uVdrTx.Outs) + len(uVdrTx.Stake)),
-				},
-				Asset: avax.Asset{ID: vm.Ctx.AVAXAssetID},
-				Out:   out,
-			}); err != nil {
-				return nil, nil, nil, nil, tempError{err}
-			}
-
-			offset++
+	// Provide the reward here
+	rewardUTXOs, err := vm.rewardUTXOs(txID, vdrTx.UnsignedTx, parentTx)
+	if err != nil {
+		return nil, nil, nil, nil, permError{err}
+	}
+	for _, utxo := range rewardUTXOs {
+		if err := vm.putUTXO(onCommitDB, utxo); err != nil {
+			return nil, nil, nil, nil, tempError{err}
 		}
+	}
 
-		// Reward the delegatee here
-		if delegateeReward > 0 {
-			outIntf, err := vm.fx.CreateOutput(delegateeReward, unsignedParentTx.RewardsOwner)
-			if err != nil {
-				return nil, nil, nil, nil, permError{err}
-			}
-			out, ok := outIntf.(verify.State)
-			if !ok {
-				return nil, nil, nil, nil, permError{errInvalidState}
-			}
-			if err := vm.putUTXO(onCommitDB, &avax.UTXO{
-				UTXOID: avax.UTXOID{
-					TxID:        txID,
-					OutputIndex: uint32(len(uVdrTx.Outs) + len(uVdrTx.Stake) + offset),
-				},
-				Asset: avax.Asset{ID: vm.Ctx.AVAXAssetID},
-				Out:   out,
-			}); err != nil {
-				return nil, nil, nil, nil, tempError{err}
-			}
-		}
-	default:
-		return nil, nil, nil, nil, permError{errShouldBeDSValidator}
+	// Remember what the staker was rewarded, so that it can be looked up
+	// after the reward UTXOs are spent
+	if err := vm.putRewardUTXOs(onCommitDB, txID, rewardUTXOs); err != nil {
+		return nil, nil, nil, nil, tempError{err}
+	}
+	if err := vm.putRewardUTXOs(onAbortDB, txID, nil); err != nil {
+		return nil, nil, nil, nil, tempError{err}
 	}
 
 	// Regardless of whether this tx is committed or aborted, update the
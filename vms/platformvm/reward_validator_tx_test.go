@@ -92,7 +92,7 @@ func TestUnsignedRewardValidatorTxSemanticVerify(t *testing.T) {
 		if onAbortBalance != oldBalance+nextToRemove.Validator.Weight() {
 			t.Fatalf("on abort, should have got back staked amount")
 		}
-		expectedReward := reward(nextToRemove.Validator.Duration(), nextToRemove.Validator.Weight(), InflationRate)
+		expectedReward := vm.rewards.Calculate(nextToRemove.Validator.Weight(), nextToRemove.Validator.Duration())
 		if onCommitBalance != oldBalance+expectedReward+nextToRemove.Validator.Weight() {
 			t.Fatalf("on commit, should have old balance (%d) + staked amount (%d) + reward (%d) but have %d",
 				oldBalance, nextToRemove.Validator.Weight(), expectedReward, onCommitBalance)
@@ -169,10 +169,9 @@ func TestRewardDelegatorTxSemanticVerify(t *testing.T) {
 	delDestSet := ids.ShortSet{}
 	delDestSet.Add(delRewardAddress)
 
-	expectedReward := reward(
-		time.Unix(int64(delEndTime), 0).Sub(time.Unix(int64(delStartTime), 0)), // duration
+	expectedReward := vm.rewards.Calculate(
 		unsignedDelTx.Validator.Weight(),                                       // amount
-		InflationRate,                                                          // inflation rate
+		time.Unix(int64(delEndTime), 0).Sub(time.Unix(int64(delStartTime), 0)), // duration
 	)
 
 	// If tx is committed, delegator and delegatee should get reward
@@ -681,6 +681,60 @@ func (service *Service) SampleValidators(_ *http.Request, args *SampleValidators
 	return nil
 }
 
+// GetDelegatorsArgs are the arguments for calling GetDelegators
+type GetDelegatorsArgs struct {
+	// Subnet of the validator
+	// If omitted, defaults to the default subnet
+	SubnetID ids.ID `json:"subnetID"`
+
+	// Node ID of the validator
+	NodeID string `json:"nodeID"`
+}
+
+// APIDelegation is the representation of a delegation used in API calls
+type APIDelegation struct {
+	TxID        ids.ID      `json:"txID"`
+	StakeAmount json.Uint64 `json:"stakeAmount"`
+	EndTime     json.Uint64 `json:"endTime"`
+}
+
+// GetDelegatorsReply are the results from calling GetDelegators
+type GetDelegatorsReply struct {
+	Delegations       []APIDelegation `json:"delegations"`
+	TotalDelegated    json.Uint64     `json:"totalDelegated"`
+	RemainingCapacity json.Uint64     `json:"remainingCapacity"`
+}
+
+// GetDelegators returns the current and pending delegations to a validator
+func (service *Service) GetDelegators(_ *http.Request, args *GetDelegatorsArgs, reply *GetDelegatorsReply) error {
+	service.vm.Ctx.Log.Info("Platform: GetDelegators called with NodeID = %s", args.NodeID)
+	if args.SubnetID.IsZero() {
+		args.SubnetID = constants.DefaultSubnetID
+	}
+
+	nodeID, err := ids.ShortFromPrefixedString(args.NodeID, constants.NodeIDPrefix)
+	if err != nil {
+		return fmt.Errorf("problem parsing 'nodeID': %w", err)
+	}
+
+	delegations, err := service.vm.GetDelegators(args.SubnetID, nodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get delegators of %s: %w", args.NodeID, err)
+	}
+
+	reply.Delegations = make([]APIDelegation, len(delegations.Delegations))
+	for i, delegation := range delegations.Delegations {
+		reply.Delegations[i] = APIDelegation{
+			TxID:        delegation.TxID,
+			StakeAmount: json.Uint64(delegation.Amount),
+			EndTime:     json.Uint64(delegation.EndTime.Unix()),
+		}
+	}
+	reply.TotalDelegated = json.Uint64(delegations.TotalDelegated)
+	reply.RemainingCapacity = json.Uint64(delegations.RemainingCapacity)
+	return nil
+}
+
 /*
  ******************************************************
  ************ Add Validators to Subnets ***************
@@ -1264,6 +1318,67 @@ func (service *Service) GetBlockchains(_ *http.Request, args *struct{}, response
 	return nil
 }
 
+// GetSubnetBlockchainsArgs are the arguments for calling GetSubnetBlockchains
+type GetSubnetBlockchainsArgs struct {
+	SubnetID ids.ID `json:"subnetID"`
+}
+
+// APIBlockchainStatus is a blockchain along with its status on this node
+type APIBlockchainStatus struct {
+	APIBlockchain
+
+	// Created if this node doesn't run the blockchain, Bootstrapping if it's
+	// still bootstrapping and Validating otherwise
+	Status Status `json:"status"`
+}
+
+// GetSubnetBlockchainsResponse is the response from calling
+// GetSubnetBlockchains
+type GetSubnetBlockchainsResponse struct {
+	Blockchains []APIBlockchainStatus `json:"blockchains"`
+}
+
+// GetSubnetBlockchains returns the blockchains validated by [args.SubnetID],
+// along with whether this node is running and has bootstrapped each of them
+func (service *Service) GetSubnetBlockchains(_ *http.Request, args *GetSubnetBlockchainsArgs, response *GetSubnetBlockchainsResponse) error {
+	service.vm.Ctx.Log.Info("Platform: GetSubnetBlockchains called")
+	// Ignore lookup error if it's the DefaultSubnetID
+	if _, err := service.vm.getSubnet(service.vm.DB, args.SubnetID); err != nil && !args.SubnetID.Equals(constants.DefaultSubnetID) {
+		return fmt.Errorf("problem retrieving subnet '%s': %w", args.SubnetID, err)
+	}
+	chains, err := service.vm.getChains(service.vm.DB)
+	if err != nil {
+		return fmt.Errorf("problem retrieving chains for subnet '%s': %w", args.SubnetID, err)
+	}
+
+	response.Blockchains = []APIBlockchainStatus{}
+	for _, chain := range chains {
+		uChain := chain.UnsignedTx.(*UnsignedCreateChainTx)
+		if !uChain.SubnetID.Equals(args.SubnetID) {
+			continue
+		}
+
+		chainID := uChain.ID()
+		status := Created
+		if _, running := service.vm.chainManager.Engine(chainID); running {
+			status = Bootstrapping
+			if service.vm.chainManager.IsBootstrapped(chainID) {
+				status = Validating
+			}
+		}
+		response.Blockchains = append(response.Blockchains, APIBlockchainStatus{
+			APIBlockchain: APIBlockchain{
+				ID:       chainID,
+				Name:     uChain.ChainName,
+				SubnetID: uChain.SubnetID,
+				VMID:     uChain.VMID,
+			},
+			Status: status,
+		})
+	}
+	return nil
+}
+
 // IssueTxArgs ...
 type IssueTxArgs struct {
 	// Raw byte representation of the transaction
@@ -14,7 +14,9 @@ import (
 	"github.com/ava-labs/gecko/utils/formatting"
 
 	"github.com/ava-labs/gecko/api/keystore"
+	"github.com/ava-labs/gecko/chains"
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/vms/avm"
 )
@@ -293,3 +295,80 @@ func TestGetTx(t *testing.T) {
 		}
 	}
 }
+
+// testChainManager runs the chains in [running], of which the chains in
+// [bootstrapped] have finished bootstrapping
+type testChainManager struct {
+	chains.MockManager
+	running, bootstrapped ids.Set
+}
+
+func (m testChainManager) Engine(chainID ids.ID) (common.Engine, bool) {
+	return nil, m.running.Contains(chainID)
+}
+
+func (m testChainManager) IsBootstrapped(chainID ids.ID) bool {
+	return m.bootstrapped.Contains(chainID)
+}
+
+func TestGetSubnetBlockchains(t *testing.T) {
+	service := defaultService(t)
+	service.vm.Ctx.Lock.Lock()
+	defer func() { service.vm.Shutdown(); service.vm.Ctx.Lock.Unlock() }()
+
+	chainNames := []string{"bootstrapped chain", "bootstrapping chain", "remote chain"}
+	newChains := []*Tx(nil)
+	for _, chainName := range chainNames {
+		tx, err := service.vm.newCreateChainTx(
+			testSubnet1.ID(),
+			nil,
+			avm.ID,
+			nil,
+			chainName,
+			[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		newChains = append(newChains, tx)
+	}
+	existingChains, err := service.vm.getChains(service.vm.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.vm.putChains(service.vm.DB, append(existingChains, newChains...)); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := testChainManager{}
+	manager.running.Add(newChains[0].ID(), newChains[1].ID())
+	manager.bootstrapped.Add(newChains[0].ID())
+	service.vm.chainManager = manager
+
+	response := GetSubnetBlockchainsResponse{}
+	if err := service.GetSubnetBlockchains(nil, &GetSubnetBlockchainsArgs{SubnetID: testSubnet1.ID()}, &response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Blockchains) != len(newChains) {
+		t.Fatalf("Expected %d blockchains but got %d", len(newChains), len(response.Blockchains))
+	}
+
+	expectedStatuses := []Status{Validating, Bootstrapping, Created}
+	for i, chain := range response.Blockchains {
+		switch {
+		case !chain.ID.Equals(newChains[i].ID()):
+			t.Fatalf("Expected blockchain %s but got %s", newChains[i].ID(), chain.ID)
+		case chain.Name != chainNames[i]:
+			t.Fatalf("Expected name %q but got %q", chainNames[i], chain.Name)
+		case !chain.VMID.Equals(avm.ID):
+			t.Fatalf("Expected VM %s but got %s", avm.ID, chain.VMID)
+		case chain.Status != expectedStatuses[i]:
+			t.Fatalf("Expected %s to be %s but it's %s", chain.Name, expectedStatuses[i], chain.Status)
+		}
+	}
+
+	// Unknown subnets should error
+	if err := service.GetSubnetBlockchains(nil, &GetSubnetBlockchainsArgs{SubnetID: ids.GenerateTestID()}, &response); err == nil {
+		t.Fatal("Should have errored due to an unknown subnet")
+	}
+}
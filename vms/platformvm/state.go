@@ -185,6 +185,26 @@ func (vm *VM) removeUTXO(db database.Database, utxoID ids.ID) error {
 	return nil
 }
 
+// putRewardUTXOs persists the UTXOs that rewarded the staker added by the tx
+// with ID [txID]
+func (vm *VM) putRewardUTXOs(db database.Database, txID ids.ID, utxos []*avax.UTXO) error {
+	return vm.State.Put(db, rewardUTXOsTypeID, txID, utxos)
+}
+
+// getRewardUTXOs returns the UTXOs that rewarded the staker added by the tx
+// with ID [txID]. Returns database.ErrNotFound if the staker hasn't been
+// removed from the validator set.
+func (vm *VM) getRewardUTXOs(db database.Database, txID ids.ID) ([]*avax.UTXO, error) {
+	utxosIntf, err := vm.State.Get(db, rewardUTXOsTypeID, txID)
+	if err != nil {
+		return nil, err
+	}
+	if utxos, ok := utxosIntf.([]*avax.UTXO); ok {
+		return utxos, nil
+	}
+	return nil, fmt.Errorf("expected []*avax.UTXO from database but got %T", utxosIntf)
+}
+
 // Return the IDs of UTXOs that reference [addr].
 // Only returns UTXOs after [start].
 // Returns at most [limit] UTXO IDs.
@@ -504,6 +524,23 @@ func (vm *VM) registerDBTypes() {
 		vm.Ctx.Log.Warn(errRegisteringType.Error())
 	}
 
+	marshalRewardUTXOsFunc := func(utxosIntf interface{}) ([]byte, error) {
+		if utxos, ok := utxosIntf.([]*avax.UTXO); ok {
+			return Codec.Marshal(utxos)
+		}
+		return nil, fmt.Errorf("expected []*avax.UTXO but got type %T", utxosIntf)
+	}
+	unmarshalRewardUTXOsFunc := func(bytes []byte) (interface{}, error) {
+		var utxos []*avax.UTXO
+		if err := Codec.Unmarshal(bytes, &utxos); err != nil {
+			return nil, err
+		}
+		return utxos, nil
+	}
+	if err := vm.State.RegisterType(rewardUTXOsTypeID, marshalRewardUTXOsFunc, unmarshalRewardUTXOsFunc); err != nil {
+		vm.Ctx.Log.Warn(errRegisteringType.Error())
+	}
+
 }
 
 // Unmarshal a Block from bytes and initialize it
@@ -19,6 +19,7 @@ type Status uint32
 // [Preferred] means the operation is known and preferred, but hasn't been decided yet
 // [Created] means the operation occurred, but isn't managed locally
 // [Validating] means the operation was accepted and is managed locally
+// [Bootstrapping] means the blockchain is managed locally, but isn't bootstrapped
 const (
 	Unknown Status = iota
 	Preferred
@@ -28,6 +29,7 @@ const (
 	Aborted
 	Processing
 	Dropped
+	Bootstrapping
 )
 
 // MarshalJSON ...
@@ -61,6 +63,8 @@ func (s *Status) UnmarshalJSON(b []byte) error {
 		*s = Processing
 	case "\"Dropped\"":
 		*s = Dropped
+	case "\"Bootstrapping\"":
+		*s = Bootstrapping
 	default:
 		return errUnknownStatus
 	}
@@ -70,7 +74,7 @@ func (s *Status) UnmarshalJSON(b []byte) error {
 // Valid returns nil if the status is a valid status.
 func (s Status) Valid() error {
 	switch s {
-	case Unknown, Preferred, Created, Validating, Committed, Aborted, Processing, Dropped:
+	case Unknown, Preferred, Created, Validating, Committed, Aborted, Processing, Dropped, Bootstrapping:
 		return nil
 	default:
 		return errUnknownStatus
@@ -95,6 +99,8 @@ func (s Status) String() string {
 		return "Processing"
 	case Dropped:
 		return "Dropped"
+	case Bootstrapping:
+		return "Bootstrapping"
 	default:
 		return "Invalid status"
 	}
@@ -79,7 +79,7 @@ func (u *user) putAddress(privKey *crypto.PrivateKeySECP256K1R) error {
 		return nil
 	}
 
-	if err := u.db.Put(address.Bytes(), privKey.Bytes()); err != nil { // Address --> private key
+	if err := u.db.Put(address.Bytes(), privKey.VersionedBytes()); err != nil { // Address --> private key
 		return err
 	}
 
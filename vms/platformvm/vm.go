@@ -29,6 +29,7 @@ import (
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/vms/components/avax"
 	"github.com/ava-labs/gecko/vms/components/core"
+	"github.com/ava-labs/gecko/vms/platformvm/reward"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 
 	safemath "github.com/ava-labs/gecko/utils/math"
@@ -44,6 +45,7 @@ const (
 	utxoSetTypeID
 	txTypeID
 	statusTypeID
+	rewardUTXOsTypeID
 
 	// Delta is the synchrony bound used for safe decision making
 	Delta = 10 * time.Second
@@ -187,6 +189,9 @@ type VM struct {
 	// The minimum amount of tokens one must bond to be a staker
 	minStake uint64
 
+	// Calculates the reward given to stakers
+	rewards reward.Calculator
+
 	// This timer goes off when it is time for the next validator to add/leave the validator set
 	// When it goes off resetTimer() is called, triggering creation of a new block
 	timer *timer.Timer
@@ -223,6 +228,8 @@ func (vm *VM) Initialize(
 	}
 	vm.codec = Codec
 
+	vm.rewards = reward.NewCalculator(InflationRate, MaximumStakingDuration)
+
 	vm.droppedTxCache = cache.LRU{Size: droppedTxCacheSize}
 
 	// Register this VM's types with the database so we can get/put structs to/from it
@@ -12,7 +12,6 @@ import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
-	"github.com/ava-labs/gecko/utils/hashing"
 )
 
 // Output describes what functions every output must implement
@@ -285,7 +284,7 @@ func checkRawAddresses(threshold uint32, addrs []ids.ShortID, sigs []*Sig) bool
 	}
 	for _, sig := range sigs {
 		i := int(sig.index)
-		if !checkRange(i, 0, len(addrs)) || !bytes.Equal(addrs[i].Bytes(), hashing.PubkeyBytesToAddress(sig.parsedPubKey)) {
+		if !checkRange(i, 0, len(addrs)) || !addrs[i].Equals(crypto.PubkeyToAddress(sig.parsedPubKey)) {
 			return false
 		}
 	}
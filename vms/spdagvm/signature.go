@@ -14,7 +14,7 @@ import (
 type Sig struct {
 	index        uint32
 	sig          []byte
-	parsedPubKey []byte
+	parsedPubKey crypto.PublicKey
 }
 
 // InputSigner stores the keys used to sign an input
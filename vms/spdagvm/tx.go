@@ -221,7 +221,7 @@ func (t *Tx) verifySigs() error {
 				if err != nil {
 					return err
 				}
-				sig.parsedPubKey = key.Bytes()
+				sig.parsedPubKey = key
 			}
 		}
 	}
@@ -111,7 +111,7 @@ func (w *Wallet) Send(amount uint64, locktime uint64, destAddr ids.ShortID) *spd
 	return tx
 }
 
-func (w Wallet) String() string {
+func (w *Wallet) String() string {
 	return fmt.Sprintf(
 		"Keychain:\n"+
 			"%s\n"+